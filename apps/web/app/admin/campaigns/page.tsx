@@ -132,7 +132,7 @@ export default function CampaignsPage() {
   }
 
   const handleDeleteCampaign = async (campaignId: string) => {
-    if (!confirm('Are you sure you want to delete this campaign? This will also delete all related links, campaign products, and clicks.')) {
+    if (!confirm('Are you sure you want to delete this campaign? It will be hidden and its links will stop redirecting; its clicks stay in dashboard history.')) {
       return
     }
 
@@ -2,6 +2,7 @@
 
 import { useState, useEffect } from 'react'
 import AdminLayout from '@/components/AdminLayout'
+import { authHeaders } from '@/lib/api'
 
 interface DashboardStats {
   total_clicks: number
@@ -39,7 +40,9 @@ async function fetchDashboardStats(marketplace?: string): Promise<DashboardStats
   const params = new URLSearchParams()
   if (marketplace) params.append('marketplace', marketplace)
 
-  const response = await fetch(`${API_BASE_URL}/api/dashboard?${params.toString()}`)
+  const response = await fetch(`${API_BASE_URL}/api/dashboard?${params.toString()}`, {
+    headers: authHeaders(),
+  })
 
   if (!response.ok) {
     throw new Error('Failed to fetch dashboard stats')
@@ -123,7 +123,7 @@ export default function ProductsPage() {
   }
 
   const handleDeleteProduct = async (productId: string) => {
-    if (!confirm('Are you sure you want to delete this product? This will also delete all related offers, links, and clicks.')) {
+    if (!confirm('Are you sure you want to delete this product? It will be hidden and its links will stop redirecting; its clicks stay in dashboard history.')) {
       return
     }
 
@@ -143,6 +143,21 @@ export default function CampaignPage() {
 
                   {/* Buy Buttons and Copy Links */}
                   <div className="space-y-2">
+                    {(() => {
+                      // Best-price links redirect to whichever marketplace is cheapest when clicked
+                      const bestPriceLink = product.links?.find(l => l.marketplace === 'best_price')
+                      if (!bestPriceLink) {
+                        return null
+                      }
+                      return (
+                        <button
+                          onClick={() => handleBuyClick(bestPriceLink.short_code)}
+                          className="w-full px-4 py-2 rounded-lg font-medium transition bg-green-600 text-white hover:bg-green-700"
+                        >
+                          Buy at the Best Price
+                        </button>
+                      )
+                    })()}
                     {product.offers.map((offer) => {
                       // Find the link for this marketplace - ensure case-insensitive matching
                       const link = product.links?.find(l => 
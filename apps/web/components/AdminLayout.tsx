@@ -1,7 +1,9 @@
 'use client'
 
+import { useEffect, useState } from 'react'
 import Link from 'next/link'
-import { usePathname } from 'next/navigation'
+import { usePathname, useRouter } from 'next/navigation'
+import { getSessionToken, logout } from '@/lib/api'
 
 export default function AdminLayout({
   children,
@@ -9,6 +11,17 @@ export default function AdminLayout({
   children: React.ReactNode
 }) {
   const pathname = usePathname()
+  const router = useRouter()
+  const [signedIn, setSignedIn] = useState(false)
+
+  useEffect(() => {
+    setSignedIn(getSessionToken() !== null)
+  }, [pathname])
+
+  const handleSignOut = () => {
+    logout()
+    router.push('/admin/login')
+  }
 
   const navItems = [
     { href: '/admin/products', label: 'Products' },
@@ -43,6 +56,23 @@ export default function AdminLayout({
                 ))}
               </div>
             </div>
+            <div className="flex items-center">
+              {signedIn ? (
+                <button
+                  onClick={handleSignOut}
+                  className="text-sm font-medium text-gray-500 hover:text-gray-700"
+                >
+                  Sign out
+                </button>
+              ) : (
+                <Link
+                  href="/admin/login"
+                  className="text-sm font-medium text-gray-500 hover:text-gray-700"
+                >
+                  Sign in
+                </Link>
+              )}
+            </div>
           </div>
         </div>
       </nav>
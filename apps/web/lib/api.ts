@@ -1,4 +1,33 @@
 const API_BASE_URL = process.env.NEXT_PUBLIC_API_BASE_URL || 'http://localhost:8080';
+// Admin API key (read + write scopes), used when nobody is signed in
+const API_KEY = process.env.NEXT_PUBLIC_API_KEY;
+const SESSION_TOKEN_KEY = 'session_token';
+
+export function getSessionToken(): string | null {
+  if (typeof window === 'undefined') return null;
+  return window.localStorage.getItem(SESSION_TOKEN_KEY);
+}
+
+export function authHeaders(): Record<string, string> {
+  const token = getSessionToken() || API_KEY;
+  return token ? { Authorization: `Bearer ${token}` } : {};
+}
+
+export interface UserResponse {
+  id: string;
+  email: string;
+  name: string;
+  role: 'admin' | 'campaign_manager' | 'analyst';
+  is_active: boolean;
+  last_login_at?: string;
+  created_at: string;
+}
+
+export interface LoginResponse {
+  token: string;
+  expires_at: string;
+  user: UserResponse;
+}
 
 export interface CreateProductRequest {
   source: string;
@@ -114,6 +143,7 @@ async function apiRequest<T>(
   const url = `${API_BASE_URL}${endpoint}`;
   const headers = new Headers(options.headers);
   headers.set('Content-Type', 'application/json');
+  Object.entries(authHeaders()).forEach(([name, value]) => headers.set(name, value));
 
   const response = await fetch(url, {
     ...options,
@@ -139,6 +169,21 @@ async function apiRequest<T>(
   return response.json();
 }
 
+// Auth API
+export async function login(email: string, password: string): Promise<LoginResponse> {
+  const session = await apiRequest<LoginResponse>('/api/auth/login', {
+    method: 'POST',
+    body: JSON.stringify({ email, password }),
+  });
+  window.localStorage.setItem(SESSION_TOKEN_KEY, session.token);
+  return session;
+}
+
+// Session tokens can't be revoked server-side; forgetting it signs out this browser
+export function logout(): void {
+  window.localStorage.removeItem(SESSION_TOKEN_KEY);
+}
+
 // Product API
 export async function createProduct(data: CreateProductRequest): Promise<ProductResponse> {
   return apiRequest<ProductResponse>('/api/products', {
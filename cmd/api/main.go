@@ -13,10 +13,16 @@ import (
 	echoSwagger "github.com/swaggo/echo-swagger"
 
 	_ "github.com/jonosize/affiliate-platform/docs" // swagger docs (generated by swag init)
+	"github.com/jonosize/affiliate-platform/internal/adapters/factory"
 	"github.com/jonosize/affiliate-platform/internal/api"
+	"github.com/jonosize/affiliate-platform/internal/cache"
 	"github.com/jonosize/affiliate-platform/internal/config"
 	"github.com/jonosize/affiliate-platform/internal/database"
+	"github.com/jonosize/affiliate-platform/internal/geoip"
 	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/qrcode"
+	"github.com/jonosize/affiliate-platform/internal/repository"
+	"github.com/jonosize/affiliate-platform/internal/service"
 	"github.com/jonosize/affiliate-platform/internal/worker"
 )
 
@@ -25,6 +31,11 @@ import (
 // @description API for affiliate link generation and price comparison
 // @host localhost:8080
 // @BasePath /api
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description User session token (from POST /api/auth/login) or admin API key, sent as "Bearer <token>"
+// @securityDefinitions.basic BasicAuth
 func main() {
 	// Load configuration
 	configPath := os.Getenv("CONFIG_PATH")
@@ -44,6 +55,13 @@ func main() {
 	log := logger.Get()
 	log.Info("Starting application...")
 
+	// Initialize marketplace adapters (fails fast on missing credentials)
+	registry, err := factory.NewRegistry(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize adapters", logger.Error(err))
+	}
+	log.Info("Marketplace adapters initialized", logger.Bool("mock_mode", cfg.GetMockMode()))
+
 	// Initialize database
 	db, err := database.InitGORM(cfg)
 	if err != nil {
@@ -57,8 +75,30 @@ func main() {
 
 	log.Info("Database initialized successfully")
 
+	// Initialize cache (Redis when configured, in-process LRU otherwise)
+	cacheCtx, cacheCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	cacheStore, err := cache.NewStore(cacheCtx, cfg)
+	cacheCancel()
+	if err != nil {
+		log.Fatal("Failed to initialize cache", logger.Error(err))
+	}
+	defer func() {
+		if err := cacheStore.Close(); err != nil {
+			log.Error("Error closing cache", logger.Error(err))
+		}
+	}()
+
+	log.Info("Cache initialized", logger.Bool("redis", cfg.GetRedisURL() != ""))
+
+	// Click dedupe windows share Redis with the cache, but get their own LRU so
+	// visitor entries cannot evict cached short codes
+	var dedupeStore cache.Store = cacheStore
+	if cfg.GetRedisURL() == "" {
+		dedupeStore = cache.NewLRUStore(cfg.GetClickDedupeCacheSize())
+	}
+
 	// Initialize price refresh worker
-	priceRefreshWorker := worker.NewPriceRefreshWorker(db, cfg, log)
+	priceRefreshWorker := worker.NewPriceRefreshWorker(db, cfg, registry, log)
 	if err := priceRefreshWorker.Start(); err != nil {
 		log.Fatal("Failed to start price refresh worker", logger.Error(err))
 	}
@@ -66,6 +106,42 @@ func main() {
 
 	log.Info("Price refresh worker started")
 
+	// Initialize bot filter (invalid IP ranges fail fast)
+	botFilter, err := service.NewBotFilter(cfg.GetBotExcludedIPRanges(), cfg.GetBotUserAgentPatterns())
+	if err != nil {
+		log.Fatal("Failed to initialize bot filter", logger.Error(err))
+	}
+
+	// Initialize the GeoIP country database (optional; country redirect rules need it)
+	var countryResolver service.CountryResolver
+	if path := cfg.GetGeoIPDatabasePath(); path != "" {
+		geoReader, err := geoip.Open(path)
+		if err != nil {
+			log.Fatal("Failed to initialize geoip database", logger.Error(err))
+		}
+		defer func() {
+			if err := geoReader.Close(); err != nil {
+				log.Error("Error closing geoip database", logger.Error(err))
+			}
+		}()
+		countryResolver = geoReader
+		log.Info("GeoIP database loaded", logger.String("path", path))
+	}
+
+	// Load the QR code logo (optional; QR codes requested with a logo need it)
+	var qrLogo *qrcode.Logo
+	if path := cfg.GetQRLogoPath(); path != "" {
+		qrLogo, err = qrcode.LoadLogo(path)
+		if err != nil {
+			log.Fatal("Failed to load QR logo", logger.Error(err))
+		}
+		log.Info("QR logo loaded", logger.String("path", path))
+	}
+
+	// Initialize click ingestor (drained on shutdown after the HTTP server stops)
+	clickIngestor := worker.NewClickIngestor(repository.NewClickRepository(db), cfg, log)
+	clickIngestor.Start()
+
 	// Initialize Echo
 	e := echo.New()
 	e.HideBanner = true
@@ -101,7 +177,7 @@ func main() {
 	e.GET("/health", healthCheck)
 
 	// Setup API routes
-	api.SetupRoutes(e, db, cfg, log, priceRefreshWorker)
+	api.SetupRoutes(e, db, cacheStore, cfg, log, registry, priceRefreshWorker, clickIngestor, botFilter, dedupeStore, countryResolver, qrLogo)
 
 	// Start server in a goroutine
 	port := cfg.GetServerPort()
@@ -133,6 +209,11 @@ func main() {
 		log.Fatal("Server forced to shutdown", logger.Error(err))
 	}
 
+	// Drain queued clicks now that no new redirects are accepted
+	if err := clickIngestor.Stop(ctx); err != nil {
+		log.Error("Click ingestor did not drain before shutdown timeout", logger.Error(err))
+	}
+
 	log.Info("Server exited gracefully")
 }
 
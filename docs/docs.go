@@ -17,7 +17,15 @@ const docTemplate = `{
     "paths": {
         "/api/admin/worker/refresh-prices": {
             "post": {
-                "description": "Manually triggers the price refresh worker to update all product prices",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Manually triggers the price refresh worker to update all product prices. The trigger is recorded in the audit log.",
                 "consumes": [
                     "application/json"
                 ],
@@ -47,9 +55,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/campaigns": {
+        "/api/alerts": {
             "get": {
-                "description": "Get a list of all campaigns with pagination",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get a list of all price alert rules with pagination",
                 "consumes": [
                     "application/json"
                 ],
@@ -57,9 +73,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "campaigns"
+                    "alerts"
                 ],
-                "summary": "Get all campaigns",
+                "summary": "Get all price alerts",
                 "parameters": [
                     {
                         "type": "integer",
@@ -78,11 +94,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Campaigns retrieved successfully",
+                        "description": "Alerts retrieved successfully",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dto.CampaignResponse"
+                                "$ref": "#/definitions/dto.AlertResponse"
                             }
                         }
                     },
@@ -95,7 +111,15 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new marketing campaign with UTM parameters",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Create a price change alert rule scoped to a product or a campaign. Matching price changes found by the price refresh worker are delivered as HMAC-signed JSON webhooks (X-Alert-Signature: sha256=HMAC(secret, \"\u003cX-Alert-Timestamp\u003e.\u003cbody\u003e\")). The secret is only returned in this response.",
                 "consumes": [
                     "application/json"
                 ],
@@ -103,25 +127,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "campaigns"
+                    "alerts"
                 ],
-                "summary": "Create a new campaign",
+                "summary": "Create a price alert",
                 "parameters": [
                     {
-                        "description": "Campaign creation request",
+                        "description": "Alert creation request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateCampaignRequest"
+                            "$ref": "#/definitions/dto.CreateAlertRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Campaign created successfully",
+                        "description": "Alert created successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.CampaignResponse"
+                            "$ref": "#/definitions/dto.AlertResponse"
                         }
                     },
                     "400": {
@@ -130,6 +154,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Product or campaign not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -139,9 +169,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/campaigns/{id}": {
+        "/api/alerts/{id}": {
             "get": {
-                "description": "Get campaign details including product IDs",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get a price alert rule",
                 "consumes": [
                     "application/json"
                 ],
@@ -149,14 +187,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "campaigns"
+                    "alerts"
                 ],
-                "summary": "Get a campaign by ID",
+                "summary": "Get a price alert by ID",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Campaign ID",
+                        "description": "Alert ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -164,19 +202,19 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Campaign retrieved successfully",
+                        "description": "Alert retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.CampaignResponse"
+                            "$ref": "#/definitions/dto.AlertResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid campaign ID",
+                        "description": "Invalid alert ID",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Campaign not found",
+                        "description": "Alert not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -190,7 +228,15 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a campaign and all related data (campaign products, links, clicks)",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Delete a price alert rule and its delivery log",
                 "consumes": [
                     "application/json"
                 ],
@@ -198,14 +244,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "campaigns"
+                    "alerts"
                 ],
-                "summary": "Delete a campaign",
+                "summary": "Delete a price alert",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Campaign ID",
+                        "description": "Alert ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -213,16 +259,16 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "Campaign deleted successfully"
+                        "description": "Alert deleted successfully"
                     },
                     "400": {
-                        "description": "Invalid campaign ID",
+                        "description": "Invalid alert ID",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Campaign not found",
+                        "description": "Alert not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -236,7 +282,15 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Update campaign details (name, utm_campaign, dates, products)",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Update a price alert rule (name, marketplace filter, direction, threshold, webhook, secret, active flag)",
                 "consumes": [
                     "application/json"
                 ],
@@ -244,33 +298,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "campaigns"
+                    "alerts"
                 ],
-                "summary": "Update a campaign",
+                "summary": "Update a price alert",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Campaign ID",
+                        "description": "Alert ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Campaign update request",
+                        "description": "Alert update request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateCampaignRequest"
+                            "$ref": "#/definitions/dto.UpdateAlertRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Campaign updated successfully",
+                        "description": "Alert updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.CampaignResponse"
+                            "$ref": "#/definitions/dto.AlertResponse"
                         }
                     },
                     "400": {
@@ -280,7 +334,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Campaign not found",
+                        "description": "Alert not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -294,9 +348,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/campaigns/{id}/products": {
-            "patch": {
-                "description": "Replace all products in a campaign with the provided list",
+        "/api/alerts/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get the webhook delivery log of a price alert rule, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -304,43 +366,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "campaigns"
+                    "alerts"
                 ],
-                "summary": "Update products in a campaign",
+                "summary": "Get webhook deliveries for a price alert",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Campaign ID",
+                        "description": "Alert ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Products update request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateCampaignProductsRequest"
-                        }
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit number of results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Campaign products updated successfully",
+                        "description": "Deliveries retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.CampaignResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.AlertDeliveryResponse"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid alert ID",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Campaign not found",
+                        "description": "Alert not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -354,9 +424,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/campaigns/{id}/public": {
+        "/api/api-keys": {
             "get": {
-                "description": "Get campaign details with products and offers for public landing page",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "List admin API keys (newest first). Keys themselves are never returned after creation; key_prefix tells them apart.",
                 "consumes": [
                     "application/json"
                 ],
@@ -364,44 +442,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "public"
+                    "api-keys"
                 ],
-                "summary": "Get public campaign details",
+                "summary": "List API keys",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Campaign ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Campaign details retrieved successfully",
+                        "description": "API keys retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.CampaignPublicResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.APIKeyResponse"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Campaign is not active",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Campaign not found",
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/dashboard": {
-            "get": {
-                "description": "Get aggregated click statistics, CTR, and top-performing products",
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Create an admin API key with scopes: read (GET admin routes), write (create, update and delete) and worker (worker triggers and status). Send it as \"Authorization: Bearer \u003ckey\u003e\". The key is only returned in this response; it is stored hashed. Managing API keys needs every scope.",
                 "consumes": [
                     "application/json"
                 ],
@@ -409,45 +508,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "dashboard"
+                    "api-keys"
                 ],
-                "summary": "Get dashboard statistics",
+                "summary": "Create an API key",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Filter by campaign ID",
-                        "name": "campaign_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by marketplace (lazada or shopee)",
-                        "name": "marketplace",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Start date filter (RFC3339)",
-                        "name": "start_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "End date filter (RFC3339)",
-                        "name": "end_date",
-                        "in": "query"
+                        "description": "API key creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateAPIKeyRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Dashboard statistics retrieved successfully",
+                    "201": {
+                        "description": "API key created successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.DashboardStatsResponse"
+                            "$ref": "#/definitions/dto.APIKeyResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request parameters",
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -461,9 +556,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/links": {
-            "post": {
-                "description": "Generate a short affiliate link for a product/marketplace combination",
+        "/api/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Delete an admin API key; requests using it are rejected immediately",
                 "consumes": [
                     "application/json"
                 ],
@@ -471,35 +574,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "links"
+                    "api-keys"
                 ],
-                "summary": "Generate affiliate short link",
+                "summary": "Revoke an API key",
                 "parameters": [
                     {
-                        "description": "Link creation request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateLinkRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Link created successfully",
+                    "204": {
+                        "description": "API key revoked successfully"
+                    },
+                    "400": {
+                        "description": "Invalid API key ID",
                         "schema": {
-                            "$ref": "#/definitions/dto.LinkResponse"
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Product or campaign not found",
+                        "description": "API key not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -513,9 +624,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/products": {
+        "/api/audit": {
             "get": {
-                "description": "Get a list of all products with pagination",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "List who created, updated, deleted, restored or purged products, campaigns and links, and who triggered price refreshes, newest first. Updates show only the fields that changed; creates and restores show the entity (after), deletes and purges the removed one (before). Events outlive the entities they describe.",
                 "consumes": [
                     "application/json"
                 ],
@@ -523,20 +642,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "audit"
                 ],
-                "summary": "Get all products",
+                "summary": "Get the audit log",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 100,
-                        "description": "Limit number of results",
-                        "name": "limit",
+                        "type": "string",
+                        "description": "Filter by entity type (product, campaign, link, price_refresh)",
+                        "name": "entity",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "default": 0,
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Filter by entity ID",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by actor subject (e.g. user:\u003cid\u003e, api_key:\u003cid\u003e) or name (e.g. the user's email)",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events at or after this time (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events at or before this time (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit number of results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
                         "description": "Offset for pagination",
                         "name": "offset",
                         "in": "query"
@@ -544,14 +694,20 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Products retrieved successfully",
+                        "description": "Audit events retrieved successfully",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dto.ProductResponse"
+                                "$ref": "#/definitions/dto.AuditEventResponse"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -559,9 +715,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/auth/login": {
             "post": {
-                "description": "Create a new product by fetching data from marketplace URL or SKU",
+                "description": "Log in to the admin API with an email and password. The returned session token is sent as \"Authorization: Bearer \u003ctoken\u003e\" and grants the scopes of the user's role: admin (read, write, worker; manages users and API keys), campaign_manager (read, write) or analyst (read).",
                 "consumes": [
                     "application/json"
                 ],
@@ -569,25 +727,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "auth"
                 ],
-                "summary": "Add product from Lazada/Shopee URL or SKU",
+                "summary": "Log in",
                 "parameters": [
                     {
-                        "description": "Product creation request",
+                        "description": "Login request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateProductRequest"
+                            "$ref": "#/definitions/dto.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Product created successfully",
+                    "200": {
+                        "description": "Logged in successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProductResponse"
+                            "$ref": "#/definitions/dto.LoginResponse"
                         }
                     },
                     "400": {
@@ -596,6 +754,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Invalid email or password",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -605,9 +769,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/products/{id}": {
-            "delete": {
-                "description": "Delete a product and all related data (offers, links, campaign associations, clicks)",
+        "/api/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get the authenticated user, API key or BasicAuth caller and its scopes (for the admin UI to hide what it can't do)",
                 "consumes": [
                     "application/json"
                 ],
@@ -615,31 +787,126 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "auth"
                 ],
-                "summary": "Delete a product",
+                "summary": "Get the current caller",
+                "responses": {
+                    "200": {
+                        "description": "Caller retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PrincipalResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/campaigns": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get a list of all campaigns with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "campaigns"
+                ],
+                "summary": "Get all campaigns",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit number of results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Product deleted successfully"
+                    "200": {
+                        "description": "Campaigns retrieved successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.CampaignResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Create a new marketing campaign with UTM parameters",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "campaigns"
+                ],
+                "summary": "Create a new campaign",
+                "parameters": [
+                    {
+                        "description": "Campaign creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateCampaignRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Campaign created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CampaignResponse"
+                        }
                     },
                     "400": {
-                        "description": "Invalid product ID",
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Product not found",
+                    "409": {
+                        "description": "Short code already in use",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -653,9 +920,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/products/{id}/offers": {
+        "/api/campaigns/{id}": {
             "get": {
-                "description": "Get all marketplace offers for a specific product",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get campaign details including product IDs",
                 "consumes": [
                     "application/json"
                 ],
@@ -663,14 +938,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "campaigns"
                 ],
-                "summary": "Get offers (prices) for a product",
+                "summary": "Get a campaign by ID",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Product ID",
+                        "description": "Campaign ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -678,13 +953,19 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product offers retrieved successfully",
+                        "description": "Campaign retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProductOffersResponse"
+                            "$ref": "#/definitions/dto.CampaignResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid campaign ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Product not found",
+                        "description": "Campaign not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -696,11 +977,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/go/{short_code}": {
-            "get": {
-                "description": "Redirects to the target marketplace URL and tracks the click event",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Soft-delete a campaign. It disappears from listings and its public page and its links stop redirecting, but its clicks keep counting in the dashboard. Restore it with POST /api/campaigns/{id}/restore or remove it for good with DELETE /api/campaigns/{id}/purge.",
                 "consumes": [
                     "application/json"
                 ],
@@ -708,72 +995,4505 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "public"
+                    "campaigns"
                 ],
-                "summary": "Redirect to marketplace product URL",
+                "summary": "Delete a campaign",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Short code",
-                        "name": "short_code",
+                        "format": "uuid",
+                        "description": "Campaign ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "302": {
-                        "description": "Redirect to target URL"
+                    "204": {
+                        "description": "Campaign deleted successfully"
                     },
                     "400": {
-                        "description": "Invalid redirect URL",
+                        "description": "Invalid campaign ID",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Link not found",
+                        "description": "Campaign not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "dto.BestPrice": {
-            "type": "object",
-            "properties": {
-                "marketplace": {
-                    "type": "string",
-                    "example": "shopee"
-                },
-                "price": {
-                    "type": "number",
-                    "example": 279
-                }
-            }
-        },
-        "dto.CampaignProduct": {
-            "type": "object",
-            "properties": {
-                "best_price": {
-                    "$ref": "#/definitions/dto.BestPrice"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
-                },
-                "image_url": {
-                    "type": "string",
-                    "example": "https://example.com/image.jpg"
-                },
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Update campaign details (name, utm_campaign, dates, products)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "campaigns"
+                ],
+                "summary": "Update a campaign",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Campaign ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Campaign update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateCampaignRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Campaign updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CampaignResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Campaign not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Short code already in use",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/campaigns/{id}/products": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Replace all products in a campaign with the provided list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "campaigns"
+                ],
+                "summary": "Update products in a campaign",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Campaign ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Products update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateCampaignProductsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Campaign products updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CampaignResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Campaign not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Short code already in use",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/campaigns/{id}/public": {
+            "get": {
+                "description": "Get campaign details with products and offers for public landing page. Each view records a campaign impression and one impression per rendered product link.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "Get public campaign details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Campaign ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Campaign details retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CampaignPublicResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Campaign is not active",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Campaign not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/campaigns/{id}/purge": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Permanently delete a soft-deleted campaign with all related data (campaign products, links, clicks). Its clicks drop out of dashboard history. The confirm parameter must repeat the campaign ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "campaigns"
+                ],
+                "summary": "Permanently delete a campaign",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Campaign ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "The campaign ID again, to confirm",
+                        "name": "confirm",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Campaign purged successfully"
+                    },
+                    "400": {
+                        "description": "Invalid campaign ID or confirmation",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Deleted campaign not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/campaigns/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Undo a campaign's soft deletion, bringing back its links",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "campaigns"
+                ],
+                "summary": "Restore a deleted campaign",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Campaign ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Campaign restored successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CampaignResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid campaign ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Deleted campaign not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dashboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get aggregated click statistics, CTR, and top-performing products. Bot and prefetch clicks are excluded unless include_bots=true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dashboard"
+                ],
+                "summary": "Get dashboard statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Filter by campaign ID",
+                        "name": "campaign_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by marketplace (e.g. lazada, shopee)",
+                        "name": "marketplace",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date filter (RFC3339)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date filter (RFC3339)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Include clicks flagged as bot traffic",
+                        "name": "include_bots",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dashboard statistics retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DashboardStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Generate a short affiliate link for a product/marketplace combination. An optional vanity short_code (3-32 lowercase letters, digits and hyphens) replaces the generated code.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Generate affiliate short link",
+                "parameters": [
+                    {
+                        "description": "Link creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Link created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product or campaign not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Short code already in use",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{id}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Change a link's short code to a vanity code (the previous code becomes an alias and keeps redirecting), pause, archive or reactivate it, or set or clear its expiry. Paused, archived and expired links redirect to the campaign's fallback URL when its policy is \"fallback\", otherwise they show an \"offer ended\" page (410).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Update a link's short code or lifecycle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Link update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Link updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Short code already in use",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{id}/aliases": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Add another short code (3-32 lowercase letters, digits and hyphens) that redirects to the link",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Add a short code alias to a link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Alias creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateLinkAliasRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Alias added successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Short code already in use",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{id}/aliases/{short_code}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Remove one of a link's aliases; the code stops redirecting",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Remove a short code alias from a link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alias short code",
+                        "name": "short_code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Alias removed successfully"
+                    },
+                    "400": {
+                        "description": "Invalid link ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link or alias not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{id}/qr": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Render the link's short URL as a QR code (PNG or SVG). The encoded URL carries ?src=qr, so scans show up as the \"qr\" source in link and dashboard stats. logo=true overlays the configured logo (api.qr.logo_path) and needs q or h error correction (h by default).",
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Get a link's QR code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "png",
+                            "svg"
+                        ],
+                        "type": "string",
+                        "default": "png",
+                        "description": "Image format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 512,
+                        "description": "Image width and height in pixels (64-2048)",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 4,
+                        "description": "Quiet zone in modules (0-16)",
+                        "name": "margin",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "l",
+                            "m",
+                            "q",
+                            "h"
+                        ],
+                        "type": "string",
+                        "default": "m",
+                        "description": "Error correction level (h with a logo)",
+                        "name": "ecc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Overlay the configured logo",
+                        "name": "logo",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "QR code image",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{id}/redirect-rules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get a link's redirect rules in evaluation order (priority, lowest first)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Get a link's redirect rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Redirect rules retrieved successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.RedirectRuleResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid link ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Route the link's visitors by device OS (ios, android, windows, macos, linux), country (ISO 3166-1 alpha-2, resolved by the optional GeoIP database) and language (primary subtag of the preferred Accept-Language). Empty lists match anyone; the first matching active rule by priority wins. Targets: a web URL, the product's offer on another marketplace (target_url is resolved from the offer) or an app deep link with an optional web fallback (the link's default target otherwise).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Add a redirect rule to a link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Redirect rule creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateRedirectRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Redirect rule created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RedirectRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{id}/redirect-rules/{rule_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Delete a link's redirect rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Delete a redirect rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Redirect rule ID",
+                        "name": "rule_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Redirect rule deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link or redirect rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Update a link's redirect rule. Omitted fields are kept; an empty match list matches anyone. Marketplace targets are re-resolved from the current offer.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Update a redirect rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Redirect rule ID",
+                        "name": "rule_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Redirect rule update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateRedirectRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Redirect rule updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RedirectRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link or redirect rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get total and unique clicks for a link with a time series bucketed by hour or day, plus referrer, browser and device breakdowns. Defaults to the last 30 days bucketed by day; hourly series are limited to 31 days. Bot clicks are excluded unless include_bots=true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Get click statistics for a link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start of time range (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End of time range (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "hour",
+                            "day"
+                        ],
+                        "type": "string",
+                        "default": "day",
+                        "description": "Time series bucket size",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Include clicks flagged as bot traffic",
+                        "name": "include_bots",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Link statistics retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{id}/variants": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get a link's A/B variants in creation order (the first is the control)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Get a link's A/B variants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Variants retrieved successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.LinkVariantResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid link ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Split the link's traffic: once a link has variants with weight, each visitor is sent to one of them in proportion to the weights (e.g. 70/30) and keeps it on later clicks. A variant sends traffic to the product's offer on a marketplace (target_url is resolved from the offer) or to a landing URL. Redirect rules take precedence over variants.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Add an A/B variant to a link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Variant creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateLinkVariantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Variant created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkVariantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{id}/variants/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Clicks, unique clicks, conversions, revenue and EPC of the clicks sent to each variant, with 95% Wilson intervals for conversion rates and Newcombe intervals for each variant's conversion rate difference from the control (the first variant). Conversions are attributed to the variant of the converting click and counted for clicks in the time range. Defaults to the last 30 days; bot clicks are excluded unless include_bots=true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Compare a link's A/B variants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start of time range (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End of time range (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Include clicks flagged as bot traffic",
+                        "name": "include_bots",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Variant statistics retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkVariantStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{id}/variants/{variant_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Delete a link's A/B variant. Its clicks are kept but no longer attributed to a variant; set the weight to 0 instead to keep its stats.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Delete an A/B variant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Variant ID",
+                        "name": "variant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Variant deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link or variant not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Update a link's A/B variant. Changing weights reassigns some returning visitors to other variants; a weight of 0 stops new traffic but keeps the variant's stats. Marketplace variants are re-resolved from the current offer.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "links"
+                ],
+                "summary": "Update an A/B variant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Variant ID",
+                        "name": "variant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Variant update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateLinkVariantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Variant updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkVariantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link or variant not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/postbacks/{network}": {
+            "get": {
+                "description": "Server-to-server conversion postback from an affiliate network. Parameters are read from the query string or a form body: order_id (required), amount, commission, currency, status (pending, approved or rejected; default pending) and click_id or sub_id. Requests are signed with the network's shared secret: hex HMAC-SHA256 of the sorted, URL-encoded parameters without \"signature\", sent in the X-Postback-Signature header or the signature parameter. Repeated postbacks update a pending conversion; approved and rejected conversions are final.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "postbacks"
+                ],
+                "summary": "Receive a conversion postback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "lazada",
+                        "description": "Affiliate network",
+                        "name": "network",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Network order ID",
+                        "name": "order_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Order value",
+                        "name": "amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Commission earned",
+                        "name": "commission",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency code",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "description": "Conversion status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Click ID passed to the network",
+                        "name": "click_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sub ID passed to the network (used when click_id is absent)",
+                        "name": "sub_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature (alternative to the X-Postback-Signature header)",
+                        "name": "signature",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature: hex HMAC-SHA256, optionally prefixed with sha256=",
+                        "name": "X-Postback-Signature",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Conversion recorded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ConversionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid postback",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid signature",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown network",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Status transition not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Server-to-server conversion postback from an affiliate network. Parameters are read from the query string or a form body: order_id (required), amount, commission, currency, status (pending, approved or rejected; default pending) and click_id or sub_id. Requests are signed with the network's shared secret: hex HMAC-SHA256 of the sorted, URL-encoded parameters without \"signature\", sent in the X-Postback-Signature header or the signature parameter. Repeated postbacks update a pending conversion; approved and rejected conversions are final.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "postbacks"
+                ],
+                "summary": "Receive a conversion postback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "lazada",
+                        "description": "Affiliate network",
+                        "name": "network",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Network order ID",
+                        "name": "order_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Order value",
+                        "name": "amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Commission earned",
+                        "name": "commission",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency code",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "description": "Conversion status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Click ID passed to the network",
+                        "name": "click_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sub ID passed to the network (used when click_id is absent)",
+                        "name": "sub_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature (alternative to the X-Postback-Signature header)",
+                        "name": "signature",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature: hex HMAC-SHA256, optionally prefixed with sha256=",
+                        "name": "X-Postback-Signature",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Conversion recorded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ConversionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid postback",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid signature",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown network",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Status transition not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get a list of all products with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get all products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit number of results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Products retrieved successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ProductResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Create a new product by fetching data from marketplace URL or SKU",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Add product from Lazada/Shopee URL or SKU",
+                "parameters": [
+                    {
+                        "description": "Product creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Product created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Soft-delete a product. It disappears from listings, campaigns and public pages and its links stop redirecting, but its clicks keep counting in the dashboard. Restore it with POST /api/products/{id}/restore or remove it for good with DELETE /api/products/{id}/purge.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Delete a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Product deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products/{id}/offers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get all marketplace offers for a specific product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get offers (prices) for a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product offers retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductOffersResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products/{id}/price-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get the recorded price series for a product with min/max/avg, optionally filtered by marketplace and time range",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get price history for a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by marketplace (e.g. lazada, shopee)",
+                        "name": "marketplace",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start of time range (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End of time range (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Price history retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PriceHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products/{id}/purge": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Permanently delete a soft-deleted product with all related data (offers, links, campaign associations, clicks). Its clicks drop out of dashboard history. The confirm parameter must repeat the product ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Permanently delete a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "The product ID again, to confirm",
+                        "name": "confirm",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Product purged successfully"
+                    },
+                    "400": {
+                        "description": "Invalid product ID or confirmation",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Deleted product not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Undo a product's soft deletion, bringing back its links",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Restore a deleted product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product restored successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Deleted product not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "List the roles users can have and the scopes each grants",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List roles",
+                "responses": {
+                    "200": {
+                        "description": "Roles retrieved successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.RoleResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "List admin UI users (by email)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users retrieved successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.UserResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Create an admin UI user with a role (see GET /api/roles). Managing users needs every scope.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a user",
+                "parameters": [
+                    {
+                        "description": "User creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already in use",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get an admin UI user by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Delete an admin UI user; their sessions stop working immediately. The last active admin can't be deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Update an admin UI user's name, password, role or active flag. Changing the password or deactivating the user ends their sessions; role changes apply to their next request. The last active admin can't be demoted or deactivated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/worker/click-ingestor": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get counters of the buffered click ingestion pipeline: clicks enqueued, dropped (queue full), persisted and failed, batch inserts, and current queue depth",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get click ingestion pipeline statistics",
+                "responses": {
+                    "200": {
+                        "description": "Click ingestor statistics",
+                        "schema": {
+                            "$ref": "#/definitions/worker.ClickIngestorStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/workspace": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get the workspace the caller acts in: their own for users and API keys; for BasicAuth operators, the one named by the X-Workspace-ID header or the default workspace",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Get the current workspace",
+                "responses": {
+                    "200": {
+                        "description": "Workspace retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.WorkspaceResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/workspaces": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "List publisher workspaces (by name). Operators (BasicAuth) only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "List workspaces",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Workspaces retrieved successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.WorkspaceResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an operator",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Create a publisher workspace (affiliate brand). Products, campaigns, links, clicks, users and API keys belong to one workspace. With a redirect domain, the workspace's short links use https://\u003credirect_domain\u003e/go/\u003ccode\u003e; point the domain at this deployment. Operators (BasicAuth) only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Create a workspace",
+                "parameters": [
+                    {
+                        "description": "Workspace creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateWorkspaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Workspace created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.WorkspaceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an operator",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Slug or redirect domain already in use",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/workspaces/{id}": {
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Rename a workspace or change its redirect domain (an empty string removes it). Short links already shared on the previous domain stop resolving there. Operators (BasicAuth) only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Update a workspace",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Workspace update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateWorkspaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Workspace updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.WorkspaceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an operator",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Redirect domain already in use",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/go/{short_code}": {
+            "get": {
+                "description": "Redirects to the target marketplace URL and tracks the click event. HEAD requests, prefetches (Purpose / Sec-Purpose headers), known bot user agents and excluded IP ranges are recorded as bot clicks and left out of analytics by default. Repeat clicks by the same visitor (first-party cookie, or salted IP + user agent hash) within the dedupe window are stored but not counted as unique. Each redirect carries a unique click ID as the marketplace's affiliate sub-ID (Lazada sub_aff_id, Shopee sub_id), and publisher sub IDs sub1..sub5 are stored with the click and forwarded (Lazada sub_id1..sub_id5, Shopee sub_id slots 2-5). Paused, archived and expired links, and campaigns outside their start/end window, follow the campaign's out-of-window policy: redirect anyway, redirect to the campaign's fallback URL, or show an \"offer ended\" page (410); the outcome is recorded with the click. The link's redirect rules can send visitors elsewhere by device OS, country and Accept-Language: a web URL, the product on another marketplace, or an app deep link served as a page that opens the app and falls back to the web URL.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "Redirect to marketplace product URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short code",
+                        "name": "short_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Publisher sub ID 1 (letters, digits and underscores; up to 50 characters)",
+                        "name": "sub1",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Publisher sub ID 2",
+                        "name": "sub2",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Publisher sub ID 3",
+                        "name": "sub3",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Publisher sub ID 4",
+                        "name": "sub4",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Publisher sub ID 5 (not forwarded to Shopee)",
+                        "name": "sub5",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Click source marker stored with the click (e.g. qr, added to QR code links)",
+                        "name": "src",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "App deep link page (deep link redirect rules)",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "302": {
+                        "description": "Redirect to target URL (or the campaign's fallback URL)"
+                    },
+                    "400": {
+                        "description": "Invalid redirect URL",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Offer ended page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:00Z"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "key": {
+                    "description": "Only returned on create",
+                    "type": "string",
+                    "example": "afk_3f9a1c2e..."
+                },
+                "key_prefix": {
+                    "type": "string",
+                    "example": "afk_3f9a1c2e"
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Reporting script"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read",
+                        "write"
+                    ]
+                }
+            }
+        },
+        "dto.AlertDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "alert_rule_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:00Z"
+                },
+                "delivered_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:01Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "last_error": {
+                    "type": "string",
+                    "example": ""
+                },
+                "marketplace": {
+                    "type": "string",
+                    "example": "lazada"
+                },
+                "new_price": {
+                    "type": "number",
+                    "example": 249
+                },
+                "old_price": {
+                    "type": "number",
+                    "example": 299
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "response_status": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "status": {
+                    "type": "string",
+                    "example": "delivered"
+                }
+            }
+        },
+        "dto.AlertResponse": {
+            "type": "object",
+            "properties": {
+                "campaign_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:00Z"
+                },
+                "direction": {
+                    "type": "string",
+                    "example": "drop"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "marketplace": {
+                    "type": "string",
+                    "example": "lazada"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Matcha price drop"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "secret": {
+                    "description": "Only returned on create",
+                    "type": "string",
+                    "example": "whsec_change_me"
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 10
+                },
+                "threshold_type": {
+                    "type": "string",
+                    "example": "percent"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:00Z"
+                },
+                "webhook_url": {
+                    "type": "string",
+                    "example": "https://hooks.example.com/price-alerts"
+                }
+            }
+        },
+        "dto.AuditEventResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "actor": {
+                    "type": "string",
+                    "example": "user:123e4567-e89b-12d3-a456-426614174000"
+                },
+                "actor_name": {
+                    "type": "string",
+                    "example": "me@example.com"
+                },
+                "after": {
+                    "description": "Changed fields after an update; the entity after a create",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "before": {
+                    "description": "Changed fields before an update; the entity before a delete",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:00Z"
+                },
+                "entity_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "entity_type": {
+                    "type": "string",
+                    "example": "campaign"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "dto.BestPrice": {
+            "type": "object",
+            "properties": {
+                "is_lowest_30d": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "lowest_30d": {
+                    "description": "Optional: lowest recorded price across marketplaces in the last 30 days (public campaign view)",
+                    "type": "number",
+                    "example": 269
+                },
+                "marketplace": {
+                    "type": "string",
+                    "example": "shopee"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 279
+                }
+            }
+        },
+        "dto.CampaignProduct": {
+            "type": "object",
+            "properties": {
+                "best_price": {
+                    "$ref": "#/definitions/dto.BestPrice"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "image_url": {
+                    "type": "string",
+                    "example": "https://example.com/image.jpg"
+                },
                 "links": {
                     "description": "Links for this product in the campaign",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.ProductLink"
-                    }
+                        "$ref": "#/definitions/dto.ProductLink"
+                    }
+                },
+                "offers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OfferResponse"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Product Title"
+                }
+            }
+        },
+        "dto.CampaignPublicResponse": {
+            "type": "object",
+            "properties": {
+                "end_at": {
+                    "type": "string",
+                    "example": "2025-08-31T23:59:59Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Summer Deal 2025"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CampaignProduct"
+                    }
+                },
+                "start_at": {
+                    "type": "string",
+                    "example": "2025-06-01T00:00:00Z"
+                }
+            }
+        },
+        "dto.CampaignResponse": {
+            "type": "object",
+            "properties": {
+                "best_price_link": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:00Z"
+                },
+                "end_at": {
+                    "type": "string",
+                    "example": "2025-08-31T23:59:59Z"
+                },
+                "fallback_url": {
+                    "type": "string",
+                    "example": "https://shop.example.com/offers"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Summer Deal 2025"
+                },
+                "out_of_window_policy": {
+                    "type": "string",
+                    "example": "fallback"
+                },
+                "product_ids": {
+                    "description": "Product IDs in this campaign",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"123e4567-e89b-12d3-a456-426614174000\"]"
+                    ]
+                },
+                "start_at": {
+                    "type": "string",
+                    "example": "2025-06-01T00:00:00Z"
+                },
+                "utm_campaign": {
+                    "type": "string",
+                    "example": "summer_2025"
+                },
+                "utm_content": {
+                    "type": "string",
+                    "example": "{{marketplace}}"
+                },
+                "utm_medium": {
+                    "type": "string",
+                    "example": "social"
+                },
+                "utm_source": {
+                    "type": "string",
+                    "example": "facebook"
+                },
+                "utm_term": {
+                    "type": "string",
+                    "example": "product_{{product_id}}"
+                }
+            }
+        },
+        "dto.CampaignStat": {
+            "type": "object",
+            "properties": {
+                "campaign_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "campaign_name": {
+                    "type": "string",
+                    "example": "Summer Deal 2025"
+                },
+                "clicks": {
+                    "type": "integer",
+                    "example": 450
+                },
+                "conversion_rate": {
+                    "description": "Conversions / clicks (percentage)",
+                    "type": "number",
+                    "example": 3.11
+                },
+                "conversions": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "ctr": {
+                    "description": "Clicks / impressions (percentage)",
+                    "type": "number",
+                    "example": 2.81
+                },
+                "epc": {
+                    "description": "Revenue / clicks",
+                    "type": "number",
+                    "example": 2.02
+                },
+                "impressions": {
+                    "description": "Product links rendered on the campaign page",
+                    "type": "integer",
+                    "example": 16000
+                },
+                "page_views": {
+                    "type": "integer",
+                    "example": 3200
+                },
+                "revenue": {
+                    "description": "Sum of commission",
+                    "type": "number",
+                    "example": 910
+                },
+                "unique_clicks": {
+                    "type": "integer",
+                    "example": 380
+                }
+            }
+        },
+        "dto.ClickBreakdown": {
+            "type": "object",
+            "properties": {
+                "clicks": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "percentage": {
+                    "type": "number",
+                    "example": 48
+                },
+                "value": {
+                    "type": "string",
+                    "example": "www.facebook.com"
+                }
+            }
+        },
+        "dto.ClickTimeBucket": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string",
+                    "example": "2025-01-15T00:00:00Z"
+                },
+                "clicks": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "unique_clicks": {
+                    "type": "integer",
+                    "example": 35
+                }
+            }
+        },
+        "dto.ConfidenceInterval": {
+            "type": "object",
+            "properties": {
+                "lower": {
+                    "type": "number",
+                    "example": 2.1
+                },
+                "upper": {
+                    "type": "number",
+                    "example": 4.4
+                }
+            }
+        },
+        "dto.ConversionResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 1299
+                },
+                "attributed": {
+                    "description": "Whether the click or sub ID matched a click or link",
+                    "type": "boolean",
+                    "example": true
+                },
+                "campaign_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "click_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "click_ref": {
+                    "description": "Click or sub ID as reported",
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "commission": {
+                    "type": "number",
+                    "example": 64.95
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:00Z"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "THB"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "link_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "marketplace": {
+                    "type": "string",
+                    "example": "lazada"
+                },
+                "network": {
+                    "type": "string",
+                    "example": "lazada"
+                },
+                "order_id": {
+                    "type": "string",
+                    "example": "ORD-20250115-0001"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:00Z"
+                }
+            }
+        },
+        "dto.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "description": "Optional: never expires if omitted",
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Reporting script"
+                },
+                "scopes": {
+                    "description": "read, write and/or worker",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read",
+                        "write"
+                    ]
+                }
+            }
+        },
+        "dto.CreateAlertRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "threshold",
+                "threshold_type",
+                "webhook_url"
+            ],
+            "properties": {
+                "campaign_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "direction": {
+                    "description": "Default: drop",
+                    "type": "string",
+                    "enum": [
+                        "drop",
+                        "rise",
+                        "any"
+                    ],
+                    "example": "drop"
+                },
+                "is_active": {
+                    "description": "Default: true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "marketplace": {
+                    "description": "Optional: all marketplaces if omitted",
+                    "type": "string",
+                    "example": "lazada"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Matcha price drop"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "secret": {
+                    "description": "Optional: generated if omitted",
+                    "type": "string",
+                    "example": "whsec_change_me"
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 10
+                },
+                "threshold_type": {
+                    "type": "string",
+                    "enum": [
+                        "percent",
+                        "absolute"
+                    ],
+                    "example": "percent"
+                },
+                "webhook_url": {
+                    "type": "string",
+                    "example": "https://hooks.example.com/price-alerts"
+                }
+            }
+        },
+        "dto.CreateCampaignRequest": {
+            "type": "object",
+            "required": [
+                "end_at",
+                "name",
+                "start_at",
+                "utm_campaign"
+            ],
+            "properties": {
+                "best_price_link": {
+                    "description": "Also sync a link per product that redirects to the cheapest offer",
+                    "type": "boolean",
+                    "example": true
+                },
+                "end_at": {
+                    "type": "string",
+                    "example": "2025-08-31T23:59:59Z"
+                },
+                "fallback_url": {
+                    "description": "Required by the fallback policy",
+                    "type": "string",
+                    "example": "https://shop.example.com/offers"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Summer Deal 2025"
+                },
+                "out_of_window_policy": {
+                    "description": "Redirects outside start_at..end_at; default: redirect",
+                    "type": "string",
+                    "enum": [
+                        "redirect",
+                        "fallback",
+                        "gone"
+                    ],
+                    "example": "fallback"
+                },
+                "product_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"123e4567-e89b-12d3-a456-426614174000\"]"
+                    ]
+                },
+                "short_codes": {
+                    "description": "Optional vanity codes for the synced links",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LinkShortCode"
+                    }
+                },
+                "start_at": {
+                    "type": "string",
+                    "example": "2025-06-01T00:00:00Z"
+                },
+                "utm_campaign": {
+                    "type": "string",
+                    "example": "summer_2025"
+                },
+                "utm_content": {
+                    "description": "Optional; supports placeholders",
+                    "type": "string",
+                    "example": "{{marketplace}}"
+                },
+                "utm_medium": {
+                    "description": "Default: affiliate",
+                    "type": "string",
+                    "example": "social"
+                },
+                "utm_source": {
+                    "description": "Default: affiliate",
+                    "type": "string",
+                    "example": "facebook"
+                },
+                "utm_term": {
+                    "description": "Optional; supports placeholders",
+                    "type": "string",
+                    "example": "product_{{product_id}}"
+                }
+            }
+        },
+        "dto.CreateLinkAliasRequest": {
+            "type": "object",
+            "required": [
+                "short_code"
+            ],
+            "properties": {
+                "short_code": {
+                    "type": "string",
+                    "example": "matcha-ig"
+                }
+            }
+        },
+        "dto.CreateLinkRequest": {
+            "type": "object",
+            "required": [
+                "campaign_id",
+                "marketplace",
+                "product_id"
+            ],
+            "properties": {
+                "campaign_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "marketplace": {
+                    "type": "string",
+                    "example": "lazada"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "short_code": {
+                    "description": "Optional vanity code (lowercased); generated when omitted",
+                    "type": "string",
+                    "example": "matcha-summer"
+                },
+                "utm_content": {
+                    "type": "string",
+                    "example": "banner_{{marketplace}}"
+                },
+                "utm_medium": {
+                    "type": "string",
+                    "example": "chat"
+                },
+                "utm_source": {
+                    "description": "Optional UTM overrides (omitted fields inherit the campaign's values; an empty string omits the parameter)",
+                    "type": "string",
+                    "example": "line"
+                },
+                "utm_term": {
+                    "type": "string",
+                    "example": "{{product_id}}"
+                }
+            }
+        },
+        "dto.CreateLinkVariantRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "weight"
+            ],
+            "properties": {
+                "marketplace": {
+                    "description": "Marketplace offer variants",
+                    "type": "string",
+                    "example": "shopee"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "shopee"
+                },
+                "target_url": {
+                    "description": "Landing URL variants",
+                    "type": "string",
+                    "example": "https://brand.example.com/matcha?utm_source=ab"
+                },
+                "weight": {
+                    "description": "Relative share of traffic (0-10000); 0 stops new traffic",
+                    "type": "integer",
+                    "example": 30
+                }
+            }
+        },
+        "dto.CreateProductRequest": {
+            "type": "object",
+            "required": [
+                "source",
+                "sourceType"
+            ],
+            "properties": {
+                "lazada_url": {
+                    "description": "Optional: specific URLs for each marketplace",
+                    "type": "string",
+                    "example": "https://www.lazada.co.th/products/example-i123456.html"
+                },
+                "marketplace_urls": {
+                    "description": "Optional: URLs keyed by marketplace, for marketplaces without a dedicated field",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "shopee_url": {
+                    "type": "string",
+                    "example": "https://shopee.co.th/product/123456"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "https://www.lazada.co.th/products/example-i123456.html"
+                },
+                "sourceType": {
+                    "type": "string",
+                    "enum": [
+                        "url",
+                        "sku"
+                    ],
+                    "example": "url"
+                }
+            }
+        },
+        "dto.CreateRedirectRuleRequest": {
+            "type": "object",
+            "required": [
+                "target_type"
+            ],
+            "properties": {
+                "countries": {
+                    "description": "ISO 3166-1 alpha-2",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "TH"
+                    ]
+                },
+                "fallback_url": {
+                    "description": "Deep links only; default: the link's target URL",
+                    "type": "string",
+                    "example": "https://www.lazada.co.th/products/matcha-i123456.html"
+                },
+                "is_active": {
+                    "description": "Default: true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "languages": {
+                    "description": "Primary language subtags",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "th"
+                    ]
+                },
+                "marketplace": {
+                    "description": "Marketplace targets only",
+                    "type": "string",
+                    "example": "shopee"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "iOS app"
+                },
+                "os": {
+                    "description": "ios, android, windows, macos, linux",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "ios",
+                        "android"
+                    ]
+                },
+                "priority": {
+                    "description": "Lower runs first",
+                    "type": "integer",
+                    "example": 10
+                },
+                "target_type": {
+                    "description": "Where matching visitors go",
+                    "type": "string",
+                    "enum": [
+                        "web",
+                        "deep_link",
+                        "marketplace"
+                    ]
+                },
+                "target_url": {
+                    "description": "Web URL or app deep link; resolved from the offer for marketplace targets",
+                    "type": "string",
+                    "example": "lazada://th/d?p=123456"
+                }
+            }
+        },
+        "dto.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "analyst@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Ana Lyst"
+                },
+                "password": {
+                    "description": "8-72 bytes",
+                    "type": "string",
+                    "example": "correct horse battery staple"
+                },
+                "role": {
+                    "description": "admin, campaign_manager or analyst",
+                    "type": "string",
+                    "example": "analyst"
+                }
+            }
+        },
+        "dto.CreateWorkspaceRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "slug"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Gadget Deals"
+                },
+                "redirect_domain": {
+                    "description": "Optional: short links use api.base_url if omitted",
+                    "type": "string",
+                    "example": "go.gadgetdeals.example"
+                },
+                "slug": {
+                    "description": "Lowercase letters, digits and hyphens",
+                    "type": "string",
+                    "example": "gadget-deals"
+                }
+            }
+        },
+        "dto.DashboardStatsResponse": {
+            "type": "object",
+            "properties": {
+                "campaign_stats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CampaignStat"
+                    }
+                },
+                "conversion_rate": {
+                    "description": "Conversions / clicks (percentage)",
+                    "type": "number",
+                    "example": 3.04
+                },
+                "conversions": {
+                    "description": "Postback-reported orders, rejected excluded",
+                    "type": "integer",
+                    "example": 38
+                },
+                "ctr": {
+                    "description": "Click-through rate: clicks / link impressions (percentage)",
+                    "type": "number",
+                    "example": 2.78
+                },
+                "epc": {
+                    "description": "Earnings per click: revenue / clicks",
+                    "type": "number",
+                    "example": 1.98
+                },
+                "marketplace_stats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MarketplaceStat"
+                    }
+                },
+                "page_views": {
+                    "description": "Public campaign page views",
+                    "type": "integer",
+                    "example": 9400
+                },
+                "recent_clicks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RecentClick"
+                    }
+                },
+                "revenue": {
+                    "description": "Sum of commission",
+                    "type": "number",
+                    "example": 2470.5
+                },
+                "source_stats": {
+                    "description": "Clicks by ?src= marker, e.g. QR code scans vs other clicks",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SourceStat"
+                    }
+                },
+                "top_products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TopProduct"
+                    }
+                },
+                "total_clicks": {
+                    "type": "integer",
+                    "example": 1250
+                },
+                "total_impressions": {
+                    "description": "Product links rendered on campaign pages",
+                    "type": "integer",
+                    "example": 45000
+                },
+                "total_links": {
+                    "type": "integer",
+                    "example": 45
+                },
+                "unique_clicks": {
+                    "description": "Clicks outside the per-visitor dedupe window",
+                    "type": "integer",
+                    "example": 980
+                }
+            }
+        },
+        "dto.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "ERROR_CODE"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Error Type"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Human-readable message"
+                }
+            }
+        },
+        "dto.LinkResponse": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "Other short codes that redirect to this link",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "matcha-2024"
+                    ]
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-08-31T23:59:59Z"
+                },
+                "full_url": {
+                    "type": "string",
+                    "example": "https://demo.jonosize.com/go/abc123xyz"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "short_code": {
+                    "type": "string",
+                    "example": "abc123xyz"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "target_url": {
+                    "type": "string",
+                    "example": "https://www.lazada.co.th/products/...?utm_source=...\u0026utm_medium=affiliate\u0026utm_campaign=summer_2025"
+                }
+            }
+        },
+        "dto.LinkShortCode": {
+            "type": "object",
+            "properties": {
+                "marketplace": {
+                    "description": "best_price for the product's best-price link",
+                    "type": "string",
+                    "example": "lazada"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "short_code": {
+                    "type": "string",
+                    "example": "matcha-summer"
+                }
+            }
+        },
+        "dto.LinkStatsResponse": {
+            "type": "object",
+            "properties": {
+                "browsers": {
+                    "description": "Browser family parsed from the user agent",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ClickBreakdown"
+                    }
+                },
+                "devices": {
+                    "description": "Device type parsed from the user agent",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ClickBreakdown"
+                    }
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "interval": {
+                    "type": "string",
+                    "example": "day"
+                },
+                "link_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "marketplace": {
+                    "type": "string",
+                    "example": "lazada"
+                },
+                "outcomes": {
+                    "description": "Redirect outcome: redirected, fallback or gone",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ClickBreakdown"
+                    }
+                },
+                "referrers": {
+                    "description": "Top referrer hosts, \"(direct)\" when no referrer was sent",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ClickBreakdown"
+                    }
+                },
+                "series": {
+                    "description": "Buckets without clicks are omitted",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ClickTimeBucket"
+                    }
+                },
+                "short_code": {
+                    "type": "string",
+                    "example": "abc123xyz"
+                },
+                "sources": {
+                    "description": "?src= marker, e.g. qr for QR code scans; \"(none)\" when unmarked",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ClickBreakdown"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-01-31T23:59:59Z"
+                },
+                "total_clicks": {
+                    "type": "integer",
+                    "example": 250
+                },
+                "unique_clicks": {
+                    "type": "integer",
+                    "example": 198
+                }
+            }
+        },
+        "dto.LinkVariantResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "link_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "marketplace": {
+                    "type": "string",
+                    "example": "shopee"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "shopee"
+                },
+                "target_url": {
+                    "type": "string",
+                    "example": "https://shopee.co.th/product/123456/789012?utm_source=affiliate"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:00Z"
+                },
+                "weight": {
+                    "type": "integer",
+                    "example": 30
+                }
+            }
+        },
+        "dto.LinkVariantStats": {
+            "type": "object",
+            "properties": {
+                "click_share": {
+                    "description": "Actual share of the link's split clicks (percentage)",
+                    "type": "number",
+                    "example": 29.4
+                },
+                "clicks": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "conversion_rate": {
+                    "description": "Conversions / clicks (percentage)",
+                    "type": "number",
+                    "example": 3
+                },
+                "conversion_rate_interval": {
+                    "description": "Wilson score interval (percentage)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.ConfidenceInterval"
+                        }
+                    ]
+                },
+                "conversions": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "epc": {
+                    "description": "Revenue / clicks",
+                    "type": "number",
+                    "example": 1.8
+                },
+                "marketplace": {
+                    "type": "string",
+                    "example": "shopee"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "shopee"
+                },
+                "revenue": {
+                    "type": "number",
+                    "example": 540
+                },
+                "unique_clicks": {
+                    "type": "integer",
+                    "example": 240
+                },
+                "variant_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "vs_control": {
+                    "description": "Omitted for the control (first) variant",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.VariantComparison"
+                        }
+                    ]
+                },
+                "weight": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "weight_share": {
+                    "description": "Configured share of traffic (percentage)",
+                    "type": "number",
+                    "example": 30
+                }
+            }
+        },
+        "dto.LinkVariantStatsResponse": {
+            "type": "object",
+            "properties": {
+                "confidence_level": {
+                    "type": "number",
+                    "example": 0.95
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "link_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "short_code": {
+                    "type": "string",
+                    "example": "abc123xyz"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-01-31T23:59:59Z"
+                },
+                "variants": {
+                    "description": "In creation order; the first is the control",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LinkVariantStats"
+                    }
+                }
+            }
+        },
+        "dto.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "analyst@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "correct horse battery staple"
+                }
+            }
+        },
+        "dto.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-01-15T22:00:00Z"
+                },
+                "token": {
+                    "description": "Send as \"Authorization: Bearer \u003ctoken\u003e\"",
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserResponse"
+                }
+            }
+        },
+        "dto.MarketplaceStat": {
+            "type": "object",
+            "properties": {
+                "clicks": {
+                    "type": "integer",
+                    "example": 750
+                },
+                "ctr": {
+                    "description": "Clicks / impressions (percentage)",
+                    "type": "number",
+                    "example": 3.13
+                },
+                "impressions": {
+                    "type": "integer",
+                    "example": 24000
+                },
+                "marketplace": {
+                    "type": "string",
+                    "example": "lazada"
+                },
+                "percentage": {
+                    "type": "number",
+                    "example": 60
+                },
+                "unique_clicks": {
+                    "type": "integer",
+                    "example": 610
+                }
+            }
+        },
+        "dto.OfferResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "last_checked_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:00Z"
+                },
+                "marketplace": {
+                    "type": "string",
+                    "example": "lazada"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 299
+                },
+                "store_name": {
+                    "type": "string",
+                    "example": "Store Name"
+                }
+            }
+        },
+        "dto.PriceHistoryPoint": {
+            "type": "object",
+            "properties": {
+                "marketplace": {
+                    "type": "string",
+                    "example": "lazada"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 299
+                },
+                "recorded_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:00Z"
+                },
+                "store_name": {
+                    "type": "string",
+                    "example": "Official Store"
+                }
+            }
+        },
+        "dto.PriceHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "marketplace": {
+                    "type": "string",
+                    "example": "lazada"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PriceHistoryPoint"
+                    }
+                },
+                "stats": {
+                    "description": "Omitted when the series is empty",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.PriceHistoryStats"
+                        }
+                    ]
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-01-31T23:59:59Z"
+                }
+            }
+        },
+        "dto.PriceHistoryStats": {
+            "type": "object",
+            "properties": {
+                "avg": {
+                    "type": "number",
+                    "example": 305.5
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "max": {
+                    "type": "number",
+                    "example": 349
+                },
+                "min": {
+                    "type": "number",
+                    "example": 279
+                }
+            }
+        },
+        "dto.PrincipalResponse": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "analyst@example.com"
+                },
+                "role": {
+                    "description": "Empty for API keys and BasicAuth",
+                    "type": "string",
+                    "example": "analyst"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read"
+                    ]
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "user:123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "dto.ProductLink": {
+            "type": "object",
+            "properties": {
+                "full_url": {
+                    "type": "string",
+                    "example": "https://demo.jonosize.com/go/abc123xyz"
+                },
+                "marketplace": {
+                    "description": "best_price for the link to the cheapest offer",
+                    "type": "string",
+                    "example": "lazada"
+                },
+                "short_code": {
+                    "type": "string",
+                    "example": "abc123xyz"
+                }
+            }
+        },
+        "dto.ProductOffersResponse": {
+            "type": "object",
+            "properties": {
+                "best_price": {
+                    "$ref": "#/definitions/dto.BestPrice"
                 },
                 "offers": {
                     "type": "array",
@@ -781,469 +5501,637 @@ const docTemplate = `{
                         "$ref": "#/definitions/dto.OfferResponse"
                     }
                 },
-                "title": {
+                "product_id": {
                     "type": "string",
-                    "example": "Product Title"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 }
             }
         },
-        "dto.CampaignPublicResponse": {
+        "dto.ProductResponse": {
             "type": "object",
             "properties": {
-                "end_at": {
+                "created_at": {
                     "type": "string",
-                    "example": "2025-08-31T23:59:59Z"
+                    "example": "2025-01-15T10:00:00Z"
                 },
                 "id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "name": {
+                "image_url": {
                     "type": "string",
-                    "example": "Summer Deal 2025"
+                    "example": "https://example.com/image.jpg"
                 },
-                "products": {
+                "offers": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.CampaignProduct"
+                        "$ref": "#/definitions/dto.OfferResponse"
                     }
                 },
-                "start_at": {
+                "title": {
                     "type": "string",
-                    "example": "2025-06-01T00:00:00Z"
+                    "example": "Product Title"
                 }
             }
         },
-        "dto.CampaignResponse": {
+        "dto.RecentClick": {
+            "type": "object",
+            "properties": {
+                "campaign_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "campaign_name": {
+                    "type": "string",
+                    "example": "Summer Deal 2025"
+                },
+                "datetime": {
+                    "type": "string",
+                    "example": "2025-01-15T10:30:00Z"
+                },
+                "marketplace": {
+                    "type": "string",
+                    "example": "lazada"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "product_name": {
+                    "type": "string",
+                    "example": "Product Title"
+                }
+            }
+        },
+        "dto.RedirectRuleResponse": {
             "type": "object",
             "properties": {
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "TH"
+                    ]
+                },
                 "created_at": {
                     "type": "string",
                     "example": "2025-01-15T10:00:00Z"
                 },
-                "end_at": {
+                "fallback_url": {
                     "type": "string",
-                    "example": "2025-08-31T23:59:59Z"
+                    "example": "https://www.lazada.co.th/products/matcha-i123456.html"
                 },
                 "id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "name": {
-                    "type": "string",
-                    "example": "Summer Deal 2025"
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "product_ids": {
-                    "description": "Product IDs in this campaign",
+                "languages": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     },
                     "example": [
-                        "[\"123e4567-e89b-12d3-a456-426614174000\"]"
+                        "th"
                     ]
                 },
-                "start_at": {
+                "link_id": {
                     "type": "string",
-                    "example": "2025-06-01T00:00:00Z"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "utm_campaign": {
-                    "type": "string",
-                    "example": "summer_2025"
-                }
-            }
-        },
-        "dto.CampaignStat": {
-            "type": "object",
-            "properties": {
-                "campaign_id": {
+                "marketplace": {
                     "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                    "example": "shopee"
                 },
-                "campaign_name": {
+                "name": {
                     "type": "string",
-                    "example": "Summer Deal 2025"
+                    "example": "iOS app"
                 },
-                "clicks": {
+                "os": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "ios"
+                    ]
+                },
+                "priority": {
                     "type": "integer",
-                    "example": 450
+                    "example": 10
+                },
+                "target_type": {
+                    "type": "string",
+                    "example": "deep_link"
+                },
+                "target_url": {
+                    "type": "string",
+                    "example": "lazada://th/d?p=123456"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-01-15T10:00:00Z"
                 }
             }
         },
-        "dto.CreateCampaignRequest": {
+        "dto.RoleResponse": {
             "type": "object",
-            "required": [
-                "end_at",
-                "name",
-                "start_at",
-                "utm_campaign"
-            ],
             "properties": {
-                "end_at": {
+                "description": {
                     "type": "string",
-                    "example": "2025-08-31T23:59:59Z"
+                    "example": "Read-only access to the catalog, stats and dashboard"
                 },
                 "name": {
                     "type": "string",
-                    "example": "Summer Deal 2025"
+                    "example": "analyst"
                 },
-                "product_ids": {
+                "scopes": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     },
                     "example": [
-                        "[\"123e4567-e89b-12d3-a456-426614174000\"]"
+                        "read"
                     ]
+                }
+            }
+        },
+        "dto.SourceStat": {
+            "type": "object",
+            "properties": {
+                "clicks": {
+                    "type": "integer",
+                    "example": 180
                 },
-                "start_at": {
-                    "type": "string",
-                    "example": "2025-06-01T00:00:00Z"
+                "percentage": {
+                    "type": "number",
+                    "example": 14.4
                 },
-                "utm_campaign": {
+                "source": {
+                    "description": "\"(none)\" for clicks without a marker",
                     "type": "string",
-                    "example": "summer_2025"
+                    "example": "qr"
+                },
+                "unique_clicks": {
+                    "type": "integer",
+                    "example": 150
                 }
             }
         },
-        "dto.CreateLinkRequest": {
+        "dto.TopProduct": {
             "type": "object",
-            "required": [
-                "campaign_id",
-                "marketplace",
-                "product_id"
-            ],
             "properties": {
-                "campaign_id": {
-                    "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                "clicks": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "ctr": {
+                    "description": "Clicks / impressions (percentage)",
+                    "type": "number",
+                    "example": 5
+                },
+                "impressions": {
+                    "type": "integer",
+                    "example": 2400
                 },
                 "marketplace": {
                     "type": "string",
-                    "enum": [
-                        "lazada",
-                        "shopee"
-                    ],
                     "example": "lazada"
                 },
                 "product_id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "product_name": {
+                    "type": "string",
+                    "example": "Product Title"
                 }
             }
         },
-        "dto.CreateProductRequest": {
+        "dto.UpdateAlertRequest": {
             "type": "object",
-            "required": [
-                "source",
-                "sourceType"
-            ],
             "properties": {
-                "lazada_url": {
-                    "description": "Optional: specific URLs for each marketplace",
+                "direction": {
                     "type": "string",
-                    "example": "https://www.lazada.co.th/products/example-i123456.html"
+                    "enum": [
+                        "drop",
+                        "rise",
+                        "any"
+                    ],
+                    "example": "drop"
                 },
-                "shopee_url": {
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "marketplace": {
+                    "description": "Empty string clears the filter",
                     "type": "string",
-                    "example": "https://shopee.co.th/product/123456"
+                    "example": "lazada"
                 },
-                "source": {
+                "name": {
                     "type": "string",
-                    "example": "https://www.lazada.co.th/products/example-i123456.html"
+                    "example": "Matcha price drop"
                 },
-                "sourceType": {
+                "secret": {
+                    "type": "string",
+                    "example": "whsec_change_me"
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 10
+                },
+                "threshold_type": {
                     "type": "string",
                     "enum": [
-                        "url",
-                        "sku"
+                        "percent",
+                        "absolute"
                     ],
-                    "example": "url"
+                    "example": "percent"
+                },
+                "webhook_url": {
+                    "type": "string",
+                    "example": "https://hooks.example.com/price-alerts"
                 }
             }
         },
-        "dto.DashboardStatsResponse": {
+        "dto.UpdateCampaignProductsRequest": {
             "type": "object",
             "properties": {
-                "campaign_stats": {
+                "product_ids": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.CampaignStat"
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"123e4567-e89b-12d3-a456-426614174000\"]"
+                    ]
+                },
+                "short_codes": {
+                    "description": "Optional vanity codes; existing links keep their old code as an alias",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LinkShortCode"
                     }
+                }
+            }
+        },
+        "dto.UpdateCampaignRequest": {
+            "type": "object",
+            "properties": {
+                "best_price_link": {
+                    "description": "Adds or removes the campaign's best-price links",
+                    "type": "boolean",
+                    "example": true
+                },
+                "end_at": {
+                    "type": "string",
+                    "example": "2025-08-31T23:59:59Z"
                 },
-                "ctr": {
-                    "description": "Click-through rate (percentage)",
-                    "type": "number",
-                    "example": 2.78
+                "fallback_url": {
+                    "type": "string",
+                    "example": "https://shop.example.com/offers"
                 },
-                "marketplace_stats": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.MarketplaceStat"
-                    }
+                "name": {
+                    "type": "string",
+                    "example": "Summer Deal 2025"
                 },
-                "recent_clicks": {
+                "out_of_window_policy": {
+                    "type": "string",
+                    "enum": [
+                        "redirect",
+                        "fallback",
+                        "gone"
+                    ],
+                    "example": "gone"
+                },
+                "product_ids": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.RecentClick"
-                    }
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"123e4567-e89b-12d3-a456-426614174000\"]"
+                    ]
                 },
-                "top_products": {
+                "short_codes": {
+                    "description": "Optional vanity codes; existing links keep their old code as an alias",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.TopProduct"
+                        "$ref": "#/definitions/dto.LinkShortCode"
                     }
                 },
-                "total_clicks": {
-                    "type": "integer",
-                    "example": 1250
+                "start_at": {
+                    "type": "string",
+                    "example": "2025-06-01T00:00:00Z"
                 },
-                "total_links": {
-                    "type": "integer",
-                    "example": 45
-                }
-            }
-        },
-        "dto.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "code": {
+                "utm_campaign": {
                     "type": "string",
-                    "example": "ERROR_CODE"
+                    "example": "summer_2025"
                 },
-                "details": {
-                    "type": "object",
-                    "additionalProperties": true
+                "utm_content": {
+                    "type": "string",
+                    "example": "{{marketplace}}"
                 },
-                "error": {
+                "utm_medium": {
                     "type": "string",
-                    "example": "Error Type"
+                    "example": "social"
                 },
-                "message": {
+                "utm_source": {
+                    "description": "Empty string omits the parameter",
                     "type": "string",
-                    "example": "Human-readable message"
+                    "example": "facebook"
+                },
+                "utm_term": {
+                    "type": "string",
+                    "example": "product_{{product_id}}"
                 }
             }
         },
-        "dto.LinkResponse": {
+        "dto.UpdateLinkRequest": {
             "type": "object",
             "properties": {
-                "full_url": {
-                    "type": "string",
-                    "example": "https://demo.jonosize.com/go/abc123xyz"
+                "clear_expires_at": {
+                    "description": "Remove the expiry",
+                    "type": "boolean",
+                    "example": false
                 },
-                "id": {
+                "expires_at": {
                     "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                    "example": "2025-08-31T23:59:59Z"
                 },
                 "short_code": {
                     "type": "string",
-                    "example": "abc123xyz"
+                    "example": "matcha-summer"
                 },
-                "target_url": {
+                "status": {
                     "type": "string",
-                    "example": "https://www.lazada.co.th/products/...?utm_source=...\u0026utm_medium=affiliate\u0026utm_campaign=summer_2025"
+                    "enum": [
+                        "active",
+                        "paused",
+                        "archived"
+                    ],
+                    "example": "paused"
                 }
             }
         },
-        "dto.MarketplaceStat": {
+        "dto.UpdateLinkVariantRequest": {
             "type": "object",
             "properties": {
-                "clicks": {
-                    "type": "integer",
-                    "example": 750
-                },
                 "marketplace": {
                     "type": "string",
-                    "example": "lazada"
+                    "example": "shopee"
                 },
-                "percentage": {
-                    "type": "number",
-                    "example": 60
+                "name": {
+                    "type": "string",
+                    "example": "shopee"
+                },
+                "target_url": {
+                    "type": "string",
+                    "example": "https://brand.example.com/matcha"
+                },
+                "weight": {
+                    "type": "integer",
+                    "example": 50
                 }
             }
         },
-        "dto.OfferResponse": {
+        "dto.UpdateRedirectRuleRequest": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "TH"
+                    ]
                 },
-                "last_checked_at": {
+                "fallback_url": {
                     "type": "string",
-                    "example": "2025-01-15T10:00:00Z"
+                    "example": "https://www.lazada.co.th/products/matcha-i123456.html"
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "th"
+                    ]
                 },
                 "marketplace": {
                     "type": "string",
-                    "example": "lazada"
+                    "example": "shopee"
                 },
-                "price": {
-                    "type": "number",
-                    "example": 299
+                "name": {
+                    "type": "string",
+                    "example": "iOS app"
                 },
-                "store_name": {
+                "os": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "ios"
+                    ]
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "target_type": {
                     "type": "string",
-                    "example": "Store Name"
+                    "enum": [
+                        "web",
+                        "deep_link",
+                        "marketplace"
+                    ]
+                },
+                "target_url": {
+                    "type": "string",
+                    "example": "lazada://th/d?p=123456"
                 }
             }
         },
-        "dto.ProductLink": {
+        "dto.UpdateUserRequest": {
             "type": "object",
             "properties": {
-                "full_url": {
+                "is_active": {
+                    "description": "Inactive users can't log in; their sessions stop working",
+                    "type": "boolean",
+                    "example": false
+                },
+                "name": {
                     "type": "string",
-                    "example": "https://demo.jonosize.com/go/abc123xyz"
+                    "example": "Ana Lyst"
                 },
-                "marketplace": {
+                "password": {
+                    "description": "Signs the user out everywhere",
                     "type": "string",
-                    "example": "lazada"
+                    "example": "correct horse battery staple"
                 },
-                "short_code": {
+                "role": {
                     "type": "string",
-                    "example": "abc123xyz"
+                    "example": "campaign_manager"
                 }
             }
         },
-        "dto.ProductOffersResponse": {
+        "dto.UpdateWorkspaceRequest": {
             "type": "object",
             "properties": {
-                "best_price": {
-                    "$ref": "#/definitions/dto.BestPrice"
-                },
-                "offers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.OfferResponse"
-                    }
+                "name": {
+                    "type": "string",
+                    "example": "Gadget Deals"
                 },
-                "product_id": {
+                "redirect_domain": {
+                    "description": "Empty string removes the domain",
                     "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                    "example": "go.gadgetdeals.example"
                 }
             }
         },
-        "dto.ProductResponse": {
+        "dto.UserResponse": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string",
                     "example": "2025-01-15T10:00:00Z"
                 },
+                "email": {
+                    "type": "string",
+                    "example": "analyst@example.com"
+                },
                 "id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "image_url": {
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "last_login_at": {
                     "type": "string",
-                    "example": "https://example.com/image.jpg"
+                    "example": "2025-01-15T10:00:00Z"
                 },
-                "offers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.OfferResponse"
-                    }
+                "name": {
+                    "type": "string",
+                    "example": "Ana Lyst"
                 },
-                "title": {
+                "role": {
                     "type": "string",
-                    "example": "Product Title"
+                    "example": "analyst"
                 }
             }
         },
-        "dto.RecentClick": {
+        "dto.VariantComparison": {
             "type": "object",
             "properties": {
-                "campaign_id": {
-                    "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
-                },
-                "campaign_name": {
-                    "type": "string",
-                    "example": "Summer Deal 2025"
+                "difference": {
+                    "type": "number",
+                    "example": 0.8
                 },
-                "datetime": {
-                    "type": "string",
-                    "example": "2025-01-15T10:30:00Z"
+                "interval": {
+                    "$ref": "#/definitions/dto.ConfidenceInterval"
                 },
-                "marketplace": {
+                "significant": {
+                    "description": "The interval excludes 0",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "dto.WorkspaceResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string",
-                    "example": "lazada"
+                    "example": "2025-01-15T10:00:00Z"
                 },
-                "product_id": {
+                "id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "product_name": {
+                "name": {
                     "type": "string",
-                    "example": "Product Title"
-                }
-            }
-        },
-        "dto.TopProduct": {
-            "type": "object",
-            "properties": {
-                "clicks": {
-                    "type": "integer",
-                    "example": 120
+                    "example": "Gadget Deals"
                 },
-                "marketplace": {
+                "redirect_domain": {
                     "type": "string",
-                    "example": "lazada"
+                    "example": "go.gadgetdeals.example"
                 },
-                "product_id": {
+                "slug": {
                     "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                    "example": "gadget-deals"
                 },
-                "product_name": {
+                "updated_at": {
                     "type": "string",
-                    "example": "Product Title"
-                }
-            }
-        },
-        "dto.UpdateCampaignProductsRequest": {
-            "type": "object",
-            "properties": {
-                "product_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"123e4567-e89b-12d3-a456-426614174000\"]"
-                    ]
+                    "example": "2025-01-15T10:00:00Z"
                 }
             }
         },
-        "dto.UpdateCampaignRequest": {
+        "worker.ClickIngestorStats": {
             "type": "object",
             "properties": {
-                "end_at": {
-                    "type": "string",
-                    "example": "2025-08-31T23:59:59Z"
+                "batches": {
+                    "description": "Batch inserts attempted",
+                    "type": "integer",
+                    "example": 310
                 },
-                "name": {
-                    "type": "string",
-                    "example": "Summer Deal 2025"
+                "dropped": {
+                    "description": "Clicks rejected because the queue was full or stopped",
+                    "type": "integer",
+                    "example": 0
                 },
-                "product_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"123e4567-e89b-12d3-a456-426614174000\"]"
-                    ]
+                "enqueued": {
+                    "description": "Clicks accepted into the queue",
+                    "type": "integer",
+                    "example": 125000
                 },
-                "start_at": {
-                    "type": "string",
-                    "example": "2025-06-01T00:00:00Z"
+                "failed": {
+                    "description": "Clicks lost to failed batch inserts",
+                    "type": "integer",
+                    "example": 0
                 },
-                "utm_campaign": {
-                    "type": "string",
-                    "example": "summer_2025"
+                "persisted": {
+                    "description": "Clicks written to the database",
+                    "type": "integer",
+                    "example": 124980
+                },
+                "queue_depth": {
+                    "description": "Clicks currently waiting in the queue",
+                    "type": "integer",
+                    "example": 20
+                },
+                "queue_size": {
+                    "description": "Queue capacity",
+                    "type": "integer",
+                    "example": 10000
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BasicAuth": {
+            "type": "basic"
+        },
+        "BearerAuth": {
+            "description": "User session token (from POST /api/auth/login) or admin API key, sent as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
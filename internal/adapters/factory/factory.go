@@ -0,0 +1,68 @@
+package factory
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jonosize/affiliate-platform/internal/config"
+	"github.com/jonosize/affiliate-platform/pkg/adapters"
+	"github.com/jonosize/affiliate-platform/pkg/adapters/lazada"
+	"github.com/jonosize/affiliate-platform/pkg/adapters/mock"
+	"github.com/jonosize/affiliate-platform/pkg/adapters/shopee"
+)
+
+// NewRegistry builds the marketplace adapter registry from config
+// In mock mode, fixture-backed mock adapters are used; otherwise the real
+// Lazada and Shopee adapters are built and their credentials are required
+func NewRegistry(cfg config.Config) (*adapters.Registry, error) {
+	if cfg.GetMockMode() {
+		return mock.NewRegistry()
+	}
+
+	if missing := MissingCredentials(cfg); len(missing) > 0 {
+		return nil, fmt.Errorf("missing adapter credentials (set adapters.mock_mode=true to use mock adapters): %s", strings.Join(missing, ", "))
+	}
+
+	lazadaAdapter := lazada.NewAdapter(cfg.GetLazadaAppKey(), cfg.GetLazadaAppSecret(), cfg.GetLazadaAccessToken())
+	if apiURL := cfg.GetLazadaAPIURL(); apiURL != "" {
+		lazadaAdapter.SetAPIURL(apiURL)
+	}
+
+	shopeeAdapter := shopee.NewAdapter(cfg.GetShopeePartnerID(), cfg.GetShopeePartnerKey(), cfg.GetShopeeShopID(), cfg.GetShopeeAccessToken())
+	if apiURL := cfg.GetShopeeAPIURL(); apiURL != "" {
+		shopeeAdapter.SetAPIURL(apiURL)
+	}
+
+	registry := adapters.NewRegistry()
+	if err := registry.Register(lazadaAdapter, lazada.Domains, nil, lazada.EncodeSubIDs); err != nil {
+		return nil, fmt.Errorf("failed to register lazada adapter: %w", err)
+	}
+	if err := registry.Register(shopeeAdapter, shopee.Domains, shopee.MatchURL, shopee.EncodeSubIDs); err != nil {
+		return nil, fmt.Errorf("failed to register shopee adapter: %w", err)
+	}
+
+	return registry, nil
+}
+
+// MissingCredentials returns the config keys of required adapter credentials that are empty
+func MissingCredentials(cfg config.Config) []string {
+	required := []struct {
+		key   string
+		value string
+	}{
+		{"adapters.lazada.app_key", cfg.GetLazadaAppKey()},
+		{"adapters.lazada.app_secret", cfg.GetLazadaAppSecret()},
+		{"adapters.lazada.access_token", cfg.GetLazadaAccessToken()},
+		{"adapters.shopee.partner_id", cfg.GetShopeePartnerID()},
+		{"adapters.shopee.partner_key", cfg.GetShopeePartnerKey()},
+		{"adapters.shopee.access_token", cfg.GetShopeeAccessToken()},
+	}
+
+	missing := make([]string, 0)
+	for _, r := range required {
+		if strings.TrimSpace(r.value) == "" {
+			missing = append(missing, r.key)
+		}
+	}
+	return missing
+}
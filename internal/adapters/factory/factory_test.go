@@ -0,0 +1,73 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/config"
+	"github.com/jonosize/affiliate-platform/pkg/adapters"
+	"github.com/jonosize/affiliate-platform/pkg/adapters/lazada"
+	"github.com/jonosize/affiliate-platform/pkg/adapters/mock"
+	"github.com/jonosize/affiliate-platform/pkg/adapters/shopee"
+)
+
+func loadConfig(t *testing.T, env map[string]string) config.Config {
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	cfg, err := config.NewViperConfig(t.TempDir())
+	require.NoError(t, err)
+	return cfg
+}
+
+func TestNewRegistry_MockMode(t *testing.T) {
+	cfg := loadConfig(t, map[string]string{"ADAPTERS_MOCK_MODE": "true"})
+
+	registry, err := NewRegistry(cfg)
+	require.NoError(t, err)
+
+	adapter, err := registry.Get(adapters.MarketplaceLazada)
+	require.NoError(t, err)
+	assert.IsType(t, &mock.MockAdapter{}, adapter)
+}
+
+func TestNewRegistry_MissingCredentials(t *testing.T) {
+	cfg := loadConfig(t, map[string]string{
+		"ADAPTERS_MOCK_MODE":      "false",
+		"ADAPTERS_LAZADA_APP_KEY": "key",
+	})
+
+	_, err := NewRegistry(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "adapters.lazada.app_secret")
+	assert.Contains(t, err.Error(), "adapters.shopee.partner_key")
+	assert.NotContains(t, err.Error(), "adapters.lazada.app_key")
+}
+
+func TestNewRegistry_RealAdapters(t *testing.T) {
+	cfg := loadConfig(t, map[string]string{
+		"ADAPTERS_MOCK_MODE":           "false",
+		"ADAPTERS_LAZADA_APP_KEY":      "key",
+		"ADAPTERS_LAZADA_APP_SECRET":   "secret",
+		"ADAPTERS_LAZADA_ACCESS_TOKEN": "token",
+		"ADAPTERS_SHOPEE_PARTNER_ID":   "2001234",
+		"ADAPTERS_SHOPEE_PARTNER_KEY":  "partner-key",
+		"ADAPTERS_SHOPEE_ACCESS_TOKEN": "token",
+	})
+
+	registry, err := NewRegistry(cfg)
+	require.NoError(t, err)
+
+	lazadaAdapter, err := registry.Get(adapters.MarketplaceLazada)
+	require.NoError(t, err)
+	assert.IsType(t, &lazada.LazadaAdapter{}, lazadaAdapter)
+
+	shopeeAdapter, err := registry.Get(adapters.MarketplaceShopee)
+	require.NoError(t, err)
+	assert.IsType(t, &shopee.ShopeeAdapter{}, shopeeAdapter)
+
+	assert.True(t, registry.IsAllowedHost("www.lazada.co.th"))
+	assert.True(t, registry.IsAllowedHost("shopee.co.th"))
+}
@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/service"
+)
+
+// AlertHandler handles price alert HTTP requests
+type AlertHandler struct {
+	service *service.AlertService
+	logger  logger.Logger
+}
+
+// NewAlertHandler creates a new alert handler
+func NewAlertHandler(service *service.AlertService, logger logger.Logger) *AlertHandler {
+	return &AlertHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateAlert handles POST /api/alerts
+// @Summary Create a price alert
+// @Description Create a price change alert rule scoped to a product or a campaign. Matching price changes found by the price refresh worker are delivered as HMAC-signed JSON webhooks (X-Alert-Signature: sha256=HMAC(secret, "<X-Alert-Timestamp>.<body>")). The secret is only returned in this response.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAlertRequest true "Alert creation request"
+// @Success 201 {object} dto.AlertResponse "Alert created successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 404 {object} dto.ErrorResponse "Product or campaign not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/alerts [post]
+func (h *AlertHandler) CreateAlert(c echo.Context) error {
+	var req dto.CreateAlertRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid request body",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	alert, err := h.service.CreateAlert(c.Request().Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to create alert", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to create alert")
+	}
+
+	return c.JSON(http.StatusCreated, alert)
+}
+
+// GetAllAlerts handles GET /api/alerts
+// @Summary Get all price alerts
+// @Description Get a list of all price alert rules with pagination
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param limit query int false "Limit number of results" default(100)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} dto.AlertResponse "Alerts retrieved successfully"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/alerts [get]
+func (h *AlertHandler) GetAllAlerts(c echo.Context) error {
+	limit, offset := parsePagination(c)
+
+	alerts, err := h.service.GetAllAlerts(c.Request().Context(), limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to get alerts", logger.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to get alerts",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(http.StatusOK, alerts)
+}
+
+// GetAlert handles GET /api/alerts/:id
+// @Summary Get a price alert by ID
+// @Description Get a price alert rule
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert ID" format(uuid)
+// @Success 200 {object} dto.AlertResponse "Alert retrieved successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid alert ID"
+// @Failure 404 {object} dto.ErrorResponse "Alert not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/alerts/{id} [get]
+func (h *AlertHandler) GetAlert(c echo.Context) error {
+	alertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid alert ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	alert, err := h.service.GetAlert(c.Request().Context(), alertID)
+	if err != nil {
+		h.logger.Error("Failed to get alert", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to get alert")
+	}
+
+	return c.JSON(http.StatusOK, alert)
+}
+
+// UpdateAlert handles PATCH /api/alerts/:id
+// @Summary Update a price alert
+// @Description Update a price alert rule (name, marketplace filter, direction, threshold, webhook, secret, active flag)
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert ID" format(uuid)
+// @Param request body dto.UpdateAlertRequest true "Alert update request"
+// @Success 200 {object} dto.AlertResponse "Alert updated successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 404 {object} dto.ErrorResponse "Alert not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/alerts/{id} [patch]
+func (h *AlertHandler) UpdateAlert(c echo.Context) error {
+	alertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid alert ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	var req dto.UpdateAlertRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid request body",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	alert, err := h.service.UpdateAlert(c.Request().Context(), alertID, req)
+	if err != nil {
+		h.logger.Error("Failed to update alert", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to update alert")
+	}
+
+	return c.JSON(http.StatusOK, alert)
+}
+
+// DeleteAlert handles DELETE /api/alerts/:id
+// @Summary Delete a price alert
+// @Description Delete a price alert rule and its delivery log
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert ID" format(uuid)
+// @Success 204 "Alert deleted successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid alert ID"
+// @Failure 404 {object} dto.ErrorResponse "Alert not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/alerts/{id} [delete]
+func (h *AlertHandler) DeleteAlert(c echo.Context) error {
+	alertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid alert ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	if err := h.service.DeleteAlert(c.Request().Context(), alertID); err != nil {
+		h.logger.Error("Failed to delete alert", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to delete alert")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetAlertDeliveries handles GET /api/alerts/:id/deliveries
+// @Summary Get webhook deliveries for a price alert
+// @Description Get the webhook delivery log of a price alert rule, newest first
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert ID" format(uuid)
+// @Param limit query int false "Limit number of results" default(100)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} dto.AlertDeliveryResponse "Deliveries retrieved successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid alert ID"
+// @Failure 404 {object} dto.ErrorResponse "Alert not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/alerts/{id}/deliveries [get]
+func (h *AlertHandler) GetAlertDeliveries(c echo.Context) error {
+	alertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid alert ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	limit, offset := parsePagination(c)
+
+	deliveries, err := h.service.GetAlertDeliveries(c.Request().Context(), alertID, limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to get alert deliveries", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to get alert deliveries")
+	}
+
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+// errorResponse maps alert service errors to HTTP responses
+func (h *AlertHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	errMsg := err.Error()
+	switch {
+	case strings.Contains(errMsg, "alert not found"):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Alert Not Found",
+			Message: "Alert with the specified ID was not found",
+			Code:    "ALERT_NOT_FOUND",
+		})
+	case strings.Contains(errMsg, "product not found"):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Product Not Found",
+			Message: "Product with the specified ID was not found",
+			Code:    "PRODUCT_NOT_FOUND",
+		})
+	case strings.Contains(errMsg, "campaign not found"):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Campaign Not Found",
+			Message: "Campaign with the specified ID was not found",
+			Code:    "CAMPAIGN_NOT_FOUND",
+		})
+	case strings.HasPrefix(errMsg, "invalid "):
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: errMsg,
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "Internal Server Error",
+		Message: fallback,
+		Code:    "INTERNAL_ERROR",
+	})
+}
+
+// parsePagination parses limit (default 100) and offset (default 0) query parameters
+func parsePagination(c echo.Context) (int, int) {
+	limit := 100 // default limit
+	offset := 0  // default offset
+
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	if offsetStr := c.QueryParam("offset"); offsetStr != "" {
+		if parsed, err := strconv.Atoi(offsetStr); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	return limit, offset
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/service"
+)
+
+// APIKeyHandler handles admin API key HTTP requests
+type APIKeyHandler struct {
+	service *service.APIKeyService
+	logger  logger.Logger
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(service *service.APIKeyService, logger logger.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateAPIKey handles POST /api/api-keys
+// @Summary Create an API key
+// @Description Create an admin API key with scopes: read (GET admin routes), write (create, update and delete) and worker (worker triggers and status). Send it as "Authorization: Bearer <key>". The key is only returned in this response; it is stored hashed. Managing API keys needs every scope.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAPIKeyRequest true "API key creation request"
+// @Success 201 {object} dto.APIKeyResponse "API key created successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Missing scope"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c echo.Context) error {
+	var req dto.CreateAPIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid request body",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	key, err := h.service.CreateAPIKey(c.Request().Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to create api key", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to create API key")
+	}
+
+	return c.JSON(http.StatusCreated, key)
+}
+
+// GetAllAPIKeys handles GET /api/api-keys
+// @Summary List API keys
+// @Description List admin API keys (newest first). Keys themselves are never returned after creation; key_prefix tells them apart.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param limit query int false "Limit" default(100)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} dto.APIKeyResponse "API keys retrieved successfully"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Missing scope"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/api-keys [get]
+func (h *APIKeyHandler) GetAllAPIKeys(c echo.Context) error {
+	limit, offset := parsePagination(c)
+
+	keys, err := h.service.GetAllAPIKeys(c.Request().Context(), limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to get api keys", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to get API keys")
+	}
+
+	return c.JSON(http.StatusOK, keys)
+}
+
+// DeleteAPIKey handles DELETE /api/api-keys/:id
+// @Summary Revoke an API key
+// @Description Delete an admin API key; requests using it are rejected immediately
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param id path string true "API key ID" format(uuid)
+// @Success 204 "API key revoked successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid API key ID"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Missing scope"
+// @Failure 404 {object} dto.ErrorResponse "API key not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/api-keys/{id} [delete]
+func (h *APIKeyHandler) DeleteAPIKey(c echo.Context) error {
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid API key ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	if err := h.service.DeleteAPIKey(c.Request().Context(), keyID); err != nil {
+		h.logger.Error("Failed to delete api key", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to delete API key")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// errorResponse maps API key service errors to HTTP responses
+func (h *APIKeyHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	errMsg := err.Error()
+	switch {
+	case strings.Contains(errMsg, "api key not found"):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "API Key Not Found",
+			Message: "API key with the specified ID was not found",
+			Code:    "API_KEY_NOT_FOUND",
+		})
+	case strings.HasPrefix(errMsg, "invalid "):
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: errMsg,
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "Internal Server Error",
+		Message: fallback,
+		Code:    "INTERNAL_ERROR",
+	})
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/service"
+)
+
+// AuditHandler handles audit log HTTP requests
+type AuditHandler struct {
+	service *service.AuditService
+	logger  logger.Logger
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(service *service.AuditService, logger logger.Logger) *AuditHandler {
+	return &AuditHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetAuditEvents handles GET /api/audit
+// @Summary Get the audit log
+// @Description List who created, updated, deleted, restored or purged products, campaigns and links, and who triggered price refreshes, newest first. Updates show only the fields that changed; creates and restores show the entity (after), deletes and purges the removed one (before). Events outlive the entities they describe.
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param entity query string false "Filter by entity type (product, campaign, link, price_refresh)"
+// @Param entity_id query string false "Filter by entity ID" format(uuid)
+// @Param actor query string false "Filter by actor subject (e.g. user:<id>, api_key:<id>) or name (e.g. the user's email)"
+// @Param from query string false "Only events at or after this time (RFC3339)" example:"2025-01-01T00:00:00Z"
+// @Param to query string false "Only events at or before this time (RFC3339)" example:"2025-12-31T23:59:59Z"
+// @Param limit query int false "Limit number of results" default(100)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} dto.AuditEventResponse "Audit events retrieved successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request parameters"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/audit [get]
+func (h *AuditHandler) GetAuditEvents(c echo.Context) error {
+	params := dto.AuditQueryParams{
+		Entity: c.QueryParam("entity"),
+		Actor:  c.QueryParam("actor"),
+	}
+
+	// Parse entity_id filter
+	if entityIDStr := c.QueryParam("entity_id"); entityIDStr != "" {
+		entityID, err := uuid.Parse(entityIDStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid entity_id format",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.EntityID = &entityID
+	}
+
+	// Parse from filter
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid from format (expected RFC3339)",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.From = &from
+	}
+
+	// Parse to filter
+	if toStr := c.QueryParam("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid to format (expected RFC3339)",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.To = &to
+	}
+
+	limit, offset := parsePagination(c)
+
+	events, err := h.service.GetAuditEvents(c.Request().Context(), params, limit, offset)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: err.Error(),
+				Code:    "INVALID_INPUT",
+			})
+		}
+		h.logger.Error("Failed to get audit events", logger.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to get audit events",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(http.StatusOK, events)
+}
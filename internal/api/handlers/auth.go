@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/service"
+)
+
+// AuthHandler handles admin login HTTP requests
+type AuthHandler struct {
+	service *service.UserService
+	logger  logger.Logger
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(service *service.UserService, logger logger.Logger) *AuthHandler {
+	return &AuthHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Login handles POST /api/auth/login
+// @Summary Log in
+// @Description Log in to the admin API with an email and password. The returned session token is sent as "Authorization: Bearer <token>" and grants the scopes of the user's role: admin (read, write, worker; manages users and API keys), campaign_manager (read, write) or analyst (read).
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.LoginRequest true "Login request"
+// @Success 200 {object} dto.LoginResponse "Logged in successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 401 {object} dto.ErrorResponse "Invalid email or password"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /api/auth/login [post]
+func (h *AuthHandler) Login(c echo.Context) error {
+	var req dto.LoginRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid request body",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	session, err := h.service.Login(c.Request().Context(), req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidLogin) {
+			return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Error:   "Unauthorized",
+				Message: "Invalid email or password",
+				Code:    "INVALID_CREDENTIALS",
+			})
+		}
+		h.logger.Error("Failed to log in", logger.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to log in",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(http.StatusOK, session)
+}
+
+// Me handles GET /api/auth/me
+// @Summary Get the current caller
+// @Description Get the authenticated user, API key or BasicAuth caller and its scopes (for the admin UI to hide what it can't do)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.PrincipalResponse "Caller retrieved successfully"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/auth/me [get]
+func (h *AuthHandler) Me(c echo.Context) error {
+	principal := auth.PrincipalFrom(c.Request().Context())
+
+	response := dto.PrincipalResponse{
+		Subject: principal.Subject,
+		Name:    principal.Name,
+		Role:    principal.Role,
+		Scopes:  make([]string, len(principal.Scopes)),
+	}
+	for i, scope := range principal.Scopes {
+		response.Scopes[i] = string(scope)
+	}
+	return c.JSON(http.StatusOK, response)
+}
@@ -36,7 +36,10 @@ func NewCampaignHandler(service *service.CampaignService, logger logger.Logger)
 // @Param request body dto.CreateCampaignRequest true "Campaign creation request"
 // @Success 201 {object} dto.CampaignResponse "Campaign created successfully"
 // @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 409 {object} dto.ErrorResponse "Short code already in use"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
 // @Router /api/campaigns [post]
 func (h *CampaignHandler) CreateCampaign(c echo.Context) error {
 	var req dto.CreateCampaignRequest
@@ -69,6 +72,20 @@ func (h *CampaignHandler) CreateCampaign(c echo.Context) error {
 	campaign, err := h.service.CreateCampaign(c.Request().Context(), req)
 	if err != nil {
 		h.logger.Error("Failed to create campaign", logger.String("error", err.Error()))
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: err.Error(),
+				Code:    "INVALID_INPUT",
+			})
+		}
+		if strings.Contains(err.Error(), "short code already in use") {
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Conflict",
+				Message: err.Error(),
+				Code:    "SHORT_CODE_CONFLICT",
+			})
+		}
 		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Internal Server Error",
 			Message: err.Error(),
@@ -89,6 +106,8 @@ func (h *CampaignHandler) CreateCampaign(c echo.Context) error {
 // @Param offset query int false "Offset for pagination" default(0)
 // @Success 200 {array} dto.CampaignResponse "Campaigns retrieved successfully"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
 // @Router /api/campaigns [get]
 func (h *CampaignHandler) GetAllCampaigns(c echo.Context) error {
 	// Parse query parameters
@@ -132,6 +151,8 @@ func (h *CampaignHandler) GetAllCampaigns(c echo.Context) error {
 // @Failure 400 {object} dto.ErrorResponse "Invalid campaign ID"
 // @Failure 404 {object} dto.ErrorResponse "Campaign not found"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
 // @Router /api/campaigns/{id} [get]
 func (h *CampaignHandler) GetCampaign(c echo.Context) error {
 	campaignIDStr := c.Param("id")
@@ -170,7 +191,7 @@ func (h *CampaignHandler) GetCampaign(c echo.Context) error {
 
 // DeleteCampaign handles DELETE /api/campaigns/:id
 // @Summary Delete a campaign
-// @Description Delete a campaign and all related data (campaign products, links, clicks)
+// @Description Soft-delete a campaign. It disappears from listings and its public page and its links stop redirecting, but its clicks keep counting in the dashboard. Restore it with POST /api/campaigns/{id}/restore or remove it for good with DELETE /api/campaigns/{id}/purge.
 // @Tags campaigns
 // @Accept json
 // @Produce json
@@ -179,6 +200,8 @@ func (h *CampaignHandler) GetCampaign(c echo.Context) error {
 // @Failure 400 {object} dto.ErrorResponse "Invalid campaign ID"
 // @Failure 404 {object} dto.ErrorResponse "Campaign not found"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
 // @Router /api/campaigns/{id} [delete]
 func (h *CampaignHandler) DeleteCampaign(c echo.Context) error {
 	campaignIDStr := c.Param("id")
@@ -215,6 +238,105 @@ func (h *CampaignHandler) DeleteCampaign(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// RestoreCampaign handles POST /api/campaigns/:id/restore
+// @Summary Restore a deleted campaign
+// @Description Undo a campaign's soft deletion, bringing back its links
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Param id path string true "Campaign ID" format(uuid)
+// @Success 200 {object} dto.CampaignResponse "Campaign restored successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid campaign ID"
+// @Failure 404 {object} dto.ErrorResponse "Deleted campaign not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/campaigns/{id}/restore [post]
+func (h *CampaignHandler) RestoreCampaign(c echo.Context) error {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid campaign ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	campaign, err := h.service.RestoreCampaign(c.Request().Context(), campaignID)
+	if err != nil {
+		if strings.Contains(err.Error(), "campaign not found") {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Campaign Not Found",
+				Message: "No deleted campaign with the specified ID was found",
+				Code:    "CAMPAIGN_NOT_FOUND",
+			})
+		}
+
+		h.logger.Error("Failed to restore campaign", logger.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to restore campaign",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(http.StatusOK, campaign)
+}
+
+// PurgeCampaign handles DELETE /api/campaigns/:id/purge
+// @Summary Permanently delete a campaign
+// @Description Permanently delete a soft-deleted campaign with all related data (campaign products, links, clicks). Its clicks drop out of dashboard history. The confirm parameter must repeat the campaign ID.
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Param id path string true "Campaign ID" format(uuid)
+// @Param confirm query string true "The campaign ID again, to confirm" format(uuid)
+// @Success 204 "Campaign purged successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid campaign ID or confirmation"
+// @Failure 404 {object} dto.ErrorResponse "Deleted campaign not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/campaigns/{id}/purge [delete]
+func (h *CampaignHandler) PurgeCampaign(c echo.Context) error {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid campaign ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	err = h.service.PurgeCampaign(c.Request().Context(), campaignID, c.QueryParam("confirm"))
+	if err != nil {
+		errMsg := err.Error()
+		if strings.HasPrefix(errMsg, "invalid ") {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: errMsg,
+				Code:    "INVALID_INPUT",
+			})
+		}
+		if strings.Contains(errMsg, "campaign not found") {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Campaign Not Found",
+				Message: "No deleted campaign with the specified ID was found",
+				Code:    "CAMPAIGN_NOT_FOUND",
+			})
+		}
+
+		h.logger.Error("Failed to purge campaign", logger.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to purge campaign",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 // UpdateCampaign handles PATCH /api/campaigns/:id
 // @Summary Update a campaign
 // @Description Update campaign details (name, utm_campaign, dates, products)
@@ -226,7 +348,10 @@ func (h *CampaignHandler) DeleteCampaign(c echo.Context) error {
 // @Success 200 {object} dto.CampaignResponse "Campaign updated successfully"
 // @Failure 400 {object} dto.ErrorResponse "Invalid request"
 // @Failure 404 {object} dto.ErrorResponse "Campaign not found"
+// @Failure 409 {object} dto.ErrorResponse "Short code already in use"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
 // @Router /api/campaigns/{id} [patch]
 func (h *CampaignHandler) UpdateCampaign(c echo.Context) error {
 	campaignIDStr := c.Param("id")
@@ -261,6 +386,20 @@ func (h *CampaignHandler) UpdateCampaign(c echo.Context) error {
 				Code:    "CAMPAIGN_NOT_FOUND",
 			})
 		}
+		if strings.HasPrefix(errMsg, "invalid ") {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: errMsg,
+				Code:    "INVALID_INPUT",
+			})
+		}
+		if strings.Contains(errMsg, "short code already in use") {
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Conflict",
+				Message: errMsg,
+				Code:    "SHORT_CODE_CONFLICT",
+			})
+		}
 
 		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Internal Server Error",
@@ -283,7 +422,10 @@ func (h *CampaignHandler) UpdateCampaign(c echo.Context) error {
 // @Success 200 {object} dto.CampaignResponse "Campaign products updated successfully"
 // @Failure 400 {object} dto.ErrorResponse "Invalid request"
 // @Failure 404 {object} dto.ErrorResponse "Campaign not found"
+// @Failure 409 {object} dto.ErrorResponse "Short code already in use"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
 // @Router /api/campaigns/{id}/products [patch]
 func (h *CampaignHandler) UpdateCampaignProducts(c echo.Context) error {
 	campaignIDStr := c.Param("id")
@@ -306,7 +448,7 @@ func (h *CampaignHandler) UpdateCampaignProducts(c echo.Context) error {
 	}
 
 	// Update campaign products
-	err = h.service.UpdateCampaignProducts(c.Request().Context(), campaignID, req.ProductIDs)
+	err = h.service.UpdateCampaignProducts(c.Request().Context(), campaignID, req.ProductIDs, req.ShortCodes)
 	if err != nil {
 		h.logger.Error("Failed to update campaign products", logger.String("error", err.Error()))
 
@@ -318,6 +460,20 @@ func (h *CampaignHandler) UpdateCampaignProducts(c echo.Context) error {
 				Code:    "CAMPAIGN_NOT_FOUND",
 			})
 		}
+		if strings.HasPrefix(errMsg, "invalid ") {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: errMsg,
+				Code:    "INVALID_INPUT",
+			})
+		}
+		if strings.Contains(errMsg, "short code already in use") {
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Conflict",
+				Message: errMsg,
+				Code:    "SHORT_CODE_CONFLICT",
+			})
+		}
 
 		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Internal Server Error",
@@ -337,12 +493,18 @@ func (h *CampaignHandler) UpdateCampaignProducts(c echo.Context) error {
 	}
 
 	response := &dto.CampaignResponse{
-		ID:          campaign.ID,
-		Name:        campaign.Name,
-		UTMCampaign: campaign.UTMCampaign,
-		StartAt:     campaign.StartAt,
-		EndAt:       campaign.EndAt,
-		CreatedAt:   campaign.CreatedAt,
+		ID:                campaign.ID,
+		Name:              campaign.Name,
+		UTMCampaign:       campaign.UTMCampaign,
+		UTMSource:         campaign.UTMSource,
+		UTMMedium:         campaign.UTMMedium,
+		UTMContent:        campaign.UTMContent,
+		UTMTerm:           campaign.UTMTerm,
+		StartAt:           campaign.StartAt,
+		EndAt:             campaign.EndAt,
+		CreatedAt:         campaign.CreatedAt,
+		OutOfWindowPolicy: string(campaign.OutOfWindowPolicy),
+		FallbackURL:       campaign.FallbackURL,
 	}
 
 	return c.JSON(http.StatusOK, response)
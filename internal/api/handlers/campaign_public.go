@@ -28,7 +28,7 @@ func NewCampaignPublicHandler(service *service.CampaignPublicService, logger log
 
 // GetPublicCampaign handles GET /api/campaigns/:id/public
 // @Summary Get public campaign details
-// @Description Get campaign details with products and offers for public landing page
+// @Description Get campaign details with products and offers for public landing page. Each view records a campaign impression and one impression per rendered product link.
 // @Tags public
 // @Accept json
 // @Produce json
@@ -49,7 +49,7 @@ func (h *CampaignPublicHandler) GetPublicCampaign(c echo.Context) error {
 	}
 
 	// Get public campaign
-	campaign, err := h.service.GetPublicCampaign(c.Request().Context(), campaignID)
+	campaign, err := h.service.GetPublicCampaign(c.Request().Context(), campaignID, requestMetadata(c))
 	if err != nil {
 		h.logger.Error("Failed to get public campaign", logger.String("error", err.Error()))
 
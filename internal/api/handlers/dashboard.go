@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -28,17 +30,20 @@ func NewDashboardHandler(service *service.DashboardService, logger logger.Logger
 
 // GetDashboardStats handles GET /api/dashboard
 // @Summary Get dashboard statistics
-// @Description Get aggregated click statistics, CTR, and top-performing products
+// @Description Get aggregated click statistics, CTR, and top-performing products. Bot and prefetch clicks are excluded unless include_bots=true.
 // @Tags dashboard
 // @Accept json
 // @Produce json
 // @Param campaign_id query string false "Filter by campaign ID" format(uuid)
-// @Param marketplace query string false "Filter by marketplace (lazada or shopee)"
+// @Param marketplace query string false "Filter by marketplace (e.g. lazada, shopee)"
 // @Param start_date query string false "Start date filter (RFC3339)" example:"2025-01-01T00:00:00Z"
 // @Param end_date query string false "End date filter (RFC3339)" example:"2025-12-31T23:59:59Z"
+// @Param include_bots query bool false "Include clicks flagged as bot traffic" default(false)
 // @Success 200 {object} dto.DashboardStatsResponse "Dashboard statistics retrieved successfully"
 // @Failure 400 {object} dto.ErrorResponse "Invalid request parameters"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
 // @Router /api/dashboard [get]
 func (h *DashboardHandler) GetDashboardStats(c echo.Context) error {
 	params := dto.DashboardQueryParams{}
@@ -58,13 +63,6 @@ func (h *DashboardHandler) GetDashboardStats(c echo.Context) error {
 
 	// Parse marketplace filter
 	if marketplace := c.QueryParam("marketplace"); marketplace != "" {
-		if marketplace != "lazada" && marketplace != "shopee" {
-			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				Error:   "Invalid Input",
-				Message: "marketplace must be 'lazada' or 'shopee'",
-				Code:    "INVALID_INPUT",
-			})
-		}
 		params.Marketplace = &marketplace
 	}
 
@@ -94,9 +92,29 @@ func (h *DashboardHandler) GetDashboardStats(c echo.Context) error {
 		params.EndDate = &endDate
 	}
 
+	// Parse include_bots flag
+	if includeBotsStr := c.QueryParam("include_bots"); includeBotsStr != "" {
+		includeBots, err := strconv.ParseBool(includeBotsStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid include_bots value (expected true or false)",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.IncludeBots = includeBots
+	}
+
 	// Get dashboard stats
 	stats, err := h.service.GetDashboardStats(c.Request().Context(), params)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid marketplace") {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: err.Error(),
+				Code:    "INVALID_INPUT",
+			})
+		}
 		h.logger.Error("Failed to get dashboard stats", logger.String("error", err.Error()))
 		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Internal Server Error",
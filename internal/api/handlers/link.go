@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 
 	"github.com/jonosize/affiliate-platform/internal/dto"
@@ -12,21 +17,23 @@ import (
 
 // LinkHandler handles link-related HTTP requests
 type LinkHandler struct {
-	service *service.LinkService
-	logger  logger.Logger
+	service      *service.LinkService
+	clickService *service.ClickService
+	logger       logger.Logger
 }
 
 // NewLinkHandler creates a new link handler
-func NewLinkHandler(service *service.LinkService, logger logger.Logger) *LinkHandler {
+func NewLinkHandler(service *service.LinkService, clickService *service.ClickService, logger logger.Logger) *LinkHandler {
 	return &LinkHandler{
-		service: service,
-		logger:  logger,
+		service:      service,
+		clickService: clickService,
+		logger:       logger,
 	}
 }
 
 // CreateLink handles POST /api/links
 // @Summary Generate affiliate short link
-// @Description Generate a short affiliate link for a product/marketplace combination
+// @Description Generate a short affiliate link for a product/marketplace combination. An optional vanity short_code (3-32 lowercase letters, digits and hyphens) replaces the generated code.
 // @Tags links
 // @Accept json
 // @Produce json
@@ -34,7 +41,10 @@ func NewLinkHandler(service *service.LinkService, logger logger.Logger) *LinkHan
 // @Success 201 {object} dto.LinkResponse "Link created successfully"
 // @Failure 400 {object} dto.ErrorResponse "Invalid request"
 // @Failure 404 {object} dto.ErrorResponse "Product or campaign not found"
+// @Failure 409 {object} dto.ErrorResponse "Short code already in use"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
 // @Router /api/links [post]
 func (h *LinkHandler) CreateLink(c echo.Context) error {
 	var req dto.CreateLinkRequest
@@ -46,11 +56,11 @@ func (h *LinkHandler) CreateLink(c echo.Context) error {
 		})
 	}
 
-	// Basic validation
-	if req.Marketplace != "lazada" && req.Marketplace != "shopee" {
+	// Basic validation (supported marketplaces are checked by the service against the adapter registry)
+	if req.Marketplace == "" {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error:   "Invalid Input",
-			Message: "marketplace must be 'lazada' or 'shopee'",
+			Message: "marketplace is required",
 			Code:    "INVALID_INPUT",
 		})
 	}
@@ -61,6 +71,20 @@ func (h *LinkHandler) CreateLink(c echo.Context) error {
 		h.logger.Error("Failed to create link", logger.String("error", err.Error()))
 
 		// Check error type
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: err.Error(),
+				Code:    "INVALID_INPUT",
+			})
+		}
+		if strings.Contains(err.Error(), "short code already in use") {
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Conflict",
+				Message: err.Error(),
+				Code:    "SHORT_CODE_CONFLICT",
+			})
+		}
 		if err.Error() == "product not found: record not found" {
 			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
 				Error:   "Product Not Found",
@@ -85,3 +109,379 @@ func (h *LinkHandler) CreateLink(c echo.Context) error {
 
 	return c.JSON(http.StatusCreated, link)
 }
+
+// UpdateLink handles PATCH /api/links/:id
+// @Summary Update a link's short code or lifecycle
+// @Description Change a link's short code to a vanity code (the previous code becomes an alias and keeps redirecting), pause, archive or reactivate it, or set or clear its expiry. Paused, archived and expired links redirect to the campaign's fallback URL when its policy is "fallback", otherwise they show an "offer ended" page (410).
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Link ID" format(uuid)
+// @Param request body dto.UpdateLinkRequest true "Link update request"
+// @Success 200 {object} dto.LinkResponse "Link updated successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 404 {object} dto.ErrorResponse "Link not found"
+// @Failure 409 {object} dto.ErrorResponse "Short code already in use"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/links/{id} [patch]
+func (h *LinkHandler) UpdateLink(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid link ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	var req dto.UpdateLinkRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid request body",
+			Code:    "INVALID_INPUT",
+		})
+	}
+	if req.ShortCode == "" && req.Status == nil && req.ExpiresAt == nil && !req.ClearExpiresAt {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "short_code, status, expires_at or clear_expires_at is required",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	link, err := h.service.UpdateLink(c.Request().Context(), linkID, req)
+	if err != nil {
+		h.logger.Error("Failed to update link", logger.String("error", err.Error()))
+		return h.shortCodeErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, link)
+}
+
+// AddLinkAlias handles POST /api/links/:id/aliases
+// @Summary Add a short code alias to a link
+// @Description Add another short code (3-32 lowercase letters, digits and hyphens) that redirects to the link
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Link ID" format(uuid)
+// @Param request body dto.CreateLinkAliasRequest true "Alias creation request"
+// @Success 201 {object} dto.LinkResponse "Alias added successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 404 {object} dto.ErrorResponse "Link not found"
+// @Failure 409 {object} dto.ErrorResponse "Short code already in use"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/links/{id}/aliases [post]
+func (h *LinkHandler) AddLinkAlias(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid link ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	var req dto.CreateLinkAliasRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid request body",
+			Code:    "INVALID_INPUT",
+		})
+	}
+	if req.ShortCode == "" {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "short_code is required",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	link, err := h.service.AddLinkAlias(c.Request().Context(), linkID, req)
+	if err != nil {
+		h.logger.Error("Failed to add link alias", logger.String("error", err.Error()))
+		return h.shortCodeErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, link)
+}
+
+// DeleteLinkAlias handles DELETE /api/links/:id/aliases/:short_code
+// @Summary Remove a short code alias from a link
+// @Description Remove one of a link's aliases; the code stops redirecting
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Link ID" format(uuid)
+// @Param short_code path string true "Alias short code"
+// @Success 204 "Alias removed successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid link ID"
+// @Failure 404 {object} dto.ErrorResponse "Link or alias not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/links/{id}/aliases/{short_code} [delete]
+func (h *LinkHandler) DeleteLinkAlias(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid link ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	if err := h.service.DeleteLinkAlias(c.Request().Context(), linkID, c.Param("short_code")); err != nil {
+		h.logger.Error("Failed to delete link alias", logger.String("error", err.Error()))
+		return h.shortCodeErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// shortCodeErrorResponse maps link short code and alias errors to HTTP responses
+func (h *LinkHandler) shortCodeErrorResponse(c echo.Context, err error) error {
+	errMsg := err.Error()
+	switch {
+	case strings.Contains(errMsg, "link not found"):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Link Not Found",
+			Message: "Link with the specified ID was not found",
+			Code:    "LINK_NOT_FOUND",
+		})
+	case strings.Contains(errMsg, "link alias not found"):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Alias Not Found",
+			Message: errMsg,
+			Code:    "ALIAS_NOT_FOUND",
+		})
+	case strings.Contains(errMsg, "short code already in use"):
+		return c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "Conflict",
+			Message: errMsg,
+			Code:    "SHORT_CODE_CONFLICT",
+		})
+	case strings.HasPrefix(errMsg, "invalid "):
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: errMsg,
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "Internal Server Error",
+		Message: errMsg,
+		Code:    "INTERNAL_ERROR",
+	})
+}
+
+// GetLinkStats handles GET /api/links/:id/stats
+// @Summary Get click statistics for a link
+// @Description Get total and unique clicks for a link with a time series bucketed by hour or day, plus referrer, browser and device breakdowns. Defaults to the last 30 days bucketed by day; hourly series are limited to 31 days. Bot clicks are excluded unless include_bots=true.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Link ID" format(uuid)
+// @Param from query string false "Start of time range (RFC3339)" format(date-time)
+// @Param to query string false "End of time range (RFC3339)" format(date-time)
+// @Param interval query string false "Time series bucket size" Enums(hour, day) default(day)
+// @Param include_bots query bool false "Include clicks flagged as bot traffic" default(false)
+// @Success 200 {object} dto.LinkStatsResponse "Link statistics retrieved successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 404 {object} dto.ErrorResponse "Link not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/links/{id}/stats [get]
+func (h *LinkHandler) GetLinkStats(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid link ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	params := dto.LinkStatsQueryParams{
+		Interval: c.QueryParam("interval"),
+	}
+
+	// Parse from filter
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid from format (expected RFC3339)",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.From = &from
+	}
+
+	// Parse to filter
+	if toStr := c.QueryParam("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid to format (expected RFC3339)",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.To = &to
+	}
+
+	// Parse include_bots flag
+	if includeBotsStr := c.QueryParam("include_bots"); includeBotsStr != "" {
+		includeBots, err := strconv.ParseBool(includeBotsStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid include_bots value (expected true or false)",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.IncludeBots = includeBots
+	}
+
+	// Get link stats
+	stats, err := h.clickService.GetLinkStats(c.Request().Context(), linkID, params)
+	if err != nil {
+		h.logger.Error("Failed to get link stats", logger.String("error", err.Error()))
+
+		if strings.Contains(err.Error(), "link not found") {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Link Not Found",
+				Message: "Link with the specified ID was not found",
+				Code:    "LINK_NOT_FOUND",
+			})
+		}
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: err.Error(),
+				Code:    "INVALID_INPUT",
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to get link statistics",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+// GetLinkQRCode handles GET /api/links/:id/qr
+// @Summary Get a link's QR code
+// @Description Render the link's short URL as a QR code (PNG or SVG). The encoded URL carries ?src=qr, so scans show up as the "qr" source in link and dashboard stats. logo=true overlays the configured logo (api.qr.logo_path) and needs q or h error correction (h by default).
+// @Tags links
+// @Produce png
+// @Produce image/svg+xml
+// @Param id path string true "Link ID" format(uuid)
+// @Param format query string false "Image format" Enums(png, svg) default(png)
+// @Param size query int false "Image width and height in pixels (64-2048)" default(512)
+// @Param margin query int false "Quiet zone in modules (0-16)" default(4)
+// @Param ecc query string false "Error correction level (h with a logo)" Enums(l, m, q, h) default(m)
+// @Param logo query bool false "Overlay the configured logo" default(false)
+// @Success 200 {file} file "QR code image"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 404 {object} dto.ErrorResponse "Link not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/links/{id}/qr [get]
+func (h *LinkHandler) GetLinkQRCode(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid link ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	params := dto.LinkQRCodeQueryParams{
+		Format: c.QueryParam("format"),
+		ECC:    c.QueryParam("ecc"),
+	}
+
+	// Parse size
+	if sizeStr := c.QueryParam("size"); sizeStr != "" {
+		size, err := strconv.Atoi(sizeStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid size (expected a number of pixels)",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.Size = size
+	}
+
+	// Parse margin
+	if marginStr := c.QueryParam("margin"); marginStr != "" {
+		margin, err := strconv.Atoi(marginStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid margin (expected a number of modules)",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.Margin = &margin
+	}
+
+	// Parse logo flag
+	if logoStr := c.QueryParam("logo"); logoStr != "" {
+		logo, err := strconv.ParseBool(logoStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid logo value (expected true or false)",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.Logo = logo
+	}
+
+	qr, err := h.service.GetLinkQRCode(c.Request().Context(), linkID, params)
+	if err != nil {
+		h.logger.Error("Failed to get link QR code", logger.String("error", err.Error()))
+
+		if strings.Contains(err.Error(), "link not found") {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Link Not Found",
+				Message: "Link with the specified ID was not found",
+				Code:    "LINK_NOT_FOUND",
+			})
+		}
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: err.Error(),
+				Code:    "INVALID_INPUT",
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to render QR code",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", qr.Filename))
+	return c.Blob(http.StatusOK, qr.ContentType, qr.Data)
+}
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/service"
+)
+
+// LinkVariantHandler handles A/B split link HTTP requests
+type LinkVariantHandler struct {
+	service *service.LinkVariantService
+	logger  logger.Logger
+}
+
+// NewLinkVariantHandler creates a new link variant handler
+func NewLinkVariantHandler(service *service.LinkVariantService, logger logger.Logger) *LinkVariantHandler {
+	return &LinkVariantHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetVariants handles GET /api/links/:id/variants
+// @Summary Get a link's A/B variants
+// @Description Get a link's A/B variants in creation order (the first is the control)
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Link ID" format(uuid)
+// @Success 200 {array} dto.LinkVariantResponse "Variants retrieved successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid link ID"
+// @Failure 404 {object} dto.ErrorResponse "Link not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/links/{id}/variants [get]
+func (h *LinkVariantHandler) GetVariants(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid link ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	variants, err := h.service.GetVariants(c.Request().Context(), linkID)
+	if err != nil {
+		h.logger.Error("Failed to get link variants", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to get link variants")
+	}
+
+	return c.JSON(http.StatusOK, variants)
+}
+
+// CreateVariant handles POST /api/links/:id/variants
+// @Summary Add an A/B variant to a link
+// @Description Split the link's traffic: once a link has variants with weight, each visitor is sent to one of them in proportion to the weights (e.g. 70/30) and keeps it on later clicks. A variant sends traffic to the product's offer on a marketplace (target_url is resolved from the offer) or to a landing URL. Redirect rules take precedence over variants.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Link ID" format(uuid)
+// @Param request body dto.CreateLinkVariantRequest true "Variant creation request"
+// @Success 201 {object} dto.LinkVariantResponse "Variant created successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 404 {object} dto.ErrorResponse "Link not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/links/{id}/variants [post]
+func (h *LinkVariantHandler) CreateVariant(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid link ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	var req dto.CreateLinkVariantRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid request body",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	variant, err := h.service.CreateVariant(c.Request().Context(), linkID, req)
+	if err != nil {
+		h.logger.Error("Failed to create link variant", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to create link variant")
+	}
+
+	return c.JSON(http.StatusCreated, variant)
+}
+
+// UpdateVariant handles PATCH /api/links/:id/variants/:variant_id
+// @Summary Update an A/B variant
+// @Description Update a link's A/B variant. Changing weights reassigns some returning visitors to other variants; a weight of 0 stops new traffic but keeps the variant's stats. Marketplace variants are re-resolved from the current offer.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Link ID" format(uuid)
+// @Param variant_id path string true "Variant ID" format(uuid)
+// @Param request body dto.UpdateLinkVariantRequest true "Variant update request"
+// @Success 200 {object} dto.LinkVariantResponse "Variant updated successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 404 {object} dto.ErrorResponse "Link or variant not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/links/{id}/variants/{variant_id} [patch]
+func (h *LinkVariantHandler) UpdateVariant(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid link ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+	variantID, err := uuid.Parse(c.Param("variant_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid variant ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	var req dto.UpdateLinkVariantRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid request body",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	variant, err := h.service.UpdateVariant(c.Request().Context(), linkID, variantID, req)
+	if err != nil {
+		h.logger.Error("Failed to update link variant", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to update link variant")
+	}
+
+	return c.JSON(http.StatusOK, variant)
+}
+
+// DeleteVariant handles DELETE /api/links/:id/variants/:variant_id
+// @Summary Delete an A/B variant
+// @Description Delete a link's A/B variant. Its clicks are kept but no longer attributed to a variant; set the weight to 0 instead to keep its stats.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Link ID" format(uuid)
+// @Param variant_id path string true "Variant ID" format(uuid)
+// @Success 204 "Variant deleted successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid ID"
+// @Failure 404 {object} dto.ErrorResponse "Link or variant not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/links/{id}/variants/{variant_id} [delete]
+func (h *LinkVariantHandler) DeleteVariant(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid link ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+	variantID, err := uuid.Parse(c.Param("variant_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid variant ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	if err := h.service.DeleteVariant(c.Request().Context(), linkID, variantID); err != nil {
+		h.logger.Error("Failed to delete link variant", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to delete link variant")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetVariantStats handles GET /api/links/:id/variants/stats
+// @Summary Compare a link's A/B variants
+// @Description Clicks, unique clicks, conversions, revenue and EPC of the clicks sent to each variant, with 95% Wilson intervals for conversion rates and Newcombe intervals for each variant's conversion rate difference from the control (the first variant). Conversions are attributed to the variant of the converting click and counted for clicks in the time range. Defaults to the last 30 days; bot clicks are excluded unless include_bots=true.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Link ID" format(uuid)
+// @Param from query string false "Start of time range (RFC3339)" format(date-time)
+// @Param to query string false "End of time range (RFC3339)" format(date-time)
+// @Param include_bots query bool false "Include clicks flagged as bot traffic" default(false)
+// @Success 200 {object} dto.LinkVariantStatsResponse "Variant statistics retrieved successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 404 {object} dto.ErrorResponse "Link not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/links/{id}/variants/stats [get]
+func (h *LinkVariantHandler) GetVariantStats(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid link ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	var params dto.LinkVariantStatsQueryParams
+
+	// Parse from filter
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid from format (expected RFC3339)",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.From = &from
+	}
+
+	// Parse to filter
+	if toStr := c.QueryParam("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid to format (expected RFC3339)",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.To = &to
+	}
+
+	// Parse include_bots flag
+	if includeBotsStr := c.QueryParam("include_bots"); includeBotsStr != "" {
+		includeBots, err := strconv.ParseBool(includeBotsStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid include_bots value (expected true or false)",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.IncludeBots = includeBots
+	}
+
+	stats, err := h.service.GetVariantStats(c.Request().Context(), linkID, params)
+	if err != nil {
+		h.logger.Error("Failed to get link variant stats", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to get link variant stats")
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+// errorResponse maps link variant service errors to HTTP responses
+func (h *LinkVariantHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	errMsg := err.Error()
+	switch {
+	case strings.Contains(errMsg, "variant not found"):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Variant Not Found",
+			Message: "Variant with the specified ID was not found",
+			Code:    "VARIANT_NOT_FOUND",
+		})
+	case strings.Contains(errMsg, "link not found"):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Link Not Found",
+			Message: "Link with the specified ID was not found",
+			Code:    "LINK_NOT_FOUND",
+		})
+	case strings.Contains(errMsg, "campaign not found"):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Campaign Not Found",
+			Message: "The link's campaign was not found",
+			Code:    "CAMPAIGN_NOT_FOUND",
+		})
+	case strings.HasPrefix(errMsg, "invalid "):
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: errMsg,
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "Internal Server Error",
+		Message: fallback,
+		Code:    "INTERNAL_ERROR",
+	})
+}
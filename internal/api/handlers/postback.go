@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/service"
+)
+
+// PostbackHandler handles affiliate network conversion postbacks
+type PostbackHandler struct {
+	service *service.ConversionService
+	logger  logger.Logger
+}
+
+// NewPostbackHandler creates a new postback handler
+func NewPostbackHandler(service *service.ConversionService, logger logger.Logger) *PostbackHandler {
+	return &PostbackHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// HandlePostback handles GET and POST /api/postbacks/:network
+// @Summary Receive a conversion postback
+// @Description Server-to-server conversion postback from an affiliate network. Parameters are read from the query string or a form body: order_id (required), amount, commission, currency, status (pending, approved or rejected; default pending) and click_id or sub_id. Requests are signed with the network's shared secret: hex HMAC-SHA256 of the sorted, URL-encoded parameters without "signature", sent in the X-Postback-Signature header or the signature parameter. Repeated postbacks update a pending conversion; approved and rejected conversions are final.
+// @Tags postbacks
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param network path string true "Affiliate network" example(lazada)
+// @Param order_id query string true "Network order ID"
+// @Param amount query number false "Order value"
+// @Param commission query number false "Commission earned"
+// @Param currency query string false "ISO 4217 currency code"
+// @Param status query string false "Conversion status" Enums(pending, approved, rejected)
+// @Param click_id query string false "Click ID passed to the network"
+// @Param sub_id query string false "Sub ID passed to the network (used when click_id is absent)"
+// @Param signature query string false "Signature (alternative to the X-Postback-Signature header)"
+// @Param X-Postback-Signature header string false "Signature: hex HMAC-SHA256, optionally prefixed with sha256="
+// @Success 200 {object} dto.ConversionResponse "Conversion recorded"
+// @Failure 400 {object} dto.ErrorResponse "Invalid postback"
+// @Failure 401 {object} dto.ErrorResponse "Invalid signature"
+// @Failure 404 {object} dto.ErrorResponse "Unknown network"
+// @Failure 409 {object} dto.ErrorResponse "Status transition not allowed"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /api/postbacks/{network} [get]
+// @Router /api/postbacks/{network} [post]
+func (h *PostbackHandler) HandlePostback(c echo.Context) error {
+	params, err := c.FormParams()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid postback parameters",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	network := c.Param("network")
+	conversion, err := h.service.HandlePostback(c.Request().Context(), network, params, c.Request().Header.Get(service.PostbackHeaderSignature))
+	if err != nil {
+		h.logger.Warn("Failed to handle postback", logger.String("network", network), logger.Error(err))
+
+		errMsg := err.Error()
+		switch {
+		case strings.Contains(errMsg, "postback network not found"):
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Network Not Found",
+				Message: "Postbacks are not configured for this network",
+				Code:    "NETWORK_NOT_FOUND",
+			})
+		case strings.Contains(errMsg, "signature mismatch"):
+			return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Error:   "Unauthorized",
+				Message: "Invalid postback signature",
+				Code:    "INVALID_SIGNATURE",
+			})
+		case strings.Contains(errMsg, "status transition not allowed"):
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Conflict",
+				Message: errMsg,
+				Code:    "STATUS_TRANSITION_NOT_ALLOWED",
+			})
+		case strings.HasPrefix(errMsg, "invalid "):
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: errMsg,
+				Code:    "INVALID_INPUT",
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to record conversion",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(http.StatusOK, conversion)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -37,6 +38,8 @@ func NewProductHandler(service *service.ProductService, logger logger.Logger) *P
 // @Success 201 {object} dto.ProductResponse "Product created successfully"
 // @Failure 400 {object} dto.ErrorResponse "Invalid request"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
 // @Router /api/products [post]
 func (h *ProductHandler) CreateProduct(c echo.Context) error {
 	var req dto.CreateProductRequest
@@ -64,6 +67,13 @@ func (h *ProductHandler) CreateProduct(c echo.Context) error {
 	// Create product
 	product, err := h.service.CreateProduct(c.Request().Context(), req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid ") || strings.HasPrefix(err.Error(), "unsupported marketplace") || strings.HasPrefix(err.Error(), "at least one") {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: err.Error(),
+				Code:    "INVALID_INPUT",
+			})
+		}
 		h.logger.Error("Failed to create product", logger.String("error", err.Error()))
 		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Internal Server Error",
@@ -85,6 +95,8 @@ func (h *ProductHandler) CreateProduct(c echo.Context) error {
 // @Success 200 {object} dto.ProductOffersResponse "Product offers retrieved successfully"
 // @Failure 404 {object} dto.ErrorResponse "Product not found"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
 // @Router /api/products/{id}/offers [get]
 func (h *ProductHandler) GetProductOffers(c echo.Context) error {
 	productIDStr := c.Param("id")
@@ -121,6 +133,97 @@ func (h *ProductHandler) GetProductOffers(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+// GetPriceHistory handles GET /api/products/:id/price-history
+// @Summary Get price history for a product
+// @Description Get the recorded price series for a product with min/max/avg, optionally filtered by marketplace and time range
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Param marketplace query string false "Filter by marketplace (e.g. lazada, shopee)"
+// @Param from query string false "Start of time range (RFC3339)" format(date-time)
+// @Param to query string false "End of time range (RFC3339)" format(date-time)
+// @Success 200 {object} dto.PriceHistoryResponse "Price history retrieved successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 404 {object} dto.ErrorResponse "Product not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/products/{id}/price-history [get]
+func (h *ProductHandler) GetPriceHistory(c echo.Context) error {
+	productIDStr := c.Param("id")
+	productID, err := uuid.Parse(productIDStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid product ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	var params dto.PriceHistoryQueryParams
+
+	// Parse marketplace filter
+	if marketplace := c.QueryParam("marketplace"); marketplace != "" {
+		params.Marketplace = &marketplace
+	}
+
+	// Parse from filter
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid from format (expected RFC3339)",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.From = &from
+	}
+
+	// Parse to filter
+	if toStr := c.QueryParam("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: "Invalid to format (expected RFC3339)",
+				Code:    "INVALID_INPUT",
+			})
+		}
+		params.To = &to
+	}
+
+	// Get price history
+	response, err := h.service.GetPriceHistory(c.Request().Context(), productID, params)
+	if err != nil {
+		h.logger.Error("Failed to get price history", logger.String("error", err.Error()))
+
+		if strings.Contains(err.Error(), "product not found") {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Product Not Found",
+				Message: "Product with the specified ID was not found",
+				Code:    "PRODUCT_NOT_FOUND",
+			})
+		}
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: err.Error(),
+				Code:    "INVALID_INPUT",
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to get price history",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
 // GetAllProducts handles GET /api/products
 // @Summary Get all products
 // @Description Get a list of all products with pagination
@@ -131,6 +234,8 @@ func (h *ProductHandler) GetProductOffers(c echo.Context) error {
 // @Param offset query int false "Offset for pagination" default(0)
 // @Success 200 {array} dto.ProductResponse "Products retrieved successfully"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
 // @Router /api/products [get]
 func (h *ProductHandler) GetAllProducts(c echo.Context) error {
 	// Parse query parameters
@@ -165,7 +270,7 @@ func (h *ProductHandler) GetAllProducts(c echo.Context) error {
 
 // DeleteProduct handles DELETE /api/products/:id
 // @Summary Delete a product
-// @Description Delete a product and all related data (offers, links, campaign associations, clicks)
+// @Description Soft-delete a product. It disappears from listings, campaigns and public pages and its links stop redirecting, but its clicks keep counting in the dashboard. Restore it with POST /api/products/{id}/restore or remove it for good with DELETE /api/products/{id}/purge.
 // @Tags products
 // @Accept json
 // @Produce json
@@ -174,6 +279,8 @@ func (h *ProductHandler) GetAllProducts(c echo.Context) error {
 // @Failure 400 {object} dto.ErrorResponse "Invalid product ID"
 // @Failure 404 {object} dto.ErrorResponse "Product not found"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
 // @Router /api/products/{id} [delete]
 func (h *ProductHandler) DeleteProduct(c echo.Context) error {
 	productIDStr := c.Param("id")
@@ -209,3 +316,102 @@ func (h *ProductHandler) DeleteProduct(c echo.Context) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// RestoreProduct handles POST /api/products/:id/restore
+// @Summary Restore a deleted product
+// @Description Undo a product's soft deletion, bringing back its links
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Success 200 {object} dto.ProductResponse "Product restored successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid product ID"
+// @Failure 404 {object} dto.ErrorResponse "Deleted product not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/products/{id}/restore [post]
+func (h *ProductHandler) RestoreProduct(c echo.Context) error {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid product ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	product, err := h.service.RestoreProduct(c.Request().Context(), productID)
+	if err != nil {
+		if strings.Contains(err.Error(), "product not found") {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Product Not Found",
+				Message: "No deleted product with the specified ID was found",
+				Code:    "PRODUCT_NOT_FOUND",
+			})
+		}
+
+		h.logger.Error("Failed to restore product", logger.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to restore product",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(http.StatusOK, product)
+}
+
+// PurgeProduct handles DELETE /api/products/:id/purge
+// @Summary Permanently delete a product
+// @Description Permanently delete a soft-deleted product with all related data (offers, links, campaign associations, clicks). Its clicks drop out of dashboard history. The confirm parameter must repeat the product ID.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Param confirm query string true "The product ID again, to confirm" format(uuid)
+// @Success 204 "Product purged successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid product ID or confirmation"
+// @Failure 404 {object} dto.ErrorResponse "Deleted product not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/products/{id}/purge [delete]
+func (h *ProductHandler) PurgeProduct(c echo.Context) error {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid product ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	err = h.service.PurgeProduct(c.Request().Context(), productID, c.QueryParam("confirm"))
+	if err != nil {
+		errMsg := err.Error()
+		if strings.HasPrefix(errMsg, "invalid ") {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid Input",
+				Message: errMsg,
+				Code:    "INVALID_INPUT",
+			})
+		}
+		if strings.Contains(errMsg, "product not found") {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Product Not Found",
+				Message: "No deleted product with the specified ID was found",
+				Code:    "PRODUCT_NOT_FOUND",
+			})
+		}
+
+		h.logger.Error("Failed to purge product", logger.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to purge product",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -1,37 +1,86 @@
 package handlers
 
 import (
+	"errors"
+	"html/template"
 	"net"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/jonosize/affiliate-platform/internal/dto"
 	"github.com/jonosize/affiliate-platform/internal/logger"
 	"github.com/jonosize/affiliate-platform/internal/service"
 )
 
+// visitorCookieMaxAge is how long the first-party visitor cookie is kept
+const visitorCookieMaxAge = 365 * 24 * time.Hour
+
+// offerEndedPage is served with 410 Gone for paused, archived and expired links
+// and for campaigns outside their window with the "gone" policy
+const offerEndedPage = `<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><meta name="robots" content="noindex"><title>Offer ended</title></head>
+<body style="font-family: sans-serif; text-align: center; padding: 4rem 1rem;"><h1>This offer has ended</h1><p>The deal you are looking for is no longer available.</p></body>
+</html>`
+
+// deepLinkPage tries to open an app deep link and falls back to the web URL
+// if the app did not take over (the page is still visible) shortly after
+var deepLinkPage = template.Must(template.New("deep_link").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><meta name="robots" content="noindex"><title>Opening the app…</title></head>
+<body style="font-family: sans-serif; text-align: center; padding: 4rem 1rem;">
+<p>Opening the app…</p>
+<p><a href="{{.URL}}">Continue in the browser</a></p>
+<script>
+(function () {
+  var fallback = setTimeout(function () { window.location.replace({{.URL}}); }, 1500);
+  document.addEventListener("visibilitychange", function () {
+    if (document.hidden) { clearTimeout(fallback); }
+  });
+  window.location.href = {{.AppURL}};
+})();
+</script>
+</body>
+</html>`))
+
 // RedirectHandler handles redirect-related HTTP requests
 type RedirectHandler struct {
-	service *service.RedirectService
-	logger  logger.Logger
+	service       *service.RedirectService
+	visitors      *service.VisitorIdentifier
+	visitorCookie string
+	logger        logger.Logger
 }
 
 // NewRedirectHandler creates a new redirect handler
-func NewRedirectHandler(service *service.RedirectService, logger logger.Logger) *RedirectHandler {
+// visitorCookie is the name of the first-party cookie carrying the visitor ID
+func NewRedirectHandler(service *service.RedirectService, visitors *service.VisitorIdentifier, visitorCookie string, logger logger.Logger) *RedirectHandler {
 	return &RedirectHandler{
-		service: service,
-		logger:  logger,
+		service:       service,
+		visitors:      visitors,
+		visitorCookie: visitorCookie,
+		logger:        logger,
 	}
 }
 
-// Redirect handles GET /go/:short_code
+// Redirect handles GET and HEAD /go/:short_code
 // @Summary Redirect to marketplace product URL
-// @Description Redirects to the target marketplace URL and tracks the click event
+// @Description Redirects to the target marketplace URL and tracks the click event. HEAD requests, prefetches (Purpose / Sec-Purpose headers), known bot user agents and excluded IP ranges are recorded as bot clicks and left out of analytics by default. Repeat clicks by the same visitor (first-party cookie, or salted IP + user agent hash) within the dedupe window are stored but not counted as unique. Each redirect carries a unique click ID as the marketplace's affiliate sub-ID (Lazada sub_aff_id, Shopee sub_id), and publisher sub IDs sub1..sub5 are stored with the click and forwarded (Lazada sub_id1..sub_id5, Shopee sub_id slots 2-5). Paused, archived and expired links, and campaigns outside their start/end window, follow the campaign's out-of-window policy: redirect anyway, redirect to the campaign's fallback URL, or show an "offer ended" page (410); the outcome is recorded with the click. The link's redirect rules can send visitors elsewhere by device OS, country and Accept-Language: a web URL, the product on another marketplace, or an app deep link served as a page that opens the app and falls back to the web URL.
 // @Tags public
 // @Accept json
 // @Produce json
 // @Param short_code path string true "Short code" example:"abc123xyz"
-// @Success 302 "Redirect to target URL"
+// @Param sub1 query string false "Publisher sub ID 1 (letters, digits and underscores; up to 50 characters)"
+// @Param sub2 query string false "Publisher sub ID 2"
+// @Param sub3 query string false "Publisher sub ID 3"
+// @Param sub4 query string false "Publisher sub ID 4"
+// @Param sub5 query string false "Publisher sub ID 5 (not forwarded to Shopee)"
+// @Param src query string false "Click source marker stored with the click (e.g. qr, added to QR code links)"
+// @Success 302 "Redirect to target URL (or the campaign's fallback URL)"
+// @Success 200 {string} string "App deep link page (deep link redirect rules)"
+// @Failure 410 {string} string "Offer ended page"
 // @Failure 404 {object} dto.ErrorResponse "Link not found"
 // @Failure 400 {object} dto.ErrorResponse "Invalid redirect URL"
 // @Router /go/{short_code} [get]
@@ -44,33 +93,89 @@ func (h *RedirectHandler) Redirect(c echo.Context) error {
 	}
 
 	// Extract request metadata
-	ipAddress := net.ParseIP(c.RealIP())
-	if ipAddress == nil {
-		// Fallback to X-Forwarded-For header
-		ipStr := c.Request().Header.Get("X-Forwarded-For")
-		if ipStr != "" {
-			ipAddress = net.ParseIP(ipStr)
+	meta := requestMetadata(c)
+	if h.visitorCookie != "" {
+		if cookie, err := c.Cookie(h.visitorCookie); err == nil {
+			meta.VisitorCookie = cookie.Value
 		}
 	}
-	userAgent := c.Request().UserAgent()
-	referrer := c.Request().Referer()
+	meta.VisitorID = h.visitors.Identify(meta)
+	meta.SubIDs = service.ParseSubIDs(c.QueryParams())
+	meta.Source = service.ParseClickSource(c.QueryParam("src"))
 
 	// Perform redirect
-	targetURL, err := h.service.Redirect(c.Request().Context(), shortCode, ipAddress, userAgent, referrer)
+	target, err := h.service.Redirect(c.Request().Context(), shortCode, meta)
 	if err != nil {
-		h.logger.Error("Redirect failed", logger.String("error", err.Error()), logger.String("short_code", shortCode))
+		if strings.HasPrefix(err.Error(), "offer ended") {
+			return c.HTML(http.StatusGone, offerEndedPage)
+		}
 
-		if err.Error() == "link not found: record not found" {
+		if errors.Is(err, service.ErrLinkNotFound) {
 			return c.JSON(http.StatusNotFound, map[string]string{
 				"error": "Link not found",
 			})
 		}
 
+		h.logger.Error("Redirect failed", logger.String("error", err.Error()), logger.String("short_code", shortCode))
+
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
 		})
 	}
 
+	// Keep the visitor ID stable across IP and user agent changes
+	if h.visitorCookie != "" {
+		c.SetCookie(&http.Cookie{
+			Name:     h.visitorCookie,
+			Value:    meta.VisitorID,
+			Path:     "/go/",
+			MaxAge:   int(visitorCookieMaxAge.Seconds()),
+			HttpOnly: true,
+			Secure:   c.Scheme() == "https",
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+
+	if target.AppURL != "" {
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+		c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+		c.Response().WriteHeader(http.StatusOK)
+		return deepLinkPage.Execute(c.Response(), target)
+	}
+
 	// Redirect to target URL
-	return c.Redirect(http.StatusFound, targetURL)
+	return c.Redirect(http.StatusFound, target.URL)
+}
+
+// requestMetadata extracts the visitor attributes recorded with clicks and impressions
+func requestMetadata(c echo.Context) dto.ClickMetadata {
+	ipAddress := net.ParseIP(c.RealIP())
+	if ipAddress == nil {
+		// Fallback to X-Forwarded-For header
+		ipStr := c.Request().Header.Get("X-Forwarded-For")
+		if ipStr != "" {
+			ipAddress = net.ParseIP(ipStr)
+		}
+	}
+
+	return dto.ClickMetadata{
+		IPAddress: ipAddress,
+		UserAgent: c.Request().UserAgent(),
+		Referrer:  c.Request().Referer(),
+		Method:    c.Request().Method,
+		Purpose:   purposeHeader(c.Request().Header),
+		Host:      c.Request().Host,
+
+		AcceptLanguage: c.Request().Header.Get("Accept-Language"),
+	}
+}
+
+// purposeHeader returns the speculative-request hint sent by browsers and link previewers
+func purposeHeader(h http.Header) string {
+	for _, name := range []string{"Sec-Purpose", "Purpose", "X-Purpose", "X-Moz"} {
+		if v := h.Get(name); v != "" {
+			return v
+		}
+	}
+	return ""
 }
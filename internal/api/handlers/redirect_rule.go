@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/service"
+)
+
+// RedirectRuleHandler handles link redirect rule HTTP requests
+type RedirectRuleHandler struct {
+	service *service.RedirectRuleService
+	logger  logger.Logger
+}
+
+// NewRedirectRuleHandler creates a new redirect rule handler
+func NewRedirectRuleHandler(service *service.RedirectRuleService, logger logger.Logger) *RedirectRuleHandler {
+	return &RedirectRuleHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetRedirectRules handles GET /api/links/:id/redirect-rules
+// @Summary Get a link's redirect rules
+// @Description Get a link's redirect rules in evaluation order (priority, lowest first)
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Link ID" format(uuid)
+// @Success 200 {array} dto.RedirectRuleResponse "Redirect rules retrieved successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid link ID"
+// @Failure 404 {object} dto.ErrorResponse "Link not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/links/{id}/redirect-rules [get]
+func (h *RedirectRuleHandler) GetRedirectRules(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid link ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	rules, err := h.service.GetRedirectRules(c.Request().Context(), linkID)
+	if err != nil {
+		h.logger.Error("Failed to get redirect rules", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to get redirect rules")
+	}
+
+	return c.JSON(http.StatusOK, rules)
+}
+
+// CreateRedirectRule handles POST /api/links/:id/redirect-rules
+// @Summary Add a redirect rule to a link
+// @Description Route the link's visitors by device OS (ios, android, windows, macos, linux), country (ISO 3166-1 alpha-2, resolved by the optional GeoIP database) and language (primary subtag of the preferred Accept-Language). Empty lists match anyone; the first matching active rule by priority wins. Targets: a web URL, the product's offer on another marketplace (target_url is resolved from the offer) or an app deep link with an optional web fallback (the link's default target otherwise).
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Link ID" format(uuid)
+// @Param request body dto.CreateRedirectRuleRequest true "Redirect rule creation request"
+// @Success 201 {object} dto.RedirectRuleResponse "Redirect rule created successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 404 {object} dto.ErrorResponse "Link not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/links/{id}/redirect-rules [post]
+func (h *RedirectRuleHandler) CreateRedirectRule(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid link ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	var req dto.CreateRedirectRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid request body",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	rule, err := h.service.CreateRedirectRule(c.Request().Context(), linkID, req)
+	if err != nil {
+		h.logger.Error("Failed to create redirect rule", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to create redirect rule")
+	}
+
+	return c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRedirectRule handles PATCH /api/links/:id/redirect-rules/:rule_id
+// @Summary Update a redirect rule
+// @Description Update a link's redirect rule. Omitted fields are kept; an empty match list matches anyone. Marketplace targets are re-resolved from the current offer.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Link ID" format(uuid)
+// @Param rule_id path string true "Redirect rule ID" format(uuid)
+// @Param request body dto.UpdateRedirectRuleRequest true "Redirect rule update request"
+// @Success 200 {object} dto.RedirectRuleResponse "Redirect rule updated successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 404 {object} dto.ErrorResponse "Link or redirect rule not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/links/{id}/redirect-rules/{rule_id} [patch]
+func (h *RedirectRuleHandler) UpdateRedirectRule(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid link ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+	ruleID, err := uuid.Parse(c.Param("rule_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid redirect rule ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	var req dto.UpdateRedirectRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid request body",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	rule, err := h.service.UpdateRedirectRule(c.Request().Context(), linkID, ruleID, req)
+	if err != nil {
+		h.logger.Error("Failed to update redirect rule", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to update redirect rule")
+	}
+
+	return c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRedirectRule handles DELETE /api/links/:id/redirect-rules/:rule_id
+// @Summary Delete a redirect rule
+// @Description Delete a link's redirect rule
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Link ID" format(uuid)
+// @Param rule_id path string true "Redirect rule ID" format(uuid)
+// @Success 204 "Redirect rule deleted successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid ID"
+// @Failure 404 {object} dto.ErrorResponse "Link or redirect rule not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/links/{id}/redirect-rules/{rule_id} [delete]
+func (h *RedirectRuleHandler) DeleteRedirectRule(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid link ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+	ruleID, err := uuid.Parse(c.Param("rule_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid redirect rule ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	if err := h.service.DeleteRedirectRule(c.Request().Context(), linkID, ruleID); err != nil {
+		h.logger.Error("Failed to delete redirect rule", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to delete redirect rule")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// errorResponse maps redirect rule service errors to HTTP responses
+func (h *RedirectRuleHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	errMsg := err.Error()
+	switch {
+	case strings.Contains(errMsg, "redirect rule not found"):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Redirect Rule Not Found",
+			Message: "Redirect rule with the specified ID was not found",
+			Code:    "REDIRECT_RULE_NOT_FOUND",
+		})
+	case strings.Contains(errMsg, "link not found"):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Link Not Found",
+			Message: "Link with the specified ID was not found",
+			Code:    "LINK_NOT_FOUND",
+		})
+	case strings.Contains(errMsg, "campaign not found"):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Campaign Not Found",
+			Message: "The link's campaign was not found",
+			Code:    "CAMPAIGN_NOT_FOUND",
+		})
+	case strings.HasPrefix(errMsg, "invalid "):
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: errMsg,
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "Internal Server Error",
+		Message: fallback,
+		Code:    "INTERNAL_ERROR",
+	})
+}
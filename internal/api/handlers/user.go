@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/service"
+)
+
+// UserHandler handles admin user and role HTTP requests
+type UserHandler struct {
+	service *service.UserService
+	logger  logger.Logger
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(service *service.UserService, logger logger.Logger) *UserHandler {
+	return &UserHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateUser handles POST /api/users
+// @Summary Create a user
+// @Description Create an admin UI user with a role (see GET /api/roles). Managing users needs every scope.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateUserRequest true "User creation request"
+// @Success 201 {object} dto.UserResponse "User created successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Missing scope"
+// @Failure 409 {object} dto.ErrorResponse "Email already in use"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/users [post]
+func (h *UserHandler) CreateUser(c echo.Context) error {
+	var req dto.CreateUserRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid request body",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	user, err := h.service.CreateUser(c.Request().Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to create user", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to create user")
+	}
+
+	return c.JSON(http.StatusCreated, user)
+}
+
+// GetAllUsers handles GET /api/users
+// @Summary List users
+// @Description List admin UI users (by email)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param limit query int false "Limit" default(100)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} dto.UserResponse "Users retrieved successfully"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Missing scope"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/users [get]
+func (h *UserHandler) GetAllUsers(c echo.Context) error {
+	limit, offset := parsePagination(c)
+
+	users, err := h.service.GetAllUsers(c.Request().Context(), limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to get users", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to get users")
+	}
+
+	return c.JSON(http.StatusOK, users)
+}
+
+// GetUser handles GET /api/users/:id
+// @Summary Get a user
+// @Description Get an admin UI user by ID
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Success 200 {object} dto.UserResponse "User retrieved successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid user ID"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Missing scope"
+// @Failure 404 {object} dto.ErrorResponse "User not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/users/{id} [get]
+func (h *UserHandler) GetUser(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid user ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	user, err := h.service.GetUser(c.Request().Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get user", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to get user")
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// UpdateUser handles PATCH /api/users/:id
+// @Summary Update a user
+// @Description Update an admin UI user's name, password, role or active flag. Changing the password or deactivating the user ends their sessions; role changes apply to their next request. The last active admin can't be demoted or deactivated.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param request body dto.UpdateUserRequest true "User update request"
+// @Success 200 {object} dto.UserResponse "User updated successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Missing scope"
+// @Failure 404 {object} dto.ErrorResponse "User not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/users/{id} [patch]
+func (h *UserHandler) UpdateUser(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid user ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	var req dto.UpdateUserRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid request body",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	user, err := h.service.UpdateUser(c.Request().Context(), userID, req)
+	if err != nil {
+		h.logger.Error("Failed to update user", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to update user")
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// DeleteUser handles DELETE /api/users/:id
+// @Summary Delete a user
+// @Description Delete an admin UI user; their sessions stop working immediately. The last active admin can't be deleted.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Success 204 "User deleted successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Missing scope"
+// @Failure 404 {object} dto.ErrorResponse "User not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/users/{id} [delete]
+func (h *UserHandler) DeleteUser(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid user ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	if err := h.service.DeleteUser(c.Request().Context(), userID); err != nil {
+		h.logger.Error("Failed to delete user", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to delete user")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetRoles handles GET /api/roles
+// @Summary List roles
+// @Description List the roles users can have and the scopes each grants
+// @Tags users
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.RoleResponse "Roles retrieved successfully"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Missing scope"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/roles [get]
+func (h *UserHandler) GetRoles(c echo.Context) error {
+	roles, err := h.service.GetRoles(c.Request().Context())
+	if err != nil {
+		h.logger.Error("Failed to get roles", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to get roles")
+	}
+
+	return c.JSON(http.StatusOK, roles)
+}
+
+// errorResponse maps user service errors to HTTP responses
+func (h *UserHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	errMsg := err.Error()
+	switch {
+	case strings.Contains(errMsg, "user not found"):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "User Not Found",
+			Message: "User with the specified ID was not found",
+			Code:    "USER_NOT_FOUND",
+		})
+	case strings.Contains(errMsg, "email already in use"):
+		return c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "Conflict",
+			Message: errMsg,
+			Code:    "EMAIL_CONFLICT",
+		})
+	case strings.HasPrefix(errMsg, "invalid "):
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: errMsg,
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "Internal Server Error",
+		Message: fallback,
+		Code:    "INTERNAL_ERROR",
+	})
+}
@@ -3,34 +3,43 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 
 	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	"github.com/jonosize/affiliate-platform/internal/service"
 	"github.com/jonosize/affiliate-platform/internal/worker"
 )
 
 // WorkerHandler handles worker-related HTTP requests
 type WorkerHandler struct {
-	worker *worker.PriceRefreshWorker
-	logger logger.Logger
+	worker        *worker.PriceRefreshWorker
+	clickIngestor *worker.ClickIngestor
+	audit         *service.AuditService
+	logger        logger.Logger
 }
 
 // NewWorkerHandler creates a new worker handler
-func NewWorkerHandler(worker *worker.PriceRefreshWorker, logger logger.Logger) *WorkerHandler {
+func NewWorkerHandler(worker *worker.PriceRefreshWorker, clickIngestor *worker.ClickIngestor, audit *service.AuditService, logger logger.Logger) *WorkerHandler {
 	return &WorkerHandler{
-		worker: worker,
-		logger: logger,
+		worker:        worker,
+		clickIngestor: clickIngestor,
+		audit:         audit,
+		logger:        logger,
 	}
 }
 
 // TriggerPriceRefresh handles POST /api/admin/worker/refresh-prices
 // @Summary Manually trigger price refresh job
-// @Description Manually triggers the price refresh worker to update all product prices
+// @Description Manually triggers the price refresh worker to update all product prices. The trigger is recorded in the audit log.
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]string "Price refresh triggered successfully"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
 // @Router /api/admin/worker/refresh-prices [post]
 func (h *WorkerHandler) TriggerPriceRefresh(c echo.Context) error {
 	if err := h.worker.TriggerManualRefresh(); err != nil {
@@ -40,8 +49,23 @@ func (h *WorkerHandler) TriggerPriceRefresh(c echo.Context) error {
 			"message": "Failed to trigger price refresh",
 		})
 	}
+	h.audit.Record(c.Request().Context(), model.AuditActionTrigger, model.AuditEntityPriceRefresh, uuid.Nil, nil, nil)
 
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "Price refresh triggered successfully",
 	})
 }
+
+// GetClickIngestorStats handles GET /api/worker/click-ingestor
+// @Summary Get click ingestion pipeline statistics
+// @Description Get counters of the buffered click ingestion pipeline: clicks enqueued, dropped (queue full), persisted and failed, batch inserts, and current queue depth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} worker.ClickIngestorStats "Click ingestor statistics"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/worker/click-ingestor [get]
+func (h *WorkerHandler) GetClickIngestorStats(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.clickIngestor.Stats())
+}
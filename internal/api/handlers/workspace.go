@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/service"
+)
+
+// WorkspaceHandler handles publisher workspace HTTP requests
+type WorkspaceHandler struct {
+	service *service.WorkspaceService
+	logger  logger.Logger
+}
+
+// NewWorkspaceHandler creates a new workspace handler
+func NewWorkspaceHandler(service *service.WorkspaceService, logger logger.Logger) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetCurrentWorkspace handles GET /api/workspace
+// @Summary Get the current workspace
+// @Description Get the workspace the caller acts in: their own for users and API keys; for BasicAuth operators, the one named by the X-Workspace-ID header or the default workspace
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.WorkspaceResponse "Workspace retrieved successfully"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security BasicAuth
+// @Router /api/workspace [get]
+func (h *WorkspaceHandler) GetCurrentWorkspace(c echo.Context) error {
+	workspace, err := h.service.GetCurrentWorkspace(c.Request().Context())
+	if err != nil {
+		h.logger.Error("Failed to get current workspace", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to get workspace")
+	}
+
+	return c.JSON(http.StatusOK, workspace)
+}
+
+// CreateWorkspace handles POST /api/workspaces
+// @Summary Create a workspace
+// @Description Create a publisher workspace (affiliate brand). Products, campaigns, links, clicks, users and API keys belong to one workspace. With a redirect domain, the workspace's short links use https://<redirect_domain>/go/<code>; point the domain at this deployment. Operators (BasicAuth) only.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateWorkspaceRequest true "Workspace creation request"
+// @Success 201 {object} dto.WorkspaceResponse "Workspace created successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Not an operator"
+// @Failure 409 {object} dto.ErrorResponse "Slug or redirect domain already in use"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BasicAuth
+// @Router /api/workspaces [post]
+func (h *WorkspaceHandler) CreateWorkspace(c echo.Context) error {
+	var req dto.CreateWorkspaceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid request body",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	workspace, err := h.service.CreateWorkspace(c.Request().Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to create workspace", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to create workspace")
+	}
+
+	return c.JSON(http.StatusCreated, workspace)
+}
+
+// GetAllWorkspaces handles GET /api/workspaces
+// @Summary List workspaces
+// @Description List publisher workspaces (by name). Operators (BasicAuth) only.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param limit query int false "Limit" default(100)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} dto.WorkspaceResponse "Workspaces retrieved successfully"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Not an operator"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BasicAuth
+// @Router /api/workspaces [get]
+func (h *WorkspaceHandler) GetAllWorkspaces(c echo.Context) error {
+	limit, offset := parsePagination(c)
+
+	workspaces, err := h.service.GetAllWorkspaces(c.Request().Context(), limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to get workspaces", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to get workspaces")
+	}
+
+	return c.JSON(http.StatusOK, workspaces)
+}
+
+// UpdateWorkspace handles PATCH /api/workspaces/:id
+// @Summary Update a workspace
+// @Description Rename a workspace or change its redirect domain (an empty string removes it). Short links already shared on the previous domain stop resolving there. Operators (BasicAuth) only.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID" format(uuid)
+// @Param request body dto.UpdateWorkspaceRequest true "Workspace update request"
+// @Success 200 {object} dto.WorkspaceResponse "Workspace updated successfully"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Not an operator"
+// @Failure 404 {object} dto.ErrorResponse "Workspace not found"
+// @Failure 409 {object} dto.ErrorResponse "Redirect domain already in use"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security BasicAuth
+// @Router /api/workspaces/{id} [patch]
+func (h *WorkspaceHandler) UpdateWorkspace(c echo.Context) error {
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid workspace ID format",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	var req dto.UpdateWorkspaceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: "Invalid request body",
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	workspace, err := h.service.UpdateWorkspace(c.Request().Context(), workspaceID, req)
+	if err != nil {
+		h.logger.Error("Failed to update workspace", logger.String("error", err.Error()))
+		return h.errorResponse(c, err, "Failed to update workspace")
+	}
+
+	return c.JSON(http.StatusOK, workspace)
+}
+
+// errorResponse maps workspace service errors to HTTP responses
+func (h *WorkspaceHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	errMsg := err.Error()
+	switch {
+	case strings.Contains(errMsg, "workspace not found"):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Workspace Not Found",
+			Message: "Workspace with the specified ID was not found",
+			Code:    "WORKSPACE_NOT_FOUND",
+		})
+	case strings.Contains(errMsg, "already in use"):
+		return c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "Conflict",
+			Message: errMsg,
+			Code:    "WORKSPACE_CONFLICT",
+		})
+	case strings.HasPrefix(errMsg, "invalid "):
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid Input",
+			Message: errMsg,
+			Code:    "INVALID_INPUT",
+		})
+	}
+
+	return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "Internal Server Error",
+		Message: fallback,
+		Code:    "INTERNAL_ERROR",
+	})
+}
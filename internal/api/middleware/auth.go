@@ -1,32 +1,199 @@
 package middleware
 
 import (
+	"context"
 	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	echomw "github.com/labstack/echo/v4/middleware"
+	"gorm.io/gorm"
 
+	"github.com/jonosize/affiliate-platform/internal/auth"
 	"github.com/jonosize/affiliate-platform/internal/config"
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	"github.com/jonosize/affiliate-platform/internal/service"
 )
 
-// BasicAuth creates a BasicAuth middleware using credentials from config
-func BasicAuth() echo.MiddlewareFunc {
-	return echomw.BasicAuth(func(username, password string, c echo.Context) (bool, error) {
-		cfg := config.Get()
-		expectedUsername := cfg.GetBasicAuthUsername()
-		expectedPassword := cfg.GetBasicAuthPassword()
-
-		// Use constant-time comparison to prevent timing attacks
-		usernameMatch := subtle.ConstantTimeCompare(
-			[]byte(username),
-			[]byte(expectedUsername),
-		) == 1
-
-		passwordMatch := subtle.ConstantTimeCompare(
-			[]byte(password),
-			[]byte(expectedPassword),
-		) == 1
-
-		return usernameMatch && passwordMatch, nil
-	})
+// WorkspaceHeader lets BasicAuth operators pick the workspace a request acts in
+const WorkspaceHeader = "X-Workspace-ID"
+
+// TokenAuthenticator resolves bearer tokens to the principal they grant
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*auth.Principal, error)
+}
+
+// WorkspaceFinder looks up the workspaces operators name in WorkspaceHeader
+type WorkspaceFinder interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Workspace, error)
+}
+
+// Authenticate requires a user session token or an admin API key
+// ("Authorization: Bearer <token>") or, when auth.basic_auth is configured,
+// the bootstrap BasicAuth credentials, which grant every scope
+// The principal and its workspace are stored in the request context for
+// RequireScope, services and repositories. Users and API keys act in their own
+// workspace; BasicAuth operators act in the one named by WorkspaceHeader, or
+// the default workspace.
+func Authenticate(keys, sessions TokenAuthenticator, workspaces WorkspaceFinder, cfg config.Config, log logger.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, err := authenticate(c, keys, sessions, cfg)
+			if err != nil {
+				log.Error("Failed to authenticate request", logger.String("error", err.Error()))
+				return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+					Error:   "Internal Server Error",
+					Message: "Failed to authenticate request",
+					Code:    "INTERNAL_ERROR",
+				})
+			}
+			if principal == nil {
+				challenge := `Bearer realm="admin"`
+				if basicAuthEnabled(cfg) {
+					challenge += `, Basic realm="admin"`
+				}
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, challenge)
+				return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+					Error:   "Unauthorized",
+					Message: "A valid session token or API key (Authorization: Bearer <token>) is required",
+					Code:    "UNAUTHORIZED",
+				})
+			}
+
+			if principal.Operator {
+				if header := c.Request().Header.Get(WorkspaceHeader); header != "" {
+					id, err := uuid.Parse(header)
+					if err != nil {
+						return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+							Error:   "Invalid Input",
+							Message: "Invalid " + WorkspaceHeader + " header format",
+							Code:    "INVALID_INPUT",
+						})
+					}
+					if _, err := workspaces.FindByID(c.Request().Context(), id); err != nil {
+						if errors.Is(err, gorm.ErrRecordNotFound) {
+							return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+								Error:   "Invalid Input",
+								Message: "Workspace named by " + WorkspaceHeader + " was not found",
+								Code:    "INVALID_INPUT",
+							})
+						}
+						log.Error("Failed to get workspace", logger.String("error", err.Error()))
+						return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+							Error:   "Internal Server Error",
+							Message: "Failed to authenticate request",
+							Code:    "INTERNAL_ERROR",
+						})
+					}
+					principal.WorkspaceID = id
+				}
+			}
+
+			req := c.Request()
+			ctx := auth.WithPrincipal(req.Context(), principal)
+			c.SetRequest(req.WithContext(auth.WithWorkspace(ctx, principal.WorkspaceID)))
+			return next(c)
+		}
+	}
+}
+
+// RequireScope rejects principals missing any of scopes with 403
+// Must run after Authenticate
+func RequireScope(scopes ...auth.Scope) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal := auth.PrincipalFrom(c.Request().Context())
+			for _, scope := range scopes {
+				if !principal.HasScope(scope) {
+					return c.JSON(http.StatusForbidden, dto.ErrorResponse{
+						Error:   "Forbidden",
+						Message: "Missing scope: " + string(scope),
+						Code:    "FORBIDDEN",
+					})
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireOperator rejects principals other than BasicAuth operators with 403
+// Must run after Authenticate
+func RequireOperator() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if principal := auth.PrincipalFrom(c.Request().Context()); principal == nil || !principal.Operator {
+				return c.JSON(http.StatusForbidden, dto.ErrorResponse{
+					Error:   "Forbidden",
+					Message: "Only deployment operators (BasicAuth) can manage workspaces",
+					Code:    "FORBIDDEN",
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+// authenticate returns the request's principal, or nil when its credentials are missing or invalid
+func authenticate(c echo.Context, keys, sessions TokenAuthenticator, cfg config.Config) (*auth.Principal, error) {
+	header := c.Request().Header.Get(echo.HeaderAuthorization)
+	scheme, credentials, _ := strings.Cut(header, " ")
+
+	switch strings.ToLower(scheme) {
+	case "bearer":
+		// API keys are recognized by their prefix without a lookup, so trying them first is cheap
+		token := strings.TrimSpace(credentials)
+		principal, err := keys.Authenticate(c.Request().Context(), token)
+		if !errors.Is(err, service.ErrInvalidAPIKey) {
+			return principal, err
+		}
+		principal, err = sessions.Authenticate(c.Request().Context(), token)
+		if errors.Is(err, service.ErrInvalidSession) {
+			return nil, nil
+		}
+		return principal, err
+	case "basic":
+		username, password, ok := c.Request().BasicAuth()
+		if !ok || !basicAuthValid(cfg, username, password) {
+			return nil, nil
+		}
+		return &auth.Principal{
+			Subject:     "basic:" + username,
+			Name:        username,
+			Scopes:      auth.AllScopes,
+			WorkspaceID: model.DefaultWorkspaceID,
+			Operator:    true,
+		}, nil
+	}
+	return nil, nil
+}
+
+// basicAuthEnabled reports whether bootstrap BasicAuth credentials are configured
+// Both must be set; empty credentials never authenticate
+func basicAuthEnabled(cfg config.Config) bool {
+	return cfg.GetBasicAuthUsername() != "" && cfg.GetBasicAuthPassword() != ""
+}
+
+// basicAuthValid checks BasicAuth credentials against config
+func basicAuthValid(cfg config.Config, username, password string) bool {
+	if !basicAuthEnabled(cfg) {
+		return false
+	}
+
+	// Use constant-time comparison to prevent timing attacks
+	usernameMatch := subtle.ConstantTimeCompare(
+		[]byte(username),
+		[]byte(cfg.GetBasicAuthUsername()),
+	) == 1
+
+	passwordMatch := subtle.ConstantTimeCompare(
+		[]byte(password),
+		[]byte(cfg.GetBasicAuthPassword()),
+	) == 1
+
+	return usernameMatch && passwordMatch
 }
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/config"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	"github.com/jonosize/affiliate-platform/internal/service"
+)
+
+// basicAuthConfig overrides the BasicAuth credentials of config.Config
+type basicAuthConfig struct {
+	config.Config
+	username, password string
+}
+
+func (c basicAuthConfig) GetBasicAuthUsername() string { return c.username }
+func (c basicAuthConfig) GetBasicAuthPassword() string { return c.password }
+
+// staticKeys authenticates a fixed set of bearer keys
+type staticKeys map[string]*auth.Principal
+
+func (k staticKeys) Authenticate(ctx context.Context, key string) (*auth.Principal, error) {
+	if key == "afk_broken" {
+		return nil, errors.New("database unavailable")
+	}
+	if p, ok := k[key]; ok {
+		return p, nil
+	}
+	return nil, service.ErrInvalidAPIKey
+}
+
+// staticSessions authenticates a fixed set of session tokens
+type staticSessions map[string]*auth.Principal
+
+func (s staticSessions) Authenticate(ctx context.Context, token string) (*auth.Principal, error) {
+	if p, ok := s[token]; ok {
+		return p, nil
+	}
+	return nil, service.ErrInvalidSession
+}
+
+// staticWorkspaces finds a fixed set of workspaces
+type staticWorkspaces map[uuid.UUID]bool
+
+func (w staticWorkspaces) FindByID(ctx context.Context, id uuid.UUID) (*model.Workspace, error) {
+	if w[id] {
+		return &model.Workspace{ID: id}, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// brandWorkspaceID is the workspace of the test sessions
+var brandWorkspaceID = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+func newTestServer(t *testing.T, cfg config.Config) *echo.Echo {
+	t.Helper()
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+
+	keys := staticKeys{
+		"afk_reader": {Subject: "api_key:reader", Name: "reader", Scopes: []auth.Scope{auth.ScopeRead}},
+		"afk_writer": {Subject: "api_key:writer", Name: "writer", Scopes: []auth.Scope{auth.ScopeRead, auth.ScopeWrite}},
+	}
+	sessions := staticSessions{
+		"analyst-session": {Subject: "user:analyst", Name: "analyst@example.com", Role: "analyst", Scopes: []auth.Scope{auth.ScopeRead}, WorkspaceID: brandWorkspaceID},
+		"manager-session": {Subject: "user:manager", Name: "manager@example.com", Role: "campaign_manager", Scopes: []auth.Scope{auth.ScopeRead, auth.ScopeWrite}, WorkspaceID: brandWorkspaceID},
+	}
+	whoami := func(c echo.Context) error {
+		return c.String(http.StatusOK, auth.PrincipalFrom(c.Request().Context()).Subject)
+	}
+	workspace := func(c echo.Context) error {
+		id, _ := auth.WorkspaceFrom(c.Request().Context())
+		return c.String(http.StatusOK, id.String())
+	}
+	workspaces := staticWorkspaces{model.DefaultWorkspaceID: true, brandWorkspaceID: true}
+
+	e := echo.New()
+	admin := e.Group("/api", Authenticate(keys, sessions, workspaces, cfg, log))
+	admin.Group("", RequireScope(auth.ScopeRead)).GET("/products", whoami)
+	admin.Group("", RequireScope(auth.ScopeRead)).GET("/dashboard", whoami)
+	admin.Group("", RequireScope(auth.ScopeWrite)).POST("/products", whoami)
+	admin.Group("", RequireScope(auth.ScopeWrite)).DELETE("/products/:id", whoami)
+	admin.Group("", RequireScope(auth.ScopeWorker)).POST("/worker/refresh-prices", whoami)
+	admin.GET("/workspace", workspace)
+	admin.Group("", RequireOperator()).GET("/workspaces", whoami)
+	e.Group("/api").GET("/campaigns/:id/public", func(c echo.Context) error {
+		return c.String(http.StatusOK, "public")
+	})
+	return e
+}
+
+func TestAuthenticate(t *testing.T) {
+	cfg := basicAuthConfig{username: "admin", password: "s3cret"}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		setup    func(r *http.Request)
+		cfg      config.Config
+		wantCode int
+		wantBody string
+	}{
+		{name: "missing credentials", method: http.MethodGet, path: "/api/products", wantCode: http.StatusUnauthorized},
+		{name: "unknown key", method: http.MethodGet, path: "/api/products", setup: bearer("afk_nope"), wantCode: http.StatusUnauthorized},
+		{name: "key with scope", method: http.MethodGet, path: "/api/products", setup: bearer("afk_reader"), wantCode: http.StatusOK, wantBody: "api_key:reader"},
+		{name: "scheme is case-insensitive", method: http.MethodGet, path: "/api/products", setup: header("bearer afk_reader"), wantCode: http.StatusOK},
+		{name: "key without scope", method: http.MethodPost, path: "/api/products", setup: bearer("afk_reader"), wantCode: http.StatusForbidden},
+		{name: "write key", method: http.MethodPost, path: "/api/products", setup: bearer("afk_writer"), wantCode: http.StatusOK},
+		{name: "worker scope", method: http.MethodPost, path: "/api/worker/refresh-prices", setup: bearer("afk_writer"), wantCode: http.StatusForbidden},
+		{name: "analyst session reads the dashboard", method: http.MethodGet, path: "/api/dashboard", setup: bearer("analyst-session"), wantCode: http.StatusOK, wantBody: "user:analyst"},
+		{name: "analyst session can't delete products", method: http.MethodDelete, path: "/api/products/123", setup: bearer("analyst-session"), wantCode: http.StatusForbidden},
+		{name: "campaign manager deletes products", method: http.MethodDelete, path: "/api/products/123", setup: bearer("manager-session"), wantCode: http.StatusOK, wantBody: "user:manager"},
+		{name: "campaign manager can't trigger workers", method: http.MethodPost, path: "/api/worker/refresh-prices", setup: bearer("manager-session"), wantCode: http.StatusForbidden},
+		{name: "unknown session", method: http.MethodGet, path: "/api/dashboard", setup: bearer("expired-session"), wantCode: http.StatusUnauthorized},
+		{name: "lookup error", method: http.MethodGet, path: "/api/products", setup: bearer("afk_broken"), wantCode: http.StatusInternalServerError},
+		{name: "basic auth grants every scope", method: http.MethodPost, path: "/api/worker/refresh-prices", setup: basic("admin", "s3cret"), wantCode: http.StatusOK, wantBody: "basic:admin"},
+		{name: "wrong basic password", method: http.MethodGet, path: "/api/products", setup: basic("admin", "nope"), wantCode: http.StatusUnauthorized},
+		{name: "basic auth disabled", method: http.MethodGet, path: "/api/products", setup: basic("", ""), cfg: basicAuthConfig{}, wantCode: http.StatusUnauthorized},
+		{name: "sessions act in the user's workspace", method: http.MethodGet, path: "/api/workspace", setup: bearer("analyst-session"), wantCode: http.StatusOK, wantBody: brandWorkspaceID.String()},
+		{name: "only operators pick a workspace", method: http.MethodGet, path: "/api/workspace", setup: both(bearer("analyst-session"), workspaceHeader(model.DefaultWorkspaceID.String())), wantCode: http.StatusOK, wantBody: brandWorkspaceID.String()},
+		{name: "operators default to the default workspace", method: http.MethodGet, path: "/api/workspace", setup: basic("admin", "s3cret"), wantCode: http.StatusOK, wantBody: model.DefaultWorkspaceID.String()},
+		{name: "operators pick a workspace", method: http.MethodGet, path: "/api/workspace", setup: both(basic("admin", "s3cret"), workspaceHeader(brandWorkspaceID.String())), wantCode: http.StatusOK, wantBody: brandWorkspaceID.String()},
+		{name: "unknown workspace", method: http.MethodGet, path: "/api/workspace", setup: both(basic("admin", "s3cret"), workspaceHeader(uuid.NewString())), wantCode: http.StatusBadRequest},
+		{name: "malformed workspace", method: http.MethodGet, path: "/api/workspace", setup: both(basic("admin", "s3cret"), workspaceHeader("brand")), wantCode: http.StatusBadRequest},
+		{name: "operators manage workspaces", method: http.MethodGet, path: "/api/workspaces", setup: basic("admin", "s3cret"), wantCode: http.StatusOK},
+		{name: "admin keys can't manage workspaces", method: http.MethodGet, path: "/api/workspaces", setup: bearer("afk_writer"), wantCode: http.StatusForbidden},
+		{name: "public route", method: http.MethodGet, path: "/api/campaigns/123/public", wantCode: http.StatusOK, wantBody: "public"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverCfg := config.Config(cfg)
+			if tt.cfg != nil {
+				serverCfg = tt.cfg
+			}
+			e := newTestServer(t, serverCfg)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.setup != nil {
+				tt.setup(req)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code, rec.Body.String())
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			}
+			if tt.wantCode == http.StatusUnauthorized {
+				assert.Contains(t, rec.Header().Get(echo.HeaderWWWAuthenticate), "Bearer")
+			}
+		})
+	}
+}
+
+func bearer(key string) func(r *http.Request) {
+	return header("Bearer " + key)
+}
+
+func header(value string) func(r *http.Request) {
+	return func(r *http.Request) { r.Header.Set(echo.HeaderAuthorization, value) }
+}
+
+func basic(username, password string) func(r *http.Request) {
+	return func(r *http.Request) { r.SetBasicAuth(username, password) }
+}
+
+func workspaceHeader(value string) func(r *http.Request) {
+	return func(r *http.Request) { r.Header.Set(WorkspaceHeader, value) }
+}
+
+func both(setups ...func(r *http.Request)) func(r *http.Request) {
+	return func(r *http.Request) {
+		for _, setup := range setups {
+			setup(r)
+		}
+	}
+}
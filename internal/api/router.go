@@ -1,85 +1,206 @@
 package api
 
 import (
+	"time"
+
 	"github.com/labstack/echo/v4"
 
 	"github.com/jonosize/affiliate-platform/internal/api/handlers"
+	"github.com/jonosize/affiliate-platform/internal/api/middleware"
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/cache"
 	"github.com/jonosize/affiliate-platform/internal/config"
 	"github.com/jonosize/affiliate-platform/internal/database"
 	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/qrcode"
 	"github.com/jonosize/affiliate-platform/internal/repository"
 	"github.com/jonosize/affiliate-platform/internal/service"
 	"github.com/jonosize/affiliate-platform/internal/worker"
-	"github.com/jonosize/affiliate-platform/pkg/adapters/mock"
+	"github.com/jonosize/affiliate-platform/pkg/adapters"
 )
 
 // SetupRoutes configures all API routes
-func SetupRoutes(e *echo.Echo, db *database.DB, cfg config.Config, log logger.Logger, priceRefreshWorker *worker.PriceRefreshWorker) {
+func SetupRoutes(e *echo.Echo, db *database.DB, cacheStore cache.Store, cfg config.Config, log logger.Logger, registry *adapters.Registry, priceRefreshWorker *worker.PriceRefreshWorker, clickIngestor *worker.ClickIngestor, botFilter *service.BotFilter, dedupeStore service.DedupeStore, countryResolver service.CountryResolver, qrLogo *qrcode.Logo) {
 	// Initialize repositories
 	productRepo := repository.NewProductRepository(db)
 	offerRepo := repository.NewOfferRepository(db)
 	campaignRepo := repository.NewCampaignRepository(db)
-	linkRepo := repository.NewLinkRepository(db)
+	linkRepo := cache.NewCachedLinkRepository(
+		repository.NewLinkRepository(db),
+		cacheStore,
+		time.Duration(cfg.GetShortCodeCacheTTL())*time.Second,
+		time.Duration(cfg.GetShortCodeCacheNegativeTTL())*time.Second,
+		log,
+	)
 	clickRepo := repository.NewClickRepository(db)
-
-	// Initialize adapters
-	lazadaAdapter, shopeeAdapter, err := mock.GetMockAdapters()
-	if err != nil {
-		log.Fatal("Failed to initialize adapters", logger.Error(err))
-	}
+	priceHistoryRepo := repository.NewPriceHistoryRepository(db)
+	alertRuleRepo := repository.NewAlertRuleRepository(db)
+	alertDeliveryRepo := repository.NewAlertDeliveryRepository(db)
+	impressionRepo := repository.NewImpressionRepository(db)
+	conversionRepo := repository.NewConversionRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	workspaceRepo := repository.NewWorkspaceRepository(db)
+	auditEventRepo := repository.NewAuditEventRepository(db)
 
 	// Initialize services with repository interfaces and adapters
-	productService := service.NewProductService(productRepo, offerRepo, lazadaAdapter, shopeeAdapter, log)
-	campaignService := service.NewCampaignService(campaignRepo, linkRepo, offerRepo, productRepo, cfg, log)
-	linkService := service.NewLinkService(linkRepo, campaignRepo, productRepo, offerRepo, cfg, log)
-	clickService := service.NewClickService(clickRepo, linkRepo, log)
-	redirectService := service.NewRedirectService(linkRepo, clickService, log)
-	campaignPublicService := service.NewCampaignPublicService(campaignRepo, productRepo, offerRepo, linkRepo, cfg, log)
-	dashboardService := service.NewDashboardService(clickRepo, linkRepo, campaignRepo, productRepo, log)
+	auditService := service.NewAuditService(auditEventRepo, log)
+	productService := service.NewProductService(productRepo, offerRepo, priceHistoryRepo, registry, linkRepo, auditService, log)
+	campaignService := service.NewCampaignService(campaignRepo, linkRepo, offerRepo, productRepo, linkRepo, auditService, cfg, log)
+	linkService := service.NewLinkService(linkRepo, campaignRepo, productRepo, offerRepo, workspaceRepo, registry, qrLogo, auditService, cfg, log)
+	clickDedupe := service.NewClickDeduplicator(dedupeStore, time.Duration(cfg.GetClickDedupeWindow())*time.Second)
+	clickService := service.NewClickService(clickRepo, linkRepo, clickIngestor, botFilter, clickDedupe, log)
+	redirectService := service.NewRedirectService(linkRepo, offerRepo, clickService, registry, countryResolver, cfg, log)
+	redirectRuleService := service.NewRedirectRuleService(linkRepo, campaignRepo, offerRepo, registry, log)
+	linkVariantService := service.NewLinkVariantService(linkRepo, campaignRepo, offerRepo, clickRepo, conversionRepo, registry, log)
+	impressionService := service.NewImpressionService(impressionRepo, botFilter, log)
+	campaignPublicService := service.NewCampaignPublicService(campaignRepo, productRepo, offerRepo, linkRepo, priceHistoryRepo, workspaceRepo, impressionService, cfg, log)
+	dashboardService := service.NewDashboardService(clickRepo, impressionRepo, conversionRepo, linkRepo, campaignRepo, productRepo, registry, log)
+	alertService := service.NewAlertService(alertRuleRepo, alertDeliveryRepo, productRepo, campaignRepo, registry, log)
+	conversionService := service.NewConversionService(conversionRepo, clickRepo, linkRepo, cfg, log)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, log)
+	userService := service.NewUserService(userRepo, roleRepo, cfg, log)
+	workspaceService := service.NewWorkspaceService(workspaceRepo, log)
 
 	// Initialize handlers
 	productHandler := handlers.NewProductHandler(productService, log)
 	campaignHandler := handlers.NewCampaignHandler(campaignService, log)
-	linkHandler := handlers.NewLinkHandler(linkService, log)
-	redirectHandler := handlers.NewRedirectHandler(redirectService, log)
+	linkHandler := handlers.NewLinkHandler(linkService, clickService, log)
+	if cfg.GetVisitorSalt() == "" {
+		log.Warn("tracking.visitor.salt is not set; visitor fingerprints will change on restart and differ between instances")
+	}
+	redirectRuleHandler := handlers.NewRedirectRuleHandler(redirectRuleService, log)
+	linkVariantHandler := handlers.NewLinkVariantHandler(linkVariantService, log)
+	redirectHandler := handlers.NewRedirectHandler(redirectService, service.NewVisitorIdentifier(cfg.GetVisitorSalt()), cfg.GetVisitorCookieName(), log)
 	campaignPublicHandler := handlers.NewCampaignPublicHandler(campaignPublicService, log)
-	workerHandler := handlers.NewWorkerHandler(priceRefreshWorker, log)
+	workerHandler := handlers.NewWorkerHandler(priceRefreshWorker, clickIngestor, auditService, log)
 	dashboardHandler := handlers.NewDashboardHandler(dashboardService, log)
+	alertHandler := handlers.NewAlertHandler(alertService, log)
+	postbackHandler := handlers.NewPostbackHandler(conversionService, log)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService, log)
+	authHandler := handlers.NewAuthHandler(userService, log)
+	userHandler := handlers.NewUserHandler(userService, log)
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceService, log)
+	auditHandler := handlers.NewAuditHandler(auditService, log)
 
-	// Admin routes (no auth)
-	adminGroup := e.Group("/api")
+	if cfg.GetBasicAuthUsername() == "" || cfg.GetBasicAuthPassword() == "" {
+		log.Warn("auth.basic_auth is not set; the admin API only accepts session tokens and API keys")
+	}
+	if cfg.GetSessionSecret() == "" {
+		log.Warn("auth.session.secret is not set; logins will end on restart and only work on the instance that issued them")
+	}
+
+	// Admin routes (session token, API key or bootstrap BasicAuth; each route needs a scope)
+	// Every admin request acts in one workspace (see middleware.Authenticate)
+	// Scopes are route middleware: group middleware would register catch-all
+	// routes, answering unknown paths with 403 instead of 404
+	adminGroup := e.Group("/api", middleware.Authenticate(apiKeyService, userService, workspaceRepo, cfg, log))
+	requireRead := middleware.RequireScope(auth.ScopeRead)
+	requireWrite := middleware.RequireScope(auth.ScopeWrite)
+	requireWorker := middleware.RequireScope(auth.ScopeWorker)
 	{
 		// Products
-		adminGroup.GET("/products", productHandler.GetAllProducts)
-		adminGroup.POST("/products", productHandler.CreateProduct)
-		adminGroup.GET("/products/:id/offers", productHandler.GetProductOffers)
-		adminGroup.DELETE("/products/:id", productHandler.DeleteProduct)
+		adminGroup.GET("/products", productHandler.GetAllProducts, requireRead)
+		adminGroup.POST("/products", productHandler.CreateProduct, requireWrite)
+		adminGroup.GET("/products/:id/offers", productHandler.GetProductOffers, requireRead)
+		adminGroup.GET("/products/:id/price-history", productHandler.GetPriceHistory, requireRead)
+		adminGroup.DELETE("/products/:id", productHandler.DeleteProduct, requireWrite)
+		adminGroup.POST("/products/:id/restore", productHandler.RestoreProduct, requireWrite)
+		adminGroup.DELETE("/products/:id/purge", productHandler.PurgeProduct, requireWrite)
 
 		// Campaigns
-		adminGroup.GET("/campaigns", campaignHandler.GetAllCampaigns)
-		adminGroup.GET("/campaigns/:id", campaignHandler.GetCampaign)
-		adminGroup.POST("/campaigns", campaignHandler.CreateCampaign)
-		adminGroup.PATCH("/campaigns/:id", campaignHandler.UpdateCampaign)
-		adminGroup.PATCH("/campaigns/:id/products", campaignHandler.UpdateCampaignProducts)
-		adminGroup.DELETE("/campaigns/:id", campaignHandler.DeleteCampaign)
+		adminGroup.GET("/campaigns", campaignHandler.GetAllCampaigns, requireRead)
+		adminGroup.GET("/campaigns/:id", campaignHandler.GetCampaign, requireRead)
+		adminGroup.POST("/campaigns", campaignHandler.CreateCampaign, requireWrite)
+		adminGroup.PATCH("/campaigns/:id", campaignHandler.UpdateCampaign, requireWrite)
+		adminGroup.PATCH("/campaigns/:id/products", campaignHandler.UpdateCampaignProducts, requireWrite)
+		adminGroup.DELETE("/campaigns/:id", campaignHandler.DeleteCampaign, requireWrite)
+		adminGroup.POST("/campaigns/:id/restore", campaignHandler.RestoreCampaign, requireWrite)
+		adminGroup.DELETE("/campaigns/:id/purge", campaignHandler.PurgeCampaign, requireWrite)
 
 		// Links
-		adminGroup.POST("/links", linkHandler.CreateLink)
+		adminGroup.POST("/links", linkHandler.CreateLink, requireWrite)
+		adminGroup.PATCH("/links/:id", linkHandler.UpdateLink, requireWrite)
+		adminGroup.POST("/links/:id/aliases", linkHandler.AddLinkAlias, requireWrite)
+		adminGroup.DELETE("/links/:id/aliases/:short_code", linkHandler.DeleteLinkAlias, requireWrite)
+		adminGroup.GET("/links/:id/stats", linkHandler.GetLinkStats, requireRead)
+		adminGroup.GET("/links/:id/qr", linkHandler.GetLinkQRCode, requireRead)
+		adminGroup.GET("/links/:id/redirect-rules", redirectRuleHandler.GetRedirectRules, requireRead)
+		adminGroup.POST("/links/:id/redirect-rules", redirectRuleHandler.CreateRedirectRule, requireWrite)
+		adminGroup.PATCH("/links/:id/redirect-rules/:rule_id", redirectRuleHandler.UpdateRedirectRule, requireWrite)
+		adminGroup.DELETE("/links/:id/redirect-rules/:rule_id", redirectRuleHandler.DeleteRedirectRule, requireWrite)
+		adminGroup.GET("/links/:id/variants", linkVariantHandler.GetVariants, requireRead)
+		adminGroup.POST("/links/:id/variants", linkVariantHandler.CreateVariant, requireWrite)
+		adminGroup.GET("/links/:id/variants/stats", linkVariantHandler.GetVariantStats, requireRead)
+		adminGroup.PATCH("/links/:id/variants/:variant_id", linkVariantHandler.UpdateVariant, requireWrite)
+		adminGroup.DELETE("/links/:id/variants/:variant_id", linkVariantHandler.DeleteVariant, requireWrite)
+
+		// Price alerts
+		adminGroup.GET("/alerts", alertHandler.GetAllAlerts, requireRead)
+		adminGroup.GET("/alerts/:id", alertHandler.GetAlert, requireRead)
+		adminGroup.POST("/alerts", alertHandler.CreateAlert, requireWrite)
+		adminGroup.PATCH("/alerts/:id", alertHandler.UpdateAlert, requireWrite)
+		adminGroup.DELETE("/alerts/:id", alertHandler.DeleteAlert, requireWrite)
+		adminGroup.GET("/alerts/:id/deliveries", alertHandler.GetAlertDeliveries, requireRead)
 
 		// Worker
-		adminGroup.POST("/worker/refresh-prices", workerHandler.TriggerPriceRefresh)
+		adminGroup.POST("/worker/refresh-prices", workerHandler.TriggerPriceRefresh, requireWorker)
+		adminGroup.GET("/worker/click-ingestor", workerHandler.GetClickIngestorStats, requireWorker)
 
 		// Dashboard
-		adminGroup.GET("/dashboard", dashboardHandler.GetDashboardStats)
+		adminGroup.GET("/dashboard", dashboardHandler.GetDashboardStats, requireRead)
+
+		// Audit log
+		adminGroup.GET("/audit", auditHandler.GetAuditEvents, requireRead)
+
+		// Current caller and roles
+		adminGroup.GET("/auth/me", authHandler.Me)
+		adminGroup.GET("/workspace", workspaceHandler.GetCurrentWorkspace)
+		adminGroup.GET("/roles", userHandler.GetRoles, requireRead)
+	}
+
+	// API keys (every scope, so a key can't mint a more powerful one)
+	apiKeyGroup := adminGroup.Group("/api-keys", middleware.RequireScope(auth.AllScopes...))
+	{
+		apiKeyGroup.GET("", apiKeyHandler.GetAllAPIKeys)
+		apiKeyGroup.POST("", apiKeyHandler.CreateAPIKey)
+		apiKeyGroup.DELETE("/:id", apiKeyHandler.DeleteAPIKey)
+	}
+
+	// Users (every scope, i.e. the admin role, so nobody can grant themselves more)
+	userGroup := adminGroup.Group("/users", middleware.RequireScope(auth.AllScopes...))
+	{
+		userGroup.GET("", userHandler.GetAllUsers)
+		userGroup.POST("", userHandler.CreateUser)
+		userGroup.GET("/:id", userHandler.GetUser)
+		userGroup.PATCH("/:id", userHandler.UpdateUser)
+		userGroup.DELETE("/:id", userHandler.DeleteUser)
+	}
+
+	// Workspaces (deployment operators only)
+	workspaceGroup := adminGroup.Group("/workspaces", middleware.RequireOperator())
+	{
+		workspaceGroup.GET("", workspaceHandler.GetAllWorkspaces)
+		workspaceGroup.POST("", workspaceHandler.CreateWorkspace)
+		workspaceGroup.PATCH("/:id", workspaceHandler.UpdateWorkspace)
 	}
 
 	// Public routes (no auth)
 	publicGroup := e.Group("/api")
 	{
+		// Login (issues session tokens)
+		publicGroup.POST("/auth/login", authHandler.Login)
+
 		// Public campaign endpoint
 		publicGroup.GET("/campaigns/:id/public", campaignPublicHandler.GetPublicCampaign)
+
+		// Conversion postbacks (authenticated by per-network signature)
+		publicGroup.GET("/postbacks/:network", postbackHandler.HandlePostback)
+		publicGroup.POST("/postbacks/:network", postbackHandler.HandlePostback)
 	}
 
 	// Public redirect route (no group, direct route)
 	e.GET("/go/:short_code", redirectHandler.Redirect)
+	e.HEAD("/go/:short_code", redirectHandler.Redirect)
 }
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jonosize/affiliate-platform/internal/config"
+)
+
+// ErrMiss is returned by Store.Get when a key is not cached
+var ErrMiss = errors.New("cache miss")
+
+// Store is a key/value cache with per-entry expiry
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Add stores value only if key is absent, reporting whether it was stored
+	Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, keys ...string) error
+	Close() error
+}
+
+// NewStore builds the cache store from config
+// Redis is used when redis.url is set (and must be reachable); otherwise an
+// in-process LRU is used, which is only coherent within a single API instance
+func NewStore(ctx context.Context, cfg config.Config) (Store, error) {
+	redisURL := cfg.GetRedisURL()
+	if redisURL == "" {
+		return NewLRUStore(cfg.GetShortCodeCacheSize()), nil
+	}
+
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis.url: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return NewRedisStore(client), nil
+}
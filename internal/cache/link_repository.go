@@ -0,0 +1,420 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	"github.com/jonosize/affiliate-platform/internal/service"
+)
+
+// shortCodeKeyPrefix namespaces short code resolution entries in the store
+const shortCodeKeyPrefix = "link:short_code:"
+
+// cachedLink is the cached resolution of a short code
+// Found is false for negative entries (unknown short codes)
+type cachedLink struct {
+	Found       bool              `json:"found"`
+	ID          uuid.UUID         `json:"id,omitempty"`
+	WorkspaceID uuid.UUID         `json:"workspace_id,omitempty"`
+	ShortCode   string            `json:"short_code,omitempty"` // Canonical code; the key may be an alias
+	ProductID   uuid.UUID         `json:"product_id,omitempty"`
+	CampaignID  uuid.UUID         `json:"campaign_id,omitempty"`
+	Marketplace model.Marketplace `json:"marketplace,omitempty"`
+	TargetURL   string            `json:"target_url,omitempty"`
+	Status      model.LinkStatus  `json:"status,omitempty"`
+	ExpiresAt   *time.Time        `json:"expires_at,omitempty"`
+
+	RedirectDomain string `json:"redirect_domain,omitempty"` // Workspace redirect domain, checked on redirect
+
+	// Campaign window and policy, enforced on redirect
+	CampaignStartAt   time.Time               `json:"campaign_start_at,omitempty"`
+	CampaignEndAt     time.Time               `json:"campaign_end_at,omitempty"`
+	OutOfWindowPolicy model.OutOfWindowPolicy `json:"out_of_window_policy,omitempty"`
+	FallbackURL       string                  `json:"fallback_url,omitempty"`
+
+	RedirectRules []model.RedirectRule `json:"redirect_rules,omitempty"` // Active rules in evaluation order
+	Variants      []model.LinkVariant  `json:"variants,omitempty"`       // A/B variants receiving traffic
+
+	// UTM settings, for best-price links that build their target URL on redirect
+	UTMSource           *string `json:"utm_source,omitempty"`
+	UTMMedium           *string `json:"utm_medium,omitempty"`
+	UTMContent          *string `json:"utm_content,omitempty"`
+	UTMTerm             *string `json:"utm_term,omitempty"`
+	CampaignUTMCampaign string  `json:"campaign_utm_campaign,omitempty"`
+	CampaignUTMSource   string  `json:"campaign_utm_source,omitempty"`
+	CampaignUTMMedium   string  `json:"campaign_utm_medium,omitempty"`
+	CampaignUTMContent  string  `json:"campaign_utm_content,omitempty"`
+	CampaignUTMTerm     string  `json:"campaign_utm_term,omitempty"`
+}
+
+// CachedLinkRepository is a read-through cache in front of a link repository
+// FindByShortCode is served from the store, including negative entries for
+// unknown codes; writes made through this repository invalidate the affected
+// short codes. Cached links carry their workspace's redirect domain, their
+// campaign's window, out-of-window policy and UTM settings, their active
+// redirect rules and A/B variants, but no other preloaded relationships.
+// Entries are shared by all workspaces: a hit from another workspace than the
+// caller's reads as not found, and only unscoped misses are cached. Campaign
+// edits and product and campaign soft deletes and restores invalidate their
+// links through the service.LinkCache methods.
+type CachedLinkRepository struct {
+	service.LinkRepositoryInterface
+	store       Store
+	ttl         time.Duration
+	negativeTTL time.Duration
+	logger      logger.Logger
+}
+
+// NewCachedLinkRepository wraps next with a short code resolution cache
+func NewCachedLinkRepository(next service.LinkRepositoryInterface, store Store, ttl, negativeTTL time.Duration, log logger.Logger) *CachedLinkRepository {
+	return &CachedLinkRepository{
+		LinkRepositoryInterface: next,
+		store:                   store,
+		ttl:                     ttl,
+		negativeTTL:             negativeTTL,
+		logger:                  log,
+	}
+}
+
+// FindByShortCode resolves a short code from the cache, falling back to the wrapped repository
+func (r *CachedLinkRepository) FindByShortCode(ctx context.Context, shortCode string) (*model.Link, error) {
+	key := shortCodeKey(shortCode)
+
+	data, err := r.store.Get(ctx, key)
+	if err == nil {
+		var entry cachedLink
+		if err := json.Unmarshal(data, &entry); err == nil {
+			if !entry.Found {
+				return nil, gorm.ErrRecordNotFound
+			}
+			if id, ok := auth.WorkspaceFrom(ctx); ok && id != entry.WorkspaceID {
+				return nil, gorm.ErrRecordNotFound
+			}
+			if entry.ShortCode == "" {
+				entry.ShortCode = shortCode
+			}
+			return &model.Link{
+				ID:          entry.ID,
+				WorkspaceID: entry.WorkspaceID,
+				Workspace:   &model.Workspace{ID: entry.WorkspaceID, RedirectDomain: entry.RedirectDomain},
+				ProductID:   entry.ProductID,
+				CampaignID:  entry.CampaignID,
+				Marketplace: entry.Marketplace,
+				ShortCode:   entry.ShortCode,
+				TargetURL:   entry.TargetURL,
+				Status:      entry.Status,
+				ExpiresAt:   entry.ExpiresAt,
+				UTMSource:   entry.UTMSource,
+				UTMMedium:   entry.UTMMedium,
+				UTMContent:  entry.UTMContent,
+				UTMTerm:     entry.UTMTerm,
+				Campaign: model.Campaign{
+					ID:                entry.CampaignID,
+					UTMCampaign:       entry.CampaignUTMCampaign,
+					UTMSource:         entry.CampaignUTMSource,
+					UTMMedium:         entry.CampaignUTMMedium,
+					UTMContent:        entry.CampaignUTMContent,
+					UTMTerm:           entry.CampaignUTMTerm,
+					StartAt:           entry.CampaignStartAt,
+					EndAt:             entry.CampaignEndAt,
+					OutOfWindowPolicy: entry.OutOfWindowPolicy,
+					FallbackURL:       entry.FallbackURL,
+				},
+				RedirectRules: entry.RedirectRules,
+				Variants:      entry.Variants,
+			}, nil
+		}
+		r.logger.Warn("Discarding undecodable short code cache entry", logger.String("short_code", shortCode))
+	} else if !errors.Is(err, ErrMiss) {
+		// Cache failures must never break redirects
+		r.logger.Warn("Short code cache read failed", logger.Error(err), logger.String("short_code", shortCode))
+	}
+
+	link, err := r.LinkRepositoryInterface.FindByShortCode(ctx, shortCode)
+	if err != nil {
+		if _, scoped := auth.WorkspaceFrom(ctx); !scoped && errors.Is(err, gorm.ErrRecordNotFound) {
+			r.set(ctx, key, cachedLink{Found: false}, r.negativeTTL)
+		}
+		return nil, err
+	}
+
+	entry := cachedLink{
+		Found:       true,
+		ID:          link.ID,
+		WorkspaceID: link.WorkspaceID,
+		ShortCode:   link.ShortCode,
+		ProductID:   link.ProductID,
+		CampaignID:  link.CampaignID,
+		Marketplace: link.Marketplace,
+		TargetURL:   link.TargetURL,
+		Status:      link.Status,
+		ExpiresAt:   link.ExpiresAt,
+
+		CampaignStartAt:   link.Campaign.StartAt,
+		CampaignEndAt:     link.Campaign.EndAt,
+		OutOfWindowPolicy: link.Campaign.OutOfWindowPolicy,
+		FallbackURL:       link.Campaign.FallbackURL,
+		RedirectRules:     link.RedirectRules,
+		Variants:          link.Variants,
+
+		UTMSource:           link.UTMSource,
+		UTMMedium:           link.UTMMedium,
+		UTMContent:          link.UTMContent,
+		UTMTerm:             link.UTMTerm,
+		CampaignUTMCampaign: link.Campaign.UTMCampaign,
+		CampaignUTMSource:   link.Campaign.UTMSource,
+		CampaignUTMMedium:   link.Campaign.UTMMedium,
+		CampaignUTMContent:  link.Campaign.UTMContent,
+		CampaignUTMTerm:     link.Campaign.UTMTerm,
+	}
+	if link.Workspace != nil {
+		entry.RedirectDomain = link.Workspace.RedirectDomain
+	}
+	r.set(ctx, key, entry, r.ttl)
+
+	return link, nil
+}
+
+// Create creates a link and clears any negative entry for its short code
+func (r *CachedLinkRepository) Create(ctx context.Context, link *model.Link) error {
+	if err := r.LinkRepositoryInterface.Create(ctx, link); err != nil {
+		return err
+	}
+	r.invalidate(ctx, link.ShortCode)
+	return nil
+}
+
+// Update updates a link and invalidates its current and previous short codes and its aliases
+func (r *CachedLinkRepository) Update(ctx context.Context, link *model.Link) error {
+	var previous string
+	if existing, err := r.LinkRepositoryInterface.FindByID(ctx, link.ID); err == nil && existing.ShortCode != link.ShortCode {
+		previous = existing.ShortCode
+	}
+
+	if err := r.LinkRepositoryInterface.Update(ctx, link); err != nil {
+		return err
+	}
+	r.invalidate(ctx, previous)
+	r.invalidateLinks(ctx, []*model.Link{link})
+	return nil
+}
+
+// ChangeShortCode changes a link's short code and invalidates its previous and new codes
+func (r *CachedLinkRepository) ChangeShortCode(ctx context.Context, link *model.Link, shortCode string) error {
+	previous := link.ShortCode
+	if err := r.LinkRepositoryInterface.ChangeShortCode(ctx, link, shortCode); err != nil {
+		return err
+	}
+	r.invalidate(ctx, previous, shortCode)
+	return nil
+}
+
+// CreateAlias creates a link alias and clears any negative entry for its short code
+func (r *CachedLinkRepository) CreateAlias(ctx context.Context, alias *model.LinkAlias) error {
+	if err := r.LinkRepositoryInterface.CreateAlias(ctx, alias); err != nil {
+		return err
+	}
+	r.invalidate(ctx, alias.ShortCode)
+	return nil
+}
+
+// DeleteAlias deletes a link alias and invalidates its short code
+func (r *CachedLinkRepository) DeleteAlias(ctx context.Context, linkID uuid.UUID, shortCode string) error {
+	if err := r.LinkRepositoryInterface.DeleteAlias(ctx, linkID, shortCode); err != nil {
+		return err
+	}
+	r.invalidate(ctx, shortCode)
+	return nil
+}
+
+// CreateRedirectRule creates a redirect rule and invalidates the link's short codes
+func (r *CachedLinkRepository) CreateRedirectRule(ctx context.Context, rule *model.RedirectRule) error {
+	if err := r.LinkRepositoryInterface.CreateRedirectRule(ctx, rule); err != nil {
+		return err
+	}
+	r.invalidateLink(ctx, rule.LinkID)
+	return nil
+}
+
+// UpdateRedirectRule updates a redirect rule and invalidates the link's short codes
+func (r *CachedLinkRepository) UpdateRedirectRule(ctx context.Context, rule *model.RedirectRule) error {
+	if err := r.LinkRepositoryInterface.UpdateRedirectRule(ctx, rule); err != nil {
+		return err
+	}
+	r.invalidateLink(ctx, rule.LinkID)
+	return nil
+}
+
+// DeleteRedirectRule deletes a redirect rule and invalidates the link's short codes
+func (r *CachedLinkRepository) DeleteRedirectRule(ctx context.Context, linkID, ruleID uuid.UUID) error {
+	if err := r.LinkRepositoryInterface.DeleteRedirectRule(ctx, linkID, ruleID); err != nil {
+		return err
+	}
+	r.invalidateLink(ctx, linkID)
+	return nil
+}
+
+// CreateVariant creates an A/B variant and invalidates the link's short codes
+func (r *CachedLinkRepository) CreateVariant(ctx context.Context, variant *model.LinkVariant) error {
+	if err := r.LinkRepositoryInterface.CreateVariant(ctx, variant); err != nil {
+		return err
+	}
+	r.invalidateLink(ctx, variant.LinkID)
+	return nil
+}
+
+// UpdateVariant updates an A/B variant and invalidates the link's short codes
+func (r *CachedLinkRepository) UpdateVariant(ctx context.Context, variant *model.LinkVariant) error {
+	if err := r.LinkRepositoryInterface.UpdateVariant(ctx, variant); err != nil {
+		return err
+	}
+	r.invalidateLink(ctx, variant.LinkID)
+	return nil
+}
+
+// DeleteVariant deletes an A/B variant and invalidates the link's short codes
+func (r *CachedLinkRepository) DeleteVariant(ctx context.Context, linkID, variantID uuid.UUID) error {
+	if err := r.LinkRepositoryInterface.DeleteVariant(ctx, linkID, variantID); err != nil {
+		return err
+	}
+	r.invalidateLink(ctx, linkID)
+	return nil
+}
+
+// Delete deletes a link and invalidates its short code and aliases
+func (r *CachedLinkRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	existing, findErr := r.LinkRepositoryInterface.FindByID(ctx, id)
+
+	if err := r.LinkRepositoryInterface.Delete(ctx, id); err != nil {
+		return err
+	}
+	if findErr == nil {
+		r.invalidateLinks(ctx, []*model.Link{existing})
+	}
+	return nil
+}
+
+// DeleteByProductIDAndCampaignID deletes a product's links in a campaign and invalidates their short codes and aliases
+func (r *CachedLinkRepository) DeleteByProductIDAndCampaignID(ctx context.Context, productID, campaignID uuid.UUID) error {
+	links, findErr := r.LinkRepositoryInterface.FindByProductIDAndCampaignID(ctx, productID, campaignID)
+
+	if err := r.LinkRepositoryInterface.DeleteByProductIDAndCampaignID(ctx, productID, campaignID); err != nil {
+		return err
+	}
+	if findErr == nil {
+		r.invalidateLinks(ctx, links)
+	}
+	return nil
+}
+
+// DeleteByCampaignIDAndNotInProducts deletes a campaign's links for other products and invalidates their short codes and aliases
+func (r *CachedLinkRepository) DeleteByCampaignIDAndNotInProducts(ctx context.Context, campaignID uuid.UUID, productIDs []uuid.UUID) error {
+	links, findErr := r.LinkRepositoryInterface.FindByCampaignID(ctx, campaignID)
+
+	if err := r.LinkRepositoryInterface.DeleteByCampaignIDAndNotInProducts(ctx, campaignID, productIDs); err != nil {
+		return err
+	}
+	if findErr == nil {
+		keep := make(map[uuid.UUID]bool, len(productIDs))
+		for _, id := range productIDs {
+			keep[id] = true
+		}
+		removed := make([]*model.Link, 0, len(links))
+		for _, link := range links {
+			if !keep[link.ProductID] {
+				removed = append(removed, link)
+			}
+		}
+		r.invalidateLinks(ctx, removed)
+	}
+	return nil
+}
+
+// InvalidateProductLinks invalidates the short codes and aliases of a product's links
+func (r *CachedLinkRepository) InvalidateProductLinks(ctx context.Context, productID uuid.UUID) {
+	links, err := r.LinkRepositoryInterface.FindByProductID(ctx, productID)
+	if err != nil {
+		r.logger.Warn("Short code cache invalidation failed", logger.Error(err), logger.String("product_id", productID.String()))
+		return
+	}
+	r.invalidateLinks(ctx, links)
+}
+
+// InvalidateCampaignLinks invalidates the short codes and aliases of a campaign's links
+func (r *CachedLinkRepository) InvalidateCampaignLinks(ctx context.Context, campaignID uuid.UUID) {
+	links, err := r.LinkRepositoryInterface.FindByCampaignID(ctx, campaignID)
+	if err != nil {
+		r.logger.Warn("Short code cache invalidation failed", logger.Error(err), logger.String("campaign_id", campaignID.String()))
+		return
+	}
+	r.invalidateLinks(ctx, links)
+}
+
+// set stores an entry, logging (not returning) failures
+func (r *CachedLinkRepository) set(ctx context.Context, key string, entry cachedLink, ttl time.Duration) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := r.store.Set(ctx, key, data, ttl); err != nil {
+		r.logger.Warn("Short code cache write failed", logger.Error(err), logger.String("key", key))
+	}
+}
+
+// invalidateLink removes a link's short code and aliases from the cache, logging (not returning) failures
+func (r *CachedLinkRepository) invalidateLink(ctx context.Context, linkID uuid.UUID) {
+	link, err := r.LinkRepositoryInterface.FindByID(ctx, linkID)
+	if err != nil {
+		r.logger.Warn("Short code cache invalidation failed", logger.Error(err), logger.String("link_id", linkID.String()))
+		return
+	}
+	shortCodes := []string{link.ShortCode}
+	if aliases, err := r.LinkRepositoryInterface.FindAliasesByLinkID(ctx, linkID); err == nil {
+		for _, alias := range aliases {
+			shortCodes = append(shortCodes, alias.ShortCode)
+		}
+	}
+	r.invalidate(ctx, shortCodes...)
+}
+
+// invalidateLinks removes links' short codes and aliases from the cache, logging (not returning) failures
+func (r *CachedLinkRepository) invalidateLinks(ctx context.Context, links []*model.Link) {
+	shortCodes := make([]string, 0, len(links))
+	for _, link := range links {
+		shortCodes = append(shortCodes, link.ShortCode)
+		if aliases, err := r.LinkRepositoryInterface.FindAliasesByLinkID(ctx, link.ID); err == nil {
+			for _, alias := range aliases {
+				shortCodes = append(shortCodes, alias.ShortCode)
+			}
+		}
+	}
+	r.invalidate(ctx, shortCodes...)
+}
+
+// invalidate removes short codes from the cache, logging (not returning) failures
+func (r *CachedLinkRepository) invalidate(ctx context.Context, shortCodes ...string) {
+	keys := make([]string, 0, len(shortCodes))
+	for _, code := range shortCodes {
+		if code != "" {
+			keys = append(keys, shortCodeKey(code))
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	if err := r.store.Delete(ctx, keys...); err != nil {
+		r.logger.Warn("Short code cache invalidation failed", logger.Error(err), logger.Int("keys", len(keys)))
+	}
+}
+
+func shortCodeKey(shortCode string) string {
+	return shortCodeKeyPrefix + shortCode
+}
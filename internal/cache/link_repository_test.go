@@ -0,0 +1,498 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	"github.com/jonosize/affiliate-platform/internal/service"
+)
+
+// fakeLinkRepository is an in-memory link repository that counts short code lookups
+// Methods not overridden panic through the nil embedded interface
+type fakeLinkRepository struct {
+	service.LinkRepositoryInterface
+	links   map[uuid.UUID]*model.Link
+	aliases map[string]uuid.UUID
+	deleted map[uuid.UUID]bool // Soft-deleted products and campaigns, whose links short code lookups hide
+	lookups int
+}
+
+func newFakeLinkRepository(links ...*model.Link) *fakeLinkRepository {
+	repo := &fakeLinkRepository{
+		links:   make(map[uuid.UUID]*model.Link),
+		aliases: make(map[string]uuid.UUID),
+		deleted: make(map[uuid.UUID]bool),
+	}
+	for _, link := range links {
+		repo.links[link.ID] = link
+	}
+	return repo
+}
+
+func (f *fakeLinkRepository) FindByShortCode(ctx context.Context, shortCode string) (*model.Link, error) {
+	f.lookups++
+	for _, link := range f.links {
+		if id, ok := auth.WorkspaceFrom(ctx); ok && link.WorkspaceID != id {
+			continue
+		}
+		if link.ShortCode == shortCode && f.live(link) {
+			copied := *link
+			return &copied, nil
+		}
+	}
+	if link, ok := f.links[f.aliases[shortCode]]; ok && f.live(link) {
+		copied := *link
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeLinkRepository) live(link *model.Link) bool {
+	return !f.deleted[link.ProductID] && !f.deleted[link.CampaignID]
+}
+
+func (f *fakeLinkRepository) FindByID(_ context.Context, id uuid.UUID) (*model.Link, error) {
+	link, ok := f.links[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *link
+	return &copied, nil
+}
+
+func (f *fakeLinkRepository) FindByCampaignID(_ context.Context, campaignID uuid.UUID) ([]*model.Link, error) {
+	var links []*model.Link
+	for _, link := range f.links {
+		if link.CampaignID == campaignID {
+			copied := *link
+			links = append(links, &copied)
+		}
+	}
+	return links, nil
+}
+
+func (f *fakeLinkRepository) FindByProductID(_ context.Context, productID uuid.UUID) ([]*model.Link, error) {
+	var links []*model.Link
+	for _, link := range f.links {
+		if link.ProductID == productID {
+			copied := *link
+			links = append(links, &copied)
+		}
+	}
+	return links, nil
+}
+
+func (f *fakeLinkRepository) Create(_ context.Context, link *model.Link) error {
+	copied := *link
+	f.links[link.ID] = &copied
+	return nil
+}
+
+func (f *fakeLinkRepository) Update(_ context.Context, link *model.Link) error {
+	copied := *link
+	f.links[link.ID] = &copied
+	return nil
+}
+
+func (f *fakeLinkRepository) ChangeShortCode(_ context.Context, link *model.Link, shortCode string) error {
+	delete(f.aliases, shortCode)
+	f.aliases[link.ShortCode] = link.ID
+	f.links[link.ID].ShortCode = shortCode
+	link.ShortCode = shortCode
+	return nil
+}
+
+func (f *fakeLinkRepository) CreateAlias(_ context.Context, alias *model.LinkAlias) error {
+	f.aliases[alias.ShortCode] = alias.LinkID
+	return nil
+}
+
+func (f *fakeLinkRepository) FindAliasesByLinkID(_ context.Context, linkID uuid.UUID) ([]*model.LinkAlias, error) {
+	var aliases []*model.LinkAlias
+	for code, id := range f.aliases {
+		if id == linkID {
+			aliases = append(aliases, &model.LinkAlias{LinkID: id, ShortCode: code})
+		}
+	}
+	return aliases, nil
+}
+
+func (f *fakeLinkRepository) CreateRedirectRule(_ context.Context, rule *model.RedirectRule) error {
+	link := f.links[rule.LinkID]
+	link.RedirectRules = append(link.RedirectRules, *rule)
+	return nil
+}
+
+func (f *fakeLinkRepository) DeleteAlias(_ context.Context, _ uuid.UUID, shortCode string) error {
+	delete(f.aliases, shortCode)
+	return nil
+}
+
+func (f *fakeLinkRepository) Delete(_ context.Context, id uuid.UUID) error {
+	delete(f.links, id)
+	return nil
+}
+
+func (f *fakeLinkRepository) FindByProductIDAndCampaignID(_ context.Context, productID, campaignID uuid.UUID) ([]*model.Link, error) {
+	var links []*model.Link
+	for _, link := range f.links {
+		if link.ProductID == productID && link.CampaignID == campaignID {
+			copied := *link
+			links = append(links, &copied)
+		}
+	}
+	return links, nil
+}
+
+func (f *fakeLinkRepository) DeleteByProductIDAndCampaignID(_ context.Context, productID, campaignID uuid.UUID) error {
+	for id, link := range f.links {
+		if link.ProductID == productID && link.CampaignID == campaignID {
+			delete(f.links, id)
+		}
+	}
+	return nil
+}
+
+func (f *fakeLinkRepository) DeleteByCampaignIDAndNotInProducts(_ context.Context, campaignID uuid.UUID, productIDs []uuid.UUID) error {
+	keep := make(map[uuid.UUID]bool)
+	for _, id := range productIDs {
+		keep[id] = true
+	}
+	for id, link := range f.links {
+		if link.CampaignID == campaignID && !keep[link.ProductID] {
+			delete(f.links, id)
+		}
+	}
+	return nil
+}
+
+// stores returns both store implementations so every test runs against each
+func stores(t *testing.T) map[string]Store {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return map[string]Store{
+		"redis": NewRedisStore(client),
+		"lru":   NewLRUStore(100),
+	}
+}
+
+func newTestLink(campaignID uuid.UUID) *model.Link {
+	return &model.Link{
+		ID:          uuid.New(),
+		ProductID:   uuid.New(),
+		CampaignID:  campaignID,
+		Marketplace: model.MarketplaceLazada,
+		ShortCode:   "abc123",
+		TargetURL:   "https://www.lazada.co.th/products/item.html?utm_campaign=old",
+	}
+}
+
+func TestCachedLinkRepository(t *testing.T) {
+	ctx := context.Background()
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			t.Run("read-through caches hits", func(t *testing.T) {
+				link := newTestLink(uuid.New())
+				expiresAt := time.Date(2025, 8, 31, 23, 59, 59, 0, time.UTC)
+				link.Status = model.LinkStatusPaused
+				link.ExpiresAt = &expiresAt
+				utmSource := "instagram"
+				link.UTMSource = &utmSource
+				link.Campaign = model.Campaign{
+					ID:                link.CampaignID,
+					UTMCampaign:       "summer",
+					UTMSource:         "affiliate",
+					UTMContent:        "{{marketplace}}",
+					StartAt:           time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+					EndAt:             expiresAt,
+					OutOfWindowPolicy: model.OutOfWindowFallback,
+					FallbackURL:       "https://shop.example.com/offers",
+				}
+				next := newFakeLinkRepository(link)
+				repo := NewCachedLinkRepository(next, store, time.Minute, time.Minute, log)
+				t.Cleanup(func() { _ = store.Delete(ctx, shortCodeKey(link.ShortCode)) })
+
+				for i := 0; i < 3; i++ {
+					got, err := repo.FindByShortCode(ctx, link.ShortCode)
+					require.NoError(t, err)
+					assert.Equal(t, link.ID, got.ID)
+					assert.Equal(t, link.TargetURL, got.TargetURL)
+					// Lifecycle and campaign window survive the round trip for redirect enforcement
+					assert.Equal(t, link.Status, got.Status)
+					require.NotNil(t, got.ExpiresAt)
+					assert.True(t, expiresAt.Equal(*got.ExpiresAt))
+					assert.True(t, link.Campaign.StartAt.Equal(got.Campaign.StartAt))
+					assert.True(t, link.Campaign.EndAt.Equal(got.Campaign.EndAt))
+					assert.Equal(t, link.Campaign.OutOfWindowPolicy, got.Campaign.OutOfWindowPolicy)
+					assert.Equal(t, link.Campaign.FallbackURL, got.Campaign.FallbackURL)
+					// UTM settings too, for best-price links built on redirect
+					assert.Equal(t, link.UTMSource, got.UTMSource)
+					assert.Equal(t, link.Campaign.UTMCampaign, got.Campaign.UTMCampaign)
+					assert.Equal(t, link.Campaign.UTMSource, got.Campaign.UTMSource)
+					assert.Equal(t, link.Campaign.UTMContent, got.Campaign.UTMContent)
+				}
+				assert.Equal(t, 1, next.lookups)
+			})
+
+			t.Run("negative caching until create", func(t *testing.T) {
+				next := newFakeLinkRepository()
+				repo := NewCachedLinkRepository(next, store, time.Minute, time.Minute, log)
+
+				for i := 0; i < 2; i++ {
+					_, err := repo.FindByShortCode(ctx, "unknown")
+					assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				}
+				assert.Equal(t, 1, next.lookups)
+
+				link := newTestLink(uuid.New())
+				link.ShortCode = "unknown"
+				require.NoError(t, repo.Create(ctx, link))
+				t.Cleanup(func() { _ = store.Delete(ctx, shortCodeKey(link.ShortCode)) })
+
+				got, err := repo.FindByShortCode(ctx, "unknown")
+				require.NoError(t, err)
+				assert.Equal(t, link.ID, got.ID)
+			})
+
+			t.Run("entries are shared across workspaces", func(t *testing.T) {
+				link := newTestLink(uuid.New())
+				link.WorkspaceID = uuid.New()
+				link.Workspace = &model.Workspace{ID: link.WorkspaceID, RedirectDomain: "go.gadgetdeals.example"}
+				next := newFakeLinkRepository(link)
+				repo := NewCachedLinkRepository(next, store, time.Minute, time.Minute, log)
+				t.Cleanup(func() { _ = store.Delete(ctx, shortCodeKey(link.ShortCode), shortCodeKey("other")) })
+
+				// Public redirects resolve any workspace's links, with their redirect domain
+				got, err := repo.FindByShortCode(ctx, link.ShortCode)
+				require.NoError(t, err)
+				got, err = repo.FindByShortCode(ctx, link.ShortCode)
+				require.NoError(t, err)
+				assert.Equal(t, link.WorkspaceID, got.WorkspaceID)
+				require.NotNil(t, got.Workspace)
+				assert.Equal(t, "go.gadgetdeals.example", got.Workspace.RedirectDomain)
+
+				// Other workspaces don't see the cached link
+				_, err = repo.FindByShortCode(auth.WithWorkspace(ctx, uuid.New()), link.ShortCode)
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				got, err = repo.FindByShortCode(auth.WithWorkspace(ctx, link.WorkspaceID), link.ShortCode)
+				require.NoError(t, err)
+				assert.Equal(t, link.ID, got.ID)
+				assert.Equal(t, 1, next.lookups)
+
+				// A miss in one workspace is not cached for everyone
+				_, err = repo.FindByShortCode(auth.WithWorkspace(ctx, uuid.New()), "other")
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				_, err = repo.FindByShortCode(ctx, "other")
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				assert.Equal(t, 3, next.lookups)
+			})
+
+			t.Run("update invalidates the short code and aliases", func(t *testing.T) {
+				link := newTestLink(uuid.New())
+				next := newFakeLinkRepository(link)
+				next.aliases["matcha"] = link.ID
+				repo := NewCachedLinkRepository(next, store, time.Minute, time.Minute, log)
+				t.Cleanup(func() { _ = store.Delete(ctx, shortCodeKey(link.ShortCode), shortCodeKey("matcha")) })
+
+				for _, code := range []string{link.ShortCode, "matcha"} {
+					_, err := repo.FindByShortCode(ctx, code)
+					require.NoError(t, err)
+				}
+
+				updated := *link
+				updated.TargetURL = "https://www.lazada.co.th/products/item.html?utm_campaign=new"
+				updated.Status = model.LinkStatusPaused
+				require.NoError(t, repo.Update(ctx, &updated))
+
+				for _, code := range []string{link.ShortCode, "matcha"} {
+					got, err := repo.FindByShortCode(ctx, code)
+					require.NoError(t, err)
+					assert.Equal(t, updated.TargetURL, got.TargetURL, code)
+					assert.Equal(t, model.LinkStatusPaused, got.Status, code)
+				}
+			})
+
+			t.Run("delete invalidates the short code and aliases", func(t *testing.T) {
+				link := newTestLink(uuid.New())
+				next := newFakeLinkRepository(link)
+				next.aliases["matcha"] = link.ID
+				repo := NewCachedLinkRepository(next, store, time.Minute, time.Minute, log)
+				t.Cleanup(func() { _ = store.Delete(ctx, shortCodeKey(link.ShortCode), shortCodeKey("matcha")) })
+
+				for _, code := range []string{link.ShortCode, "matcha"} {
+					_, err := repo.FindByShortCode(ctx, code)
+					require.NoError(t, err)
+				}
+				require.NoError(t, repo.Delete(ctx, link.ID))
+
+				for _, code := range []string{link.ShortCode, "matcha"} {
+					_, err := repo.FindByShortCode(ctx, code)
+					assert.ErrorIs(t, err, gorm.ErrRecordNotFound, code)
+				}
+			})
+
+			t.Run("short code changes and aliases invalidate", func(t *testing.T) {
+				link := newTestLink(uuid.New())
+				next := newFakeLinkRepository(link)
+				repo := NewCachedLinkRepository(next, store, time.Minute, time.Minute, log)
+				t.Cleanup(func() {
+					_ = store.Delete(ctx, shortCodeKey("abc123"), shortCodeKey("matcha-summer"), shortCodeKey("matcha-ig"))
+				})
+
+				// Prime a positive entry for the old code and negative entries for the new ones
+				_, err := repo.FindByShortCode(ctx, "abc123")
+				require.NoError(t, err)
+				for _, code := range []string{"matcha-summer", "matcha-ig"} {
+					_, err := repo.FindByShortCode(ctx, code)
+					assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				}
+
+				require.NoError(t, repo.ChangeShortCode(ctx, link, "matcha-summer"))
+				require.NoError(t, repo.CreateAlias(ctx, &model.LinkAlias{LinkID: link.ID, ShortCode: "matcha-ig"}))
+
+				for _, code := range []string{"abc123", "matcha-summer", "matcha-ig"} {
+					got, err := repo.FindByShortCode(ctx, code)
+					require.NoError(t, err, code)
+					assert.Equal(t, link.ID, got.ID)
+				}
+
+				require.NoError(t, repo.DeleteAlias(ctx, link.ID, "matcha-ig"))
+				_, err = repo.FindByShortCode(ctx, "matcha-ig")
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			})
+
+			t.Run("redirect rule writes invalidate the short code and aliases", func(t *testing.T) {
+				link := newTestLink(uuid.New())
+				next := newFakeLinkRepository(link)
+				next.aliases["matcha"] = link.ID
+				repo := NewCachedLinkRepository(next, store, time.Minute, time.Minute, log)
+				t.Cleanup(func() { _ = store.Delete(ctx, shortCodeKey(link.ShortCode), shortCodeKey("matcha")) })
+
+				for _, code := range []string{link.ShortCode, "matcha"} {
+					got, err := repo.FindByShortCode(ctx, code)
+					require.NoError(t, err)
+					assert.Empty(t, got.RedirectRules)
+				}
+
+				require.NoError(t, repo.CreateRedirectRule(ctx, &model.RedirectRule{
+					ID:         uuid.New(),
+					LinkID:     link.ID,
+					OS:         []string{"ios"},
+					TargetType: model.RedirectTargetDeepLink,
+					TargetURL:  "lazada://th/d?p=123",
+					IsActive:   true,
+				}))
+
+				for _, code := range []string{link.ShortCode, "matcha"} {
+					got, err := repo.FindByShortCode(ctx, code)
+					require.NoError(t, err)
+					require.Len(t, got.RedirectRules, 1, code)
+					assert.Equal(t, []string{"ios"}, got.RedirectRules[0].OS)
+				}
+			})
+
+			t.Run("campaign sync delete invalidates removed products only", func(t *testing.T) {
+				campaignID := uuid.New()
+				kept := newTestLink(campaignID)
+				kept.ShortCode = "kept01"
+				removed := newTestLink(campaignID)
+				removed.ShortCode = "gone01"
+				next := newFakeLinkRepository(kept, removed)
+				next.aliases["gone-alias"] = removed.ID
+				repo := NewCachedLinkRepository(next, store, time.Minute, time.Minute, log)
+				t.Cleanup(func() {
+					_ = store.Delete(ctx, shortCodeKey(kept.ShortCode), shortCodeKey(removed.ShortCode), shortCodeKey("gone-alias"))
+				})
+
+				for _, code := range []string{kept.ShortCode, removed.ShortCode, "gone-alias"} {
+					_, err := repo.FindByShortCode(ctx, code)
+					require.NoError(t, err)
+				}
+
+				require.NoError(t, repo.DeleteByCampaignIDAndNotInProducts(ctx, campaignID, []uuid.UUID{kept.ProductID}))
+
+				for _, code := range []string{removed.ShortCode, "gone-alias"} {
+					_, err := repo.FindByShortCode(ctx, code)
+					assert.ErrorIs(t, err, gorm.ErrRecordNotFound, code)
+				}
+				_, err := repo.FindByShortCode(ctx, kept.ShortCode)
+				require.NoError(t, err)
+				assert.Equal(t, 5, next.lookups) // kept stayed cached
+			})
+
+			t.Run("campaign product removal invalidates the short codes and aliases", func(t *testing.T) {
+				link := newTestLink(uuid.New())
+				next := newFakeLinkRepository(link)
+				next.aliases["matcha"] = link.ID
+				repo := NewCachedLinkRepository(next, store, time.Minute, time.Minute, log)
+				t.Cleanup(func() { _ = store.Delete(ctx, shortCodeKey(link.ShortCode), shortCodeKey("matcha")) })
+
+				for _, code := range []string{link.ShortCode, "matcha"} {
+					_, err := repo.FindByShortCode(ctx, code)
+					require.NoError(t, err)
+				}
+
+				require.NoError(t, repo.DeleteByProductIDAndCampaignID(ctx, link.ProductID, link.CampaignID))
+
+				for _, code := range []string{link.ShortCode, "matcha"} {
+					_, err := repo.FindByShortCode(ctx, code)
+					assert.ErrorIs(t, err, gorm.ErrRecordNotFound, code)
+				}
+			})
+
+			t.Run("product and campaign deletes and restores invalidate links and aliases", func(t *testing.T) {
+				link := newTestLink(uuid.New())
+				next := newFakeLinkRepository(link)
+				next.aliases["matcha"] = link.ID
+				repo := NewCachedLinkRepository(next, store, time.Minute, time.Minute, log)
+				t.Cleanup(func() { _ = store.Delete(ctx, shortCodeKey(link.ShortCode), shortCodeKey("matcha")) })
+
+				for _, parent := range []struct {
+					name       string
+					id         uuid.UUID
+					invalidate func()
+				}{
+					{"product", link.ProductID, func() { repo.InvalidateProductLinks(ctx, link.ProductID) }},
+					{"campaign", link.CampaignID, func() { repo.InvalidateCampaignLinks(ctx, link.CampaignID) }},
+				} {
+					for _, code := range []string{link.ShortCode, "matcha"} {
+						_, err := repo.FindByShortCode(ctx, code)
+						require.NoError(t, err, parent.name)
+					}
+
+					next.deleted[parent.id] = true
+					parent.invalidate()
+					for _, code := range []string{link.ShortCode, "matcha"} {
+						_, err := repo.FindByShortCode(ctx, code)
+						assert.ErrorIs(t, err, gorm.ErrRecordNotFound, parent.name)
+					}
+
+					// Restoring clears the negative entries the deletion left behind
+					delete(next.deleted, parent.id)
+					parent.invalidate()
+					for _, code := range []string{link.ShortCode, "matcha"} {
+						got, err := repo.FindByShortCode(ctx, code)
+						require.NoError(t, err, parent.name)
+						assert.Equal(t, link.ID, got.ID)
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultLRUSize is used when a non-positive capacity is given
+const defaultLRUSize = 10000
+
+// LRUStore is an in-process Store that evicts the least recently used entry when full
+type LRUStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front = most recently used
+	entries  map[string]*list.Element
+	now      func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRUStore creates a new in-process LRU store holding at most capacity entries
+func NewLRUStore(capacity int) *LRUStore {
+	if capacity <= 0 {
+		capacity = defaultLRUSize
+	}
+	return &LRUStore{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+// Get returns the cached value for key, or ErrMiss if absent or expired
+func (s *LRUStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && !s.now().Before(entry.expiresAt) {
+		s.removeElement(elem)
+		return nil, ErrMiss
+	}
+
+	s.order.MoveToFront(elem)
+	return entry.value, nil
+}
+
+// Set caches value under key for ttl (no expiry when ttl is zero)
+func (s *LRUStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(key, value, ttl)
+	return nil
+}
+
+// set stores an entry and evicts the least recently used ones; callers hold mu
+func (s *LRUStore) set(key string, value []byte, ttl time.Duration) {
+	expiresAt := time.Time{}
+	if ttl > 0 {
+		expiresAt = s.now().Add(ttl)
+	}
+
+	if elem, ok := s.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		s.order.MoveToFront(elem)
+		return
+	}
+
+	s.entries[key] = s.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for s.order.Len() > s.capacity {
+		s.removeElement(s.order.Back())
+	}
+}
+
+// Add caches value under key for ttl only if key is absent or expired
+func (s *LRUStore) Add(_ context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		if entry.expiresAt.IsZero() || s.now().Before(entry.expiresAt) {
+			return false, nil
+		}
+		s.removeElement(elem)
+	}
+
+	s.set(key, value, ttl)
+	return true, nil
+}
+
+// Delete removes keys from the cache
+func (s *LRUStore) Delete(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := s.entries[key]; ok {
+			s.removeElement(elem)
+		}
+	}
+	return nil
+}
+
+// Close is a no-op for the in-process store
+func (s *LRUStore) Close() error {
+	return nil
+}
+
+// Len returns the number of cached entries, including expired ones not yet evicted
+func (s *LRUStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+func (s *LRUStore) removeElement(elem *list.Element) {
+	s.order.Remove(elem)
+	delete(s.entries, elem.Value.(*lruEntry).key)
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	store := NewLRUStore(2)
+
+	require.NoError(t, store.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, store.Set(ctx, "b", []byte("2"), 0))
+
+	// Touch "a" so "b" becomes the least recently used entry
+	_, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Set(ctx, "c", []byte("3"), 0))
+
+	_, err = store.Get(ctx, "b")
+	assert.ErrorIs(t, err, ErrMiss)
+	value, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+	assert.Equal(t, 2, store.Len())
+}
+
+func TestLRUStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	store := NewLRUStore(10)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Set(ctx, "a", []byte("1"), time.Minute))
+
+	_, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	_, err = store.Get(ctx, "a")
+	assert.ErrorIs(t, err, ErrMiss)
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestLRUStore_Delete(t *testing.T) {
+	ctx := context.Background()
+	store := NewLRUStore(10)
+
+	require.NoError(t, store.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, store.Set(ctx, "b", []byte("2"), 0))
+	require.NoError(t, store.Delete(ctx, "a", "missing"))
+
+	_, err := store.Get(ctx, "a")
+	assert.ErrorIs(t, err, ErrMiss)
+	_, err = store.Get(ctx, "b")
+	assert.NoError(t, err)
+}
+
+func TestLRUStore_Add(t *testing.T) {
+	ctx := context.Background()
+	store := NewLRUStore(10)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	added, err := store.Add(ctx, "a", []byte("1"), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = store.Add(ctx, "a", []byte("2"), time.Minute)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	// Expired entries can be added again
+	now = now.Add(time.Minute)
+	added, err = store.Add(ctx, "a", []byte("3"), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	value, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("3"), value)
+}
@@ -30,22 +30,56 @@ type Config interface {
 	// Redis
 	GetRedisURL() string
 
+	// Cache
+	GetShortCodeCacheTTL() int         // seconds
+	GetShortCodeCacheNegativeTTL() int // seconds
+	GetShortCodeCacheSize() int        // in-process LRU capacity
+
+	// Tracking
+	GetBotExcludedIPRanges() []string  // CIDRs or single IPs flagged as bot traffic
+	GetBotUserAgentPatterns() []string // extra user agent substrings flagged as bot traffic
+	GetVisitorSalt() string            // secret for IP + user agent visitor fingerprints
+	GetVisitorCookieName() string      // first-party visitor cookie set on /go/:short_code
+	GetClickDedupeWindow() int         // seconds; 0 disables click de-duplication
+	GetClickDedupeCacheSize() int      // in-process dedupe entries (used when redis.url is empty)
+	GetGeoIPDatabasePath() string      // MaxMind-format country database for redirect rules; empty disables
+
+	// Postbacks
+	GetPostbackSecret(network string) string // shared secret for /api/postbacks/:network; empty rejects the network
+
 	// Server
 	GetServerPort() string
 	GetServerHost() string
 
 	// API
 	GetAPIBaseURL() string
+	GetQRLogoPath() string // PNG or JPEG logo for link QR codes (?logo=true); empty disables
 
 	// Worker
 	GetPriceRefreshCron() string
+	GetClickQueueSize() int
+	GetClickIngestWorkers() int
+	GetClickBatchSize() int
+	GetClickFlushInterval() int  // milliseconds
+	GetClickEnqueueTimeout() int // milliseconds
 
 	// Adapters
 	GetMockMode() bool
-
-	// Authentication (Basic Auth)
-	GetBasicAuthUsername() string
+	GetLazadaAppKey() string
+	GetLazadaAppSecret() string
+	GetLazadaAccessToken() string
+	GetLazadaAPIURL() string
+	GetShopeePartnerID() string
+	GetShopeePartnerKey() string
+	GetShopeeShopID() string
+	GetShopeeAccessToken() string
+	GetShopeeAPIURL() string
+
+	// Authentication
+	GetBasicAuthUsername() string // bootstrap admin credentials; empty disables Basic auth
 	GetBasicAuthPassword() string
+	GetSessionSecret() string // HMAC key for user session tokens; empty: random per process
+	GetSessionTTL() int       // minutes
 
 	// All settings
 	GetAllSettings() map[string]interface{}
@@ -75,6 +109,36 @@ type AppConfig struct {
 		URL string `json:"url" mapstructure:"url"`
 	} `json:"redis" mapstructure:"redis"`
 
+	Cache struct {
+		ShortCode struct {
+			TTL         int `json:"ttl" mapstructure:"ttl"`                   // seconds
+			NegativeTTL int `json:"negative_ttl" mapstructure:"negative_ttl"` // seconds
+			Size        int `json:"size" mapstructure:"size"`                 // in-process LRU capacity (used when redis.url is empty)
+		} `json:"short_code" mapstructure:"short_code"`
+	} `json:"cache" mapstructure:"cache"`
+
+	Tracking struct {
+		BotFilter struct {
+			ExcludedIPRanges  []string `json:"excluded_ip_ranges" mapstructure:"excluded_ip_ranges"`   // e.g. office or monitoring networks
+			UserAgentPatterns []string `json:"user_agent_patterns" mapstructure:"user_agent_patterns"` // added to the built-in bot list
+		} `json:"bot_filter" mapstructure:"bot_filter"`
+		Visitor struct {
+			Salt       string `json:"salt" mapstructure:"salt"`               // Keep secret and identical across API instances
+			CookieName string `json:"cookie_name" mapstructure:"cookie_name"` // First-party visitor cookie
+		} `json:"visitor" mapstructure:"visitor"`
+		Dedupe struct {
+			WindowSeconds int `json:"window_seconds" mapstructure:"window_seconds"` // Repeat clicks within the window are not unique
+			CacheSize     int `json:"cache_size" mapstructure:"cache_size"`         // in-process LRU capacity (used when redis.url is empty)
+		} `json:"dedupe" mapstructure:"dedupe"`
+		GeoIP struct {
+			DatabasePath string `json:"database_path" mapstructure:"database_path"` // MaxMind-format country database (e.g. GeoLite2-Country.mmdb)
+		} `json:"geoip" mapstructure:"geoip"`
+	} `json:"tracking" mapstructure:"tracking"`
+
+	Postbacks struct {
+		Secrets map[string]string `json:"secrets" mapstructure:"secrets"` // network name -> shared signing secret
+	} `json:"postbacks" mapstructure:"postbacks"`
+
 	Server struct {
 		Port string `json:"port" mapstructure:"port"`
 		Host string `json:"host" mapstructure:"host"`
@@ -82,14 +146,37 @@ type AppConfig struct {
 
 	API struct {
 		BaseURL string `json:"base_url" mapstructure:"base_url"`
+		QR      struct {
+			LogoPath string `json:"logo_path" mapstructure:"logo_path"` // PNG or JPEG drawn on QR codes requested with a logo
+		} `json:"qr" mapstructure:"qr"`
 	} `json:"api" mapstructure:"api"`
 
 	Worker struct {
 		PriceRefreshCron string `json:"price_refresh_cron" mapstructure:"price_refresh_cron"`
+		ClickIngestor    struct {
+			QueueSize        int `json:"queue_size" mapstructure:"queue_size"`
+			Workers          int `json:"workers" mapstructure:"workers"`
+			BatchSize        int `json:"batch_size" mapstructure:"batch_size"`
+			FlushIntervalMs  int `json:"flush_interval_ms" mapstructure:"flush_interval_ms"`
+			EnqueueTimeoutMs int `json:"enqueue_timeout_ms" mapstructure:"enqueue_timeout_ms"` // How long a redirect waits for queue space before dropping the click
+		} `json:"click_ingestor" mapstructure:"click_ingestor"`
 	} `json:"worker" mapstructure:"worker"`
 
 	Adapters struct {
 		MockMode bool `json:"mock_mode" mapstructure:"mock_mode"`
+		Lazada   struct {
+			AppKey      string `json:"app_key" mapstructure:"app_key"`
+			AppSecret   string `json:"app_secret" mapstructure:"app_secret"`
+			AccessToken string `json:"access_token" mapstructure:"access_token"`
+			APIURL      string `json:"api_url,omitempty" mapstructure:"api_url"` // Region API URL (e.g. https://api.lazada.co.th/rest)
+		} `json:"lazada" mapstructure:"lazada"`
+		Shopee struct {
+			PartnerID   string `json:"partner_id" mapstructure:"partner_id"`   // App key
+			PartnerKey  string `json:"partner_key" mapstructure:"partner_key"` // App secret
+			ShopID      string `json:"shop_id,omitempty" mapstructure:"shop_id"`
+			AccessToken string `json:"access_token" mapstructure:"access_token"`
+			APIURL      string `json:"api_url,omitempty" mapstructure:"api_url"` // Region API URL (e.g. https://partner.shopeemobile.com/api/v2)
+		} `json:"shopee" mapstructure:"shopee"`
 	} `json:"adapters" mapstructure:"adapters"`
 
 	Auth struct {
@@ -97,5 +184,9 @@ type AppConfig struct {
 			Username string `json:"username" mapstructure:"username"`
 			Password string `json:"password" mapstructure:"password"`
 		} `json:"basic_auth" mapstructure:"basic_auth"`
+		Session struct {
+			Secret     string `json:"secret" mapstructure:"secret"`           // Keep secret and identical across API instances
+			TTLMinutes int    `json:"ttl_minutes" mapstructure:"ttl_minutes"` // How long a login lasts
+		} `json:"session" mapstructure:"session"`
 	} `json:"auth" mapstructure:"auth"`
 }
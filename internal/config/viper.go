@@ -75,22 +75,56 @@ func setDefaults(v *viper.Viper) {
 	// Redis defaults
 	v.SetDefault("redis.url", "")
 
+	// Cache defaults
+	v.SetDefault("cache.short_code.ttl", 300)         // 5 minutes
+	v.SetDefault("cache.short_code.negative_ttl", 30) // 30 seconds
+	v.SetDefault("cache.short_code.size", 10000)
+
+	// Tracking defaults (env values are space-separated lists)
+	v.SetDefault("tracking.bot_filter.excluded_ip_ranges", []string{})
+	v.SetDefault("tracking.bot_filter.user_agent_patterns", []string{})
+	v.SetDefault("tracking.visitor.salt", "") // Empty: random per process
+	v.SetDefault("tracking.visitor.cookie_name", "afv")
+	v.SetDefault("tracking.dedupe.window_seconds", 1800) // 30 minutes
+	v.SetDefault("tracking.dedupe.cache_size", 100000)
+	v.SetDefault("tracking.geoip.database_path", "") // Empty: country redirect rules never match
+
+	// Postback secrets have no defaults: a network without a secret is rejected
+	// (env: POSTBACKS_SECRETS_<NETWORK>)
+
 	// Server defaults
 	v.SetDefault("server.port", "8080")
 	v.SetDefault("server.host", "0.0.0.0")
 
 	// API defaults
 	v.SetDefault("api.base_url", "http://localhost:8080")
+	v.SetDefault("api.qr.logo_path", "") // Empty: QR codes are rendered without a logo
 
 	// Worker defaults (6-field format: second minute hour day month weekday)
 	v.SetDefault("worker.price_refresh_cron", "0 0 */6 * * *")
+	v.SetDefault("worker.click_ingestor.queue_size", 10000)
+	v.SetDefault("worker.click_ingestor.workers", 2)
+	v.SetDefault("worker.click_ingestor.batch_size", 500)
+	v.SetDefault("worker.click_ingestor.flush_interval_ms", 1000)
+	v.SetDefault("worker.click_ingestor.enqueue_timeout_ms", 10)
 
 	// Adapters defaults
 	v.SetDefault("adapters.mock_mode", false)
+	v.SetDefault("adapters.lazada.app_key", "")
+	v.SetDefault("adapters.lazada.app_secret", "")
+	v.SetDefault("adapters.lazada.access_token", "")
+	v.SetDefault("adapters.lazada.api_url", "") // Empty: adapter default
+	v.SetDefault("adapters.shopee.partner_id", "")
+	v.SetDefault("adapters.shopee.partner_key", "")
+	v.SetDefault("adapters.shopee.shop_id", "")
+	v.SetDefault("adapters.shopee.access_token", "")
+	v.SetDefault("adapters.shopee.api_url", "") // Empty: adapter default
 
 	// Auth defaults (empty - must be provided via env or config)
 	v.SetDefault("auth.basic_auth.username", "")
 	v.SetDefault("auth.basic_auth.password", "")
+	v.SetDefault("auth.session.secret", "")       // Empty: random per process
+	v.SetDefault("auth.session.ttl_minutes", 720) // 12 hours
 }
 
 // Implement Config interface - Database Write
@@ -231,6 +265,53 @@ func (c *viperConfig) GetRedisURL() string {
 	return c.v.GetString("redis.url")
 }
 
+// Cache
+func (c *viperConfig) GetShortCodeCacheTTL() int {
+	return c.v.GetInt("cache.short_code.ttl")
+}
+
+func (c *viperConfig) GetShortCodeCacheNegativeTTL() int {
+	return c.v.GetInt("cache.short_code.negative_ttl")
+}
+
+func (c *viperConfig) GetShortCodeCacheSize() int {
+	return c.v.GetInt("cache.short_code.size")
+}
+
+// Tracking
+func (c *viperConfig) GetBotExcludedIPRanges() []string {
+	return c.v.GetStringSlice("tracking.bot_filter.excluded_ip_ranges")
+}
+
+func (c *viperConfig) GetBotUserAgentPatterns() []string {
+	return c.v.GetStringSlice("tracking.bot_filter.user_agent_patterns")
+}
+
+func (c *viperConfig) GetVisitorSalt() string {
+	return c.v.GetString("tracking.visitor.salt")
+}
+
+func (c *viperConfig) GetVisitorCookieName() string {
+	return c.v.GetString("tracking.visitor.cookie_name")
+}
+
+func (c *viperConfig) GetClickDedupeWindow() int {
+	return c.v.GetInt("tracking.dedupe.window_seconds")
+}
+
+func (c *viperConfig) GetClickDedupeCacheSize() int {
+	return c.v.GetInt("tracking.dedupe.cache_size")
+}
+
+func (c *viperConfig) GetGeoIPDatabasePath() string {
+	return c.v.GetString("tracking.geoip.database_path")
+}
+
+// Postbacks
+func (c *viperConfig) GetPostbackSecret(network string) string {
+	return c.v.GetString("postbacks.secrets." + network)
+}
+
 func (c *viperConfig) GetServerPort() string {
 	return c.v.GetString("server.port")
 }
@@ -243,14 +324,74 @@ func (c *viperConfig) GetAPIBaseURL() string {
 	return c.v.GetString("api.base_url")
 }
 
+func (c *viperConfig) GetQRLogoPath() string {
+	return c.v.GetString("api.qr.logo_path")
+}
+
 func (c *viperConfig) GetPriceRefreshCron() string {
 	return c.v.GetString("worker.price_refresh_cron")
 }
 
+func (c *viperConfig) GetClickQueueSize() int {
+	return c.v.GetInt("worker.click_ingestor.queue_size")
+}
+
+func (c *viperConfig) GetClickIngestWorkers() int {
+	return c.v.GetInt("worker.click_ingestor.workers")
+}
+
+func (c *viperConfig) GetClickBatchSize() int {
+	return c.v.GetInt("worker.click_ingestor.batch_size")
+}
+
+func (c *viperConfig) GetClickFlushInterval() int {
+	return c.v.GetInt("worker.click_ingestor.flush_interval_ms")
+}
+
+func (c *viperConfig) GetClickEnqueueTimeout() int {
+	return c.v.GetInt("worker.click_ingestor.enqueue_timeout_ms")
+}
+
 func (c *viperConfig) GetMockMode() bool {
 	return c.v.GetBool("adapters.mock_mode")
 }
 
+func (c *viperConfig) GetLazadaAppKey() string {
+	return c.v.GetString("adapters.lazada.app_key")
+}
+
+func (c *viperConfig) GetLazadaAppSecret() string {
+	return c.v.GetString("adapters.lazada.app_secret")
+}
+
+func (c *viperConfig) GetLazadaAccessToken() string {
+	return c.v.GetString("adapters.lazada.access_token")
+}
+
+func (c *viperConfig) GetLazadaAPIURL() string {
+	return c.v.GetString("adapters.lazada.api_url")
+}
+
+func (c *viperConfig) GetShopeePartnerID() string {
+	return c.v.GetString("adapters.shopee.partner_id")
+}
+
+func (c *viperConfig) GetShopeePartnerKey() string {
+	return c.v.GetString("adapters.shopee.partner_key")
+}
+
+func (c *viperConfig) GetShopeeShopID() string {
+	return c.v.GetString("adapters.shopee.shop_id")
+}
+
+func (c *viperConfig) GetShopeeAccessToken() string {
+	return c.v.GetString("adapters.shopee.access_token")
+}
+
+func (c *viperConfig) GetShopeeAPIURL() string {
+	return c.v.GetString("adapters.shopee.api_url")
+}
+
 func (c *viperConfig) GetBasicAuthUsername() string {
 	return c.v.GetString("auth.basic_auth.username")
 }
@@ -259,6 +400,14 @@ func (c *viperConfig) GetBasicAuthPassword() string {
 	return c.v.GetString("auth.basic_auth.password")
 }
 
+func (c *viperConfig) GetSessionSecret() string {
+	return c.v.GetString("auth.session.secret")
+}
+
+func (c *viperConfig) GetSessionTTL() int {
+	return c.v.GetInt("auth.session.ttl_minutes")
+}
+
 func (c *viperConfig) GetAllSettings() map[string]interface{} {
 	return c.v.AllSettings()
 }
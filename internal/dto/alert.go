@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateAlertRequest represents the request to create a price alert rule
+// Exactly one of product_id or campaign_id must be set
+type CreateAlertRequest struct {
+	Name          string     `json:"name" validate:"required" example:"Matcha price drop"`
+	ProductID     *uuid.UUID `json:"product_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CampaignID    *uuid.UUID `json:"campaign_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Marketplace   *string    `json:"marketplace,omitempty" example:"lazada"`                   // Optional: all marketplaces if omitted
+	Direction     string     `json:"direction,omitempty" enums:"drop,rise,any" example:"drop"` // Default: drop
+	ThresholdType string     `json:"threshold_type" validate:"required,oneof=percent absolute" enums:"percent,absolute" example:"percent"`
+	Threshold     float64    `json:"threshold" validate:"required,gt=0" example:"10"`
+	WebhookURL    string     `json:"webhook_url" validate:"required,url" example:"https://hooks.example.com/price-alerts"`
+	Secret        string     `json:"secret,omitempty" example:"whsec_change_me"` // Optional: generated if omitted
+	IsActive      *bool      `json:"is_active,omitempty" example:"true"`         // Default: true
+}
+
+// UpdateAlertRequest represents the request to update a price alert rule
+// Scope (product_id/campaign_id) cannot be changed
+type UpdateAlertRequest struct {
+	Name          string   `json:"name,omitempty" example:"Matcha price drop"`
+	Marketplace   *string  `json:"marketplace,omitempty" example:"lazada"` // Empty string clears the filter
+	Direction     string   `json:"direction,omitempty" enums:"drop,rise,any" example:"drop"`
+	ThresholdType string   `json:"threshold_type,omitempty" enums:"percent,absolute" example:"percent"`
+	Threshold     *float64 `json:"threshold,omitempty" example:"10"`
+	WebhookURL    string   `json:"webhook_url,omitempty" example:"https://hooks.example.com/price-alerts"`
+	Secret        string   `json:"secret,omitempty" example:"whsec_change_me"`
+	IsActive      *bool    `json:"is_active,omitempty" example:"true"`
+}
+
+// AlertResponse represents a price alert rule response
+type AlertResponse struct {
+	ID            uuid.UUID  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name          string     `json:"name" example:"Matcha price drop"`
+	ProductID     *uuid.UUID `json:"product_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CampaignID    *uuid.UUID `json:"campaign_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Marketplace   *string    `json:"marketplace,omitempty" example:"lazada"`
+	Direction     string     `json:"direction" example:"drop"`
+	ThresholdType string     `json:"threshold_type" example:"percent"`
+	Threshold     float64    `json:"threshold" example:"10"`
+	WebhookURL    string     `json:"webhook_url" example:"https://hooks.example.com/price-alerts"`
+	Secret        string     `json:"secret,omitempty" example:"whsec_change_me"` // Only returned on create
+	IsActive      bool       `json:"is_active" example:"true"`
+	CreatedAt     time.Time  `json:"created_at" example:"2025-01-15T10:00:00Z"`
+	UpdatedAt     time.Time  `json:"updated_at" example:"2025-01-15T10:00:00Z"`
+}
+
+// AlertDeliveryResponse represents a webhook delivery log entry
+type AlertDeliveryResponse struct {
+	ID             uuid.UUID  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	AlertRuleID    uuid.UUID  `json:"alert_rule_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ProductID      uuid.UUID  `json:"product_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Marketplace    string     `json:"marketplace" example:"lazada"`
+	OldPrice       float64    `json:"old_price" example:"299.00"`
+	NewPrice       float64    `json:"new_price" example:"249.00"`
+	Status         string     `json:"status" example:"delivered"`
+	Attempts       int        `json:"attempts" example:"1"`
+	ResponseStatus *int       `json:"response_status,omitempty" example:"200"`
+	LastError      string     `json:"last_error,omitempty" example:""`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty" example:"2025-01-15T10:00:01Z"`
+	CreatedAt      time.Time  `json:"created_at" example:"2025-01-15T10:00:00Z"`
+}
+
+// AlertWebhookPayload is the JSON body sent to alert webhooks
+// The body is signed with HMAC-SHA256 using the rule secret, see X-Signature
+type AlertWebhookPayload struct {
+	Event                 string     `json:"event" example:"offer.price_changed"`
+	DeliveryID            uuid.UUID  `json:"delivery_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	AlertID               uuid.UUID  `json:"alert_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	AlertName             string     `json:"alert_name" example:"Matcha price drop"`
+	CampaignID            *uuid.UUID `json:"campaign_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ProductID             uuid.UUID  `json:"product_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OfferID               uuid.UUID  `json:"offer_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Marketplace           string     `json:"marketplace" example:"lazada"`
+	StoreName             string     `json:"store_name" example:"Official Store"`
+	MarketplaceProductURL string     `json:"marketplace_product_url" example:"https://www.lazada.co.th/products/example-i123456.html"`
+	Direction             string     `json:"direction" example:"drop"`
+	OldPrice              float64    `json:"old_price" example:"299.00"`
+	NewPrice              float64    `json:"new_price" example:"249.00"`
+	Change                float64    `json:"change" example:"-50.00"`
+	ChangePercent         float64    `json:"change_percent" example:"-16.72"`
+	OccurredAt            time.Time  `json:"occurred_at" example:"2025-01-15T10:00:00Z"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateAPIKeyRequest represents the request to create an admin API key
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required" example:"Reporting script"`
+	Scopes    []string   `json:"scopes" validate:"required" example:"read,write"`     // read, write and/or worker
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2026-01-01T00:00:00Z"` // Optional: never expires if omitted
+}
+
+// APIKeyResponse represents an admin API key
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name       string     `json:"name" example:"Reporting script"`
+	KeyPrefix  string     `json:"key_prefix" example:"afk_3f9a1c2e"`
+	Key        string     `json:"key,omitempty" example:"afk_3f9a1c2e..."` // Only returned on create
+	Scopes     []string   `json:"scopes" example:"read,write"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" example:"2026-01-01T00:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2025-01-15T10:00:00Z"`
+	CreatedAt  time.Time  `json:"created_at" example:"2025-01-15T10:00:00Z"`
+}
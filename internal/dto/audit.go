@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditQueryParams represents query parameters for audit log filtering
+type AuditQueryParams struct {
+	Entity   string     `json:"entity,omitempty"`    // Entity type: product, campaign, link or price_refresh
+	EntityID *uuid.UUID `json:"entity_id,omitempty"` // One entity's history
+	Actor    string     `json:"actor,omitempty"`     // Principal subject (e.g. user:<id>) or name (e.g. the user's email)
+	From     *time.Time `json:"from,omitempty"`
+	To       *time.Time `json:"to,omitempty"`
+}
+
+// AuditEventResponse represents an audit log entry
+type AuditEventResponse struct {
+	ID         uuid.UUID              `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Actor      string                 `json:"actor" example:"user:123e4567-e89b-12d3-a456-426614174000"`
+	ActorName  string                 `json:"actor_name" example:"me@example.com"`
+	Action     string                 `json:"action" example:"update"`
+	EntityType string                 `json:"entity_type" example:"campaign"`
+	EntityID   *uuid.UUID             `json:"entity_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Before     map[string]interface{} `json:"before,omitempty"` // Changed fields before an update; the entity before a delete
+	After      map[string]interface{} `json:"after,omitempty"`  // Changed fields after an update; the entity after a create
+	CreatedAt  time.Time              `json:"created_at" example:"2025-01-15T10:00:00Z"`
+}
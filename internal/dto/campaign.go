@@ -8,22 +8,37 @@ import (
 
 // CreateCampaignRequest represents the request to create a campaign
 type CreateCampaignRequest struct {
-	Name        string      `json:"name" validate:"required" example:"Summer Deal 2025"`
-	UTMCampaign string      `json:"utm_campaign" validate:"required" example:"summer_2025"`
-	StartAt     time.Time   `json:"start_at" validate:"required" example:"2025-06-01T00:00:00Z"`
-	EndAt       time.Time   `json:"end_at" validate:"required" example:"2025-08-31T23:59:59Z"`
-	ProductIDs  []uuid.UUID `json:"product_ids,omitempty" example:"[\"123e4567-e89b-12d3-a456-426614174000\"]"`
+	Name              string          `json:"name" validate:"required" example:"Summer Deal 2025"`
+	UTMCampaign       string          `json:"utm_campaign" validate:"required" example:"summer_2025"`
+	UTMSource         *string         `json:"utm_source,omitempty" example:"facebook"`             // Default: affiliate
+	UTMMedium         *string         `json:"utm_medium,omitempty" example:"social"`               // Default: affiliate
+	UTMContent        string          `json:"utm_content,omitempty" example:"{{marketplace}}"`     // Optional; supports placeholders
+	UTMTerm           string          `json:"utm_term,omitempty" example:"product_{{product_id}}"` // Optional; supports placeholders
+	StartAt           time.Time       `json:"start_at" validate:"required" example:"2025-06-01T00:00:00Z"`
+	EndAt             time.Time       `json:"end_at" validate:"required" example:"2025-08-31T23:59:59Z"`
+	OutOfWindowPolicy string          `json:"out_of_window_policy,omitempty" enums:"redirect,fallback,gone" example:"fallback"` // Redirects outside start_at..end_at; default: redirect
+	FallbackURL       string          `json:"fallback_url,omitempty" example:"https://shop.example.com/offers"`                 // Required by the fallback policy
+	BestPriceLink     bool            `json:"best_price_link,omitempty" example:"true"`                                         // Also sync a link per product that redirects to the cheapest offer
+	ProductIDs        []uuid.UUID     `json:"product_ids,omitempty" example:"[\"123e4567-e89b-12d3-a456-426614174000\"]"`
+	ShortCodes        []LinkShortCode `json:"short_codes,omitempty"` // Optional vanity codes for the synced links
 }
 
 // CampaignResponse represents a campaign response
 type CampaignResponse struct {
-	ID          uuid.UUID   `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Name        string      `json:"name" example:"Summer Deal 2025"`
-	UTMCampaign string      `json:"utm_campaign" example:"summer_2025"`
-	StartAt     time.Time   `json:"start_at" example:"2025-06-01T00:00:00Z"`
-	EndAt       time.Time   `json:"end_at" example:"2025-08-31T23:59:59Z"`
-	CreatedAt   time.Time   `json:"created_at" example:"2025-01-15T10:00:00Z"`
-	ProductIDs  []uuid.UUID `json:"product_ids,omitempty" example:"[\"123e4567-e89b-12d3-a456-426614174000\"]"` // Product IDs in this campaign
+	ID                uuid.UUID   `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name              string      `json:"name" example:"Summer Deal 2025"`
+	UTMCampaign       string      `json:"utm_campaign" example:"summer_2025"`
+	UTMSource         string      `json:"utm_source" example:"facebook"`
+	UTMMedium         string      `json:"utm_medium" example:"social"`
+	UTMContent        string      `json:"utm_content,omitempty" example:"{{marketplace}}"`
+	UTMTerm           string      `json:"utm_term,omitempty" example:"product_{{product_id}}"`
+	StartAt           time.Time   `json:"start_at" example:"2025-06-01T00:00:00Z"`
+	EndAt             time.Time   `json:"end_at" example:"2025-08-31T23:59:59Z"`
+	OutOfWindowPolicy string      `json:"out_of_window_policy" example:"fallback"`
+	FallbackURL       string      `json:"fallback_url,omitempty" example:"https://shop.example.com/offers"`
+	BestPriceLink     bool        `json:"best_price_link" example:"true"`
+	CreatedAt         time.Time   `json:"created_at" example:"2025-01-15T10:00:00Z"`
+	ProductIDs        []uuid.UUID `json:"product_ids,omitempty" example:"[\"123e4567-e89b-12d3-a456-426614174000\"]"` // Product IDs in this campaign
 }
 
 // CampaignPublicResponse represents a public campaign response (for public landing page)
@@ -47,21 +62,30 @@ type CampaignProduct struct {
 
 // ProductLink represents an affiliate link for a product
 type ProductLink struct {
-	Marketplace string `json:"marketplace" example:"lazada"`
+	Marketplace string `json:"marketplace" example:"lazada"` // best_price for the link to the cheapest offer
 	ShortCode   string `json:"short_code" example:"abc123xyz"`
 	FullURL     string `json:"full_url" example:"https://demo.jonosize.com/go/abc123xyz"`
 }
 
 // UpdateCampaignRequest represents the request to update a campaign
 type UpdateCampaignRequest struct {
-	Name        string      `json:"name,omitempty" example:"Summer Deal 2025"`
-	UTMCampaign string      `json:"utm_campaign,omitempty" example:"summer_2025"`
-	StartAt     *time.Time  `json:"start_at,omitempty" example:"2025-06-01T00:00:00Z"`
-	EndAt       *time.Time  `json:"end_at,omitempty" example:"2025-08-31T23:59:59Z"`
-	ProductIDs  []uuid.UUID `json:"product_ids,omitempty" example:"[\"123e4567-e89b-12d3-a456-426614174000\"]"`
+	Name              string          `json:"name,omitempty" example:"Summer Deal 2025"`
+	UTMCampaign       string          `json:"utm_campaign,omitempty" example:"summer_2025"`
+	UTMSource         *string         `json:"utm_source,omitempty" example:"facebook"` // Empty string omits the parameter
+	UTMMedium         *string         `json:"utm_medium,omitempty" example:"social"`
+	UTMContent        *string         `json:"utm_content,omitempty" example:"{{marketplace}}"`
+	UTMTerm           *string         `json:"utm_term,omitempty" example:"product_{{product_id}}"`
+	StartAt           *time.Time      `json:"start_at,omitempty" example:"2025-06-01T00:00:00Z"`
+	EndAt             *time.Time      `json:"end_at,omitempty" example:"2025-08-31T23:59:59Z"`
+	OutOfWindowPolicy *string         `json:"out_of_window_policy,omitempty" enums:"redirect,fallback,gone" example:"gone"`
+	FallbackURL       *string         `json:"fallback_url,omitempty" example:"https://shop.example.com/offers"`
+	BestPriceLink     *bool           `json:"best_price_link,omitempty" example:"true"` // Adds or removes the campaign's best-price links
+	ProductIDs        []uuid.UUID     `json:"product_ids,omitempty" example:"[\"123e4567-e89b-12d3-a456-426614174000\"]"`
+	ShortCodes        []LinkShortCode `json:"short_codes,omitempty"` // Optional vanity codes; existing links keep their old code as an alias
 }
 
 // UpdateCampaignProductsRequest represents the request to update products in a campaign
 type UpdateCampaignProductsRequest struct {
-	ProductIDs []uuid.UUID `json:"product_ids" example:"[\"123e4567-e89b-12d3-a456-426614174000\"]"`
+	ProductIDs []uuid.UUID     `json:"product_ids" example:"[\"123e4567-e89b-12d3-a456-426614174000\"]"`
+	ShortCodes []LinkShortCode `json:"short_codes,omitempty"` // Optional vanity codes; existing links keep their old code as an alias
 }
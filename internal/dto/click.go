@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"net"
+
+	"github.com/google/uuid"
+)
+
+// ClickMetadata carries the request attributes recorded with a click (and with impressions)
+type ClickMetadata struct {
+	IPAddress net.IP
+	UserAgent string
+	Referrer  string
+	Method    string // HTTP method; HEAD requests are classified as bot traffic
+	Purpose   string // Purpose / Sec-Purpose header value, sent by browser prefetch and link previews
+
+	AcceptLanguage string // Accept-Language header value, matched by redirect rules
+
+	VisitorCookie string // First-party visitor cookie value, if sent
+	VisitorID     string // Resolved visitor ID used for de-duplication
+
+	ClickID uuid.UUID // Minted before the redirect and passed to the marketplace as a sub-ID
+	SubIDs  []string  // Publisher sub IDs (sub1..sub5), positional; empty slots are ""
+
+	Outcome       string // What the redirect did (see model.ClickOutcome); empty means redirected
+	OutcomeReason string // Why the click was not a normal in-window redirect, if it wasn't
+
+	VariantID   *uuid.UUID // A/B variant the visitor was sent to, if the link is split
+	Marketplace string     // Marketplace a best-price link sent the visitor to
+	Source      string     // ?src= marker on the short link, e.g. qr for QR code scans
+
+	Host        string    // Host the short link was requested on
+	WorkspaceID uuid.UUID // Workspace of the clicked link
+}
+
+// RedirectTarget is where a redirect sends the visitor
+// AppURL is set for app deep links: clients try it first and fall back to URL
+type RedirectTarget struct {
+	URL    string
+	AppURL string
+}
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConversionResponse represents a conversion recorded from a network postback
+type ConversionResponse struct {
+	ID          uuid.UUID  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Network     string     `json:"network" example:"lazada"`
+	OrderID     string     `json:"order_id" example:"ORD-20250115-0001"`
+	ClickRef    string     `json:"click_ref,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"` // Click or sub ID as reported
+	ClickID     *uuid.UUID `json:"click_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	LinkID      *uuid.UUID `json:"link_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CampaignID  *uuid.UUID `json:"campaign_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Marketplace *string    `json:"marketplace,omitempty" example:"lazada"`
+	Attributed  bool       `json:"attributed" example:"true"` // Whether the click or sub ID matched a click or link
+	Amount      float64    `json:"amount" example:"1299.00"`
+	Commission  float64    `json:"commission" example:"64.95"`
+	Currency    string     `json:"currency,omitempty" example:"THB"`
+	Status      string     `json:"status" example:"pending"`
+	CreatedAt   time.Time  `json:"created_at" example:"2025-01-15T10:00:00Z"`
+	UpdatedAt   time.Time  `json:"updated_at" example:"2025-01-15T10:00:00Z"`
+}
@@ -9,26 +9,53 @@ import (
 // DashboardStatsResponse represents dashboard statistics
 type DashboardStatsResponse struct {
 	TotalClicks      int64             `json:"total_clicks" example:"1250"`
+	UniqueClicks     int64             `json:"unique_clicks" example:"980"` // Clicks outside the per-visitor dedupe window
 	TotalLinks       int64             `json:"total_links" example:"45"`
-	CTR              float64           `json:"ctr" example:"2.78"` // Click-through rate (percentage)
+	PageViews        int64             `json:"page_views" example:"9400"`         // Public campaign page views
+	TotalImpressions int64             `json:"total_impressions" example:"45000"` // Product links rendered on campaign pages
+	CTR              float64           `json:"ctr" example:"2.78"`                // Click-through rate: clicks / link impressions (percentage)
+	Conversions      int64             `json:"conversions" example:"38"`          // Postback-reported orders, rejected excluded
+	Revenue          float64           `json:"revenue" example:"2470.50"`         // Sum of commission
+	EPC              float64           `json:"epc" example:"1.98"`                // Earnings per click: revenue / clicks
+	ConversionRate   float64           `json:"conversion_rate" example:"3.04"`    // Conversions / clicks (percentage)
 	CampaignStats    []CampaignStat    `json:"campaign_stats"`
 	MarketplaceStats []MarketplaceStat `json:"marketplace_stats"`
+	SourceStats      []SourceStat      `json:"source_stats"` // Clicks by ?src= marker, e.g. QR code scans vs other clicks
 	TopProducts      []TopProduct      `json:"top_products"`
 	RecentClicks     []RecentClick     `json:"recent_clicks"`
 }
 
 // CampaignStat represents click statistics for a campaign
 type CampaignStat struct {
-	CampaignID   uuid.UUID `json:"campaign_id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	CampaignName string    `json:"campaign_name" example:"Summer Deal 2025"`
-	Clicks       int64     `json:"clicks" example:"450"`
+	CampaignID     uuid.UUID `json:"campaign_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CampaignName   string    `json:"campaign_name" example:"Summer Deal 2025"`
+	Clicks         int64     `json:"clicks" example:"450"`
+	UniqueClicks   int64     `json:"unique_clicks" example:"380"`
+	PageViews      int64     `json:"page_views" example:"3200"`
+	Impressions    int64     `json:"impressions" example:"16000"` // Product links rendered on the campaign page
+	CTR            float64   `json:"ctr" example:"2.81"`          // Clicks / impressions (percentage)
+	Conversions    int64     `json:"conversions" example:"14"`
+	Revenue        float64   `json:"revenue" example:"910.00"`       // Sum of commission
+	EPC            float64   `json:"epc" example:"2.02"`             // Revenue / clicks
+	ConversionRate float64   `json:"conversion_rate" example:"3.11"` // Conversions / clicks (percentage)
 }
 
 // MarketplaceStat represents click statistics by marketplace
 type MarketplaceStat struct {
-	Marketplace string  `json:"marketplace" example:"lazada"`
-	Clicks      int64   `json:"clicks" example:"750"`
-	Percentage  float64 `json:"percentage" example:"60.0"`
+	Marketplace  string  `json:"marketplace" example:"lazada"`
+	Clicks       int64   `json:"clicks" example:"750"`
+	UniqueClicks int64   `json:"unique_clicks" example:"610"`
+	Percentage   float64 `json:"percentage" example:"60.0"`
+	Impressions  int64   `json:"impressions" example:"24000"`
+	CTR          float64 `json:"ctr" example:"3.13"` // Clicks / impressions (percentage)
+}
+
+// SourceStat represents click statistics by ?src= marker
+type SourceStat struct {
+	Source       string  `json:"source" example:"qr"` // "(none)" for clicks without a marker
+	Clicks       int64   `json:"clicks" example:"180"`
+	UniqueClicks int64   `json:"unique_clicks" example:"150"`
+	Percentage   float64 `json:"percentage" example:"14.4"`
 }
 
 // TopProduct represents a top-performing product
@@ -37,6 +64,8 @@ type TopProduct struct {
 	ProductName string    `json:"product_name" example:"Product Title"`
 	Clicks      int64     `json:"clicks" example:"120"`
 	Marketplace string    `json:"marketplace" example:"lazada"`
+	Impressions int64     `json:"impressions" example:"2400"`
+	CTR         float64   `json:"ctr" example:"5.0"` // Clicks / impressions (percentage)
 }
 
 // RecentClick represents a recent click event
@@ -55,4 +84,5 @@ type DashboardQueryParams struct {
 	Marketplace *string    `json:"marketplace,omitempty"`
 	StartDate   *time.Time `json:"start_date,omitempty"`
 	EndDate     *time.Time `json:"end_date,omitempty"`
+	IncludeBots bool       `json:"include_bots,omitempty"` // Count clicks flagged as bot traffic
 }
@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -8,13 +10,100 @@ import (
 type CreateLinkRequest struct {
 	ProductID   uuid.UUID `json:"product_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
 	CampaignID  uuid.UUID `json:"campaign_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Marketplace string    `json:"marketplace" validate:"required,oneof=lazada shopee" example:"lazada"`
+	Marketplace string    `json:"marketplace" validate:"required" example:"lazada"`
+	ShortCode   string    `json:"short_code,omitempty" example:"matcha-summer"` // Optional vanity code (lowercased); generated when omitted
+
+	// Optional UTM overrides (omitted fields inherit the campaign's values; an empty string omits the parameter)
+	UTMSource  *string `json:"utm_source,omitempty" example:"line"`
+	UTMMedium  *string `json:"utm_medium,omitempty" example:"chat"`
+	UTMContent *string `json:"utm_content,omitempty" example:"banner_{{marketplace}}"`
+	UTMTerm    *string `json:"utm_term,omitempty" example:"{{product_id}}"`
 }
 
 // LinkResponse represents a link response
 type LinkResponse struct {
-	ID        uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	ShortCode string    `json:"short_code" example:"abc123xyz"`
-	TargetURL string    `json:"target_url" example:"https://www.lazada.co.th/products/...?utm_source=...&utm_medium=affiliate&utm_campaign=summer_2025"`
-	FullURL   string    `json:"full_url" example:"https://demo.jonosize.com/go/abc123xyz"`
+	ID        uuid.UUID  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ShortCode string     `json:"short_code" example:"abc123xyz"`
+	TargetURL string     `json:"target_url" example:"https://www.lazada.co.th/products/...?utm_source=...&utm_medium=affiliate&utm_campaign=summer_2025"`
+	FullURL   string     `json:"full_url" example:"https://demo.jonosize.com/go/abc123xyz"`
+	Aliases   []string   `json:"aliases,omitempty" example:"matcha-2024"` // Other short codes that redirect to this link
+	Status    string     `json:"status" example:"active"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2025-08-31T23:59:59Z"`
+}
+
+// UpdateLinkRequest represents the request to update a link's short code or lifecycle
+// A changed short code keeps the previous one as an alias, which keeps redirecting
+type UpdateLinkRequest struct {
+	ShortCode      string     `json:"short_code,omitempty" example:"matcha-summer"`
+	Status         *string    `json:"status,omitempty" enums:"active,paused,archived" example:"paused"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty" example:"2025-08-31T23:59:59Z"`
+	ClearExpiresAt bool       `json:"clear_expires_at,omitempty" example:"false"` // Remove the expiry
+}
+
+// CreateLinkAliasRequest represents the request to add an alias to a link
+type CreateLinkAliasRequest struct {
+	ShortCode string `json:"short_code" validate:"required" example:"matcha-ig"`
+}
+
+// LinkShortCode requests a vanity short code for a campaign's product link on one marketplace
+type LinkShortCode struct {
+	ProductID   uuid.UUID `json:"product_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Marketplace string    `json:"marketplace" example:"lazada"` // best_price for the product's best-price link
+	ShortCode   string    `json:"short_code" example:"matcha-summer"`
+}
+
+// LinkStatsQueryParams represents query parameters for link statistics
+type LinkStatsQueryParams struct {
+	From        *time.Time `json:"from,omitempty"`
+	To          *time.Time `json:"to,omitempty"`
+	Interval    string     `json:"interval,omitempty"`
+	IncludeBots bool       `json:"include_bots,omitempty"` // Count clicks flagged as bot traffic
+}
+
+// LinkQRCodeQueryParams represents query parameters for link QR codes
+type LinkQRCodeQueryParams struct {
+	Format string `json:"format,omitempty"` // png (default) or svg
+	Size   int    `json:"size,omitempty"`   // Image width and height in pixels; 0 uses the default
+	Margin *int   `json:"margin,omitempty"` // Quiet zone in modules; nil uses the default
+	ECC    string `json:"ecc,omitempty"`    // Error correction level: l, m, q or h
+	Logo   bool   `json:"logo,omitempty"`   // Overlay the configured logo
+}
+
+// LinkQRCode is a rendered link QR code image
+type LinkQRCode struct {
+	ContentType string
+	Filename    string
+	Data        []byte
+}
+
+// ClickTimeBucket represents click counts for one bucket of the time series
+type ClickTimeBucket struct {
+	Bucket       time.Time `json:"bucket" example:"2025-01-15T00:00:00Z"`
+	Clicks       int64     `json:"clicks" example:"42"`
+	UniqueClicks int64     `json:"unique_clicks" example:"35"`
+}
+
+// ClickBreakdown represents click counts for one value of a breakdown dimension
+type ClickBreakdown struct {
+	Value      string  `json:"value" example:"www.facebook.com"`
+	Clicks     int64   `json:"clicks" example:"120"`
+	Percentage float64 `json:"percentage" example:"48.0"`
+}
+
+// LinkStatsResponse represents click statistics for a single link
+type LinkStatsResponse struct {
+	LinkID       uuid.UUID         `json:"link_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ShortCode    string            `json:"short_code" example:"abc123xyz"`
+	Marketplace  string            `json:"marketplace" example:"lazada"`
+	From         time.Time         `json:"from" example:"2025-01-01T00:00:00Z"`
+	To           time.Time         `json:"to" example:"2025-01-31T23:59:59Z"`
+	Interval     string            `json:"interval" example:"day"`
+	TotalClicks  int64             `json:"total_clicks" example:"250"`
+	UniqueClicks int64             `json:"unique_clicks" example:"198"`
+	Series       []ClickTimeBucket `json:"series"`    // Buckets without clicks are omitted
+	Referrers    []ClickBreakdown  `json:"referrers"` // Top referrer hosts, "(direct)" when no referrer was sent
+	Browsers     []ClickBreakdown  `json:"browsers"`  // Browser family parsed from the user agent
+	Devices      []ClickBreakdown  `json:"devices"`   // Device type parsed from the user agent
+	Outcomes     []ClickBreakdown  `json:"outcomes"`  // Redirect outcome: redirected, fallback or gone
+	Sources      []ClickBreakdown  `json:"sources"`   // ?src= marker, e.g. qr for QR code scans; "(none)" when unmarked
 }
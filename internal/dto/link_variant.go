@@ -0,0 +1,86 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateLinkVariantRequest represents the request to add an A/B variant to a link
+// Set marketplace to send the variant's traffic to the product's offer there, or
+// target_url for a landing page
+type CreateLinkVariantRequest struct {
+	Name        string `json:"name" validate:"required" example:"shopee"`
+	Weight      *int   `json:"weight" validate:"required" example:"30"`                                       // Relative share of traffic (0-10000); 0 stops new traffic
+	Marketplace string `json:"marketplace,omitempty" example:"shopee"`                                        // Marketplace offer variants
+	TargetURL   string `json:"target_url,omitempty" example:"https://brand.example.com/matcha?utm_source=ab"` // Landing URL variants
+}
+
+// UpdateLinkVariantRequest represents the request to update an A/B variant
+// Omitted fields are kept; an empty marketplace turns the variant into a landing URL
+type UpdateLinkVariantRequest struct {
+	Name        *string `json:"name,omitempty" example:"shopee"`
+	Weight      *int    `json:"weight,omitempty" example:"50"`
+	Marketplace *string `json:"marketplace,omitempty" example:"shopee"`
+	TargetURL   *string `json:"target_url,omitempty" example:"https://brand.example.com/matcha"`
+}
+
+// LinkVariantResponse represents an A/B variant response
+type LinkVariantResponse struct {
+	ID          uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	LinkID      uuid.UUID `json:"link_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name        string    `json:"name" example:"shopee"`
+	Weight      int       `json:"weight" example:"30"`
+	Marketplace *string   `json:"marketplace,omitempty" example:"shopee"`
+	TargetURL   string    `json:"target_url" example:"https://shopee.co.th/product/123456/789012?utm_source=affiliate"`
+	CreatedAt   time.Time `json:"created_at" example:"2025-01-15T10:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2025-01-15T10:00:00Z"`
+}
+
+// LinkVariantStatsQueryParams represents query parameters for A/B variant statistics
+type LinkVariantStatsQueryParams struct {
+	From        *time.Time `json:"from,omitempty"`
+	To          *time.Time `json:"to,omitempty"`
+	IncludeBots bool       `json:"include_bots,omitempty"` // Count clicks flagged as bot traffic
+}
+
+// ConfidenceInterval is a two-sided interval at the response's confidence level
+type ConfidenceInterval struct {
+	Lower float64 `json:"lower" example:"2.1"`
+	Upper float64 `json:"upper" example:"4.4"`
+}
+
+// VariantComparison compares a variant's conversion rate with the control's (percentage points)
+type VariantComparison struct {
+	Difference  float64            `json:"difference" example:"0.8"`
+	Interval    ConfidenceInterval `json:"interval"`
+	Significant bool               `json:"significant" example:"false"` // The interval excludes 0
+}
+
+// LinkVariantStats represents the results of one A/B variant
+type LinkVariantStats struct {
+	VariantID              uuid.UUID          `json:"variant_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name                   string             `json:"name" example:"shopee"`
+	Marketplace            *string            `json:"marketplace,omitempty" example:"shopee"`
+	Weight                 int                `json:"weight" example:"30"`
+	WeightShare            float64            `json:"weight_share" example:"30.0"` // Configured share of traffic (percentage)
+	Clicks                 int64              `json:"clicks" example:"300"`
+	UniqueClicks           int64              `json:"unique_clicks" example:"240"`
+	ClickShare             float64            `json:"click_share" example:"29.4"` // Actual share of the link's split clicks (percentage)
+	Conversions            int64              `json:"conversions" example:"9"`
+	ConversionRate         float64            `json:"conversion_rate" example:"3.0"` // Conversions / clicks (percentage)
+	ConversionRateInterval ConfidenceInterval `json:"conversion_rate_interval"`      // Wilson score interval (percentage)
+	Revenue                float64            `json:"revenue" example:"540.00"`
+	EPC                    float64            `json:"epc" example:"1.80"`   // Revenue / clicks
+	VsControl              *VariantComparison `json:"vs_control,omitempty"` // Omitted for the control (first) variant
+}
+
+// LinkVariantStatsResponse compares the A/B variants of a link
+type LinkVariantStatsResponse struct {
+	LinkID          uuid.UUID          `json:"link_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ShortCode       string             `json:"short_code" example:"abc123xyz"`
+	From            time.Time          `json:"from" example:"2025-01-01T00:00:00Z"`
+	To              time.Time          `json:"to" example:"2025-01-31T23:59:59Z"`
+	ConfidenceLevel float64            `json:"confidence_level" example:"0.95"`
+	Variants        []LinkVariantStats `json:"variants"` // In creation order; the first is the control
+}
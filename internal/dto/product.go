@@ -13,6 +13,8 @@ type CreateProductRequest struct {
 	// Optional: specific URLs for each marketplace
 	LazadaURL string `json:"lazada_url,omitempty" example:"https://www.lazada.co.th/products/example-i123456.html"`
 	ShopeeURL string `json:"shopee_url,omitempty" example:"https://shopee.co.th/product/123456"`
+	// Optional: URLs keyed by marketplace, for marketplaces without a dedicated field
+	MarketplaceURLs map[string]string `json:"marketplace_urls,omitempty"`
 }
 
 // ProductResponse represents a product response
@@ -44,4 +46,40 @@ type ProductOffersResponse struct {
 type BestPrice struct {
 	Marketplace string  `json:"marketplace" example:"shopee"`
 	Price       float64 `json:"price" example:"279.00"`
+	// Optional: lowest recorded price across marketplaces in the last 30 days (public campaign view)
+	Lowest30Days   *float64 `json:"lowest_30d,omitempty" example:"269.00"`
+	IsLowest30Days bool     `json:"is_lowest_30d,omitempty" example:"false"`
+}
+
+// PriceHistoryQueryParams represents query parameters for price history
+type PriceHistoryQueryParams struct {
+	Marketplace *string    `json:"marketplace,omitempty"`
+	From        *time.Time `json:"from,omitempty"`
+	To          *time.Time `json:"to,omitempty"`
+}
+
+// PriceHistoryPoint represents a single recorded price
+type PriceHistoryPoint struct {
+	Marketplace string    `json:"marketplace" example:"lazada"`
+	StoreName   string    `json:"store_name" example:"Official Store"`
+	Price       float64   `json:"price" example:"299.00"`
+	RecordedAt  time.Time `json:"recorded_at" example:"2025-01-15T10:00:00Z"`
+}
+
+// PriceHistoryStats summarizes the prices in a price history series
+type PriceHistoryStats struct {
+	Min   float64 `json:"min" example:"279.00"`
+	Max   float64 `json:"max" example:"349.00"`
+	Avg   float64 `json:"avg" example:"305.50"`
+	Count int     `json:"count" example:"12"`
+}
+
+// PriceHistoryResponse represents the price history of a product
+type PriceHistoryResponse struct {
+	ProductID   uuid.UUID           `json:"product_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Marketplace string              `json:"marketplace,omitempty" example:"lazada"`
+	From        *time.Time          `json:"from,omitempty" example:"2025-01-01T00:00:00Z"`
+	To          *time.Time          `json:"to,omitempty" example:"2025-01-31T23:59:59Z"`
+	Series      []PriceHistoryPoint `json:"series"`
+	Stats       *PriceHistoryStats  `json:"stats,omitempty"` // Omitted when the series is empty
 }
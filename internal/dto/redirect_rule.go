@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateRedirectRuleRequest represents the request to add a redirect rule to a link
+// Empty match lists match any visitor
+type CreateRedirectRuleRequest struct {
+	Name        string   `json:"name,omitempty" example:"iOS app"`
+	Priority    int      `json:"priority,omitempty" example:"10"`                                                        // Lower runs first
+	OS          []string `json:"os,omitempty" example:"ios,android"`                                                     // ios, android, windows, macos, linux
+	Countries   []string `json:"countries,omitempty" example:"TH"`                                                       // ISO 3166-1 alpha-2
+	Languages   []string `json:"languages,omitempty" example:"th"`                                                       // Primary language subtags
+	TargetType  string   `json:"target_type" validate:"required" enums:"web,deep_link,marketplace"`                      // Where matching visitors go
+	TargetURL   string   `json:"target_url,omitempty" example:"lazada://th/d?p=123456"`                                  // Web URL or app deep link; resolved from the offer for marketplace targets
+	FallbackURL string   `json:"fallback_url,omitempty" example:"https://www.lazada.co.th/products/matcha-i123456.html"` // Deep links only; default: the link's target URL
+	Marketplace string   `json:"marketplace,omitempty" example:"shopee"`                                                 // Marketplace targets only
+	IsActive    *bool    `json:"is_active,omitempty" example:"true"`                                                     // Default: true
+}
+
+// UpdateRedirectRuleRequest represents the request to update a redirect rule
+// Omitted fields are kept; an empty match list matches any visitor
+type UpdateRedirectRuleRequest struct {
+	Name        *string  `json:"name,omitempty" example:"iOS app"`
+	Priority    *int     `json:"priority,omitempty" example:"10"`
+	OS          []string `json:"os,omitempty" example:"ios"`
+	Countries   []string `json:"countries,omitempty" example:"TH"`
+	Languages   []string `json:"languages,omitempty" example:"th"`
+	TargetType  *string  `json:"target_type,omitempty" enums:"web,deep_link,marketplace"`
+	TargetURL   *string  `json:"target_url,omitempty" example:"lazada://th/d?p=123456"`
+	FallbackURL *string  `json:"fallback_url,omitempty" example:"https://www.lazada.co.th/products/matcha-i123456.html"`
+	Marketplace *string  `json:"marketplace,omitempty" example:"shopee"`
+	IsActive    *bool    `json:"is_active,omitempty" example:"false"`
+}
+
+// RedirectRuleResponse represents a redirect rule response
+type RedirectRuleResponse struct {
+	ID          uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	LinkID      uuid.UUID `json:"link_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name        string    `json:"name" example:"iOS app"`
+	Priority    int       `json:"priority" example:"10"`
+	OS          []string  `json:"os" example:"ios"`
+	Countries   []string  `json:"countries" example:"TH"`
+	Languages   []string  `json:"languages" example:"th"`
+	TargetType  string    `json:"target_type" example:"deep_link"`
+	TargetURL   string    `json:"target_url" example:"lazada://th/d?p=123456"`
+	FallbackURL string    `json:"fallback_url,omitempty" example:"https://www.lazada.co.th/products/matcha-i123456.html"`
+	Marketplace *string   `json:"marketplace,omitempty" example:"shopee"`
+	IsActive    bool      `json:"is_active" example:"true"`
+	CreatedAt   time.Time `json:"created_at" example:"2025-01-15T10:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2025-01-15T10:00:00Z"`
+}
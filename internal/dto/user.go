@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginRequest represents an admin user login
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required" example:"analyst@example.com"`
+	Password string `json:"password" validate:"required" example:"correct horse battery staple"`
+}
+
+// LoginResponse represents a session token issued on login
+type LoginResponse struct {
+	Token     string       `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // Send as "Authorization: Bearer <token>"
+	ExpiresAt time.Time    `json:"expires_at" example:"2025-01-15T22:00:00Z"`
+	User      UserResponse `json:"user"`
+}
+
+// PrincipalResponse represents the authenticated caller
+type PrincipalResponse struct {
+	Subject string   `json:"subject" example:"user:123e4567-e89b-12d3-a456-426614174000"`
+	Name    string   `json:"name" example:"analyst@example.com"`
+	Role    string   `json:"role,omitempty" example:"analyst"` // Empty for API keys and BasicAuth
+	Scopes  []string `json:"scopes" example:"read"`
+}
+
+// CreateUserRequest represents the request to create an admin user
+type CreateUserRequest struct {
+	Email    string `json:"email" validate:"required" example:"analyst@example.com"`
+	Name     string `json:"name" validate:"required" example:"Ana Lyst"`
+	Password string `json:"password" validate:"required" example:"correct horse battery staple"` // 8-72 bytes
+	Role     string `json:"role" validate:"required" example:"analyst"`                          // admin, campaign_manager or analyst
+}
+
+// UpdateUserRequest represents the request to update an admin user
+// Omitted fields are left unchanged
+type UpdateUserRequest struct {
+	Name     *string `json:"name,omitempty" example:"Ana Lyst"`
+	Password *string `json:"password,omitempty" example:"correct horse battery staple"` // Signs the user out everywhere
+	Role     *string `json:"role,omitempty" example:"campaign_manager"`
+	IsActive *bool   `json:"is_active,omitempty" example:"false"` // Inactive users can't log in; their sessions stop working
+}
+
+// UserResponse represents an admin user
+type UserResponse struct {
+	ID          uuid.UUID  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Email       string     `json:"email" example:"analyst@example.com"`
+	Name        string     `json:"name" example:"Ana Lyst"`
+	Role        string     `json:"role" example:"analyst"`
+	IsActive    bool       `json:"is_active" example:"true"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty" example:"2025-01-15T10:00:00Z"`
+	CreatedAt   time.Time  `json:"created_at" example:"2025-01-15T10:00:00Z"`
+}
+
+// RoleResponse represents a role and the scopes it grants
+type RoleResponse struct {
+	Name        string   `json:"name" example:"analyst"`
+	Description string   `json:"description" example:"Read-only access to the catalog, stats and dashboard"`
+	Scopes      []string `json:"scopes" example:"read"`
+}
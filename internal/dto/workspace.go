@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateWorkspaceRequest represents the request to create a publisher workspace
+type CreateWorkspaceRequest struct {
+	Name           string `json:"name" validate:"required" example:"Gadget Deals"`
+	Slug           string `json:"slug" validate:"required" example:"gadget-deals"`            // Lowercase letters, digits and hyphens
+	RedirectDomain string `json:"redirect_domain,omitempty" example:"go.gadgetdeals.example"` // Optional: short links use api.base_url if omitted
+}
+
+// UpdateWorkspaceRequest represents the request to update a workspace
+// Omitted fields are left unchanged
+type UpdateWorkspaceRequest struct {
+	Name           *string `json:"name,omitempty" example:"Gadget Deals"`
+	RedirectDomain *string `json:"redirect_domain,omitempty" example:"go.gadgetdeals.example"` // Empty string removes the domain
+}
+
+// WorkspaceResponse represents a publisher workspace
+type WorkspaceResponse struct {
+	ID             uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name           string    `json:"name" example:"Gadget Deals"`
+	Slug           string    `json:"slug" example:"gadget-deals"`
+	RedirectDomain string    `json:"redirect_domain,omitempty" example:"go.gadgetdeals.example"`
+	CreatedAt      time.Time `json:"created_at" example:"2025-01-15T10:00:00Z"`
+	UpdatedAt      time.Time `json:"updated_at" example:"2025-01-15T10:00:00Z"`
+}
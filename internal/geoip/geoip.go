@@ -0,0 +1,57 @@
+// Package geoip resolves visitor countries from a local MaxMind-format database
+// (GeoLite2-Country, GeoIP2-Country or any DB with the same country record layout)
+package geoip
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// countryRecord is the subset of a country record used for lookups
+type countryRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+}
+
+// Reader resolves IP addresses to ISO 3166-1 alpha-2 country codes
+type Reader struct {
+	db *maxminddb.Reader
+}
+
+// Open opens a MaxMind-format database file
+func Open(path string) (*Reader, error) {
+	db, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open geoip database: %w", err)
+	}
+	return &Reader{db: db}, nil
+}
+
+// Country returns the upper-case country code of ip, or "" when it is unknown
+// Addresses without a country (e.g. anycast ranges) fall back to the registered country
+func (r *Reader) Country(ip net.IP) string {
+	if r == nil || ip == nil {
+		return ""
+	}
+	var record countryRecord
+	if err := r.db.Lookup(ip, &record); err != nil {
+		return ""
+	}
+	code := record.Country.ISOCode
+	if code == "" {
+		code = record.RegisteredCountry.ISOCode
+	}
+	return strings.ToUpper(code)
+}
+
+// Close releases the database
+func (r *Reader) Close() error {
+	return r.db.Close()
+}
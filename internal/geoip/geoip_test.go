@@ -0,0 +1,114 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestDB writes a minimal IPv4 MaxMind DB (24-bit records) mapping each
+// network to a country record, and returns its path
+func writeTestDB(t *testing.T, networks map[string]string) string {
+	t.Helper()
+
+	// Records: >= 0 is a node index, -1 is empty, <= -2 is data offset -(r+2)
+	nodes := [][2]int{{-1, -1}}
+	var data []byte
+	for cidr, country := range networks {
+		_, network, err := net.ParseCIDR(cidr)
+		require.NoError(t, err)
+		ip := network.IP.To4()
+		ones, _ := network.Mask.Size()
+
+		offset := len(data)
+		data = append(data, 0xE1)
+		data = append(data, mmdbString("country")...)
+		data = append(data, 0xE1)
+		data = append(data, mmdbString("iso_code")...)
+		data = append(data, mmdbString(country)...)
+
+		n := 0
+		for i := 0; i < ones; i++ {
+			bit := (ip[i/8] >> (7 - i%8)) & 1
+			if i == ones-1 {
+				nodes[n][bit] = -(offset + 2)
+				break
+			}
+			if nodes[n][bit] < 0 {
+				nodes = append(nodes, [2]int{-1, -1})
+				nodes[n][bit] = len(nodes) - 1
+			}
+			n = nodes[n][bit]
+		}
+	}
+
+	nodeCount := len(nodes)
+	var buf []byte
+	for _, node := range nodes {
+		for _, r := range node {
+			value := r
+			switch {
+			case r == -1:
+				value = nodeCount
+			case r <= -2:
+				value = nodeCount + 16 + (-r - 2)
+			}
+			buf = append(buf, byte(value>>16), byte(value>>8), byte(value))
+		}
+	}
+	buf = append(buf, make([]byte, 16)...)
+	buf = append(buf, data...)
+
+	buf = append(buf, "\xAB\xCD\xEFMaxMind.com"...)
+	buf = append(buf, 0xE5)
+	buf = append(buf, mmdbString("node_count")...)
+	buf = append(buf, 0xC4)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(nodeCount))
+	buf = append(buf, mmdbString("record_size")...)
+	buf = append(buf, 0xA1, 24)
+	buf = append(buf, mmdbString("ip_version")...)
+	buf = append(buf, 0xA1, 4)
+	buf = append(buf, mmdbString("database_type")...)
+	buf = append(buf, mmdbString("Test-Country")...)
+	buf = append(buf, mmdbString("binary_format_major_version")...)
+	buf = append(buf, 0xA1, 2)
+
+	path := filepath.Join(t.TempDir(), "country.mmdb")
+	require.NoError(t, os.WriteFile(path, buf, 0o600))
+	return path
+}
+
+// mmdbString encodes a short (< 29 bytes) UTF-8 string
+func mmdbString(s string) []byte {
+	return append([]byte{0x40 | byte(len(s))}, s...)
+}
+
+func TestReader_Country(t *testing.T) {
+	path := writeTestDB(t, map[string]string{
+		"203.0.113.0/24":  "TH",
+		"198.51.100.0/24": "sg",
+	})
+
+	reader, err := Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reader.Close() })
+
+	assert.Equal(t, "TH", reader.Country(net.ParseIP("203.0.113.7")))
+	assert.Equal(t, "SG", reader.Country(net.ParseIP("198.51.100.1")))
+	assert.Equal(t, "", reader.Country(net.ParseIP("192.0.2.1")), "unknown network")
+	assert.Equal(t, "", reader.Country(net.ParseIP("2001:db8::1")), "IPv6 in an IPv4 database")
+	assert.Equal(t, "", reader.Country(nil))
+
+	var none *Reader
+	assert.Equal(t, "", none.Country(net.ParseIP("203.0.113.7")))
+}
+
+func TestOpen_Missing(t *testing.T) {
+	_, err := Open(filepath.Join(t.TempDir(), "missing.mmdb"))
+	assert.ErrorContains(t, err, "failed to open geoip database")
+}
@@ -41,6 +41,10 @@ func Int(key string, value int) Field {
 	return Field{Key: key, Value: value}
 }
 
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
 func Error(err error) Field {
 	return Field{Key: "error", Value: err.Error()}
 }
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AlertDirection is the price movement an alert rule fires on
+type AlertDirection string
+
+const (
+	AlertDirectionDrop AlertDirection = "drop"
+	AlertDirectionRise AlertDirection = "rise"
+	AlertDirectionAny  AlertDirection = "any"
+)
+
+// AlertThresholdType is how an alert rule threshold is measured
+type AlertThresholdType string
+
+const (
+	AlertThresholdPercent  AlertThresholdType = "percent"
+	AlertThresholdAbsolute AlertThresholdType = "absolute"
+)
+
+// AlertDeliveryStatus is the state of a webhook delivery
+type AlertDeliveryStatus string
+
+const (
+	AlertDeliveryPending   AlertDeliveryStatus = "pending"
+	AlertDeliveryDelivered AlertDeliveryStatus = "delivered"
+	AlertDeliveryFailed    AlertDeliveryStatus = "failed"
+)
+
+// AlertRule represents a price change alert rule scoped to a product or a campaign
+type AlertRule struct {
+	ID            uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name          string             `gorm:"type:varchar(200);not null" json:"name"`
+	ProductID     *uuid.UUID         `gorm:"type:uuid;index" json:"product_id,omitempty"`
+	CampaignID    *uuid.UUID         `gorm:"type:uuid;index" json:"campaign_id,omitempty"`
+	Marketplace   *Marketplace       `gorm:"type:varchar(20)" json:"marketplace,omitempty"` // Optional filter, nil matches all marketplaces
+	Direction     AlertDirection     `gorm:"type:varchar(10);not null;default:'drop'" json:"direction"`
+	ThresholdType AlertThresholdType `gorm:"type:varchar(10);not null" json:"threshold_type"`
+	Threshold     float64            `gorm:"type:decimal(10,2);not null" json:"threshold"`
+	WebhookURL    string             `gorm:"type:text;not null" json:"webhook_url"`
+	Secret        string             `gorm:"type:varchar(255);not null" json:"-"`
+	IsActive      bool               `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt     time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for AlertRule
+func (AlertRule) TableName() string {
+	return "alert_rules"
+}
+
+// BeforeCreate hook to set UUID if not set
+func (a *AlertRule) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// AlertDelivery is a delivery log entry for an alert webhook
+type AlertDelivery struct {
+	ID             uuid.UUID           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	AlertRuleID    uuid.UUID           `gorm:"type:uuid;not null;index:idx_alert_deliveries_rule_created" json:"alert_rule_id"`
+	OfferID        uuid.UUID           `gorm:"type:uuid;not null" json:"offer_id"`
+	ProductID      uuid.UUID           `gorm:"type:uuid;not null" json:"product_id"`
+	Marketplace    Marketplace         `gorm:"type:varchar(20);not null" json:"marketplace"`
+	OldPrice       float64             `gorm:"type:decimal(10,2);not null" json:"old_price"`
+	NewPrice       float64             `gorm:"type:decimal(10,2);not null" json:"new_price"`
+	Payload        string              `gorm:"type:jsonb;not null" json:"payload"`
+	Status         AlertDeliveryStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Attempts       int                 `gorm:"not null;default:0" json:"attempts"`
+	ResponseStatus *int                `json:"response_status,omitempty"`
+	LastError      string              `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time          `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time           `gorm:"autoCreateTime;index:idx_alert_deliveries_rule_created" json:"created_at"`
+	UpdatedAt      time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for AlertDelivery
+func (AlertDelivery) TableName() string {
+	return "alert_deliveries"
+}
+
+// BeforeCreate hook to set UUID if not set
+func (d *AlertDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
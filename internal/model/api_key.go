@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKey is an admin API key with a set of scopes
+// Only the SHA-256 hash of the key is stored; the key is shown once, on create
+type APIKey struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	WorkspaceID uuid.UUID  `gorm:"type:uuid;not null;index" json:"workspace_id"`
+	Name        string     `gorm:"type:varchar(100);not null" json:"name"`
+	KeyPrefix   string     `gorm:"type:varchar(16);not null" json:"key_prefix"` // First characters of the key, to tell keys apart
+	KeyHash     string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	Scopes      []string   `gorm:"type:jsonb;serializer:json;not null" json:"scopes"` // read, write and/or worker
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`                              // nil: never expires
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for APIKey
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// BeforeCreate hook to set UUID if not set
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditAction is what an audited admin request did
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"  // Soft delete
+	AuditActionRestore AuditAction = "restore" // Undoing a soft delete
+	AuditActionPurge   AuditAction = "purge"   // Permanent deletion of a soft-deleted entity
+	AuditActionTrigger AuditAction = "trigger" // A manual worker run
+)
+
+// AuditEntityType is the kind of entity an audit event is about
+type AuditEntityType string
+
+const (
+	AuditEntityProduct      AuditEntityType = "product"
+	AuditEntityCampaign     AuditEntityType = "campaign"
+	AuditEntityLink         AuditEntityType = "link"
+	AuditEntityPriceRefresh AuditEntityType = "price_refresh" // Worker job; events have no entity ID
+)
+
+// AuditActorSystem is the actor of events recorded without an authenticated caller
+const AuditActorSystem = "system"
+
+// AuditEvent records who changed what in the admin API
+// Before and After hold only the fields that changed for updates, the full
+// entity for creates and restores (After) and deletes and purges (Before). Events outlive their entity,
+// so EntityID has no foreign key.
+type AuditEvent struct {
+	ID          uuid.UUID              `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	WorkspaceID *uuid.UUID             `gorm:"type:uuid;index" json:"workspace_id,omitempty"` // nil for changes made outside a workspace
+	Actor       string                 `gorm:"type:varchar(100);not null;index" json:"actor"` // Principal subject, e.g. user:<id>, or "system"
+	ActorName   string                 `gorm:"type:varchar(255);not null" json:"actor_name"`  // The user's email, the API key name or the username
+	Action      AuditAction            `gorm:"type:varchar(20);not null" json:"action"`
+	EntityType  AuditEntityType        `gorm:"type:varchar(30);not null" json:"entity_type"`
+	EntityID    *uuid.UUID             `gorm:"type:uuid" json:"entity_id,omitempty"`
+	Before      map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"before,omitempty"`
+	After       map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"after,omitempty"`
+	CreatedAt   time.Time              `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for AuditEvent
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// BeforeCreate hook to set UUID if not set
+func (e *AuditEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// AuditEventFilter narrows an audit event listing; zero fields match everything
+type AuditEventFilter struct {
+	EntityType AuditEntityType
+	EntityID   *uuid.UUID
+	Actor      string // Matches the actor or the actor name
+	From       time.Time
+	To         time.Time
+}
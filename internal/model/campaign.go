@@ -7,15 +7,35 @@ import (
 	"gorm.io/gorm"
 )
 
+// OutOfWindowPolicy decides what redirects do before start_at or after end_at
+type OutOfWindowPolicy string
+
+const (
+	OutOfWindowRedirect OutOfWindowPolicy = "redirect" // Redirect to the marketplace anyway
+	OutOfWindowFallback OutOfWindowPolicy = "fallback" // Redirect to the campaign's fallback URL
+	OutOfWindowGone     OutOfWindowPolicy = "gone"     // Show an "offer ended" page (410)
+)
+
 // Campaign represents a marketing campaign
 type Campaign struct {
 	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
 	Name        string    `gorm:"type:varchar(200);not null" json:"name"`
 	UTMCampaign string    `gorm:"type:varchar(100);not null" json:"utm_campaign"`
+	UTMSource   string    `gorm:"type:varchar(100);not null" json:"utm_source"` // UTM values may use {{product_id}}-style placeholders
+	UTMMedium   string    `gorm:"type:varchar(100);not null" json:"utm_medium"`
+	UTMContent  string    `gorm:"type:varchar(100);not null" json:"utm_content"`
+	UTMTerm     string    `gorm:"type:varchar(100);not null" json:"utm_term"`
 	StartAt     time.Time `gorm:"not null;index:idx_campaigns_dates" json:"start_at"`
 	EndAt       time.Time `gorm:"not null;index:idx_campaigns_dates;check:end_at > start_at" json:"end_at"`
-	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	OutOfWindowPolicy OutOfWindowPolicy `gorm:"type:varchar(20);not null;default:redirect" json:"out_of_window_policy"`
+	FallbackURL       string            `gorm:"type:text;not null" json:"fallback_url,omitempty"` // Required by the fallback policy
+	BestPriceLink     bool              `gorm:"not null" json:"best_price_link"`                  // Sync a best-price link per product
+
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete; set rows are hidden until restored or purged
 
 	// Relationships
 	CampaignProducts []CampaignProduct `gorm:"foreignKey:CampaignID;constraint:OnDelete:CASCADE" json:"campaign_products,omitempty"`
@@ -27,6 +47,11 @@ func (Campaign) TableName() string {
 	return "campaigns"
 }
 
+// InWindow reports whether t is within the campaign's start_at..end_at window
+func (c *Campaign) InWindow(t time.Time) bool {
+	return !t.Before(c.StartAt) && !t.After(c.EndAt)
+}
+
 // BeforeCreate hook to set UUID if not set
 func (c *Campaign) BeforeCreate(tx *gorm.DB) error {
 	if c.ID == uuid.Nil {
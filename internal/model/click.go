@@ -7,15 +7,52 @@ import (
 	"gorm.io/gorm"
 )
 
+// ClickOutcome is what a redirect did with a click
+type ClickOutcome string
+
+const (
+	ClickOutcomeRedirected ClickOutcome = "redirected" // Sent to the marketplace
+	ClickOutcomeFallback   ClickOutcome = "fallback"   // Sent to the campaign's fallback URL
+	ClickOutcomeGone       ClickOutcome = "gone"       // Shown the "offer ended" page (410)
+)
+
+// Reasons recorded with clicks that were not a normal in-window redirect
+const (
+	OutcomeReasonCampaignNotStarted = "campaign_not_started"
+	OutcomeReasonCampaignEnded      = "campaign_ended"
+	OutcomeReasonLinkPaused         = "link_paused"
+	OutcomeReasonLinkArchived       = "link_archived"
+	OutcomeReasonLinkExpired        = "link_expired"
+)
+
 // Click represents a click tracking record
+// The ID doubles as the click ID passed to the marketplace as a sub-ID, so it
+// is minted before the redirect rather than on insert
 type Click struct {
-	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	LinkID    uuid.UUID `gorm:"type:uuid;not null;index:idx_clicks_link_id;index:idx_clicks_link_timestamp" json:"link_id"`
-	Timestamp time.Time `gorm:"default:now();index:idx_clicks_timestamp;index:idx_clicks_link_timestamp" json:"timestamp"`
-	Referrer  string    `gorm:"type:text" json:"referrer"`
-	UserAgent string    `gorm:"type:text" json:"user_agent"`
-	IPAddress string    `gorm:"type:inet" json:"ip_address"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+	LinkID      uuid.UUID `gorm:"type:uuid;not null;index:idx_clicks_link_id;index:idx_clicks_link_timestamp;index:idx_clicks_link_visitor" json:"link_id"`
+	Timestamp   time.Time `gorm:"default:now();index:idx_clicks_timestamp;index:idx_clicks_link_timestamp" json:"timestamp"`
+	Referrer    string    `gorm:"type:text" json:"referrer"`
+	UserAgent   string    `gorm:"type:text" json:"user_agent"`
+	IPAddress   string    `gorm:"type:inet" json:"ip_address"`
+	IsBot       bool      `gorm:"not null;default:false" json:"is_bot"`
+	BotReason   string    `gorm:"type:varchar(50)" json:"bot_reason,omitempty"` // e.g. head_request, prefetch, ip_range, ua:facebook
+	VisitorID   string    `gorm:"type:varchar(64);index:idx_clicks_link_visitor" json:"visitor_id,omitempty"`
+	IsUnique    bool      `gorm:"not null;default:false" json:"is_unique"` // First click by the visitor on the link within the dedupe window
+	Sub1        string    `gorm:"type:varchar(50)" json:"sub1,omitempty"`  // Publisher sub IDs from ?sub1=..&sub5=
+	Sub2        string    `gorm:"type:varchar(50)" json:"sub2,omitempty"`
+	Sub3        string    `gorm:"type:varchar(50)" json:"sub3,omitempty"`
+	Sub4        string    `gorm:"type:varchar(50)" json:"sub4,omitempty"`
+	Sub5        string    `gorm:"type:varchar(50)" json:"sub5,omitempty"`
+
+	Outcome       ClickOutcome `gorm:"type:varchar(20);not null;default:redirected" json:"outcome"`
+	OutcomeReason string       `gorm:"type:varchar(30)" json:"outcome_reason,omitempty"` // e.g. campaign_ended, link_paused
+	CreatedAt     time.Time    `gorm:"autoCreateTime" json:"created_at"`
+
+	VariantID   *uuid.UUID   `gorm:"type:uuid" json:"variant_id,omitempty"`         // A/B variant the click was sent to
+	Marketplace *Marketplace `gorm:"type:varchar(20)" json:"marketplace,omitempty"` // Marketplace chosen by a best-price link
+	Source      string       `gorm:"type:varchar(20)" json:"source,omitempty"`      // ?src= marker, e.g. qr for QR code scans
 
 	// Relationships
 	Link Link `gorm:"foreignKey:LinkID" json:"link,omitempty"`
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ConversionStatus is the network-reported state of an order
+type ConversionStatus string
+
+const (
+	ConversionPending  ConversionStatus = "pending"
+	ConversionApproved ConversionStatus = "approved"
+	ConversionRejected ConversionStatus = "rejected"
+)
+
+// Conversion represents an order reported by an affiliate network postback
+type Conversion struct {
+	ID              uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Network         string           `gorm:"type:varchar(50);not null;uniqueIndex:idx_conversions_network_order" json:"network"`
+	OrderID         string           `gorm:"type:varchar(100);not null;uniqueIndex:idx_conversions_network_order" json:"order_id"`
+	ClickRef        string           `gorm:"type:varchar(100)" json:"click_ref,omitempty"` // Click or sub ID as reported by the network
+	ClickID         *uuid.UUID       `gorm:"type:uuid;index:idx_conversions_click_id" json:"click_id,omitempty"`
+	LinkID          *uuid.UUID       `gorm:"type:uuid" json:"link_id,omitempty"`
+	CampaignID      *uuid.UUID       `gorm:"type:uuid;index:idx_conversions_campaign_created" json:"campaign_id,omitempty"`
+	ProductID       *uuid.UUID       `gorm:"type:uuid" json:"product_id,omitempty"`
+	Marketplace     *Marketplace     `gorm:"type:varchar(20)" json:"marketplace,omitempty"`
+	Amount          float64          `gorm:"type:decimal(12,2);not null;default:0" json:"amount"`     // Order value
+	Commission      float64          `gorm:"type:decimal(12,2);not null;default:0" json:"commission"` // Our revenue
+	Currency        string           `gorm:"type:varchar(3)" json:"currency,omitempty"`
+	Status          ConversionStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	StatusUpdatedAt time.Time        `gorm:"not null;default:now()" json:"status_updated_at"`
+	CreatedAt       time.Time        `gorm:"autoCreateTime;index:idx_conversions_created_at;index:idx_conversions_campaign_created" json:"created_at"`
+	UpdatedAt       time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for Conversion
+func (Conversion) TableName() string {
+	return "conversions"
+}
+
+// BeforeCreate hook to set UUID if not set
+func (c *Conversion) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
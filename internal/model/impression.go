@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ImpressionKind distinguishes campaign page views from rendered links
+type ImpressionKind string
+
+const (
+	// ImpressionKindCampaign is recorded once per public campaign view
+	ImpressionKindCampaign ImpressionKind = "campaign"
+	// ImpressionKindLink is recorded for each product link rendered in a campaign view
+	ImpressionKindLink ImpressionKind = "link"
+)
+
+// Impression represents a campaign view or a link shown to a visitor
+type Impression struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Kind        ImpressionKind `gorm:"type:varchar(20);not null" json:"kind"`
+	CampaignID  uuid.UUID      `gorm:"type:uuid;not null;index:idx_impressions_campaign_timestamp" json:"campaign_id"`
+	ProductID   *uuid.UUID     `gorm:"type:uuid;index:idx_impressions_product_timestamp" json:"product_id,omitempty"`
+	LinkID      *uuid.UUID     `gorm:"type:uuid" json:"link_id,omitempty"`
+	Marketplace *Marketplace   `gorm:"type:varchar(20)" json:"marketplace,omitempty"`
+	Timestamp   time.Time      `gorm:"default:now();index:idx_impressions_timestamp;index:idx_impressions_campaign_timestamp;index:idx_impressions_product_timestamp" json:"timestamp"`
+	IsBot       bool           `gorm:"not null;default:false" json:"is_bot"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for Impression
+func (Impression) TableName() string {
+	return "impressions"
+}
+
+// BeforeCreate hook to set UUID if not set
+func (i *Impression) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
@@ -7,21 +7,48 @@ import (
 	"gorm.io/gorm"
 )
 
+// LinkStatus is the lifecycle state of a link
+type LinkStatus string
+
+const (
+	LinkStatusActive   LinkStatus = "active"
+	LinkStatusPaused   LinkStatus = "paused"   // Temporarily not redirecting
+	LinkStatusArchived LinkStatus = "archived" // Retired; kept for analytics
+)
+
+// MarketplaceBestPrice is the marketplace of best-price links, which redirect to
+// the product's cheapest offer at click time
+const MarketplaceBestPrice Marketplace = "best_price"
+
 // Link represents an affiliate link
 type Link struct {
 	ID          uuid.UUID   `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	WorkspaceID uuid.UUID   `gorm:"type:uuid;not null;index" json:"workspace_id"`
 	ProductID   uuid.UUID   `gorm:"type:uuid;not null;index:idx_links_product_campaign" json:"product_id"`
 	CampaignID  uuid.UUID   `gorm:"type:uuid;not null;index:idx_links_product_campaign" json:"campaign_id"`
-	Marketplace Marketplace `gorm:"type:varchar(20);not null;check:marketplace IN ('lazada', 'shopee')" json:"marketplace"`
-	ShortCode   string      `gorm:"type:varchar(20);not null;uniqueIndex:idx_links_short_code" json:"short_code"`
+	Marketplace Marketplace `gorm:"type:varchar(20);not null" json:"marketplace"`
+	ShortCode   string      `gorm:"type:varchar(32);not null;uniqueIndex:idx_links_short_code" json:"short_code"`
 	TargetURL   string      `gorm:"type:text;not null" json:"target_url"`
+	UTMSource   *string     `gorm:"type:varchar(100)" json:"utm_source,omitempty"` // UTM overrides; nil inherits the campaign value
+	UTMMedium   *string     `gorm:"type:varchar(100)" json:"utm_medium,omitempty"`
+	UTMContent  *string     `gorm:"type:varchar(100)" json:"utm_content,omitempty"`
+	UTMTerm     *string     `gorm:"type:varchar(100)" json:"utm_term,omitempty"`
+	Status      LinkStatus  `gorm:"type:varchar(20);not null;default:active" json:"status"`
+	ExpiresAt   *time.Time  `json:"expires_at,omitempty"` // Treated like a paused link from then on
 	CreatedAt   time.Time   `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
 
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete; deleted links keep their clicks and short code
+
 	// Relationships
-	Product  Product  `gorm:"foreignKey:ProductID" json:"product,omitempty"`
-	Campaign Campaign `gorm:"foreignKey:CampaignID" json:"campaign,omitempty"`
-	Clicks   []Click  `gorm:"foreignKey:LinkID;constraint:OnDelete:CASCADE" json:"clicks,omitempty"`
+	Workspace *Workspace  `gorm:"foreignKey:WorkspaceID" json:"-"`
+	Product   Product     `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	Campaign  Campaign    `gorm:"foreignKey:CampaignID" json:"campaign,omitempty"`
+	Clicks    []Click     `gorm:"foreignKey:LinkID;constraint:OnDelete:CASCADE" json:"clicks,omitempty"`
+	Aliases   []LinkAlias `gorm:"foreignKey:LinkID;constraint:OnDelete:CASCADE" json:"aliases,omitempty"`
+
+	RedirectRules []RedirectRule `gorm:"foreignKey:LinkID;constraint:OnDelete:CASCADE" json:"redirect_rules,omitempty"`
+	Variants      []LinkVariant  `gorm:"foreignKey:LinkID;constraint:OnDelete:CASCADE" json:"variants,omitempty"`
 }
 
 // TableName specifies the table name for Link
@@ -29,6 +56,11 @@ func (Link) TableName() string {
 	return "links"
 }
 
+// IsActive reports whether the link redirects at t (active and not expired)
+func (l *Link) IsActive(t time.Time) bool {
+	return (l.Status == "" || l.Status == LinkStatusActive) && (l.ExpiresAt == nil || t.Before(*l.ExpiresAt))
+}
+
 // BeforeCreate hook to set UUID if not set
 func (l *Link) BeforeCreate(tx *gorm.DB) error {
 	if l.ID == uuid.Nil {
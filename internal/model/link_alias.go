@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LinkAlias is an additional short code that resolves to a link
+// Renaming a link's short code keeps the previous code as an alias so it keeps redirecting
+type LinkAlias struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	LinkID    uuid.UUID `gorm:"type:uuid;not null;index:idx_link_aliases_link_id" json:"link_id"`
+	ShortCode string    `gorm:"type:varchar(32);not null;uniqueIndex" json:"short_code"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for LinkAlias
+func (LinkAlias) TableName() string {
+	return "link_aliases"
+}
+
+// BeforeCreate hook to set UUID if not set
+func (a *LinkAlias) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LinkVariant is one arm of an A/B split link
+// Visitors are assigned to a variant in proportion to its weight and keep it
+// on later clicks. A weight of 0 stops new traffic but keeps the variant's stats.
+type LinkVariant struct {
+	ID          uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	LinkID      uuid.UUID    `gorm:"type:uuid;not null;index:idx_link_variants_link_id" json:"link_id"`
+	Name        string       `gorm:"type:varchar(100);not null" json:"name"`
+	Weight      int          `gorm:"not null" json:"weight"`
+	Marketplace *Marketplace `gorm:"type:varchar(20)" json:"marketplace,omitempty"` // Marketplace offer variants; nil for landing URLs
+	TargetURL   string       `gorm:"type:text;not null" json:"target_url"`          // Resolved from the offer for marketplace variants
+	CreatedAt   time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for LinkVariant
+func (LinkVariant) TableName() string {
+	return "link_variants"
+}
+
+// BeforeCreate hook to set UUID if not set
+func (v *LinkVariant) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
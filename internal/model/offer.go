@@ -19,7 +19,7 @@ const (
 type Offer struct {
 	ID                    uuid.UUID   `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	ProductID             uuid.UUID   `gorm:"type:uuid;not null;index" json:"product_id"`
-	Marketplace           Marketplace `gorm:"type:varchar(20);not null;check:marketplace IN ('lazada', 'shopee');index" json:"marketplace"`
+	Marketplace           Marketplace `gorm:"type:varchar(20);not null;index" json:"marketplace"`
 	StoreName             string      `gorm:"type:varchar(200)" json:"store_name"`
 	Price                 float64     `gorm:"type:decimal(10,2);not null;check:price >= 0" json:"price"`
 	MarketplaceProductURL string      `gorm:"type:text;not null" json:"marketplace_product_url"`
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OfferPriceHistory is an append-only record of an offer's price at a point in time
+type OfferPriceHistory struct {
+	ID          uuid.UUID   `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OfferID     uuid.UUID   `gorm:"type:uuid;not null;index" json:"offer_id"`
+	ProductID   uuid.UUID   `gorm:"type:uuid;not null;index:idx_offer_price_history_product_recorded" json:"product_id"`
+	Marketplace Marketplace `gorm:"type:varchar(20);not null" json:"marketplace"`
+	StoreName   string      `gorm:"type:varchar(200)" json:"store_name"`
+	Price       float64     `gorm:"type:decimal(10,2);not null" json:"price"`
+	RecordedAt  time.Time   `gorm:"default:now();index:idx_offer_price_history_product_recorded" json:"recorded_at"`
+	CreatedAt   time.Time   `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for OfferPriceHistory
+func (OfferPriceHistory) TableName() string {
+	return "offer_price_history"
+}
+
+// BeforeCreate hook to set UUID if not set
+func (h *OfferPriceHistory) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	return nil
+}
+
+// NewOfferPriceHistory builds a price history entry from the current state of an offer
+func NewOfferPriceHistory(offer *Offer) *OfferPriceHistory {
+	recordedAt := offer.LastCheckedAt
+	if recordedAt.IsZero() {
+		recordedAt = time.Now()
+	}
+	return &OfferPriceHistory{
+		OfferID:     offer.ID,
+		ProductID:   offer.ProductID,
+		Marketplace: offer.Marketplace,
+		StoreName:   offer.StoreName,
+		Price:       offer.Price,
+		RecordedAt:  recordedAt,
+	}
+}
@@ -9,11 +9,14 @@ import (
 
 // Product represents a product entity
 type Product struct {
-	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	Title     string    `gorm:"type:varchar(500);not null" json:"title"`
-	ImageURL  string    `gorm:"type:text" json:"image_url"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+	Title       string    `gorm:"type:varchar(500);not null" json:"title"`
+	ImageURL    string    `gorm:"type:text" json:"image_url"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete; set rows are hidden until restored or purged
 
 	// Relationships
 	Offers []Offer `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"offers,omitempty"`
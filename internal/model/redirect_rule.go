@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RedirectTargetType is where a matching redirect rule sends the visitor
+type RedirectTargetType string
+
+const (
+	RedirectTargetWeb         RedirectTargetType = "web"         // A web URL
+	RedirectTargetDeepLink    RedirectTargetType = "deep_link"   // An app deep link with a web fallback
+	RedirectTargetMarketplace RedirectTargetType = "marketplace" // The product's offer on another marketplace
+)
+
+// RedirectRule routes a link's visitors by device OS, country and language
+// Empty match lists match any visitor. Rules are evaluated by priority (lowest
+// first) and the first matching active rule wins.
+type RedirectRule struct {
+	ID          uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	LinkID      uuid.UUID          `gorm:"type:uuid;not null;index:idx_redirect_rules_link_id" json:"link_id"`
+	Name        string             `gorm:"type:varchar(200);not null" json:"name"`
+	Priority    int                `gorm:"not null;index:idx_redirect_rules_link_id" json:"priority"`
+	OS          []string           `gorm:"type:jsonb;serializer:json;not null" json:"os"`        // e.g. ios, android
+	Countries   []string           `gorm:"type:jsonb;serializer:json;not null" json:"countries"` // ISO 3166-1 alpha-2
+	Languages   []string           `gorm:"type:jsonb;serializer:json;not null" json:"languages"` // Primary language subtags
+	TargetType  RedirectTargetType `gorm:"type:varchar(20);not null" json:"target_type"`
+	TargetURL   string             `gorm:"type:text;not null" json:"target_url"`             // Resolved from the offer for marketplace targets
+	FallbackURL string             `gorm:"type:text;not null" json:"fallback_url,omitempty"` // Web fallback for deep links
+	Marketplace *Marketplace       `gorm:"type:varchar(20)" json:"marketplace,omitempty"`    // Marketplace targets only
+	IsActive    bool               `gorm:"not null" json:"is_active"`
+	CreatedAt   time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for RedirectRule
+func (RedirectRule) TableName() string {
+	return "redirect_rules"
+}
+
+// BeforeCreate hook to set UUID if not set
+func (r *RedirectRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -9,12 +11,21 @@ type CampaignStatResult struct {
 	CampaignID   uuid.UUID
 	CampaignName string
 	Clicks       int64
+	UniqueClicks int64
 }
 
 // MarketplaceStatResult represents marketplace statistics result from repository
 type MarketplaceStatResult struct {
-	Marketplace string
-	Clicks      int64
+	Marketplace  string
+	Clicks       int64
+	UniqueClicks int64
+}
+
+// SourceStatResult represents click source statistics result from repository
+type SourceStatResult struct {
+	Source       string
+	Clicks       int64
+	UniqueClicks int64
 }
 
 // TopProductResult represents top product statistics result from repository
@@ -24,3 +35,75 @@ type TopProductResult struct {
 	Marketplace string
 	Clicks      int64
 }
+
+// MarketplaceMinPriceResult represents the lowest recorded price per marketplace from repository
+type MarketplaceMinPriceResult struct {
+	Marketplace string
+	MinPrice    float64
+}
+
+// ClickTimeBucketResult represents click counts for one time bucket from repository
+type ClickTimeBucketResult struct {
+	Bucket       time.Time
+	Clicks       int64
+	UniqueClicks int64
+}
+
+// ClickBreakdownResult represents click counts grouped by a single dimension from repository
+type ClickBreakdownResult struct {
+	Value  string
+	Clicks int64
+}
+
+// ImpressionCountResult represents campaign views and link impressions from repository
+type ImpressionCountResult struct {
+	PageViews       int64
+	LinkImpressions int64
+}
+
+// CampaignImpressionResult represents impressions grouped by campaign from repository
+type CampaignImpressionResult struct {
+	CampaignID      uuid.UUID
+	PageViews       int64
+	LinkImpressions int64
+}
+
+// MarketplaceImpressionResult represents link impressions grouped by marketplace from repository
+type MarketplaceImpressionResult struct {
+	Marketplace string
+	Impressions int64
+}
+
+// ProductImpressionResult represents link impressions grouped by product and marketplace from repository
+type ProductImpressionResult struct {
+	ProductID   uuid.UUID
+	Marketplace string
+	Impressions int64
+}
+
+// ConversionSummaryResult represents conversion totals from repository
+type ConversionSummaryResult struct {
+	Conversions int64
+	Revenue     float64
+}
+
+// CampaignConversionResult represents conversion totals grouped by campaign from repository
+type CampaignConversionResult struct {
+	CampaignID  uuid.UUID
+	Conversions int64
+	Revenue     float64
+}
+
+// VariantClickResult represents click counts grouped by A/B variant from repository
+type VariantClickResult struct {
+	VariantID    uuid.UUID
+	Clicks       int64
+	UniqueClicks int64
+}
+
+// VariantConversionResult represents conversion totals grouped by A/B variant from repository
+type VariantConversionResult struct {
+	VariantID   uuid.UUID
+	Conversions int64
+	Revenue     float64
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Built-in roles (seeded by migration)
+const (
+	RoleAdmin           = "admin"            // read, write and worker scopes; manages users and API keys
+	RoleCampaignManager = "campaign_manager" // read and write scopes
+	RoleAnalyst         = "analyst"          // read scope
+)
+
+// Role grants admin API scopes to the users that have it
+type Role struct {
+	Name        string   `gorm:"type:varchar(30);primary_key" json:"name"`
+	Description string   `gorm:"type:text;not null" json:"description"`
+	Scopes      []string `gorm:"type:jsonb;serializer:json;not null" json:"scopes"` // read, write and/or worker
+}
+
+// TableName specifies the table name for Role
+func (Role) TableName() string {
+	return "roles"
+}
+
+// User is an admin UI account
+type User struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	WorkspaceID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"workspace_id"`
+	Email        string     `gorm:"type:varchar(255);not null;uniqueIndex" json:"email"` // Lower-cased
+	Name         string     `gorm:"type:varchar(100);not null" json:"name"`
+	PasswordHash string     `gorm:"type:varchar(100);not null" json:"-"` // bcrypt
+	RoleName     string     `gorm:"type:varchar(30);not null;index" json:"role"`
+	IsActive     bool       `gorm:"not null;default:true" json:"is_active"`
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Role *Role `gorm:"foreignKey:RoleName;references:Name" json:"-"`
+}
+
+// TableName specifies the table name for User
+func (User) TableName() string {
+	return "users"
+}
+
+// BeforeCreate hook to set UUID if not set
+func (u *User) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	return nil
+}
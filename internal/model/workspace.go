@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultWorkspaceID is the workspace created by migration for data that predates workspaces
+var DefaultWorkspaceID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Workspace is a publisher (affiliate brand) on a shared deployment
+// Products, campaigns, links and clicks, users and API keys belong to exactly one
+type Workspace struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name           string    `gorm:"type:varchar(100);not null" json:"name"`
+	Slug           string    `gorm:"type:varchar(50);not null;uniqueIndex" json:"slug"`
+	RedirectDomain string    `gorm:"type:varchar(255);not null" json:"redirect_domain,omitempty"` // Host of the workspace's short links; empty: api.base_url
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for Workspace
+func (Workspace) TableName() string {
+	return "workspaces"
+}
+
+// BeforeCreate hook to set UUID if not set
+func (w *Workspace) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
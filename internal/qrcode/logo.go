@@ -0,0 +1,66 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register JPEG logos
+	"image/png"
+	"os"
+)
+
+// Logo is an image drawn over the center of QR codes
+type Logo struct {
+	img image.Image
+	png []byte // PNG encoding embedded in SVG output
+}
+
+// LoadLogo reads a PNG or JPEG logo
+func LoadLogo(path string) (*Logo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read QR logo: %w", err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode QR logo: %w", err)
+	}
+	return NewLogo(img)
+}
+
+// NewLogo creates a logo from an image
+func NewLogo(img image.Image) (*Logo, error) {
+	if img.Bounds().Empty() {
+		return nil, fmt.Errorf("QR logo is empty")
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode QR logo: %w", err)
+	}
+	return &Logo{img: img, png: buf.Bytes()}, nil
+}
+
+// scaled returns the logo resized (nearest neighbour) to fit a box x box
+// square, keeping its aspect ratio
+func (l *Logo) scaled(box int) image.Image {
+	src := l.img.Bounds()
+	w, h := box, box
+	if src.Dx() > src.Dy() {
+		h = box * src.Dy() / src.Dx()
+	} else {
+		w = box * src.Dx() / src.Dy()
+	}
+	if w < 1 || h < 1 {
+		return image.NewRGBA(image.Rectangle{})
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		sy := src.Min.Y + y*src.Dy()/h
+		for x := 0; x < w; x++ {
+			sx := src.Min.X + x*src.Dx()/w
+			dst.Set(x, y, l.img.At(sx, sy))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,170 @@
+// Package qrcode renders QR codes as PNG or SVG images with an optional
+// centered logo; symbols are encoded by github.com/skip2/go-qrcode (pure Go)
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	goqrcode "github.com/skip2/go-qrcode"
+)
+
+// Format is an output image format
+type Format string
+
+const (
+	FormatPNG Format = "png"
+	FormatSVG Format = "svg"
+)
+
+// Level is an error correction level: the share of the symbol that can be
+// damaged (or covered by a logo) and still decode
+type Level string
+
+const (
+	LevelLow      Level = "L" // ~7%
+	LevelMedium   Level = "M" // ~15%
+	LevelQuartile Level = "Q" // ~25%
+	LevelHigh     Level = "H" // ~30%
+)
+
+// logoRatio is the logo's width relative to the symbol (without the quiet zone)
+// 20% of the width covers ~4% of the area, well within Q and H recovery
+const logoRatio = 0.2
+
+// ErrTooSmall is returned when a PNG's size cannot fit one pixel per module
+var ErrTooSmall = errors.New("image size too small for the QR code")
+
+// Options configures a rendered QR code
+type Options struct {
+	Format Format
+	Size   int   // Image width and height in pixels
+	Margin int   // Quiet zone around the symbol in modules
+	Level  Level // Defaults to medium
+	Logo   *Logo // Optional; drawn over the center of the symbol
+}
+
+// Encode renders content as a QR code image
+func Encode(content string, opts Options) ([]byte, error) {
+	level, err := recoveryLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	q, err := goqrcode.New(content, level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	q.DisableBorder = true
+	modules := q.Bitmap()
+
+	switch opts.Format {
+	case FormatPNG, "":
+		return renderPNG(modules, opts)
+	case FormatSVG:
+		return renderSVG(modules, opts)
+	}
+	return nil, fmt.Errorf("unsupported format: %s", opts.Format)
+}
+
+// recoveryLevel maps a level to the encoder's recovery level
+func recoveryLevel(level Level) (goqrcode.RecoveryLevel, error) {
+	switch level {
+	case LevelLow:
+		return goqrcode.Low, nil
+	case LevelMedium, "":
+		return goqrcode.Medium, nil
+	case LevelQuartile:
+		return goqrcode.High, nil
+	case LevelHigh:
+		return goqrcode.Highest, nil
+	}
+	return 0, fmt.Errorf("unsupported error correction level: %s", level)
+}
+
+// renderPNG draws modules scaled by the largest whole factor that fits, so
+// modules stay crisp; leftover pixels widen the quiet zone evenly
+func renderPNG(modules [][]bool, opts Options) ([]byte, error) {
+	n := len(modules)
+	scale := opts.Size / (n + 2*opts.Margin)
+	if scale < 1 {
+		return nil, fmt.Errorf("%w: needs at least %d pixels", ErrTooSmall, n+2*opts.Margin)
+	}
+	offset := (opts.Size - n*scale) / 2
+
+	bounds := image.Rect(0, 0, opts.Size, opts.Size)
+	var img draw.Image = image.NewPaletted(bounds, color.Palette{color.White, color.Black})
+	if opts.Logo != nil {
+		img = image.NewRGBA(bounds)
+	}
+	draw.Draw(img, bounds, image.White, image.Point{}, draw.Src)
+	for y, row := range modules {
+		for x, dark := range row {
+			if dark {
+				r := image.Rect(offset+x*scale, offset+y*scale, offset+(x+1)*scale, offset+(y+1)*scale)
+				draw.Draw(img, r, image.Black, image.Point{}, draw.Src)
+			}
+		}
+	}
+
+	if opts.Logo != nil {
+		symbol := n * scale
+		box := int(float64(symbol) * logoRatio)
+		pad := scale
+		r := image.Rect(0, 0, box, box).Add(image.Pt(offset+(symbol-box)/2, offset+(symbol-box)/2))
+		draw.Draw(img, r.Inset(-pad), image.White, image.Point{}, draw.Src)
+		logo := opts.Logo.scaled(box)
+		at := r.Min.Add(image.Pt((box-logo.Bounds().Dx())/2, (box-logo.Bounds().Dy())/2))
+		draw.Draw(img, logo.Bounds().Add(at), logo, image.Point{}, draw.Over)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderSVG draws modules as one path of horizontal runs in a viewBox measured
+// in modules, so the symbol scales to any size without rounding
+func renderSVG(modules [][]bool, opts Options) ([]byte, error) {
+	n := len(modules)
+	total := n + 2*opts.Margin
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		opts.Size, opts.Size, total, total)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`, total, total)
+	b.WriteString(`<path fill="#000000" d="`)
+	for y, row := range modules {
+		for x := 0; x < n; {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < n && row[x] {
+				x++
+			}
+			fmt.Fprintf(&b, "M%d,%dh%dv1h-%dz", opts.Margin+start, opts.Margin+y, x-start, x-start)
+		}
+	}
+	b.WriteString(`"/>`)
+
+	if opts.Logo != nil {
+		box := float64(n) * logoRatio
+		pos := float64(opts.Margin) + (float64(n)-box)/2
+		fmt.Fprintf(&b, `<rect x="%g" y="%g" width="%g" height="%g" fill="#ffffff"/>`, pos-1, pos-1, box+2, box+2)
+		fmt.Fprintf(&b, `<image x="%g" y="%g" width="%g" height="%g" href="data:image/png;base64,%s"/>`,
+			pos, pos, box, box, base64.StdEncoding.EncodeToString(opts.Logo.png))
+	}
+
+	b.WriteString(`</svg>`)
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,126 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	goqrcode "github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testURL = "https://api.example.com/go/abc123xyz?src=qr"
+
+// symbol returns the borderless module matrix the encoder produces for content
+func symbol(t *testing.T, content string, level goqrcode.RecoveryLevel) [][]bool {
+	t.Helper()
+	q, err := goqrcode.New(content, level)
+	require.NoError(t, err)
+	q.DisableBorder = true
+	return q.Bitmap()
+}
+
+func isDark(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r+g+b < 3*0x8000
+}
+
+func TestEncode_PNG(t *testing.T) {
+	modules := symbol(t, testURL, goqrcode.Medium)
+	n := len(modules)
+
+	data, err := Encode(testURL, Options{Format: FormatPNG, Size: 300, Margin: 4})
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 300, 300), img.Bounds())
+
+	// Whole-pixel modules, centered; every module matches the symbol
+	scale := 300 / (n + 8)
+	offset := (300 - n*scale) / 2
+	for y := range modules {
+		for x := range modules[y] {
+			px := img.At(offset+x*scale+scale/2, offset+y*scale+scale/2)
+			assert.Equal(t, modules[y][x], isDark(px), "module %d,%d", x, y)
+		}
+	}
+
+	// Quiet zone is white
+	for i := 0; i < offset; i++ {
+		assert.False(t, isDark(img.At(i, i)))
+		assert.False(t, isDark(img.At(299-i, 299-i)))
+	}
+}
+
+func TestEncode_PNGTooSmall(t *testing.T) {
+	_, err := Encode(testURL, Options{Format: FormatPNG, Size: 20, Margin: 4})
+	assert.True(t, errors.Is(err, ErrTooSmall))
+}
+
+func TestEncode_SVG(t *testing.T) {
+	modules := symbol(t, testURL, goqrcode.Highest)
+	n := len(modules)
+
+	data, err := Encode(testURL, Options{Format: FormatSVG, Size: 512, Margin: 2, Level: LevelHigh})
+	require.NoError(t, err)
+
+	svg := string(data)
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="512" height="512"`))
+	assert.Contains(t, svg, fmt.Sprintf(`viewBox="0 0 %d %d"`, n+4, n+4))
+	// Top-left finder pattern: a 7-module run on the first row
+	assert.Contains(t, svg, `d="M2,2h7v1h-7z`)
+	assert.NotContains(t, svg, "<image")
+	assert.True(t, strings.HasSuffix(svg, "</svg>"))
+}
+
+func TestEncode_Logo(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			src.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	logo, err := NewLogo(src)
+	require.NoError(t, err)
+
+	t.Run("png", func(t *testing.T) {
+		n := len(symbol(t, testURL, goqrcode.Highest))
+		box := int(float64(n*(400/(n+8))) * logoRatio)
+
+		data, err := Encode(testURL, Options{Format: FormatPNG, Size: 400, Margin: 4, Level: LevelHigh, Logo: logo})
+		require.NoError(t, err)
+		img, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+
+		r, g, b, _ := img.At(200, 200).RGBA()
+		assert.Equal(t, [3]uint32{0xffff, 0, 0}, [3]uint32{r, g, b}, "logo drawn at the center")
+		// The wide logo is letterboxed inside the white pad
+		for _, y := range []int{200 - box*3/8, 200 + box*3/8} {
+			r, g, b, _ := img.At(200, y).RGBA()
+			assert.Equal(t, [3]uint32{0xffff, 0xffff, 0xffff}, [3]uint32{r, g, b})
+		}
+	})
+
+	t.Run("svg", func(t *testing.T) {
+		data, err := Encode(testURL, Options{Format: FormatSVG, Size: 400, Margin: 4, Level: LevelHigh, Logo: logo})
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `href="data:image/png;base64,`)
+	})
+}
+
+func TestEncode_InvalidLevel(t *testing.T) {
+	_, err := Encode(testURL, Options{Format: FormatPNG, Size: 256, Level: "X"})
+	assert.Error(t, err)
+}
+
+func TestNewLogo_Empty(t *testing.T) {
+	_, err := NewLogo(image.NewRGBA(image.Rectangle{}))
+	assert.Error(t, err)
+}
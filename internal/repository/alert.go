@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/database"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// AlertRuleRepository handles alert rule database operations
+type AlertRuleRepository struct {
+	db *database.DB
+}
+
+// NewAlertRuleRepository creates a new alert rule repository
+func NewAlertRuleRepository(db *database.DB) *AlertRuleRepository {
+	return &AlertRuleRepository{db: db}
+}
+
+// Create creates a new alert rule (uses write DB)
+func (r *AlertRuleRepository) Create(ctx context.Context, rule *model.AlertRule) error {
+	return r.db.Write.WithContext(ctx).Create(rule).Error
+}
+
+// alertRuleInWorkspace scopes alert rules to the caller's workspace through the
+// product or campaign they watch
+func alertRuleInWorkspace(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if id, ok := auth.WorkspaceFrom(ctx); ok {
+			return db.Where("(product_id IN (SELECT id FROM products WHERE workspace_id = ?) OR campaign_id IN (SELECT id FROM campaigns WHERE workspace_id = ?))", id, id)
+		}
+		return db
+	}
+}
+
+// FindByID finds an alert rule by ID (uses read DB)
+func (r *AlertRuleRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.AlertRule, error) {
+	var rule model.AlertRule
+	err := r.db.Read.WithContext(ctx).Scopes(alertRuleInWorkspace(ctx)).First(&rule, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// FindAll finds all alert rules (uses read DB)
+func (r *AlertRuleRepository) FindAll(ctx context.Context, limit, offset int) ([]*model.AlertRule, int64, error) {
+	var rules []*model.AlertRule
+	var total int64
+
+	// Count total
+	if err := r.db.Read.WithContext(ctx).Model(&model.AlertRule{}).Scopes(alertRuleInWorkspace(ctx)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Find with pagination
+	err := r.db.Read.WithContext(ctx).
+		Scopes(alertRuleInWorkspace(ctx)).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&rules).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rules, total, nil
+}
+
+// FindActiveForOffer finds active rules that apply to a product's offer on a marketplace (uses read DB)
+// A rule applies if it targets the product directly or a campaign containing the product
+func (r *AlertRuleRepository) FindActiveForOffer(ctx context.Context, productID uuid.UUID, marketplace model.Marketplace) ([]*model.AlertRule, error) {
+	var rules []*model.AlertRule
+	err := r.db.Read.WithContext(ctx).
+		Where("is_active = ?", true).
+		Where("marketplace IS NULL OR marketplace = ?", marketplace).
+		Where("product_id = ? OR campaign_id IN (SELECT campaign_id FROM campaign_products WHERE product_id = ?)", productID, productID).
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Update updates an alert rule (uses write DB)
+func (r *AlertRuleRepository) Update(ctx context.Context, rule *model.AlertRule) error {
+	return r.db.Write.WithContext(ctx).Save(rule).Error
+}
+
+// Delete deletes an alert rule (uses write DB)
+func (r *AlertRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.Write.WithContext(ctx).Scopes(alertRuleInWorkspace(ctx)).Delete(&model.AlertRule{}, "id = ?", id).Error
+}
+
+// AlertDeliveryRepository handles alert delivery log database operations
+type AlertDeliveryRepository struct {
+	db *database.DB
+}
+
+// NewAlertDeliveryRepository creates a new alert delivery repository
+func NewAlertDeliveryRepository(db *database.DB) *AlertDeliveryRepository {
+	return &AlertDeliveryRepository{db: db}
+}
+
+// Create creates a new delivery log entry (uses write DB)
+func (r *AlertDeliveryRepository) Create(ctx context.Context, delivery *model.AlertDelivery) error {
+	return r.db.Write.WithContext(ctx).Create(delivery).Error
+}
+
+// Update updates a delivery log entry (uses write DB)
+func (r *AlertDeliveryRepository) Update(ctx context.Context, delivery *model.AlertDelivery) error {
+	return r.db.Write.WithContext(ctx).Save(delivery).Error
+}
+
+// FindByAlertRuleID finds delivery log entries for an alert rule, newest first (uses read DB)
+func (r *AlertDeliveryRepository) FindByAlertRuleID(ctx context.Context, alertRuleID uuid.UUID, limit, offset int) ([]*model.AlertDelivery, int64, error) {
+	var deliveries []*model.AlertDelivery
+	var total int64
+
+	// Count total
+	if err := r.db.Read.WithContext(ctx).Model(&model.AlertDelivery{}).Where("alert_rule_id = ?", alertRuleID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Find with pagination
+	err := r.db.Read.WithContext(ctx).
+		Where("alert_rule_id = ?", alertRuleID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&deliveries).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
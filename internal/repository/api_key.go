@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/database"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// APIKeyRepository handles API key database operations
+type APIKeyRepository struct {
+	db *database.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *database.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create creates a new API key in the caller's workspace (uses write DB)
+func (r *APIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	assignWorkspace(ctx, &key.WorkspaceID)
+	return r.db.Write.WithContext(ctx).Create(key).Error
+}
+
+// FindByKeyHash finds an API key by the hash of its key (uses write DB)
+// Reads the primary so a key works as soon as it is created
+func (r *APIKeyRepository) FindByKeyHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.Write.WithContext(ctx).First(&key, "key_hash = ?", keyHash).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindAll finds all API keys (uses read DB)
+func (r *APIKeyRepository) FindAll(ctx context.Context, limit, offset int) ([]*model.APIKey, int64, error) {
+	var keys []*model.APIKey
+	var total int64
+
+	// Count total
+	if err := r.db.Read.WithContext(ctx).Model(&model.APIKey{}).Scopes(inWorkspace(ctx, "api_keys")).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Find with pagination
+	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspace(ctx, "api_keys")).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&keys).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return keys, total, nil
+}
+
+// UpdateLastUsedAt records when an API key was last used (uses write DB)
+func (r *APIKeyRepository) UpdateLastUsedAt(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.Write.WithContext(ctx).
+		Model(&model.APIKey{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", at).Error
+}
+
+// Delete deletes an API key (uses write DB)
+func (r *APIKeyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.Write.WithContext(ctx).Scopes(inWorkspace(ctx, "api_keys")).Delete(&model.APIKey{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
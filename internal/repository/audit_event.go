@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/database"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// AuditEventRepository handles audit log database operations
+// Events are append-only: there is no update or delete
+type AuditEventRepository struct {
+	db *database.DB
+}
+
+// NewAuditEventRepository creates a new audit event repository
+func NewAuditEventRepository(db *database.DB) *AuditEventRepository {
+	return &AuditEventRepository{db: db}
+}
+
+// Create appends an audit event in the caller's workspace (uses write DB)
+func (r *AuditEventRepository) Create(ctx context.Context, event *model.AuditEvent) error {
+	if id, ok := auth.WorkspaceFrom(ctx); ok {
+		event.WorkspaceID = &id
+	}
+	return r.db.Write.WithContext(ctx).Create(event).Error
+}
+
+// auditEventFilters applies an audit event filter to a query
+func auditEventFilters(filter model.AuditEventFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.EntityType != "" {
+			db = db.Where("entity_type = ?", filter.EntityType)
+		}
+		if filter.EntityID != nil {
+			db = db.Where("entity_id = ?", *filter.EntityID)
+		}
+		if filter.Actor != "" {
+			db = db.Where("(actor = ? OR actor_name = ?)", filter.Actor, filter.Actor)
+		}
+		if !filter.From.IsZero() {
+			db = db.Where("created_at >= ?", filter.From)
+		}
+		if !filter.To.IsZero() {
+			db = db.Where("created_at <= ?", filter.To)
+		}
+		return db
+	}
+}
+
+// FindAll finds audit events matching filter, newest first (uses read DB)
+func (r *AuditEventRepository) FindAll(ctx context.Context, filter model.AuditEventFilter, limit, offset int) ([]*model.AuditEvent, int64, error) {
+	var events []*model.AuditEvent
+	var total int64
+
+	// Count total
+	if err := r.db.Read.WithContext(ctx).Model(&model.AuditEvent{}).Scopes(inWorkspace(ctx, "audit_events"), auditEventFilters(filter)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Find with pagination
+	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspace(ctx, "audit_events"), auditEventFilters(filter)).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&events).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+func TestAuditEventRepository(t *testing.T) {
+	db, recorder := newDryRunDB(t)
+	events := NewAuditEventRepository(db)
+	workspaceID := uuid.New()
+	ctx := auth.WithWorkspace(context.Background(), workspaceID)
+
+	t.Run("new events join the caller's workspace", func(t *testing.T) {
+		event := &model.AuditEvent{Actor: "user:42", Action: model.AuditActionDelete, EntityType: model.AuditEntityCampaign}
+		require.NoError(t, events.Create(ctx, event))
+		require.NotNil(t, event.WorkspaceID)
+		assert.Equal(t, workspaceID, *event.WorkspaceID)
+		recorder.take()
+	})
+
+	t.Run("listings are scoped and filtered", func(t *testing.T) {
+		entityID := uuid.New()
+		_, _, _ = events.FindAll(ctx, model.AuditEventFilter{
+			EntityType: model.AuditEntityCampaign,
+			EntityID:   &entityID,
+			Actor:      "me@example.com",
+			From:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		}, 50, 100)
+
+		queries := recorder.take()
+		require.Len(t, queries, 2) // Count and page
+		for _, sql := range queries {
+			assert.Contains(t, sql, "audit_events.workspace_id = '"+workspaceID.String()+"'", sql)
+			assert.Contains(t, sql, "entity_type = 'campaign'", sql)
+			assert.Contains(t, sql, "entity_id = '"+entityID.String()+"'", sql)
+			assert.Contains(t, sql, "(actor = 'me@example.com' OR actor_name = 'me@example.com')", sql)
+			assert.Contains(t, sql, "created_at >= '2025-01-01", sql)
+		}
+		assert.Contains(t, queries[1], "ORDER BY created_at DESC LIMIT 50 OFFSET 100", queries[1])
+	})
+}
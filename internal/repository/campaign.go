@@ -4,7 +4,9 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
+	"github.com/jonosize/affiliate-platform/internal/auth"
 	"github.com/jonosize/affiliate-platform/internal/database"
 	"github.com/jonosize/affiliate-platform/internal/model"
 )
@@ -19,17 +21,21 @@ func NewCampaignRepository(db *database.DB) *CampaignRepository {
 	return &CampaignRepository{db: db}
 }
 
-// Create creates a new campaign (uses write DB)
+// Create creates a new campaign in the caller's workspace (uses write DB)
 func (r *CampaignRepository) Create(ctx context.Context, campaign *model.Campaign) error {
+	assignWorkspace(ctx, &campaign.WorkspaceID)
 	return r.db.Write.WithContext(ctx).Create(campaign).Error
 }
 
-// FindByID finds a campaign by ID (uses read DB)
+// FindByID finds a campaign by ID with its products and links, leaving out
+// deleted products and their links (uses read DB)
 func (r *CampaignRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Campaign, error) {
 	var campaign model.Campaign
 	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspace(ctx, "campaigns")).
+		Preload("CampaignProducts", "product_id NOT IN (SELECT id FROM products WHERE deleted_at IS NOT NULL)").
 		Preload("CampaignProducts.Product").
-		Preload("Links").
+		Preload("Links", liveLinks).
 		First(&campaign, "id = ?", id).Error
 	if err != nil {
 		return nil, err
@@ -43,12 +49,13 @@ func (r *CampaignRepository) FindAll(ctx context.Context, limit, offset int) ([]
 	var total int64
 
 	// Count total
-	if err := r.db.Read.WithContext(ctx).Model(&model.Campaign{}).Count(&total).Error; err != nil {
+	if err := r.db.Read.WithContext(ctx).Model(&model.Campaign{}).Scopes(inWorkspace(ctx, "campaigns")).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Find with pagination
 	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspace(ctx, "campaigns")).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -66,13 +73,74 @@ func (r *CampaignRepository) Update(ctx context.Context, campaign *model.Campaig
 	return r.db.Write.WithContext(ctx).Save(campaign).Error
 }
 
-// Delete deletes a campaign (uses write DB)
+// Delete soft-deletes a campaign, hiding it and its links (uses write DB)
 func (r *CampaignRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.Write.WithContext(ctx).Delete(&model.Campaign{}, "id = ?", id).Error
+	return r.db.Write.WithContext(ctx).Scopes(inWorkspace(ctx, "campaigns")).Delete(&model.Campaign{}, "id = ?", id).Error
+}
+
+// FindDeletedByID finds a soft-deleted campaign by ID with its product associations (uses read DB)
+func (r *CampaignRepository) FindDeletedByID(ctx context.Context, id uuid.UUID) (*model.Campaign, error) {
+	var campaign model.Campaign
+	err := r.db.Read.WithContext(ctx).
+		Unscoped().
+		Scopes(inWorkspace(ctx, "campaigns")).
+		Preload("CampaignProducts").
+		Where("deleted_at IS NOT NULL").
+		First(&campaign, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// Restore undoes a campaign's soft deletion (uses write DB)
+func (r *CampaignRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	result := r.db.Write.WithContext(ctx).
+		Unscoped().
+		Model(&model.Campaign{}).
+		Scopes(inWorkspace(ctx, "campaigns")).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge permanently deletes a soft-deleted campaign (uses write DB)
+// CASCADE constraints delete its product associations, links and their clicks
+func (r *CampaignRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	result := r.db.Write.WithContext(ctx).
+		Unscoped().
+		Scopes(inWorkspace(ctx, "campaigns")).
+		Where("deleted_at IS NOT NULL").
+		Delete(&model.Campaign{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
 // AddProducts adds products to a campaign (uses write DB)
+// Products outside the caller's workspace are skipped like duplicates
 func (r *CampaignRepository) AddProducts(ctx context.Context, campaignID uuid.UUID, productIDs []uuid.UUID) error {
+	if _, ok := auth.WorkspaceFrom(ctx); ok && len(productIDs) > 0 {
+		var inWorkspaceIDs []uuid.UUID
+		if err := r.db.Write.WithContext(ctx).
+			Model(&model.Product{}).
+			Scopes(inWorkspace(ctx, "products")).
+			Where("id IN ?", productIDs).
+			Pluck("id", &inWorkspaceIDs).Error; err != nil {
+			return err
+		}
+		productIDs = inWorkspaceIDs
+	}
 	for _, productID := range productIDs {
 		campaignProduct := &model.CampaignProduct{
 			CampaignID: campaignID,
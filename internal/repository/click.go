@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/jonosize/affiliate-platform/internal/database"
 	"github.com/jonosize/affiliate-platform/internal/model"
@@ -21,48 +23,211 @@ func NewClickRepository(db *database.DB) *ClickRepository {
 }
 
 // Create creates a new click event (uses write DB)
+// Clicks are recorded outside any workspace context, so they must name their link's workspace
 func (r *ClickRepository) Create(ctx context.Context, click *model.Click) error {
 	return r.db.Write.WithContext(ctx).Create(click).Error
 }
 
+// CreateBatch creates click events with a single multi-row insert (uses write DB)
+func (r *ClickRepository) CreateBatch(ctx context.Context, clicks []*model.Click) error {
+	if len(clicks) == 0 {
+		return nil
+	}
+	return r.db.Write.WithContext(ctx).CreateInBatches(clicks, len(clicks)).Error
+}
+
+// FindByID finds a click by ID (uses read DB)
+func (r *ClickRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Click, error) {
+	var click model.Click
+	err := r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")).First(&click, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &click, nil
+}
+
 // CountByLinkID counts clicks for a link (uses read DB)
 func (r *ClickRepository) CountByLinkID(ctx context.Context, linkID uuid.UUID) (int64, error) {
 	var count int64
 	err := r.db.Read.WithContext(ctx).
 		Model(&model.Click{}).
+		Scopes(inWorkspace(ctx, "clicks")).
 		Where("link_id = ?", linkID).
 		Count(&count).Error
 	return count, err
 }
 
 // CountByLinkIDAndTimeRange counts clicks for a link within a time range (uses read DB)
-func (r *ClickRepository) CountByLinkIDAndTimeRange(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time) (int64, error) {
+func (r *ClickRepository) CountByLinkIDAndTimeRange(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) (int64, error) {
 	var count int64
-	err := r.db.Read.WithContext(ctx).
+	err := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).
 		Model(&model.Click{}).
 		Where("link_id = ? AND timestamp >= ? AND timestamp <= ?", linkID, startAt, endAt).
 		Count(&count).Error
 	return count, err
 }
 
+// humanClicks excludes clicks flagged as bot traffic unless includeBots is set
+func humanClicks(db *gorm.DB, includeBots bool) *gorm.DB {
+	if includeBots {
+		return db
+	}
+	return db.Where("clicks.is_bot = ?", false)
+}
+
+// uniqueClicksExpr counts clicks flagged unique by the per-visitor dedupe window
+const uniqueClicksExpr = "COUNT(clicks.id) FILTER (WHERE clicks.is_unique)"
+
+// clickMarketplaceExpr is the marketplace a click was sent to: the one chosen at
+// click time by best-price links, otherwise the link's own (requires the links join)
+const clickMarketplaceExpr = "COALESCE(clicks.marketplace, links.marketplace)"
+
+// referrerHostExpr extracts the referrer host, labelling clicks without a referrer as direct
+const referrerHostExpr = "COALESCE(NULLIF(substring(clicks.referrer from '^[a-zA-Z][a-zA-Z0-9+.-]*://([^/?#:]+)'), ''), '(direct)')"
+
+// clickSourceExpr is the ?src= marker a click arrived with, "(none)" when unmarked
+const clickSourceExpr = "COALESCE(NULLIF(clicks.source, ''), '(none)')"
+
+// CountUniqueByLinkIDAndTimeRange counts unique clicks for a link within a time range (uses read DB)
+func (r *ClickRepository) CountUniqueByLinkIDAndTimeRange(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) (int64, error) {
+	var count int64
+	err := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).
+		Model(&model.Click{}).
+		Select(uniqueClicksExpr).
+		Where("link_id = ? AND timestamp >= ? AND timestamp <= ?", linkID, startAt, endAt).
+		Scan(&count).Error
+	return count, err
+}
+
+// CountByLinkIDGroupedByInterval counts clicks for a link bucketed with date_trunc (uses read DB)
+// interval must be a date_trunc field such as "hour" or "day"; empty buckets are omitted
+func (r *ClickRepository) CountByLinkIDGroupedByInterval(ctx context.Context, linkID uuid.UUID, interval string, startAt, endAt time.Time, includeBots bool) ([]model.ClickTimeBucketResult, error) {
+	switch interval {
+	case "hour", "day":
+	default:
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+
+	var results []model.ClickTimeBucketResult
+	err := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).
+		Table("clicks").
+		Select(fmt.Sprintf("date_trunc('%s', clicks.timestamp) as bucket, COUNT(clicks.id) as clicks, %s as unique_clicks", interval, uniqueClicksExpr)).
+		Where("clicks.link_id = ? AND clicks.timestamp >= ? AND clicks.timestamp <= ?", linkID, startAt, endAt).
+		Group("bucket").
+		Order("bucket ASC").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CountByReferrerForLink counts clicks for a link grouped by referrer host (uses read DB)
+func (r *ClickRepository) CountByReferrerForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, limit int, includeBots bool) ([]model.ClickBreakdownResult, error) {
+	var results []model.ClickBreakdownResult
+	err := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).
+		Table("clicks").
+		Select(referrerHostExpr+" as value, COUNT(clicks.id) as clicks").
+		Where("clicks.link_id = ? AND clicks.timestamp >= ? AND clicks.timestamp <= ?", linkID, startAt, endAt).
+		Group("value").
+		Order("clicks DESC").
+		Limit(limit).
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CountByOutcomeForLink counts clicks for a link grouped by redirect outcome (uses read DB)
+func (r *ClickRepository) CountByOutcomeForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.ClickBreakdownResult, error) {
+	var results []model.ClickBreakdownResult
+	err := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).
+		Table("clicks").
+		Select("clicks.outcome as value, COUNT(clicks.id) as clicks").
+		Where("clicks.link_id = ? AND clicks.timestamp >= ? AND clicks.timestamp <= ?", linkID, startAt, endAt).
+		Group("clicks.outcome").
+		Order("clicks DESC").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CountBySourceForLink counts clicks for a link grouped by ?src= marker (uses read DB)
+func (r *ClickRepository) CountBySourceForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.ClickBreakdownResult, error) {
+	var results []model.ClickBreakdownResult
+	err := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).
+		Table("clicks").
+		Select(clickSourceExpr+" as value, COUNT(clicks.id) as clicks").
+		Where("clicks.link_id = ? AND clicks.timestamp >= ? AND clicks.timestamp <= ?", linkID, startAt, endAt).
+		Group("value").
+		Order("clicks DESC").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CountByVariantForLink counts clicks and unique clicks for a link grouped by A/B variant (uses read DB)
+// Clicks not sent to a variant are left out
+func (r *ClickRepository) CountByVariantForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.VariantClickResult, error) {
+	var results []model.VariantClickResult
+	err := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).
+		Table("clicks").
+		Select("clicks.variant_id, COUNT(clicks.id) as clicks, "+uniqueClicksExpr+" as unique_clicks").
+		Where("clicks.link_id = ? AND clicks.variant_id IS NOT NULL AND clicks.timestamp >= ? AND clicks.timestamp <= ?", linkID, startAt, endAt).
+		Group("clicks.variant_id").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CountByUserAgentForLink counts clicks for a link grouped by raw user agent (uses read DB)
+func (r *ClickRepository) CountByUserAgentForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.ClickBreakdownResult, error) {
+	var results []model.ClickBreakdownResult
+	err := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).
+		Table("clicks").
+		Select("clicks.user_agent as value, COUNT(clicks.id) as clicks").
+		Where("clicks.link_id = ? AND clicks.timestamp >= ? AND clicks.timestamp <= ?", linkID, startAt, endAt).
+		Group("clicks.user_agent").
+		Order("clicks DESC").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 // FindByCampaignID counts clicks for a campaign (uses read DB)
 func (r *ClickRepository) CountByCampaignID(ctx context.Context, campaignID uuid.UUID) (int64, error) {
 	var count int64
 	err := r.db.Read.WithContext(ctx).
 		Model(&model.Click{}).
+		Scopes(inWorkspace(ctx, "clicks")).
 		Joins("JOIN links ON clicks.link_id = links.id").
 		Where("links.campaign_id = ?", campaignID).
 		Count(&count).Error
 	return count, err
 }
 
+// withDeleted lets a preload include soft-deleted rows
+func withDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
 // FindRecentClicks finds the most recent clicks with related data (uses read DB)
-func (r *ClickRepository) FindRecentClicks(ctx context.Context, limit int) ([]model.Click, error) {
+// Deleted links, products and campaigns are still loaded: their clicks remain history
+func (r *ClickRepository) FindRecentClicks(ctx context.Context, limit int, includeBots bool) ([]model.Click, error) {
 	var clicks []model.Click
-	err := r.db.Read.WithContext(ctx).
-		Preload("Link").
-		Preload("Link.Product").
-		Preload("Link.Campaign").
+	err := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).
+		Preload("Link", withDeleted).
+		Preload("Link.Product", withDeleted).
+		Preload("Link.Campaign", withDeleted).
 		Order("timestamp DESC").
 		Limit(limit).
 		Find(&clicks).Error
@@ -70,8 +235,8 @@ func (r *ClickRepository) FindRecentClicks(ctx context.Context, limit int) ([]mo
 }
 
 // CountWithFilters counts clicks with optional filters (uses read DB)
-func (r *ClickRepository) CountWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time) (int64, error) {
-	query := r.db.Read.WithContext(ctx).Model(&model.Click{})
+func (r *ClickRepository) CountWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) (int64, error) {
+	query := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).Model(&model.Click{})
 
 	// Apply date range filter
 	if !startDate.IsZero() {
@@ -81,16 +246,48 @@ func (r *ClickRepository) CountWithFilters(ctx context.Context, campaignID *uuid
 		query = query.Where("timestamp <= ?", endDate)
 	}
 
-	// Apply campaign filter (via link join)
-	if campaignID != nil {
-		query = query.Joins("JOIN links ON clicks.link_id = links.id").
-			Where("links.campaign_id = ?", *campaignID)
+	// Apply campaign and marketplace filters (via a single link join)
+	if campaignID != nil || marketplace != nil {
+		query = query.Joins("JOIN links ON clicks.link_id = links.id")
+		if campaignID != nil {
+			query = query.Where("links.campaign_id = ?", *campaignID)
+		}
+		if marketplace != nil {
+			query = query.Where(clickMarketplaceExpr+" = ?", *marketplace)
+		}
 	}
 
-	// Apply marketplace filter (via link join)
-	if marketplace != nil {
-		query = query.Joins("JOIN links ON clicks.link_id = links.id").
-			Where("links.marketplace = ?", *marketplace)
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CountUniqueWithFilters counts unique clicks with optional filters (uses read DB)
+func (r *ClickRepository) CountUniqueWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) (int64, error) {
+	query := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).
+		Model(&model.Click{}).
+		Where("clicks.is_unique = ?", true)
+
+	// Apply date range filter
+	if !startDate.IsZero() {
+		query = query.Where("clicks.timestamp >= ?", startDate)
+	}
+	if !endDate.IsZero() {
+		query = query.Where("clicks.timestamp <= ?", endDate)
+	}
+
+	// Apply campaign and marketplace filters (via a single link join)
+	if campaignID != nil || marketplace != nil {
+		query = query.Joins("JOIN links ON clicks.link_id = links.id")
+		if campaignID != nil {
+			query = query.Where("links.campaign_id = ?", *campaignID)
+		}
+		if marketplace != nil {
+			query = query.Where(clickMarketplaceExpr+" = ?", *marketplace)
+		}
 	}
 
 	var count int64
@@ -102,10 +299,10 @@ func (r *ClickRepository) CountWithFilters(ctx context.Context, campaignID *uuid
 }
 
 // CountByCampaignWithFilters counts clicks grouped by campaign with filters (uses read DB)
-func (r *ClickRepository) CountByCampaignWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time) ([]model.CampaignStatResult, error) {
-	query := r.db.Read.WithContext(ctx).
+func (r *ClickRepository) CountByCampaignWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.CampaignStatResult, error) {
+	query := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).
 		Table("clicks").
-		Select("campaigns.id as campaign_id, campaigns.name as campaign_name, COUNT(clicks.id) as clicks").
+		Select("campaigns.id as campaign_id, campaigns.name as campaign_name, COUNT(clicks.id) as clicks, " + uniqueClicksExpr + " as unique_clicks").
 		Joins("JOIN links ON clicks.link_id = links.id").
 		Joins("JOIN campaigns ON links.campaign_id = campaigns.id").
 		Group("campaigns.id, campaigns.name")
@@ -120,7 +317,7 @@ func (r *ClickRepository) CountByCampaignWithFilters(ctx context.Context, campai
 
 	// Apply marketplace filter
 	if marketplace != nil {
-		query = query.Where("links.marketplace = ?", *marketplace)
+		query = query.Where(clickMarketplaceExpr+" = ?", *marketplace)
 	}
 
 	// Apply campaign filter
@@ -137,12 +334,12 @@ func (r *ClickRepository) CountByCampaignWithFilters(ctx context.Context, campai
 }
 
 // CountByMarketplaceWithFilters counts clicks grouped by marketplace with filters (uses read DB)
-func (r *ClickRepository) CountByMarketplaceWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time) ([]model.MarketplaceStatResult, error) {
-	query := r.db.Read.WithContext(ctx).
+func (r *ClickRepository) CountByMarketplaceWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.MarketplaceStatResult, error) {
+	query := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).
 		Table("clicks").
-		Select("links.marketplace, COUNT(clicks.id) as clicks").
+		Select(clickMarketplaceExpr + " as marketplace, COUNT(clicks.id) as clicks, " + uniqueClicksExpr + " as unique_clicks").
 		Joins("JOIN links ON clicks.link_id = links.id").
-		Group("links.marketplace")
+		Group(clickMarketplaceExpr)
 
 	// Apply date range filter
 	if !startDate.IsZero() {
@@ -159,7 +356,7 @@ func (r *ClickRepository) CountByMarketplaceWithFilters(ctx context.Context, cam
 
 	// Apply marketplace filter
 	if marketplace != nil {
-		query = query.Where("links.marketplace = ?", *marketplace)
+		query = query.Where(clickMarketplaceExpr+" = ?", *marketplace)
 	}
 
 	var results []model.MarketplaceStatResult
@@ -170,14 +367,49 @@ func (r *ClickRepository) CountByMarketplaceWithFilters(ctx context.Context, cam
 	return results, nil
 }
 
+// CountBySourceWithFilters counts clicks grouped by ?src= marker with filters (uses read DB)
+func (r *ClickRepository) CountBySourceWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.SourceStatResult, error) {
+	query := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).
+		Table("clicks").
+		Select(clickSourceExpr + " as source, COUNT(clicks.id) as clicks, " + uniqueClicksExpr + " as unique_clicks").
+		Joins("JOIN links ON clicks.link_id = links.id").
+		Group("source").
+		Order("clicks DESC")
+
+	// Apply date range filter
+	if !startDate.IsZero() {
+		query = query.Where("clicks.timestamp >= ?", startDate)
+	}
+	if !endDate.IsZero() {
+		query = query.Where("clicks.timestamp <= ?", endDate)
+	}
+
+	// Apply campaign filter
+	if campaignID != nil {
+		query = query.Where("links.campaign_id = ?", *campaignID)
+	}
+
+	// Apply marketplace filter
+	if marketplace != nil {
+		query = query.Where(clickMarketplaceExpr+" = ?", *marketplace)
+	}
+
+	var results []model.SourceStatResult
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
 // FindTopProductsWithFilters finds top products by click count with filters (uses read DB)
-func (r *ClickRepository) FindTopProductsWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, limit int) ([]model.TopProductResult, error) {
-	query := r.db.Read.WithContext(ctx).
+func (r *ClickRepository) FindTopProductsWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, limit int, includeBots bool) ([]model.TopProductResult, error) {
+	query := humanClicks(r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "clicks")), includeBots).
 		Table("clicks").
-		Select("products.id as product_id, products.title as product_name, links.marketplace, COUNT(clicks.id) as clicks").
+		Select("products.id as product_id, products.title as product_name, " + clickMarketplaceExpr + " as marketplace, COUNT(clicks.id) as clicks").
 		Joins("JOIN links ON clicks.link_id = links.id").
 		Joins("JOIN products ON links.product_id = products.id").
-		Group("products.id, products.title, links.marketplace").
+		Group("products.id, products.title, " + clickMarketplaceExpr).
 		Order("clicks DESC").
 		Limit(limit)
 
@@ -196,7 +428,7 @@ func (r *ClickRepository) FindTopProductsWithFilters(ctx context.Context, campai
 
 	// Apply marketplace filter
 	if marketplace != nil {
-		query = query.Where("links.marketplace = ?", *marketplace)
+		query = query.Where(clickMarketplaceExpr+" = ?", *marketplace)
 	}
 
 	var results []model.TopProductResult
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/database"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// ConversionRepository handles conversion database operations
+type ConversionRepository struct {
+	db *database.DB
+}
+
+// NewConversionRepository creates a new conversion repository
+func NewConversionRepository(db *database.DB) *ConversionRepository {
+	return &ConversionRepository{db: db}
+}
+
+// Create creates a new conversion (uses write DB)
+func (r *ConversionRepository) Create(ctx context.Context, conversion *model.Conversion) error {
+	return r.db.Write.WithContext(ctx).Create(conversion).Error
+}
+
+// Update updates a conversion (uses write DB)
+func (r *ConversionRepository) Update(ctx context.Context, conversion *model.Conversion) error {
+	return r.db.Write.WithContext(ctx).Save(conversion).Error
+}
+
+// FindByNetworkAndOrderID finds a conversion by network and order ID (uses write DB)
+// Reads from the primary so repeated postbacks see the conversion they just created
+func (r *ConversionRepository) FindByNetworkAndOrderID(ctx context.Context, network, orderID string) (*model.Conversion, error) {
+	var conversion model.Conversion
+	err := r.db.Write.WithContext(ctx).
+		Where("network = ? AND order_id = ?", network, orderID).
+		First(&conversion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &conversion, nil
+}
+
+// conversionRevenueExpr sums commission, which is what the business is paid
+const conversionRevenueExpr = "COALESCE(SUM(conversions.commission), 0)"
+
+// conversionFilters applies the dashboard filters to a conversions query
+// Unattributed conversions have no campaign and so no workspace; only unscoped reads see them
+// Rejected conversions are never counted
+func conversionFilters(query *gorm.DB, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time) *gorm.DB {
+	query = query.Where("conversions.status <> ?", model.ConversionRejected)
+	if !startDate.IsZero() {
+		query = query.Where("conversions.created_at >= ?", startDate)
+	}
+	if !endDate.IsZero() {
+		query = query.Where("conversions.created_at <= ?", endDate)
+	}
+	if campaignID != nil {
+		query = query.Where("conversions.campaign_id = ?", *campaignID)
+	}
+	if marketplace != nil {
+		query = query.Where("conversions.marketplace = ?", *marketplace)
+	}
+	return query
+}
+
+// SumWithFilters counts conversions and sums revenue with optional filters (uses read DB)
+func (r *ConversionRepository) SumWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time) (*model.ConversionSummaryResult, error) {
+	query := r.db.Read.WithContext(ctx).
+		Table("conversions").
+		Scopes(inWorkspaceVia(ctx, "conversions.campaign_id", "campaigns")).
+		Select("COUNT(conversions.id) as conversions, " + conversionRevenueExpr + " as revenue")
+	query = conversionFilters(query, campaignID, marketplace, startDate, endDate)
+
+	var result model.ConversionSummaryResult
+	if err := query.Scan(&result).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SumByCampaignWithFilters counts conversions and sums revenue grouped by campaign (uses read DB)
+func (r *ConversionRepository) SumByCampaignWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time) ([]model.CampaignConversionResult, error) {
+	query := r.db.Read.WithContext(ctx).
+		Table("conversions").
+		Scopes(inWorkspaceVia(ctx, "conversions.campaign_id", "campaigns")).
+		Select("conversions.campaign_id, COUNT(conversions.id) as conversions, " + conversionRevenueExpr + " as revenue").
+		Where("conversions.campaign_id IS NOT NULL").
+		Group("conversions.campaign_id")
+	query = conversionFilters(query, campaignID, marketplace, startDate, endDate)
+
+	var results []model.CampaignConversionResult
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// SumByVariantForLink counts conversions and sums revenue for a link grouped by the A/B
+// variant of the converting click, for clicks within the time range (uses read DB)
+// Bot clicks are left out unless includeBots, matching the click counts
+func (r *ConversionRepository) SumByVariantForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.VariantConversionResult, error) {
+	query := r.db.Read.WithContext(ctx).
+		Table("conversions").
+		Scopes(inWorkspaceVia(ctx, "conversions.campaign_id", "campaigns")).
+		Joins("JOIN clicks ON clicks.id = conversions.click_id").
+		Select("clicks.variant_id, COUNT(conversions.id) as conversions, "+conversionRevenueExpr+" as revenue").
+		Where("clicks.link_id = ? AND clicks.variant_id IS NOT NULL AND clicks.timestamp >= ? AND clicks.timestamp <= ?", linkID, startAt, endAt).
+		Where("conversions.status <> ?", model.ConversionRejected).
+		Group("clicks.variant_id")
+	if !includeBots {
+		query = query.Where("clicks.is_bot = ?", false)
+	}
+
+	var results []model.VariantConversionResult
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
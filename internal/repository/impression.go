@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/database"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// ImpressionRepository handles impression database operations
+type ImpressionRepository struct {
+	db *database.DB
+}
+
+// NewImpressionRepository creates a new impression repository
+func NewImpressionRepository(db *database.DB) *ImpressionRepository {
+	return &ImpressionRepository{db: db}
+}
+
+// CreateBatch creates impressions with a single multi-row insert (uses write DB)
+func (r *ImpressionRepository) CreateBatch(ctx context.Context, impressions []*model.Impression) error {
+	if len(impressions) == 0 {
+		return nil
+	}
+	return r.db.Write.WithContext(ctx).CreateInBatches(impressions, len(impressions)).Error
+}
+
+// Page views and link impressions are counted from the same rows by kind
+const (
+	pageViewsExpr       = "COUNT(impressions.id) FILTER (WHERE impressions.kind = 'campaign')"
+	linkImpressionsExpr = "COUNT(impressions.id) FILTER (WHERE impressions.kind = 'link')"
+)
+
+// impressionFilters applies the dashboard filters to an impressions query
+// Campaign views have no marketplace, so a marketplace filter only narrows link impressions
+func impressionFilters(query *gorm.DB, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) *gorm.DB {
+	if !includeBots {
+		query = query.Where("impressions.is_bot = ?", false)
+	}
+	if !startDate.IsZero() {
+		query = query.Where("impressions.timestamp >= ?", startDate)
+	}
+	if !endDate.IsZero() {
+		query = query.Where("impressions.timestamp <= ?", endDate)
+	}
+	if campaignID != nil {
+		query = query.Where("impressions.campaign_id = ?", *campaignID)
+	}
+	if marketplace != nil {
+		query = query.Where("(impressions.kind = ? OR impressions.marketplace = ?)", model.ImpressionKindCampaign, *marketplace)
+	}
+	return query
+}
+
+// CountWithFilters counts campaign views and link impressions with optional filters (uses read DB)
+func (r *ImpressionRepository) CountWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) (*model.ImpressionCountResult, error) {
+	query := r.db.Read.WithContext(ctx).
+		Table("impressions").
+		Scopes(inWorkspaceVia(ctx, "impressions.campaign_id", "campaigns")).
+		Select(pageViewsExpr + " as page_views, " + linkImpressionsExpr + " as link_impressions")
+	query = impressionFilters(query, campaignID, marketplace, startDate, endDate, includeBots)
+
+	var result model.ImpressionCountResult
+	if err := query.Scan(&result).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CountByCampaignWithFilters counts campaign views and link impressions grouped by campaign (uses read DB)
+func (r *ImpressionRepository) CountByCampaignWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.CampaignImpressionResult, error) {
+	query := r.db.Read.WithContext(ctx).
+		Table("impressions").
+		Scopes(inWorkspaceVia(ctx, "impressions.campaign_id", "campaigns")).
+		Select("impressions.campaign_id, " + pageViewsExpr + " as page_views, " + linkImpressionsExpr + " as link_impressions").
+		Group("impressions.campaign_id")
+	query = impressionFilters(query, campaignID, marketplace, startDate, endDate, includeBots)
+
+	var results []model.CampaignImpressionResult
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// CountByMarketplaceWithFilters counts link impressions grouped by marketplace (uses read DB)
+func (r *ImpressionRepository) CountByMarketplaceWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.MarketplaceImpressionResult, error) {
+	query := r.db.Read.WithContext(ctx).
+		Table("impressions").
+		Scopes(inWorkspaceVia(ctx, "impressions.campaign_id", "campaigns")).
+		Select("impressions.marketplace, COUNT(impressions.id) as impressions").
+		Where("impressions.kind = ?", model.ImpressionKindLink).
+		Group("impressions.marketplace")
+	query = impressionFilters(query, campaignID, marketplace, startDate, endDate, includeBots)
+
+	var results []model.MarketplaceImpressionResult
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// CountByProductsWithFilters counts link impressions for the given products grouped by product and marketplace (uses read DB)
+func (r *ImpressionRepository) CountByProductsWithFilters(ctx context.Context, productIDs []uuid.UUID, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.ProductImpressionResult, error) {
+	if len(productIDs) == 0 {
+		return []model.ProductImpressionResult{}, nil
+	}
+
+	query := r.db.Read.WithContext(ctx).
+		Table("impressions").
+		Scopes(inWorkspaceVia(ctx, "impressions.campaign_id", "campaigns")).
+		Select("impressions.product_id, impressions.marketplace, COUNT(impressions.id) as impressions").
+		Where("impressions.kind = ? AND impressions.product_id IN ?", model.ImpressionKindLink, productIDs).
+		Group("impressions.product_id, impressions.marketplace")
+	query = impressionFilters(query, campaignID, marketplace, startDate, endDate, includeBots)
+
+	var results []model.ProductImpressionResult
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
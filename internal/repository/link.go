@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/jonosize/affiliate-platform/internal/database"
 	"github.com/jonosize/affiliate-platform/internal/model"
@@ -19,18 +21,66 @@ func NewLinkRepository(db *database.DB) *LinkRepository {
 	return &LinkRepository{db: db}
 }
 
-// Create creates a new link (uses write DB)
+// liveLinks hides links whose product or campaign has been soft-deleted
+// Such links keep deleted_at unset, so restoring the product or campaign brings them back
+func liveLinks(db *gorm.DB) *gorm.DB {
+	return db.
+		Where("links.product_id NOT IN (SELECT id FROM products WHERE deleted_at IS NOT NULL)").
+		Where("links.campaign_id NOT IN (SELECT id FROM campaigns WHERE deleted_at IS NOT NULL)")
+}
+
+// Create creates a new link in the caller's workspace (uses write DB)
 func (r *LinkRepository) Create(ctx context.Context, link *model.Link) error {
+	assignWorkspace(ctx, &link.WorkspaceID)
 	return r.db.Write.WithContext(ctx).Create(link).Error
 }
 
-// FindByShortCode finds a link by short code (uses read DB)
+// FindByID finds a live link by ID with its workspace, product and campaign (uses read DB)
+func (r *LinkRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Link, error) {
+	var link model.Link
+	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspace(ctx, "links"), liveLinks).
+		Preload("Workspace").
+		Preload("Product").
+		Preload("Campaign").
+		First(&link, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// FindByIDIncludingDeleted finds a link by ID even if it or its product or
+// campaign has been deleted, for attributing late conversions (uses read DB)
+func (r *LinkRepository) FindByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*model.Link, error) {
+	var link model.Link
+	err := r.db.Read.WithContext(ctx).
+		Unscoped().
+		Scopes(inWorkspace(ctx, "links")).
+		First(&link, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// FindByShortCode finds a live link by its short code or one of its aliases, with its
+// workspace, campaign, active redirect rules in evaluation order and the A/B
+// variants still receiving traffic in creation order (uses read DB)
 func (r *LinkRepository) FindByShortCode(ctx context.Context, shortCode string) (*model.Link, error) {
 	var link model.Link
 	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspace(ctx, "links"), liveLinks).
+		Preload("Workspace").
 		Preload("Product").
 		Preload("Campaign").
-		Where("short_code = ?", shortCode).
+		Preload("RedirectRules", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_active = ?", true).Order("priority ASC, created_at ASC")
+		}).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Where("weight > 0").Order("created_at ASC, id ASC")
+		}).
+		Where("short_code = ? OR id IN (SELECT link_id FROM link_aliases WHERE short_code = ?)", shortCode, shortCode).
 		First(&link).Error
 	if err != nil {
 		return nil, err
@@ -42,6 +92,7 @@ func (r *LinkRepository) FindByShortCode(ctx context.Context, shortCode string)
 func (r *LinkRepository) FindByProductIDAndCampaignID(ctx context.Context, productID, campaignID uuid.UUID) ([]*model.Link, error) {
 	var links []*model.Link
 	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspace(ctx, "links")).
 		Where("product_id = ? AND campaign_id = ?", productID, campaignID).
 		Find(&links).Error
 	if err != nil {
@@ -50,33 +101,205 @@ func (r *LinkRepository) FindByProductIDAndCampaignID(ctx context.Context, produ
 	return links, nil
 }
 
-// ShortCodeExists checks if a short code already exists (uses read DB)
+// ShortCodeExists checks if a short code is already used by a link or an alias (uses read DB)
+// Not scoped: short codes are unique across workspaces, and deleted links keep theirs
 func (r *LinkRepository) ShortCodeExists(ctx context.Context, shortCode string) (bool, error) {
 	var count int64
 	err := r.db.Read.WithContext(ctx).
+		Unscoped().
 		Model(&model.Link{}).
 		Where("short_code = ?", shortCode).
 		Count(&count).Error
 	if err != nil {
 		return false, err
 	}
+	if count > 0 {
+		return true, nil
+	}
+
+	err = r.db.Read.WithContext(ctx).
+		Model(&model.LinkAlias{}).
+		Where("short_code = ?", shortCode).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
 	return count > 0, nil
 }
 
-// Update updates a link (uses write DB)
+// FindAliasesByLinkID finds a link's aliases, oldest first (uses read DB)
+func (r *LinkRepository) FindAliasesByLinkID(ctx context.Context, linkID uuid.UUID) ([]*model.LinkAlias, error) {
+	var aliases []*model.LinkAlias
+	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspaceVia(ctx, "link_id", "links")).
+		Where("link_id = ?", linkID).
+		Order("created_at ASC").
+		Find(&aliases).Error
+	if err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// CreateAlias creates a link alias (uses write DB)
+func (r *LinkRepository) CreateAlias(ctx context.Context, alias *model.LinkAlias) error {
+	return r.db.Write.WithContext(ctx).Create(alias).Error
+}
+
+// DeleteAlias deletes one of a link's aliases, returning gorm.ErrRecordNotFound if it has no such alias (uses write DB)
+func (r *LinkRepository) DeleteAlias(ctx context.Context, linkID uuid.UUID, shortCode string) error {
+	result := r.db.Write.WithContext(ctx).
+		Scopes(inWorkspaceVia(ctx, "link_id", "links")).
+		Where("link_id = ? AND short_code = ?", linkID, shortCode).
+		Delete(&model.LinkAlias{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindRedirectRulesByLinkID finds a link's redirect rules in evaluation order (uses read DB)
+func (r *LinkRepository) FindRedirectRulesByLinkID(ctx context.Context, linkID uuid.UUID) ([]*model.RedirectRule, error) {
+	var rules []*model.RedirectRule
+	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspaceVia(ctx, "link_id", "links")).
+		Where("link_id = ?", linkID).
+		Order("priority ASC, created_at ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// FindRedirectRule finds one of a link's redirect rules (uses read DB)
+func (r *LinkRepository) FindRedirectRule(ctx context.Context, linkID, ruleID uuid.UUID) (*model.RedirectRule, error) {
+	var rule model.RedirectRule
+	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspaceVia(ctx, "link_id", "links")).
+		Where("id = ? AND link_id = ?", ruleID, linkID).
+		First(&rule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// CreateRedirectRule creates a redirect rule (uses write DB)
+func (r *LinkRepository) CreateRedirectRule(ctx context.Context, rule *model.RedirectRule) error {
+	return r.db.Write.WithContext(ctx).Create(rule).Error
+}
+
+// UpdateRedirectRule updates a redirect rule (uses write DB)
+func (r *LinkRepository) UpdateRedirectRule(ctx context.Context, rule *model.RedirectRule) error {
+	return r.db.Write.WithContext(ctx).Save(rule).Error
+}
+
+// DeleteRedirectRule deletes one of a link's redirect rules, returning gorm.ErrRecordNotFound if it has no such rule (uses write DB)
+func (r *LinkRepository) DeleteRedirectRule(ctx context.Context, linkID, ruleID uuid.UUID) error {
+	result := r.db.Write.WithContext(ctx).
+		Scopes(inWorkspaceVia(ctx, "link_id", "links")).
+		Where("id = ? AND link_id = ?", ruleID, linkID).
+		Delete(&model.RedirectRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindVariantsByLinkID finds a link's A/B variants in creation order (uses read DB)
+func (r *LinkRepository) FindVariantsByLinkID(ctx context.Context, linkID uuid.UUID) ([]*model.LinkVariant, error) {
+	var variants []*model.LinkVariant
+	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspaceVia(ctx, "link_id", "links")).
+		Where("link_id = ?", linkID).
+		Order("created_at ASC, id ASC").
+		Find(&variants).Error
+	if err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+// FindVariant finds one of a link's A/B variants (uses read DB)
+func (r *LinkRepository) FindVariant(ctx context.Context, linkID, variantID uuid.UUID) (*model.LinkVariant, error) {
+	var variant model.LinkVariant
+	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspaceVia(ctx, "link_id", "links")).
+		Where("id = ? AND link_id = ?", variantID, linkID).
+		First(&variant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// CreateVariant creates an A/B variant (uses write DB)
+func (r *LinkRepository) CreateVariant(ctx context.Context, variant *model.LinkVariant) error {
+	return r.db.Write.WithContext(ctx).Create(variant).Error
+}
+
+// UpdateVariant updates an A/B variant (uses write DB)
+func (r *LinkRepository) UpdateVariant(ctx context.Context, variant *model.LinkVariant) error {
+	return r.db.Write.WithContext(ctx).Save(variant).Error
+}
+
+// DeleteVariant deletes one of a link's A/B variants, returning gorm.ErrRecordNotFound if it has no such variant (uses write DB)
+// Clicks sent to the variant are kept without it
+func (r *LinkRepository) DeleteVariant(ctx context.Context, linkID, variantID uuid.UUID) error {
+	result := r.db.Write.WithContext(ctx).
+		Scopes(inWorkspaceVia(ctx, "link_id", "links")).
+		Where("id = ? AND link_id = ?", variantID, linkID).
+		Delete(&model.LinkVariant{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ChangeShortCode replaces a link's short code, keeping the previous code as an alias (uses write DB)
+// If the new code is already one of the link's aliases, that alias is removed
+func (r *LinkRepository) ChangeShortCode(ctx context.Context, link *model.Link, shortCode string) error {
+	err := r.db.Write.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("link_id = ? AND short_code = ?", link.ID, shortCode).Delete(&model.LinkAlias{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&model.LinkAlias{LinkID: link.ID, ShortCode: link.ShortCode}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Link{}).Where("id = ?", link.ID).Update("short_code", shortCode).Error
+	})
+	if err != nil {
+		return err
+	}
+	link.ShortCode = shortCode
+	return nil
+}
+
+// Update updates a link's own columns; preloaded relationships are not saved (uses write DB)
 func (r *LinkRepository) Update(ctx context.Context, link *model.Link) error {
-	return r.db.Write.WithContext(ctx).Save(link).Error
+	return r.db.Write.WithContext(ctx).Omit(clause.Associations).Save(link).Error
 }
 
-// Delete deletes a link (uses write DB)
+// Delete soft-deletes a link, keeping its clicks (uses write DB)
 func (r *LinkRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.Write.WithContext(ctx).Delete(&model.Link{}, "id = ?", id).Error
+	return r.db.Write.WithContext(ctx).Scopes(inWorkspace(ctx, "links")).Delete(&model.Link{}, "id = ?", id).Error
 }
 
 // FindByCampaignID finds all links for a campaign (uses read DB)
 func (r *LinkRepository) FindByCampaignID(ctx context.Context, campaignID uuid.UUID) ([]*model.Link, error) {
 	var links []*model.Link
 	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspace(ctx, "links")).
 		Where("campaign_id = ?", campaignID).
 		Find(&links).Error
 	if err != nil {
@@ -85,29 +308,45 @@ func (r *LinkRepository) FindByCampaignID(ctx context.Context, campaignID uuid.U
 	return links, nil
 }
 
-// DeleteByProductIDAndCampaignID deletes links for a specific product in a campaign (uses write DB)
+// FindByProductID finds all links for a product, including those of deleted campaigns (uses read DB)
+func (r *LinkRepository) FindByProductID(ctx context.Context, productID uuid.UUID) ([]*model.Link, error) {
+	var links []*model.Link
+	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspace(ctx, "links")).
+		Where("product_id = ?", productID).
+		Find(&links).Error
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// DeleteByProductIDAndCampaignID soft-deletes links for a specific product in a campaign (uses write DB)
 func (r *LinkRepository) DeleteByProductIDAndCampaignID(ctx context.Context, productID, campaignID uuid.UUID) error {
 	return r.db.Write.WithContext(ctx).
+		Scopes(inWorkspace(ctx, "links")).
 		Where("product_id = ? AND campaign_id = ?", productID, campaignID).
 		Delete(&model.Link{}).Error
 }
 
-// DeleteByCampaignIDAndNotInProducts deletes links for products not in the provided list (uses write DB)
+// DeleteByCampaignIDAndNotInProducts soft-deletes links for products not in the provided list (uses write DB)
 func (r *LinkRepository) DeleteByCampaignIDAndNotInProducts(ctx context.Context, campaignID uuid.UUID, productIDs []uuid.UUID) error {
 	if len(productIDs) == 0 {
 		// If no products, delete all links for this campaign
 		return r.db.Write.WithContext(ctx).
+			Scopes(inWorkspace(ctx, "links")).
 			Where("campaign_id = ?", campaignID).
 			Delete(&model.Link{}).Error
 	}
 	return r.db.Write.WithContext(ctx).
+		Scopes(inWorkspace(ctx, "links")).
 		Where("campaign_id = ? AND product_id NOT IN ?", campaignID, productIDs).
 		Delete(&model.Link{}).Error
 }
 
-// CountWithFilters counts links with optional filters (uses read DB)
+// CountWithFilters counts live links with optional filters (uses read DB)
 func (r *LinkRepository) CountWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string) (int64, error) {
-	query := r.db.Read.WithContext(ctx).Model(&model.Link{})
+	query := r.db.Read.WithContext(ctx).Model(&model.Link{}).Scopes(inWorkspace(ctx, "links"), liveLinks)
 
 	if campaignID != nil {
 		query = query.Where("campaign_id = ?", *campaignID)
@@ -28,6 +28,7 @@ func (r *OfferRepository) Create(ctx context.Context, offer *model.Offer) error
 func (r *OfferRepository) FindByProductID(ctx context.Context, productID uuid.UUID) ([]*model.Offer, error) {
 	var offers []*model.Offer
 	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspaceVia(ctx, "product_id", "products")).
 		Where("product_id = ?", productID).
 		Order("price ASC").
 		Find(&offers).Error
@@ -41,6 +42,7 @@ func (r *OfferRepository) FindByProductID(ctx context.Context, productID uuid.UU
 func (r *OfferRepository) FindByProductIDAndMarketplace(ctx context.Context, productID uuid.UUID, marketplace model.Marketplace) (*model.Offer, error) {
 	var offer model.Offer
 	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspaceVia(ctx, "product_id", "products")).
 		Where("product_id = ? AND marketplace = ?", productID, marketplace).
 		First(&offer).Error
 	if err != nil {
@@ -64,5 +66,7 @@ func (r *OfferRepository) Upsert(ctx context.Context, offer *model.Offer) error
 
 // Delete deletes an offer (uses write DB)
 func (r *OfferRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.Write.WithContext(ctx).Delete(&model.Offer{}, "id = ?", id).Error
+	return r.db.Write.WithContext(ctx).
+		Scopes(inWorkspaceVia(ctx, "product_id", "products")).
+		Delete(&model.Offer{}, "id = ?", id).Error
 }
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonosize/affiliate-platform/internal/database"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// PriceHistoryRepository handles offer price history database operations
+type PriceHistoryRepository struct {
+	db *database.DB
+}
+
+// NewPriceHistoryRepository creates a new price history repository
+func NewPriceHistoryRepository(db *database.DB) *PriceHistoryRepository {
+	return &PriceHistoryRepository{db: db}
+}
+
+// Create appends a price history entry (uses write DB)
+func (r *PriceHistoryRepository) Create(ctx context.Context, entry *model.OfferPriceHistory) error {
+	return r.db.Write.WithContext(ctx).Create(entry).Error
+}
+
+// FindByProductID finds price history for a product ordered by time with optional filters (uses read DB)
+func (r *PriceHistoryRepository) FindByProductID(ctx context.Context, productID uuid.UUID, marketplace *string, from, to time.Time) ([]*model.OfferPriceHistory, error) {
+	query := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspaceVia(ctx, "product_id", "products")).
+		Where("product_id = ?", productID)
+
+	// Apply marketplace filter
+	if marketplace != nil {
+		query = query.Where("marketplace = ?", *marketplace)
+	}
+
+	// Apply date range filter
+	if !from.IsZero() {
+		query = query.Where("recorded_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("recorded_at <= ?", to)
+	}
+
+	var entries []*model.OfferPriceHistory
+	if err := query.Order("recorded_at ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// FindMinPricesSince finds the lowest recorded price per marketplace for a product since a point in time (uses read DB)
+func (r *PriceHistoryRepository) FindMinPricesSince(ctx context.Context, productID uuid.UUID, since time.Time) ([]model.MarketplaceMinPriceResult, error) {
+	var results []model.MarketplaceMinPriceResult
+	err := r.db.Read.WithContext(ctx).
+		Model(&model.OfferPriceHistory{}).
+		Scopes(inWorkspaceVia(ctx, "product_id", "products")).
+		Select("marketplace, MIN(price) as min_price").
+		Where("product_id = ? AND recorded_at >= ?", productID, since).
+		Group("marketplace").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
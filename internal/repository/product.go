@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/jonosize/affiliate-platform/internal/database"
 	"github.com/jonosize/affiliate-platform/internal/model"
@@ -19,15 +20,16 @@ func NewProductRepository(db *database.DB) *ProductRepository {
 	return &ProductRepository{db: db}
 }
 
-// Create creates a new product (uses write DB)
+// Create creates a new product in the caller's workspace (uses write DB)
 func (r *ProductRepository) Create(ctx context.Context, product *model.Product) error {
+	assignWorkspace(ctx, &product.WorkspaceID)
 	return r.db.Write.WithContext(ctx).Create(product).Error
 }
 
 // FindByID finds a product by ID (uses read DB)
 func (r *ProductRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Product, error) {
 	var product model.Product
-	err := r.db.Read.WithContext(ctx).Preload("Offers").First(&product, "id = ?", id).Error
+	err := r.db.Read.WithContext(ctx).Scopes(inWorkspace(ctx, "products")).Preload("Offers").First(&product, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -40,12 +42,13 @@ func (r *ProductRepository) FindAll(ctx context.Context, limit, offset int) ([]*
 	var total int64
 
 	// Count total
-	if err := r.db.Read.WithContext(ctx).Model(&model.Product{}).Count(&total).Error; err != nil {
+	if err := r.db.Read.WithContext(ctx).Model(&model.Product{}).Scopes(inWorkspace(ctx, "products")).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Find with pagination
 	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspace(ctx, "products")).
 		Preload("Offers").
 		Order("created_at DESC").
 		Limit(limit).
@@ -64,7 +67,56 @@ func (r *ProductRepository) Update(ctx context.Context, product *model.Product)
 	return r.db.Write.WithContext(ctx).Save(product).Error
 }
 
-// Delete deletes a product (uses write DB)
+// Delete soft-deletes a product, hiding it and its links (uses write DB)
 func (r *ProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.Write.WithContext(ctx).Delete(&model.Product{}, "id = ?", id).Error
+	return r.db.Write.WithContext(ctx).Scopes(inWorkspace(ctx, "products")).Delete(&model.Product{}, "id = ?", id).Error
+}
+
+// FindDeletedByID finds a soft-deleted product by ID (uses read DB)
+func (r *ProductRepository) FindDeletedByID(ctx context.Context, id uuid.UUID) (*model.Product, error) {
+	var product model.Product
+	err := r.db.Read.WithContext(ctx).
+		Unscoped().
+		Scopes(inWorkspace(ctx, "products")).
+		Preload("Offers").
+		Where("deleted_at IS NOT NULL").
+		First(&product, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// Restore undoes a product's soft deletion (uses write DB)
+func (r *ProductRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	result := r.db.Write.WithContext(ctx).
+		Unscoped().
+		Model(&model.Product{}).
+		Scopes(inWorkspace(ctx, "products")).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge permanently deletes a soft-deleted product (uses write DB)
+// CASCADE constraints delete its offers, campaign associations, links and their clicks
+func (r *ProductRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	result := r.db.Write.WithContext(ctx).
+		Unscoped().
+		Scopes(inWorkspace(ctx, "products")).
+		Where("deleted_at IS NOT NULL").
+		Delete(&model.Product{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
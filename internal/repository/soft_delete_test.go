@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoftDelete(t *testing.T) {
+	db, recorder := newDryRunDB(t)
+	products := NewProductRepository(db)
+	campaigns := NewCampaignRepository(db)
+	links := NewLinkRepository(db)
+	clicks := NewClickRepository(db)
+	ctx := context.Background()
+	id := uuid.New()
+
+	t.Run("deletes set deleted_at", func(t *testing.T) {
+		_ = products.Delete(ctx, id)
+		_ = campaigns.Delete(ctx, id)
+		_ = links.Delete(ctx, id)
+
+		queries := recorder.take()
+		require.Len(t, queries, 3)
+		for i, table := range []string{"products", "campaigns", "links"} {
+			assert.True(t, strings.HasPrefix(queries[i], `UPDATE "`+table+`" SET "deleted_at"=`), queries[i])
+		}
+	})
+
+	t.Run("listings and lookups hide deleted rows", func(t *testing.T) {
+		_, _, _ = products.FindAll(ctx, 100, 0)
+		_, _, _ = campaigns.FindAll(ctx, 100, 0)
+		_, _ = products.FindByID(ctx, id)
+		_, _ = campaigns.FindByID(ctx, id)
+
+		queries := recorder.take()
+		require.Len(t, queries, 6) // Count and page for each listing, then the lookups
+		for _, sql := range queries {
+			assert.Contains(t, sql, `"deleted_at" IS NULL`, sql)
+		}
+	})
+
+	t.Run("links of deleted products and campaigns are hidden", func(t *testing.T) {
+		_, _ = links.FindByID(ctx, id)
+		_, _ = links.FindByShortCode(ctx, "abc123")
+		_, _ = links.CountWithFilters(ctx, nil, nil)
+
+		queries := recorder.take()
+		require.Len(t, queries, 3)
+		for _, sql := range queries {
+			assert.Contains(t, sql, `"links"."deleted_at" IS NULL`, sql)
+			assert.Contains(t, sql, "links.product_id NOT IN (SELECT id FROM products WHERE deleted_at IS NOT NULL)", sql)
+			assert.Contains(t, sql, "links.campaign_id NOT IN (SELECT id FROM campaigns WHERE deleted_at IS NOT NULL)", sql)
+		}
+	})
+
+	t.Run("deleted links keep their short codes and conversions", func(t *testing.T) {
+		_, _ = links.ShortCodeExists(ctx, "abc123")
+		_, _ = links.FindByIDIncludingDeleted(ctx, id)
+
+		queries := recorder.take()
+		require.Len(t, queries, 3) // Links and aliases, then the link
+		for _, sql := range queries {
+			assert.NotContains(t, sql, "deleted_at", sql)
+		}
+	})
+
+	t.Run("dashboard history keeps counting deleted items", func(t *testing.T) {
+		from, to := time.Now().Add(-24*time.Hour), time.Now()
+		_, _ = clicks.CountWithFilters(ctx, &id, nil, from, to, false)
+		_, _ = clicks.CountByCampaignWithFilters(ctx, nil, nil, from, to, false)
+		_, _ = clicks.FindTopProductsWithFilters(ctx, nil, nil, from, to, 10, false)
+
+		queries := recorder.take()
+		require.Len(t, queries, 3)
+		for _, sql := range queries {
+			assert.NotContains(t, sql, "deleted_at", sql)
+		}
+	})
+
+	t.Run("restores and purges only touch deleted rows", func(t *testing.T) {
+		_, _ = products.FindDeletedByID(ctx, id)
+		_ = products.Restore(ctx, id)
+		_ = products.Purge(ctx, id)
+		_, _ = campaigns.FindDeletedByID(ctx, id)
+		_ = campaigns.Restore(ctx, id)
+		_ = campaigns.Purge(ctx, id)
+
+		queries := recorder.take()
+		require.Len(t, queries, 6)
+		for _, sql := range queries {
+			assert.Contains(t, sql, "deleted_at IS NOT NULL", sql)
+			assert.NotContains(t, sql, `"deleted_at" IS NULL`, sql)
+		}
+		assert.True(t, strings.HasPrefix(queries[1], `UPDATE "products" SET "deleted_at"=NULL`), queries[1])
+		assert.True(t, strings.HasPrefix(queries[2], `DELETE FROM "products"`), queries[2])
+		assert.True(t, strings.HasPrefix(queries[4], `UPDATE "campaigns" SET "deleted_at"=NULL`), queries[4])
+		assert.True(t, strings.HasPrefix(queries[5], `DELETE FROM "campaigns"`), queries[5])
+	})
+}
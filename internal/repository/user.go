@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/database"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// UserRepository handles admin user database operations
+type UserRepository struct {
+	db *database.DB
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *database.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Create creates a new user in the caller's workspace (uses write DB)
+func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
+	assignWorkspace(ctx, &user.WorkspaceID)
+	return r.db.Write.WithContext(ctx).Create(user).Error
+}
+
+// FindByID finds a user by ID with its role (uses write DB)
+// Reads the primary so deactivation and role changes apply to the next request
+func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	var user model.User
+	err := r.db.Write.WithContext(ctx).
+		Scopes(inWorkspace(ctx, "users")).
+		Preload("Role").
+		First(&user, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByEmail finds a user by lower-cased email with its role (uses write DB)
+// Not scoped: emails are unique across workspaces and logins have no workspace yet
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	var user model.User
+	err := r.db.Write.WithContext(ctx).
+		Preload("Role").
+		First(&user, "email = ?", email).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindAll finds all users (uses read DB)
+func (r *UserRepository) FindAll(ctx context.Context, limit, offset int) ([]*model.User, int64, error) {
+	var users []*model.User
+	var total int64
+
+	// Count total
+	if err := r.db.Read.WithContext(ctx).Model(&model.User{}).Scopes(inWorkspace(ctx, "users")).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Find with pagination
+	err := r.db.Read.WithContext(ctx).
+		Scopes(inWorkspace(ctx, "users")).
+		Order("email ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// Update updates a user (uses write DB)
+func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
+	return r.db.Write.WithContext(ctx).Omit("Role").Save(user).Error
+}
+
+// UpdateLastLoginAt records when a user last logged in (uses write DB)
+func (r *UserRepository) UpdateLastLoginAt(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.Write.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", id).
+		UpdateColumn("last_login_at", at).Error
+}
+
+// CountActiveByRole counts active users with a role (uses write DB)
+func (r *UserRepository) CountActiveByRole(ctx context.Context, roleName string) (int64, error) {
+	var count int64
+	err := r.db.Write.WithContext(ctx).
+		Model(&model.User{}).
+		Scopes(inWorkspace(ctx, "users")).
+		Where("role_name = ? AND is_active = ?", roleName, true).
+		Count(&count).Error
+	return count, err
+}
+
+// Delete deletes a user (uses write DB)
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.Write.WithContext(ctx).Scopes(inWorkspace(ctx, "users")).Delete(&model.User{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RoleRepository handles role database operations
+type RoleRepository struct {
+	db *database.DB
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(db *database.DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// FindAll finds all roles (uses read DB)
+func (r *RoleRepository) FindAll(ctx context.Context) ([]*model.Role, error) {
+	var roles []*model.Role
+	err := r.db.Read.WithContext(ctx).Order("name ASC").Find(&roles).Error
+	return roles, err
+}
+
+// FindByName finds a role by name (uses read DB)
+func (r *RoleRepository) FindByName(ctx context.Context, name string) (*model.Role, error) {
+	var role model.Role
+	err := r.db.Read.WithContext(ctx).First(&role, "name = ?", name).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/database"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// inWorkspace scopes a query to the caller's workspace (see auth.WorkspaceFrom)
+// by table's workspace_id column
+// Contexts without a workspace are left unscoped: public redirects, postbacks
+// and workers act across workspaces
+func inWorkspace(ctx context.Context, table string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if id, ok := auth.WorkspaceFrom(ctx); ok {
+			return db.Where(table+".workspace_id = ?", id)
+		}
+		return db
+	}
+}
+
+// inWorkspaceVia scopes a query on a table without a workspace_id column to the
+// caller's workspace through the parent row that column references
+func inWorkspaceVia(ctx context.Context, column, parentTable string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if id, ok := auth.WorkspaceFrom(ctx); ok {
+			return db.Where(column+" IN (SELECT id FROM "+parentTable+" WHERE workspace_id = ?)", id)
+		}
+		return db
+	}
+}
+
+// assignWorkspace puts a new row in the caller's workspace
+// Rows created without one (e.g. clicks) must already name theirs
+func assignWorkspace(ctx context.Context, workspaceID *uuid.UUID) {
+	if id, ok := auth.WorkspaceFrom(ctx); ok {
+		*workspaceID = id
+	}
+}
+
+// WorkspaceRepository handles workspace database operations
+// Workspaces themselves are not scoped; only operators reach the listing
+type WorkspaceRepository struct {
+	db *database.DB
+}
+
+// NewWorkspaceRepository creates a new workspace repository
+func NewWorkspaceRepository(db *database.DB) *WorkspaceRepository {
+	return &WorkspaceRepository{db: db}
+}
+
+// Create creates a new workspace (uses write DB)
+func (r *WorkspaceRepository) Create(ctx context.Context, workspace *model.Workspace) error {
+	return r.db.Write.WithContext(ctx).Create(workspace).Error
+}
+
+// FindByID finds a workspace by ID (uses read DB)
+func (r *WorkspaceRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Workspace, error) {
+	var workspace model.Workspace
+	err := r.db.Read.WithContext(ctx).First(&workspace, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// FindBySlug finds a workspace by slug (uses read DB)
+func (r *WorkspaceRepository) FindBySlug(ctx context.Context, slug string) (*model.Workspace, error) {
+	var workspace model.Workspace
+	err := r.db.Read.WithContext(ctx).First(&workspace, "slug = ?", slug).Error
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// FindByRedirectDomain finds the workspace using a redirect domain (uses read DB)
+func (r *WorkspaceRepository) FindByRedirectDomain(ctx context.Context, domain string) (*model.Workspace, error) {
+	var workspace model.Workspace
+	err := r.db.Read.WithContext(ctx).First(&workspace, "redirect_domain = ?", domain).Error
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// FindAll finds all workspaces (uses read DB)
+func (r *WorkspaceRepository) FindAll(ctx context.Context, limit, offset int) ([]*model.Workspace, int64, error) {
+	var workspaces []*model.Workspace
+	var total int64
+
+	// Count total
+	if err := r.db.Read.WithContext(ctx).Model(&model.Workspace{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Find with pagination
+	err := r.db.Read.WithContext(ctx).
+		Order("name ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&workspaces).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return workspaces, total, nil
+}
+
+// Update updates a workspace (uses write DB)
+func (r *WorkspaceRepository) Update(ctx context.Context, workspace *model.Workspace) error {
+	return r.db.Write.WithContext(ctx).Save(workspace).Error
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/database"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// queryRecorder is a GORM logger that keeps the SQL of every statement
+type queryRecorder struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func (r *queryRecorder) LogMode(gormlogger.LogLevel) gormlogger.Interface { return r }
+func (r *queryRecorder) Info(context.Context, string, ...interface{})     {}
+func (r *queryRecorder) Warn(context.Context, string, ...interface{})     {}
+func (r *queryRecorder) Error(context.Context, string, ...interface{})    {}
+
+func (r *queryRecorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, sql)
+}
+
+// take returns and forgets the recorded queries
+func (r *queryRecorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queries := r.queries
+	r.queries = nil
+	return queries
+}
+
+// newDryRunDB returns a database that builds postgres SQL without connecting
+func newDryRunDB(t *testing.T) (*database.DB, *queryRecorder) {
+	t.Helper()
+	recorder := &queryRecorder{}
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=affiliate"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true, // Transactions would connect
+		Logger:                 recorder,
+	})
+	require.NoError(t, err)
+	return &database.DB{Write: db, Read: db}, recorder
+}
+
+func TestWorkspaceScoping_DashboardQueries(t *testing.T) {
+	db, recorder := newDryRunDB(t)
+	clicks := NewClickRepository(db)
+	links := NewLinkRepository(db)
+	impressions := NewImpressionRepository(db)
+	conversions := NewConversionRepository(db)
+
+	workspaceID := uuid.New()
+	campaignID := uuid.New()
+	marketplace := "shopee"
+	from, to := time.Now().Add(-24*time.Hour), time.Now()
+
+	// Every query the dashboard aggregates from
+	dashboard := func(ctx context.Context) {
+		_, _ = clicks.CountWithFilters(ctx, &campaignID, &marketplace, from, to, false)
+		_, _ = clicks.CountUniqueWithFilters(ctx, nil, nil, from, to, false)
+		_, _ = clicks.CountByCampaignWithFilters(ctx, nil, nil, from, to, false)
+		_, _ = clicks.CountByMarketplaceWithFilters(ctx, nil, nil, from, to, false)
+		_, _ = clicks.CountBySourceWithFilters(ctx, nil, nil, from, to, false)
+		_, _ = clicks.FindTopProductsWithFilters(ctx, nil, nil, from, to, 10, false)
+		_, _ = clicks.FindRecentClicks(ctx, 10, false)
+		_, _ = links.CountWithFilters(ctx, nil, nil)
+		_, _ = impressions.CountWithFilters(ctx, nil, nil, from, to, false)
+		_, _ = impressions.CountByCampaignWithFilters(ctx, nil, nil, from, to, false)
+		_, _ = impressions.CountByMarketplaceWithFilters(ctx, nil, nil, from, to, false)
+		_, _ = impressions.CountByProductsWithFilters(ctx, []uuid.UUID{uuid.New()}, nil, nil, from, to, false)
+		_, _ = conversions.SumWithFilters(ctx, nil, nil, from, to)
+		_, _ = conversions.SumByCampaignWithFilters(ctx, nil, nil, from, to)
+	}
+
+	t.Run("workspace requests only read their workspace", func(t *testing.T) {
+		dashboard(auth.WithWorkspace(context.Background(), workspaceID))
+
+		queries := recorder.take()
+		require.Len(t, queries, 14)
+		for _, sql := range queries {
+			assert.Contains(t, sql, "workspace_id = '"+workspaceID.String()+"'", sql)
+		}
+	})
+
+	t.Run("requests without a workspace are not scoped", func(t *testing.T) {
+		dashboard(context.Background())
+
+		for _, sql := range recorder.take() {
+			assert.NotContains(t, sql, "workspace_id", sql)
+		}
+	})
+}
+
+func TestWorkspaceScoping_Links(t *testing.T) {
+	db, recorder := newDryRunDB(t)
+	links := NewLinkRepository(db)
+	workspaceID := uuid.New()
+	ctx := auth.WithWorkspace(context.Background(), workspaceID)
+
+	t.Run("lookups and deletes are scoped", func(t *testing.T) {
+		_, _ = links.FindByID(ctx, uuid.New())
+		_, _ = links.FindByShortCode(ctx, "abc123")
+		_, _ = links.FindByCampaignID(ctx, uuid.New())
+		_ = links.Delete(ctx, uuid.New())
+		_, _ = links.FindRedirectRulesByLinkID(ctx, uuid.New())
+
+		queries := recorder.take()
+		require.Len(t, queries, 5)
+		for _, sql := range queries {
+			assert.Contains(t, sql, workspaceID.String(), sql)
+		}
+	})
+
+	t.Run("short codes are unique across workspaces", func(t *testing.T) {
+		_, _ = links.ShortCodeExists(ctx, "abc123")
+
+		for _, sql := range recorder.take() {
+			assert.NotContains(t, sql, "workspace_id", sql)
+		}
+	})
+
+	t.Run("new links join the caller's workspace", func(t *testing.T) {
+		link := &model.Link{ShortCode: "abc123"}
+		require.NoError(t, links.Create(ctx, link))
+		assert.Equal(t, workspaceID, link.WorkspaceID)
+
+		queries := recorder.take()
+		require.Len(t, queries, 1)
+		assert.True(t, strings.HasPrefix(queries[0], `INSERT INTO "links"`), queries[0])
+	})
+}
@@ -0,0 +1,503 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	"github.com/jonosize/affiliate-platform/pkg/adapters"
+)
+
+const (
+	// alertEventPriceChanged is the webhook event name for price changes
+	alertEventPriceChanged = "offer.price_changed"
+
+	// Webhook delivery settings
+	alertWebhookTimeout     = 10 * time.Second
+	alertWebhookMaxAttempts = 3
+	alertWebhookBackoff     = 1 * time.Second // Doubled after each failed attempt
+
+	// Webhook headers
+	AlertHeaderEvent     = "X-Alert-Event"
+	AlertHeaderDelivery  = "X-Alert-Delivery"
+	AlertHeaderTimestamp = "X-Alert-Timestamp"
+	AlertHeaderSignature = "X-Alert-Signature"
+)
+
+// AlertService handles price alert rules and webhook delivery
+type AlertService struct {
+	alertRuleRepo AlertRuleRepositoryInterface
+	deliveryRepo  AlertDeliveryRepositoryInterface
+	productRepo   ProductRepositoryInterface
+	campaignRepo  CampaignRepositoryInterface
+	registry      *adapters.Registry
+	httpClient    *http.Client
+	retryBackoff  time.Duration
+	logger        logger.Logger
+}
+
+// NewAlertService creates a new alert service
+func NewAlertService(
+	alertRuleRepo AlertRuleRepositoryInterface,
+	deliveryRepo AlertDeliveryRepositoryInterface,
+	productRepo ProductRepositoryInterface,
+	campaignRepo CampaignRepositoryInterface,
+	registry *adapters.Registry,
+	log logger.Logger,
+) *AlertService {
+	return &AlertService{
+		alertRuleRepo: alertRuleRepo,
+		deliveryRepo:  deliveryRepo,
+		productRepo:   productRepo,
+		campaignRepo:  campaignRepo,
+		registry:      registry,
+		httpClient:    &http.Client{Timeout: alertWebhookTimeout},
+		retryBackoff:  alertWebhookBackoff,
+		logger:        log,
+	}
+}
+
+// CreateAlert creates a price alert rule
+func (s *AlertService) CreateAlert(ctx context.Context, req dto.CreateAlertRequest) (*dto.AlertResponse, error) {
+	if req.Name == "" {
+		return nil, fmt.Errorf("invalid alert: name is required")
+	}
+
+	// Validate scope: exactly one of product or campaign
+	if (req.ProductID == nil) == (req.CampaignID == nil) {
+		return nil, fmt.Errorf("invalid alert: exactly one of product_id or campaign_id must be set")
+	}
+	if req.ProductID != nil {
+		if _, err := s.productRepo.FindByID(ctx, *req.ProductID); err != nil {
+			return nil, fmt.Errorf("product not found: %w", err)
+		}
+	}
+	if req.CampaignID != nil {
+		if _, err := s.campaignRepo.FindByID(ctx, *req.CampaignID); err != nil {
+			return nil, fmt.Errorf("campaign not found: %w", err)
+		}
+	}
+
+	rule := &model.AlertRule{
+		Name:          req.Name,
+		ProductID:     req.ProductID,
+		CampaignID:    req.CampaignID,
+		Direction:     model.AlertDirectionDrop,
+		ThresholdType: model.AlertThresholdType(req.ThresholdType),
+		Threshold:     req.Threshold,
+		WebhookURL:    req.WebhookURL,
+		Secret:        req.Secret,
+		IsActive:      true,
+	}
+	if req.Direction != "" {
+		rule.Direction = model.AlertDirection(req.Direction)
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	if err := s.setMarketplaceFilter(rule, req.Marketplace); err != nil {
+		return nil, err
+	}
+	if err := validateAlertRule(rule); err != nil {
+		return nil, err
+	}
+
+	// Generate a signing secret if none was provided
+	if rule.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		rule.Secret = secret
+	}
+
+	if err := s.alertRuleRepo.Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create alert: %w", err)
+	}
+
+	// The secret is only returned once, on create
+	response := toAlertResponse(rule)
+	response.Secret = rule.Secret
+	return response, nil
+}
+
+// GetAlert gets a price alert rule by ID
+func (s *AlertService) GetAlert(ctx context.Context, alertID uuid.UUID) (*dto.AlertResponse, error) {
+	rule, err := s.alertRuleRepo.FindByID(ctx, alertID)
+	if err != nil {
+		return nil, fmt.Errorf("alert not found: %w", err)
+	}
+	return toAlertResponse(rule), nil
+}
+
+// GetAllAlerts gets all price alert rules with pagination
+func (s *AlertService) GetAllAlerts(ctx context.Context, limit, offset int) ([]*dto.AlertResponse, error) {
+	rules, _, err := s.alertRuleRepo.FindAll(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts: %w", err)
+	}
+
+	responses := make([]*dto.AlertResponse, len(rules))
+	for i, rule := range rules {
+		responses[i] = toAlertResponse(rule)
+	}
+	return responses, nil
+}
+
+// UpdateAlert updates a price alert rule
+func (s *AlertService) UpdateAlert(ctx context.Context, alertID uuid.UUID, req dto.UpdateAlertRequest) (*dto.AlertResponse, error) {
+	rule, err := s.alertRuleRepo.FindByID(ctx, alertID)
+	if err != nil {
+		return nil, fmt.Errorf("alert not found: %w", err)
+	}
+
+	// Update fields if provided
+	if req.Name != "" {
+		rule.Name = req.Name
+	}
+	if req.Marketplace != nil {
+		if err := s.setMarketplaceFilter(rule, req.Marketplace); err != nil {
+			return nil, err
+		}
+	}
+	if req.Direction != "" {
+		rule.Direction = model.AlertDirection(req.Direction)
+	}
+	if req.ThresholdType != "" {
+		rule.ThresholdType = model.AlertThresholdType(req.ThresholdType)
+	}
+	if req.Threshold != nil {
+		rule.Threshold = *req.Threshold
+	}
+	if req.WebhookURL != "" {
+		rule.WebhookURL = req.WebhookURL
+	}
+	if req.Secret != "" {
+		rule.Secret = req.Secret
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := validateAlertRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.alertRuleRepo.Update(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to update alert: %w", err)
+	}
+
+	return toAlertResponse(rule), nil
+}
+
+// DeleteAlert deletes a price alert rule and its delivery log
+func (s *AlertService) DeleteAlert(ctx context.Context, alertID uuid.UUID) error {
+	if _, err := s.alertRuleRepo.FindByID(ctx, alertID); err != nil {
+		return fmt.Errorf("alert not found: %w", err)
+	}
+
+	if err := s.alertRuleRepo.Delete(ctx, alertID); err != nil {
+		return fmt.Errorf("failed to delete alert: %w", err)
+	}
+
+	return nil
+}
+
+// GetAlertDeliveries gets the webhook delivery log of a price alert rule
+func (s *AlertService) GetAlertDeliveries(ctx context.Context, alertID uuid.UUID, limit, offset int) ([]*dto.AlertDeliveryResponse, error) {
+	if _, err := s.alertRuleRepo.FindByID(ctx, alertID); err != nil {
+		return nil, fmt.Errorf("alert not found: %w", err)
+	}
+
+	deliveries, _, err := s.deliveryRepo.FindByAlertRuleID(ctx, alertID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert deliveries: %w", err)
+	}
+
+	responses := make([]*dto.AlertDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		responses[i] = &dto.AlertDeliveryResponse{
+			ID:             d.ID,
+			AlertRuleID:    d.AlertRuleID,
+			ProductID:      d.ProductID,
+			Marketplace:    string(d.Marketplace),
+			OldPrice:       d.OldPrice,
+			NewPrice:       d.NewPrice,
+			Status:         string(d.Status),
+			Attempts:       d.Attempts,
+			ResponseStatus: d.ResponseStatus,
+			LastError:      d.LastError,
+			DeliveredAt:    d.DeliveredAt,
+			CreatedAt:      d.CreatedAt,
+		}
+	}
+	return responses, nil
+}
+
+// EvaluatePriceChange evaluates alert rules for an offer whose price changed from oldPrice
+// and delivers a webhook for every rule that fires. Called in the background by the price refresh
+// worker, with a deadline covering all deliveries and their retries.
+func (s *AlertService) EvaluatePriceChange(ctx context.Context, offer *model.Offer, oldPrice float64) error {
+	if oldPrice == offer.Price {
+		return nil
+	}
+
+	rules, err := s.alertRuleRepo.FindActiveForOffer(ctx, offer.ProductID, offer.Marketplace)
+	if err != nil {
+		return fmt.Errorf("failed to get alert rules: %w", err)
+	}
+
+	for _, rule := range rules {
+		direction, fired := evaluateAlertRule(rule, oldPrice, offer.Price)
+		if !fired {
+			continue
+		}
+
+		if err := s.notify(ctx, rule, offer, oldPrice, direction); err != nil {
+			s.logger.Warn("Failed to deliver price alert", logger.Error(err),
+				logger.String("alert_id", rule.ID.String()),
+				logger.String("product_id", offer.ProductID.String()))
+		}
+	}
+
+	return nil
+}
+
+// notify records a delivery and sends the webhook, retrying on failure
+func (s *AlertService) notify(ctx context.Context, rule *model.AlertRule, offer *model.Offer, oldPrice float64, direction model.AlertDirection) error {
+	change := offer.Price - oldPrice
+	delivery := &model.AlertDelivery{
+		ID:          uuid.New(),
+		AlertRuleID: rule.ID,
+		OfferID:     offer.ID,
+		ProductID:   offer.ProductID,
+		Marketplace: offer.Marketplace,
+		OldPrice:    oldPrice,
+		NewPrice:    offer.Price,
+		Status:      model.AlertDeliveryPending,
+	}
+
+	payload := dto.AlertWebhookPayload{
+		Event:                 alertEventPriceChanged,
+		DeliveryID:            delivery.ID,
+		AlertID:               rule.ID,
+		AlertName:             rule.Name,
+		CampaignID:            rule.CampaignID,
+		ProductID:             offer.ProductID,
+		OfferID:               offer.ID,
+		Marketplace:           string(offer.Marketplace),
+		StoreName:             offer.StoreName,
+		MarketplaceProductURL: offer.MarketplaceProductURL,
+		Direction:             string(direction),
+		OldPrice:              oldPrice,
+		NewPrice:              offer.Price,
+		Change:                math.Round(change*100) / 100,
+		ChangePercent:         math.Round(change/oldPrice*100*100) / 100,
+		OccurredAt:            time.Now().UTC(),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	delivery.Payload = string(body)
+
+	if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+		return fmt.Errorf("failed to record delivery: %w", err)
+	}
+
+	s.deliver(ctx, rule, delivery, body)
+
+	if err := s.deliveryRepo.Update(ctx, delivery); err != nil {
+		return fmt.Errorf("failed to update delivery: %w", err)
+	}
+	if delivery.Status != model.AlertDeliveryDelivered {
+		return fmt.Errorf("webhook delivery failed after %d attempts: %s", delivery.Attempts, delivery.LastError)
+	}
+	return nil
+}
+
+// deliver sends the webhook with exponential backoff, updating the delivery in place
+// Network errors, 429 and 5xx responses are retried; other 4xx responses are not
+func (s *AlertService) deliver(ctx context.Context, rule *model.AlertRule, delivery *model.AlertDelivery, body []byte) {
+	backoff := s.retryBackoff
+	for attempt := 1; attempt <= alertWebhookMaxAttempts; attempt++ {
+		delivery.Attempts = attempt
+
+		statusCode, err := s.send(ctx, rule, delivery.ID, body)
+		if statusCode != 0 {
+			code := statusCode
+			delivery.ResponseStatus = &code
+		}
+		if err == nil {
+			now := time.Now()
+			delivery.Status = model.AlertDeliveryDelivered
+			delivery.DeliveredAt = &now
+			delivery.LastError = ""
+			return
+		}
+		delivery.LastError = err.Error()
+
+		retryable := statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= 500
+		if !retryable || attempt == alertWebhookMaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			delivery.LastError = ctx.Err().Error()
+			delivery.Status = model.AlertDeliveryFailed
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	delivery.Status = model.AlertDeliveryFailed
+}
+
+// send performs a single signed webhook request and returns the response status code
+func (s *AlertService) send(ctx context.Context, rule *model.AlertRule, deliveryID uuid.UUID, body []byte) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rule.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(AlertHeaderEvent, alertEventPriceChanged)
+	req.Header.Set(AlertHeaderDelivery, deliveryID.String())
+	req.Header.Set(AlertHeaderTimestamp, timestamp)
+	req.Header.Set(AlertHeaderSignature, "sha256="+SignWebhookPayload(rule.Secret, timestamp, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload computes the hex HMAC-SHA256 of "<timestamp>.<body>" with the rule secret
+// Receivers verify X-Alert-Signature by recomputing it from X-Alert-Timestamp and the raw body
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// evaluateAlertRule reports whether a price change from oldPrice to newPrice fires the rule
+func evaluateAlertRule(rule *model.AlertRule, oldPrice, newPrice float64) (model.AlertDirection, bool) {
+	change := newPrice - oldPrice
+	if change == 0 || oldPrice <= 0 {
+		return "", false
+	}
+
+	direction := model.AlertDirectionDrop
+	if change > 0 {
+		direction = model.AlertDirectionRise
+	}
+	if rule.Direction != model.AlertDirectionAny && rule.Direction != direction {
+		return "", false
+	}
+
+	magnitude := math.Abs(change)
+	if rule.ThresholdType == model.AlertThresholdPercent {
+		magnitude = magnitude / oldPrice * 100
+	}
+
+	return direction, magnitude >= rule.Threshold
+}
+
+// setMarketplaceFilter sets or clears the rule's marketplace filter, validating it against the registry
+func (s *AlertService) setMarketplaceFilter(rule *model.AlertRule, marketplace *string) error {
+	if marketplace == nil || *marketplace == "" {
+		rule.Marketplace = nil
+		return nil
+	}
+	if !s.registry.Has(adapters.Marketplace(*marketplace)) {
+		return fmt.Errorf("invalid marketplace: %s is not supported", *marketplace)
+	}
+	m := model.Marketplace(*marketplace)
+	rule.Marketplace = &m
+	return nil
+}
+
+// validateAlertRule validates rule fields shared by create and update
+func validateAlertRule(rule *model.AlertRule) error {
+	switch rule.Direction {
+	case model.AlertDirectionDrop, model.AlertDirectionRise, model.AlertDirectionAny:
+	default:
+		return fmt.Errorf("invalid alert: direction must be 'drop', 'rise' or 'any'")
+	}
+
+	switch rule.ThresholdType {
+	case model.AlertThresholdPercent, model.AlertThresholdAbsolute:
+	default:
+		return fmt.Errorf("invalid alert: threshold_type must be 'percent' or 'absolute'")
+	}
+
+	if rule.Threshold <= 0 {
+		return fmt.Errorf("invalid alert: threshold must be greater than 0")
+	}
+
+	u, err := url.Parse(rule.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid alert: webhook_url must be an absolute http(s) URL")
+	}
+
+	return nil
+}
+
+// generateWebhookSecret generates a random webhook signing secret
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// toAlertResponse converts an alert rule to a response (without secret)
+func toAlertResponse(rule *model.AlertRule) *dto.AlertResponse {
+	response := &dto.AlertResponse{
+		ID:            rule.ID,
+		Name:          rule.Name,
+		ProductID:     rule.ProductID,
+		CampaignID:    rule.CampaignID,
+		Direction:     string(rule.Direction),
+		ThresholdType: string(rule.ThresholdType),
+		Threshold:     rule.Threshold,
+		WebhookURL:    rule.WebhookURL,
+		IsActive:      rule.IsActive,
+		CreatedAt:     rule.CreatedAt,
+		UpdatedAt:     rule.UpdatedAt,
+	}
+	if rule.Marketplace != nil {
+		m := string(*rule.Marketplace)
+		response.Marketplace = &m
+	}
+	return response
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// MockAlertRuleRepository is a mock implementation of AlertRuleRepositoryInterface
+type MockAlertRuleRepository struct {
+	mock.Mock
+}
+
+func (m *MockAlertRuleRepository) Create(ctx context.Context, rule *model.AlertRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockAlertRuleRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.AlertRule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AlertRule), args.Error(1)
+}
+
+func (m *MockAlertRuleRepository) FindAll(ctx context.Context, limit, offset int) ([]*model.AlertRule, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*model.AlertRule), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAlertRuleRepository) FindActiveForOffer(ctx context.Context, productID uuid.UUID, marketplace model.Marketplace) ([]*model.AlertRule, error) {
+	args := m.Called(ctx, productID, marketplace)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.AlertRule), args.Error(1)
+}
+
+func (m *MockAlertRuleRepository) Update(ctx context.Context, rule *model.AlertRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockAlertRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockAlertDeliveryRepository is a mock implementation of AlertDeliveryRepositoryInterface
+type MockAlertDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockAlertDeliveryRepository) Create(ctx context.Context, delivery *model.AlertDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockAlertDeliveryRepository) Update(ctx context.Context, delivery *model.AlertDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockAlertDeliveryRepository) FindByAlertRuleID(ctx context.Context, alertRuleID uuid.UUID, limit, offset int) ([]*model.AlertDelivery, int64, error) {
+	args := m.Called(ctx, alertRuleID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*model.AlertDelivery), args.Get(1).(int64), args.Error(2)
+}
+
+func TestEvaluateAlertRule(t *testing.T) {
+	tests := []struct {
+		name          string
+		rule          model.AlertRule
+		oldPrice      float64
+		newPrice      float64
+		wantFired     bool
+		wantDirection model.AlertDirection
+	}{
+		{
+			name:          "percent drop above threshold",
+			rule:          model.AlertRule{Direction: model.AlertDirectionDrop, ThresholdType: model.AlertThresholdPercent, Threshold: 10},
+			oldPrice:      300,
+			newPrice:      260,
+			wantFired:     true,
+			wantDirection: model.AlertDirectionDrop,
+		},
+		{
+			name:     "percent drop below threshold",
+			rule:     model.AlertRule{Direction: model.AlertDirectionDrop, ThresholdType: model.AlertThresholdPercent, Threshold: 10},
+			oldPrice: 300,
+			newPrice: 290,
+		},
+		{
+			name:     "rise ignored by drop rule",
+			rule:     model.AlertRule{Direction: model.AlertDirectionDrop, ThresholdType: model.AlertThresholdAbsolute, Threshold: 5},
+			oldPrice: 300,
+			newPrice: 350,
+		},
+		{
+			name:          "absolute rise on any rule",
+			rule:          model.AlertRule{Direction: model.AlertDirectionAny, ThresholdType: model.AlertThresholdAbsolute, Threshold: 50},
+			oldPrice:      300,
+			newPrice:      350,
+			wantFired:     true,
+			wantDirection: model.AlertDirectionRise,
+		},
+		{
+			name:     "no previous price",
+			rule:     model.AlertRule{Direction: model.AlertDirectionAny, ThresholdType: model.AlertThresholdAbsolute, Threshold: 1},
+			oldPrice: 0,
+			newPrice: 350,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			direction, fired := evaluateAlertRule(&tt.rule, tt.oldPrice, tt.newPrice)
+			assert.Equal(t, tt.wantFired, fired)
+			if tt.wantFired {
+				assert.Equal(t, tt.wantDirection, direction)
+			}
+		})
+	}
+}
+
+func TestAlertService_EvaluatePriceChange(t *testing.T) {
+	var calls int32
+	var gotSignature, gotTimestamp string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt to exercise retries
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		gotSignature = r.Header.Get(AlertHeaderSignature)
+		gotTimestamp = r.Header.Get(AlertHeaderTimestamp)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	productID := uuid.New()
+	rule := &model.AlertRule{
+		ID:            uuid.New(),
+		Name:          "Price drop",
+		ProductID:     &productID,
+		Direction:     model.AlertDirectionDrop,
+		ThresholdType: model.AlertThresholdPercent,
+		Threshold:     10,
+		WebhookURL:    server.URL,
+		Secret:        "test-secret",
+		IsActive:      true,
+	}
+	offer := &model.Offer{
+		ID:          uuid.New(),
+		ProductID:   productID,
+		Marketplace: model.MarketplaceLazada,
+		Price:       250,
+	}
+
+	ruleRepo := new(MockAlertRuleRepository)
+	deliveryRepo := new(MockAlertDeliveryRepository)
+	ruleRepo.On("FindActiveForOffer", mock.Anything, productID, model.MarketplaceLazada).Return([]*model.AlertRule{rule}, nil)
+	deliveryRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.AlertDelivery")).Return(nil)
+	deliveryRepo.On("Update", mock.Anything, mock.MatchedBy(func(d *model.AlertDelivery) bool {
+		return d.Status == model.AlertDeliveryDelivered && d.Attempts == 2 && *d.ResponseStatus == http.StatusNoContent
+	})).Return(nil)
+
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	svc := NewAlertService(ruleRepo, deliveryRepo, nil, nil, nil, log)
+	svc.retryBackoff = 0
+
+	require.NoError(t, svc.EvaluatePriceChange(context.Background(), offer, 300))
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, "sha256="+SignWebhookPayload("test-secret", gotTimestamp, gotBody), gotSignature)
+	assert.Contains(t, string(gotBody), `"direction":"drop"`)
+	ruleRepo.AssertExpectations(t)
+	deliveryRepo.AssertExpectations(t)
+}
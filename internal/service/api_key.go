@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+const (
+	// apiKeyPrefix starts every API key, so leaked keys are easy to recognize
+	apiKeyPrefix = "afk_"
+
+	// apiKeyDisplayLength is how much of a key is stored in the clear to tell keys apart
+	apiKeyDisplayLength = len(apiKeyPrefix) + 8
+
+	// apiKeyTouchInterval limits last_used_at writes to one per key per interval
+	apiKeyTouchInterval = time.Minute
+)
+
+// ErrInvalidAPIKey is returned for unknown and expired API keys
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// APIKeyService manages admin API keys and authenticates requests that carry one
+type APIKeyService struct {
+	apiKeyRepo APIKeyRepositoryInterface
+	logger     logger.Logger
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(apiKeyRepo APIKeyRepositoryInterface, log logger.Logger) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+		logger:     log,
+	}
+}
+
+// CreateAPIKey creates an API key
+// The key is only returned here; it is stored as a SHA-256 hash
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, req dto.CreateAPIKeyRequest) (*dto.APIKeyResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("invalid api key: name is required")
+	}
+	if len(name) > 100 {
+		return nil, fmt.Errorf("invalid api key: name must be at most 100 characters")
+	}
+	if len(req.Scopes) == 0 {
+		return nil, fmt.Errorf("invalid api key: at least one scope is required")
+	}
+	scopes, err := auth.ParseScopes(req.Scopes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api key: %w", err)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("invalid api key: expires_at must be in the future")
+	}
+
+	key, err := generateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	apiKey := &model.APIKey{
+		Name:      name,
+		KeyPrefix: key[:apiKeyDisplayLength],
+		KeyHash:   hashAPIKey(key),
+		Scopes:    make([]string, len(scopes)),
+		ExpiresAt: req.ExpiresAt,
+	}
+	for i, scope := range scopes {
+		apiKey.Scopes[i] = string(scope)
+	}
+	if err := s.apiKeyRepo.Create(ctx, apiKey); err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	response := toAPIKeyResponse(apiKey)
+	response.Key = key
+	return response, nil
+}
+
+// GetAllAPIKeys lists API keys (without their keys)
+func (s *APIKeyService) GetAllAPIKeys(ctx context.Context, limit, offset int) ([]*dto.APIKeyResponse, error) {
+	keys, _, err := s.apiKeyRepo.FindAll(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+
+	responses := make([]*dto.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = toAPIKeyResponse(key)
+	}
+	return responses, nil
+}
+
+// DeleteAPIKey revokes an API key
+func (s *APIKeyService) DeleteAPIKey(ctx context.Context, id uuid.UUID) error {
+	if err := s.apiKeyRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("api key not found: %w", err)
+		}
+		return fmt.Errorf("failed to delete api key: %w", err)
+	}
+	return nil
+}
+
+// Authenticate resolves a bearer API key to the principal it grants
+// Unknown and expired keys return ErrInvalidAPIKey
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (*auth.Principal, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.apiKeyRepo.FindByKeyHash(ctx, hashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	now := time.Now()
+	if apiKey.ExpiresAt != nil && !now.Before(*apiKey.ExpiresAt) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// Best effort: a failed write must not reject a valid key
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.apiKeyRepo.UpdateLastUsedAt(ctx, apiKey.ID, now); err != nil {
+			s.logger.Warn("Failed to record api key use", logger.String("api_key_id", apiKey.ID.String()), logger.Error(err))
+		}
+	}
+
+	// Scopes were validated on create; unknown names grant nothing
+	principal := &auth.Principal{
+		Subject:     "api_key:" + apiKey.ID.String(),
+		Name:        apiKey.Name,
+		WorkspaceID: apiKey.WorkspaceID,
+	}
+	for _, name := range apiKey.Scopes {
+		principal.Scopes = append(principal.Scopes, auth.Scope(name))
+	}
+	return principal, nil
+}
+
+// generateAPIKey returns a new random API key
+func generateAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + hex.EncodeToString(b), nil
+}
+
+// hashAPIKey returns the stored form of an API key
+// Keys are 256-bit random values, so a fast unsalted hash is enough and keeps lookups indexed
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// toAPIKeyResponse converts an API key to a response (without the key)
+func toAPIKeyResponse(key *model.APIKey) *dto.APIKeyResponse {
+	return &dto.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		KeyPrefix:  key.KeyPrefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
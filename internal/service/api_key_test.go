@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// MockAPIKeyRepository is a mock implementation of APIKeyRepositoryInterface
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) FindByKeyHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindAll(ctx context.Context, limit, offset int) ([]*model.APIKey, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.APIKey), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAPIKeyRepository) UpdateLastUsedAt(ctx context.Context, id uuid.UUID, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func newTestAPIKeyService(t *testing.T) (*APIKeyService, *MockAPIKeyRepository) {
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	repo := new(MockAPIKeyRepository)
+	return NewAPIKeyService(repo, log), repo
+}
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores the hash and returns the key once", func(t *testing.T) {
+		svc, repo := newTestAPIKeyService(t)
+		var stored *model.APIKey
+		repo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*model.APIKey)
+		}).Return(nil)
+
+		resp, err := svc.CreateAPIKey(ctx, dto.CreateAPIKeyRequest{Name: " Reporting ", Scopes: []string{"read", "Worker", "read"}})
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(resp.Key, apiKeyPrefix))
+		assert.Len(t, resp.Key, len(apiKeyPrefix)+64)
+		assert.Equal(t, resp.Key[:apiKeyDisplayLength], resp.KeyPrefix)
+		assert.Equal(t, "Reporting", stored.Name)
+		assert.Equal(t, []string{"read", "worker"}, stored.Scopes)
+		assert.Equal(t, hashAPIKey(resp.Key), stored.KeyHash)
+		assert.NotContains(t, stored.KeyHash, resp.Key[len(apiKeyPrefix):])
+		assert.Empty(t, toAPIKeyResponse(stored).Key)
+	})
+
+	past := time.Now().Add(-time.Hour)
+	invalid := []struct {
+		name    string
+		req     dto.CreateAPIKeyRequest
+		wantErr string
+	}{
+		{name: "name", req: dto.CreateAPIKeyRequest{Scopes: []string{"read"}}, wantErr: "invalid api key: name is required"},
+		{name: "no scopes", req: dto.CreateAPIKeyRequest{Name: "x"}, wantErr: "invalid api key: at least one scope is required"},
+		{name: "unknown scope", req: dto.CreateAPIKeyRequest{Name: "x", Scopes: []string{"admin"}}, wantErr: "invalid api key: unknown scope: admin"},
+		{name: "expired", req: dto.CreateAPIKeyRequest{Name: "x", Scopes: []string{"read"}, ExpiresAt: &past}, wantErr: "invalid api key: expires_at must be in the future"},
+	}
+	for _, tt := range invalid {
+		t.Run("invalid "+tt.name, func(t *testing.T) {
+			svc, _ := newTestAPIKeyService(t)
+			_, err := svc.CreateAPIKey(ctx, tt.req)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	const key = "afk_0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	keyID := uuid.New()
+
+	t.Run("valid key", func(t *testing.T) {
+		svc, repo := newTestAPIKeyService(t)
+		repo.On("FindByKeyHash", ctx, hashAPIKey(key)).Return(&model.APIKey{ID: keyID, Name: "CI", Scopes: []string{"read", "write"}}, nil)
+		repo.On("UpdateLastUsedAt", ctx, keyID, mock.Anything).Return(nil)
+
+		principal, err := svc.Authenticate(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, "api_key:"+keyID.String(), principal.Subject)
+		assert.Equal(t, "CI", principal.Name)
+		assert.True(t, principal.HasScope(auth.ScopeWrite))
+		assert.False(t, principal.HasScope(auth.ScopeWorker))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("recently used key is not touched", func(t *testing.T) {
+		svc, repo := newTestAPIKeyService(t)
+		lastUsed := time.Now().Add(-10 * time.Second)
+		repo.On("FindByKeyHash", ctx, hashAPIKey(key)).Return(&model.APIKey{ID: keyID, Scopes: []string{"read"}, LastUsedAt: &lastUsed}, nil)
+
+		_, err := svc.Authenticate(ctx, key)
+		require.NoError(t, err)
+		repo.AssertNotCalled(t, "UpdateLastUsedAt", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("expired key", func(t *testing.T) {
+		svc, repo := newTestAPIKeyService(t)
+		expired := time.Now().Add(-time.Minute)
+		repo.On("FindByKeyHash", ctx, hashAPIKey(key)).Return(&model.APIKey{ID: keyID, Scopes: []string{"read"}, ExpiresAt: &expired}, nil)
+
+		_, err := svc.Authenticate(ctx, key)
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		svc, repo := newTestAPIKeyService(t)
+		repo.On("FindByKeyHash", ctx, hashAPIKey(key)).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.Authenticate(ctx, key)
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	})
+
+	t.Run("not an api key", func(t *testing.T) {
+		svc, repo := newTestAPIKeyService(t)
+
+		_, err := svc.Authenticate(ctx, "eyJhbGciOiJIUzI1NiJ9")
+		assert.ErrorIs(t, err, ErrInvalidAPIKey)
+		repo.AssertNotCalled(t, "FindByKeyHash", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAPIKeyService_DeleteAPIKey(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestAPIKeyService(t)
+	id := uuid.New()
+	repo.On("Delete", ctx, id).Return(gorm.ErrRecordNotFound)
+
+	err := svc.DeleteAPIKey(ctx, id)
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "api key not found"))
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// auditEntityTypes lists the entity types the audit log can be filtered by
+var auditEntityTypes = map[model.AuditEntityType]bool{
+	model.AuditEntityProduct:      true,
+	model.AuditEntityCampaign:     true,
+	model.AuditEntityLink:         true,
+	model.AuditEntityPriceRefresh: true,
+}
+
+// AuditService records admin mutations in the audit log and lists them
+// A nil *AuditService records nothing, for callers that don't audit (e.g. tests)
+type AuditService struct {
+	auditRepo AuditEventRepositoryInterface
+	logger    logger.Logger
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(auditRepo AuditEventRepositoryInterface, log logger.Logger) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+		logger:    log,
+	}
+}
+
+// Record stores an audit event for a change made by the principal in ctx
+// before and after are snapshots of the entity (typically its response DTO);
+// pass nil before for creates and nil after for deletes. Updates keep only the
+// fields that changed, and updates that changed nothing are not recorded.
+// The change has already happened, so a failure is logged rather than returned.
+func (s *AuditService) Record(ctx context.Context, action model.AuditAction, entityType model.AuditEntityType, entityID uuid.UUID, before, after interface{}) {
+	if s == nil {
+		return
+	}
+
+	beforeFields, afterFields, err := auditDiff(before, after)
+	if err != nil {
+		s.logger.Error("Failed to diff audit snapshots", logger.Error(err), logger.String("entity_type", string(entityType)), logger.String("entity_id", entityID.String()))
+		return
+	}
+	if action == model.AuditActionUpdate && len(beforeFields) == 0 && len(afterFields) == 0 {
+		return
+	}
+
+	event := &model.AuditEvent{
+		Actor:      model.AuditActorSystem,
+		Action:     action,
+		EntityType: entityType,
+		Before:     beforeFields,
+		After:      afterFields,
+	}
+	if principal := auth.PrincipalFrom(ctx); principal != nil {
+		event.Actor = principal.Subject
+		event.ActorName = principal.Name
+	}
+	if entityID != uuid.Nil {
+		event.EntityID = &entityID
+	}
+
+	// The request may be cancelled once the response is written; the event must still land
+	if err := s.auditRepo.Create(context.WithoutCancel(ctx), event); err != nil {
+		s.logger.Error("Failed to record audit event", logger.Error(err), logger.String("action", string(action)), logger.String("entity_type", string(entityType)), logger.String("entity_id", entityID.String()))
+	}
+}
+
+// GetAuditEvents lists audit events, newest first
+func (s *AuditService) GetAuditEvents(ctx context.Context, params dto.AuditQueryParams, limit, offset int) ([]*dto.AuditEventResponse, error) {
+	filter := model.AuditEventFilter{
+		EntityType: model.AuditEntityType(params.Entity),
+		EntityID:   params.EntityID,
+		Actor:      params.Actor,
+	}
+	if filter.EntityType != "" && !auditEntityTypes[filter.EntityType] {
+		return nil, fmt.Errorf("invalid entity: must be product, campaign, link or price_refresh")
+	}
+	if params.From != nil {
+		filter.From = *params.From
+	}
+	if params.To != nil {
+		filter.To = *params.To
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, fmt.Errorf("invalid date range: to must be after from")
+	}
+
+	events, _, err := s.auditRepo.FindAll(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit events: %w", err)
+	}
+
+	responses := make([]*dto.AuditEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = &dto.AuditEventResponse{
+			ID:         event.ID,
+			Actor:      event.Actor,
+			ActorName:  event.ActorName,
+			Action:     string(event.Action),
+			EntityType: string(event.EntityType),
+			EntityID:   event.EntityID,
+			Before:     event.Before,
+			After:      event.After,
+			CreatedAt:  event.CreatedAt,
+		}
+	}
+	return responses, nil
+}
+
+// auditDiff converts entity snapshots to JSON objects
+// When both are given only the top-level fields that differ are kept
+func auditDiff(before, after interface{}) (map[string]interface{}, map[string]interface{}, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+	if beforeFields == nil || afterFields == nil {
+		return beforeFields, afterFields, nil
+	}
+
+	changedBefore := make(map[string]interface{})
+	changedAfter := make(map[string]interface{})
+	for key, value := range beforeFields {
+		if !reflect.DeepEqual(value, afterFields[key]) {
+			changedBefore[key] = value
+		}
+	}
+	for key, value := range afterFields {
+		if !reflect.DeepEqual(value, beforeFields[key]) {
+			changedAfter[key] = value
+		}
+	}
+	return changedBefore, changedAfter, nil
+}
+
+// auditFields converts a snapshot to a JSON object, or nil for a nil snapshot
+func auditFields(snapshot interface{}) (map[string]interface{}, error) {
+	if snapshot == nil || reflect.ValueOf(snapshot).Kind() == reflect.Ptr && reflect.ValueOf(snapshot).IsNil() {
+		return nil, nil
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
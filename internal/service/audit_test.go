@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// MockAuditEventRepository is a mock implementation of AuditEventRepositoryInterface
+type MockAuditEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditEventRepository) Create(ctx context.Context, event *model.AuditEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockAuditEventRepository) FindAll(ctx context.Context, filter model.AuditEventFilter, limit, offset int) ([]*model.AuditEvent, int64, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.AuditEvent), args.Get(1).(int64), args.Error(2)
+}
+
+func newTestAuditService(t *testing.T) (*AuditService, *MockAuditEventRepository) {
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	repo := new(MockAuditEventRepository)
+	return NewAuditService(repo, log), repo
+}
+
+func TestAuditService_Record(t *testing.T) {
+	principal := &auth.Principal{Subject: "user:42", Name: "me@example.com"}
+	ctx := auth.WithPrincipal(context.Background(), principal)
+	campaignID := uuid.New()
+	before := &dto.CampaignResponse{ID: campaignID, Name: "Summer", UTMCampaign: "summer", ProductIDs: []uuid.UUID{uuid.New()}}
+
+	t.Run("updates keep only changed fields", func(t *testing.T) {
+		svc, repo := newTestAuditService(t)
+		var event *model.AuditEvent
+		repo.On("Create", mock.Anything, mock.AnythingOfType("*model.AuditEvent")).
+			Run(func(args mock.Arguments) { event = args.Get(1).(*model.AuditEvent) }).
+			Return(nil)
+
+		after := *before
+		after.Name = "Summer Sale"
+		after.ProductIDs = nil
+		svc.Record(ctx, model.AuditActionUpdate, model.AuditEntityCampaign, campaignID, before, &after)
+
+		require.NotNil(t, event)
+		assert.Equal(t, "user:42", event.Actor)
+		assert.Equal(t, "me@example.com", event.ActorName)
+		assert.Equal(t, model.AuditActionUpdate, event.Action)
+		assert.Equal(t, model.AuditEntityCampaign, event.EntityType)
+		require.NotNil(t, event.EntityID)
+		assert.Equal(t, campaignID, *event.EntityID)
+		assert.Equal(t, map[string]interface{}{
+			"name":        "Summer",
+			"product_ids": []interface{}{before.ProductIDs[0].String()},
+		}, event.Before)
+		assert.Equal(t, map[string]interface{}{"name": "Summer Sale"}, event.After)
+	})
+
+	t.Run("updates that change nothing are not recorded", func(t *testing.T) {
+		svc, repo := newTestAuditService(t)
+
+		same := *before
+		svc.Record(ctx, model.AuditActionUpdate, model.AuditEntityCampaign, campaignID, before, &same)
+
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("deletes keep the whole entity", func(t *testing.T) {
+		svc, repo := newTestAuditService(t)
+		var event *model.AuditEvent
+		repo.On("Create", mock.Anything, mock.AnythingOfType("*model.AuditEvent")).
+			Run(func(args mock.Arguments) { event = args.Get(1).(*model.AuditEvent) }).
+			Return(nil)
+
+		svc.Record(ctx, model.AuditActionDelete, model.AuditEntityCampaign, campaignID, before, nil)
+
+		require.NotNil(t, event)
+		assert.Equal(t, "Summer", event.Before["name"])
+		assert.Equal(t, "summer", event.Before["utm_campaign"])
+		assert.Nil(t, event.After)
+	})
+
+	t.Run("worker triggers without a caller or entity", func(t *testing.T) {
+		svc, repo := newTestAuditService(t)
+		var event *model.AuditEvent
+		repo.On("Create", mock.Anything, mock.AnythingOfType("*model.AuditEvent")).
+			Run(func(args mock.Arguments) { event = args.Get(1).(*model.AuditEvent) }).
+			Return(nil)
+
+		svc.Record(context.Background(), model.AuditActionTrigger, model.AuditEntityPriceRefresh, uuid.Nil, nil, nil)
+
+		require.NotNil(t, event)
+		assert.Equal(t, model.AuditActorSystem, event.Actor)
+		assert.Nil(t, event.EntityID)
+	})
+
+	t.Run("a nil service records nothing", func(t *testing.T) {
+		var svc *AuditService
+		assert.NotPanics(t, func() {
+			svc.Record(ctx, model.AuditActionDelete, model.AuditEntityCampaign, campaignID, before, nil)
+		})
+	})
+}
+
+func TestAuditService_GetAuditEvents(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("filters", func(t *testing.T) {
+		svc, repo := newTestAuditService(t)
+		entityID := uuid.New()
+		repo.On("FindAll", ctx, model.AuditEventFilter{
+			EntityType: model.AuditEntityLink,
+			EntityID:   &entityID,
+			Actor:      "me@example.com",
+			From:       from,
+		}, 20, 40).Return([]*model.AuditEvent{{Actor: "user:42", Action: model.AuditActionCreate, EntityType: model.AuditEntityLink, EntityID: &entityID}}, int64(41), nil)
+
+		events, err := svc.GetAuditEvents(ctx, dto.AuditQueryParams{Entity: "link", EntityID: &entityID, Actor: "me@example.com", From: &from}, 20, 40)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "create", events[0].Action)
+		assert.Equal(t, "link", events[0].EntityType)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("invalid entity", func(t *testing.T) {
+		svc, _ := newTestAuditService(t)
+		_, err := svc.GetAuditEvents(ctx, dto.AuditQueryParams{Entity: "user"}, 100, 0)
+		assert.ErrorContains(t, err, "invalid entity")
+	})
+
+	t.Run("invalid date range", func(t *testing.T) {
+		svc, _ := newTestAuditService(t)
+		to := from.Add(-time.Hour)
+		_, err := svc.GetAuditEvents(ctx, dto.AuditQueryParams{From: &from, To: &to}, 100, 0)
+		assert.ErrorContains(t, err, "invalid date range")
+	})
+}
+
+func TestCampaignService_DeleteCampaign_Audit(t *testing.T) {
+	ctx := context.Background()
+	campaignID := uuid.New()
+	productID := uuid.New()
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+
+	audit, auditRepo := newTestAuditService(t)
+	campaignRepo := new(MockCampaignRepository)
+	svc := NewCampaignService(campaignRepo, nil, nil, nil, nil, audit, nil, log)
+
+	campaignRepo.On("FindByID", ctx, campaignID).Return(&model.Campaign{
+		ID:               campaignID,
+		Name:             "Summer",
+		CampaignProducts: []model.CampaignProduct{{CampaignID: campaignID, ProductID: productID}},
+	}, nil)
+	campaignRepo.On("Delete", ctx, campaignID).Return(nil)
+	var event *model.AuditEvent
+	auditRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.AuditEvent")).
+		Run(func(args mock.Arguments) { event = args.Get(1).(*model.AuditEvent) }).
+		Return(nil)
+
+	require.NoError(t, svc.DeleteCampaign(ctx, campaignID))
+
+	require.NotNil(t, event)
+	assert.Equal(t, model.AuditActionDelete, event.Action)
+	assert.Equal(t, "Summer", event.Before["name"])
+	assert.Equal(t, []interface{}{productID.String()}, event.Before["product_ids"])
+	campaignRepo.AssertExpectations(t)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// bestOffer returns the cheapest of a product's offers (the first one on ties),
+// or nil when there are none
+func bestOffer(offers []*model.Offer) *model.Offer {
+	if len(offers) == 0 {
+		return nil
+	}
+	best := offers[0]
+	for _, offer := range offers[1:] {
+		if offer.Price < best.Price {
+			best = offer
+		}
+	}
+	return best
+}
+
+// toBestPrice converts the cheapest offer to DTO (nil without offers)
+func toBestPrice(offers []*model.Offer) *dto.BestPrice {
+	best := bestOffer(offers)
+	if best == nil {
+		return nil
+	}
+	return &dto.BestPrice{
+		Marketplace: string(best.Marketplace),
+		Price:       best.Price,
+	}
+}
+
+// campaignLinkTarget is the offer a campaign link points at
+type campaignLinkTarget struct {
+	marketplace model.Marketplace // The link's marketplace (best_price for best-price links)
+	offer       *model.Offer
+}
+
+// campaignLinkTargets lists the links a campaign keeps for a product: one per
+// offer, plus a best-price link pointing at the cheapest offer when the
+// campaign enables it
+func campaignLinkTargets(campaign *model.Campaign, offers []*model.Offer) []campaignLinkTarget {
+	targets := make([]campaignLinkTarget, 0, len(offers)+1)
+	for _, offer := range offers {
+		targets = append(targets, campaignLinkTarget{marketplace: offer.Marketplace, offer: offer})
+	}
+	if best := bestOffer(offers); best != nil && campaign.BestPriceLink {
+		targets = append(targets, campaignLinkTarget{marketplace: model.MarketplaceBestPrice, offer: best})
+	}
+	return targets
+}
+
+// targetURL builds the link's target URL for the offer
+// Best-price links store the cheapest offer's URL as of the last sync; redirects
+// resolve the current cheapest offer.
+func (t campaignLinkTarget) targetURL(campaign *model.Campaign, link *model.Link) (string, error) {
+	return linkTargetURLFor(t.offer.MarketplaceProductURL, t.offer.Marketplace, campaign, link)
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+func TestBestOffer(t *testing.T) {
+	lazada := &model.Offer{Marketplace: model.MarketplaceLazada, Price: 299}
+	shopee := &model.Offer{Marketplace: model.MarketplaceShopee, Price: 259}
+	shopeeAgain := &model.Offer{Marketplace: model.MarketplaceShopee, Price: 259}
+
+	assert.Same(t, shopee, bestOffer([]*model.Offer{lazada, shopee}))
+	assert.Same(t, shopee, bestOffer([]*model.Offer{lazada, shopee, shopeeAgain}), "ties keep the first offer")
+	assert.Nil(t, bestOffer(nil))
+
+	bestPrice := toBestPrice([]*model.Offer{lazada, shopee})
+	require.NotNil(t, bestPrice)
+	assert.Equal(t, "shopee", bestPrice.Marketplace)
+	assert.Equal(t, 259.0, bestPrice.Price)
+	assert.Nil(t, toBestPrice(nil))
+}
+
+func TestCampaignLinkTargets(t *testing.T) {
+	lazada := &model.Offer{Marketplace: model.MarketplaceLazada, Price: 299, MarketplaceProductURL: "https://www.lazada.co.th/products/matcha-i1.html"}
+	shopee := &model.Offer{Marketplace: model.MarketplaceShopee, Price: 259, MarketplaceProductURL: "https://shopee.co.th/product/1/2"}
+	offers := []*model.Offer{lazada, shopee}
+
+	targets := campaignLinkTargets(&model.Campaign{}, offers)
+	require.Len(t, targets, 2)
+	assert.Equal(t, model.MarketplaceLazada, targets[0].marketplace)
+	assert.Equal(t, model.MarketplaceShopee, targets[1].marketplace)
+
+	campaign := &model.Campaign{BestPriceLink: true, UTMCampaign: "summer", UTMSource: "{{marketplace}}"}
+	targets = campaignLinkTargets(campaign, offers)
+	require.Len(t, targets, 3)
+	assert.Equal(t, model.MarketplaceBestPrice, targets[2].marketplace)
+	assert.Same(t, shopee, targets[2].offer)
+
+	targetURL, err := targets[2].targetURL(campaign, &model.Link{Marketplace: model.MarketplaceBestPrice})
+	require.NoError(t, err)
+	assert.Equal(t, "https://shopee.co.th/product/1/2?utm_campaign=summer&utm_source=shopee", targetURL)
+
+	assert.Empty(t, campaignLinkTargets(campaign, nil), "products without offers get no best-price link")
+}
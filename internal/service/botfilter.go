@@ -0,0 +1,186 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+)
+
+// Bot classification reasons stored on clicks
+const (
+	BotReasonHeadRequest = "head_request"
+	BotReasonPrefetch    = "prefetch"
+	BotReasonIPRange     = "ip_range"
+	BotReasonEmptyUA     = "empty_user_agent"
+	// User agent matches are stored as "ua:<name>", e.g. "ua:facebook"
+	botReasonUserAgentPrefix = "ua:"
+)
+
+// botUserAgentPatterns lists known non-human user agents, matched case-insensitively
+// as substrings and checked in order. Keep specific entries above generic ones.
+var botUserAgentPatterns = []struct {
+	name    string
+	pattern string
+}{
+	// Link preview fetchers
+	{"facebook", "facebookexternalhit"},
+	{"facebook", "facebookcatalog"},
+	{"facebook", "facebot"},
+	{"facebook", "meta-externalagent"},
+	{"line", "line-poker"},
+	{"slack", "slackbot"},
+	{"slack", "slack-imgproxy"},
+	{"twitter", "twitterbot"},
+	{"discord", "discordbot"},
+	{"telegram", "telegrambot"},
+	{"whatsapp", "whatsapp/"},
+	{"linkedin", "linkedinbot"},
+	{"pinterest", "pinterestbot"},
+	{"skype", "skypeuripreview"},
+	{"reddit", "redditbot"},
+	{"iframely", "iframely"},
+	{"embedly", "embedly"},
+
+	// Uptime checkers and synthetic monitoring
+	{"uptime_monitor", "uptimerobot"},
+	{"uptime_monitor", "pingdom"},
+	{"uptime_monitor", "statuscake"},
+	{"uptime_monitor", "site24x7"},
+	{"uptime_monitor", "better uptime"},
+	{"uptime_monitor", "datadog/synthetics"},
+	{"uptime_monitor", "newrelicpinger"},
+	{"uptime_monitor", "googlestackdrivermonitoring"},
+
+	// Headless browsers and HTTP libraries
+	{"headless", "headlesschrome"},
+	{"headless", "phantomjs"},
+	{"headless", "puppeteer"},
+	{"headless", "playwright"},
+	{"http_client", "curl/"},
+	{"http_client", "wget/"},
+	{"http_client", "python-requests"},
+	{"http_client", "python-urllib"},
+	{"http_client", "go-http-client"},
+	{"http_client", "node-fetch"},
+	{"http_client", "axios/"},
+	{"http_client", "libwww-perl"},
+	{"http_client", "apache-httpclient"},
+
+	// Search engines and SEO crawlers
+	{"search_engine", "googlebot"},
+	{"search_engine", "bingbot"},
+	{"search_engine", "yandexbot"},
+	{"search_engine", "baiduspider"},
+	{"search_engine", "duckduckbot"},
+	{"search_engine", "applebot"},
+	{"seo_crawler", "ahrefsbot"},
+	{"seo_crawler", "semrushbot"},
+	{"seo_crawler", "mj12bot"},
+	{"seo_crawler", "petalbot"},
+	{"seo_crawler", "bytespider"},
+}
+
+// genericBotPattern catches self-declared bots not in the list above.
+// "bot" must be followed by a separator so phone models such as "CUBOT X19" don't match.
+var genericBotPattern = regexp.MustCompile(`(?i)bot[/;)]|crawler|spider|\+https?://`)
+
+// BotFilter classifies clicks as bot traffic by user agent, request method,
+// prefetch headers and excluded IP ranges
+type BotFilter struct {
+	excludedNets []*net.IPNet
+	extraUAs     []string
+}
+
+// NewBotFilter creates a bot filter
+// excludedIPRanges are CIDRs (or single IPs) whose clicks are always flagged;
+// extraUserAgentPatterns are additional case-insensitive user agent substrings
+func NewBotFilter(excludedIPRanges, extraUserAgentPatterns []string) (*BotFilter, error) {
+	filter := &BotFilter{}
+
+	for _, r := range excludedIPRanges {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		if !strings.Contains(r, "/") {
+			if ip := net.ParseIP(r); ip != nil && ip.To4() != nil {
+				r += "/32"
+			} else {
+				r += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid excluded IP range %q: %w", r, err)
+		}
+		filter.excludedNets = append(filter.excludedNets, ipNet)
+	}
+
+	for _, p := range extraUserAgentPatterns {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			filter.extraUAs = append(filter.extraUAs, p)
+		}
+	}
+
+	return filter, nil
+}
+
+// Classify reports whether a click is bot traffic and why
+func (f *BotFilter) Classify(meta dto.ClickMetadata) (bool, string) {
+	if strings.EqualFold(meta.Method, http.MethodHead) {
+		return true, BotReasonHeadRequest
+	}
+
+	if isPrefetch(meta.Purpose) {
+		return true, BotReasonPrefetch
+	}
+
+	if meta.IPAddress != nil {
+		for _, ipNet := range f.excludedNets {
+			if ipNet.Contains(meta.IPAddress) {
+				return true, BotReasonIPRange
+			}
+		}
+	}
+
+	if strings.TrimSpace(meta.UserAgent) == "" {
+		return true, BotReasonEmptyUA
+	}
+
+	lower := strings.ToLower(meta.UserAgent)
+	for _, p := range f.extraUAs {
+		if strings.Contains(lower, p) {
+			return true, botReasonUserAgentPrefix + "custom"
+		}
+	}
+	if name := matchBotUserAgent(meta.UserAgent); name != "" {
+		return true, botReasonUserAgentPrefix + name
+	}
+
+	return false, ""
+}
+
+// matchBotUserAgent returns the name of the matching bot pattern, or "" for non-bot user agents
+func matchBotUserAgent(userAgent string) string {
+	lower := strings.ToLower(userAgent)
+	for _, p := range botUserAgentPatterns {
+		if strings.Contains(lower, p.pattern) {
+			return p.name
+		}
+	}
+	if genericBotPattern.MatchString(userAgent) {
+		return "generic"
+	}
+	return ""
+}
+
+// isPrefetch reports whether a Purpose / Sec-Purpose header marks a speculative request
+// e.g. "prefetch", "prefetch;prerender", "preview"
+func isPrefetch(purpose string) bool {
+	purpose = strings.ToLower(purpose)
+	return strings.Contains(purpose, "prefetch") || strings.Contains(purpose, "prerender") || strings.Contains(purpose, "preview")
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+const chromeAndroidUA = "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36"
+
+func TestBotFilter_Classify(t *testing.T) {
+	filter, err := NewBotFilter([]string{"10.20.0.0/16", "203.0.113.7"}, []string{"InternalChecker"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		meta       dto.ClickMetadata
+		wantBot    bool
+		wantReason string
+	}{
+		{
+			name:    "human browser",
+			meta:    dto.ClickMetadata{Method: "GET", UserAgent: chromeAndroidUA, IPAddress: net.ParseIP("198.51.100.1")},
+			wantBot: false,
+		},
+		{
+			name:    "phone model containing bot",
+			meta:    dto.ClickMetadata{Method: "GET", UserAgent: "Mozilla/5.0 (Linux; Android 10; CUBOT X19) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36"},
+			wantBot: false,
+		},
+		{
+			name:       "head request",
+			meta:       dto.ClickMetadata{Method: "HEAD", UserAgent: chromeAndroidUA},
+			wantBot:    true,
+			wantReason: BotReasonHeadRequest,
+		},
+		{
+			name:       "browser prefetch",
+			meta:       dto.ClickMetadata{Method: "GET", UserAgent: chromeAndroidUA, Purpose: "prefetch;prerender"},
+			wantBot:    true,
+			wantReason: BotReasonPrefetch,
+		},
+		{
+			name:       "excluded range",
+			meta:       dto.ClickMetadata{Method: "GET", UserAgent: chromeAndroidUA, IPAddress: net.ParseIP("10.20.3.4")},
+			wantBot:    true,
+			wantReason: BotReasonIPRange,
+		},
+		{
+			name:       "excluded single ip",
+			meta:       dto.ClickMetadata{Method: "GET", UserAgent: chromeAndroidUA, IPAddress: net.ParseIP("203.0.113.7")},
+			wantBot:    true,
+			wantReason: BotReasonIPRange,
+		},
+		{
+			name:       "empty user agent",
+			meta:       dto.ClickMetadata{Method: "GET"},
+			wantBot:    true,
+			wantReason: BotReasonEmptyUA,
+		},
+		{
+			name:       "facebook link preview",
+			meta:       dto.ClickMetadata{Method: "GET", UserAgent: "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)"},
+			wantBot:    true,
+			wantReason: "ua:facebook",
+		},
+		{
+			name:       "line link preview",
+			meta:       dto.ClickMetadata{Method: "GET", UserAgent: "Mozilla/5.0 (compatible; Line-Poker/1.0)"},
+			wantBot:    true,
+			wantReason: "ua:line",
+		},
+		{
+			name:       "slack unfurl",
+			meta:       dto.ClickMetadata{Method: "GET", UserAgent: "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)"},
+			wantBot:    true,
+			wantReason: "ua:slack",
+		},
+		{
+			name:       "uptime checker",
+			meta:       dto.ClickMetadata{Method: "GET", UserAgent: "Mozilla/5.0+(compatible; UptimeRobot/2.0; http://www.uptimerobot.com/)"},
+			wantBot:    true,
+			wantReason: "ua:uptime_monitor",
+		},
+		{
+			name:       "custom pattern",
+			meta:       dto.ClickMetadata{Method: "GET", UserAgent: "internalchecker/2.3"},
+			wantBot:    true,
+			wantReason: "ua:custom",
+		},
+		{
+			name:       "generic crawler",
+			meta:       dto.ClickMetadata{Method: "GET", UserAgent: "Mozilla/5.0 (compatible; ExampleBot/1.0; +https://example.com/bot)"},
+			wantBot:    true,
+			wantReason: "ua:generic",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isBot, reason := filter.Classify(tt.meta)
+			assert.Equal(t, tt.wantBot, isBot)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func TestNewBotFilter_InvalidRange(t *testing.T) {
+	_, err := NewBotFilter([]string{"10.0.0.0/99"}, nil)
+	assert.ErrorContains(t, err, "invalid excluded IP range")
+}
+
+func TestClickService_TrackClick_FlagsBots(t *testing.T) {
+	ctx := context.Background()
+	linkID := uuid.New()
+
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	filter, err := NewBotFilter(nil, nil)
+	require.NoError(t, err)
+
+	clickRepo := new(MockClickRepository)
+	svc := NewClickService(clickRepo, new(MockLinkRepository), nil, filter, nil, log)
+
+	clickRepo.On("Create", ctx, mock.MatchedBy(func(c *model.Click) bool {
+		return c.LinkID == linkID && c.IsBot && c.BotReason == "ua:facebook"
+	})).Return(nil).Once()
+	clickRepo.On("Create", ctx, mock.MatchedBy(func(c *model.Click) bool {
+		return c.LinkID == linkID && !c.IsBot && c.BotReason == ""
+	})).Return(nil).Once()
+
+	require.NoError(t, svc.TrackClick(ctx, linkID, dto.ClickMetadata{Method: "GET", UserAgent: "facebookexternalhit/1.1"}))
+	require.NoError(t, svc.TrackClick(ctx, linkID, dto.ClickMetadata{Method: "GET", UserAgent: chromeAndroidUA}))
+	clickRepo.AssertExpectations(t)
+}
@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/jonosize/affiliate-platform/internal/config"
 	"github.com/jonosize/affiliate-platform/internal/dto"
@@ -18,6 +21,8 @@ type CampaignService struct {
 	linkRepo     LinkRepositoryInterface
 	offerRepo    OfferRepositoryInterface
 	productRepo  ProductRepositoryInterface
+	linkCache    LinkCache
+	audit        *AuditService
 	logger       logger.Logger
 	cfg          config.Config
 }
@@ -28,6 +33,8 @@ func NewCampaignService(
 	linkRepo LinkRepositoryInterface,
 	offerRepo OfferRepositoryInterface,
 	productRepo ProductRepositoryInterface,
+	linkCache LinkCache,
+	audit *AuditService,
 	cfg config.Config,
 	log logger.Logger,
 ) *CampaignService {
@@ -36,6 +43,8 @@ func NewCampaignService(
 		linkRepo:     linkRepo,
 		offerRepo:    offerRepo,
 		productRepo:  productRepo,
+		linkCache:    linkCache,
+		audit:        audit,
 		logger:       log,
 		cfg:          cfg,
 	}
@@ -52,13 +61,50 @@ func (s *CampaignService) CreateCampaign(ctx context.Context, req dto.CreateCamp
 	if len(req.UTMCampaign) > 100 {
 		return nil, fmt.Errorf("utm_campaign must be 100 characters or less")
 	}
+	if err := validateUTMFields(map[string]*string{
+		"utm_campaign": &req.UTMCampaign,
+		"utm_source":   req.UTMSource,
+		"utm_medium":   req.UTMMedium,
+		"utm_content":  &req.UTMContent,
+		"utm_term":     &req.UTMTerm,
+	}); err != nil {
+		return nil, err
+	}
+
+	policy, err := parseOutOfWindowPolicy(req.OutOfWindowPolicy)
+	if err != nil {
+		return nil, err
+	}
+	fallbackURL := strings.TrimSpace(req.FallbackURL)
+	if err := validateCampaignPolicy(policy, fallbackURL); err != nil {
+		return nil, err
+	}
 
 	// Create campaign
 	campaign := &model.Campaign{
-		Name:        req.Name,
-		UTMCampaign: req.UTMCampaign,
-		StartAt:     req.StartAt,
-		EndAt:       req.EndAt,
+		Name:              req.Name,
+		UTMCampaign:       req.UTMCampaign,
+		UTMSource:         defaultUTMSource,
+		UTMMedium:         defaultUTMMedium,
+		UTMContent:        req.UTMContent,
+		UTMTerm:           req.UTMTerm,
+		StartAt:           req.StartAt,
+		EndAt:             req.EndAt,
+		OutOfWindowPolicy: policy,
+		FallbackURL:       fallbackURL,
+		BestPriceLink:     req.BestPriceLink,
+	}
+	if req.UTMSource != nil {
+		campaign.UTMSource = *req.UTMSource
+	}
+	if req.UTMMedium != nil {
+		campaign.UTMMedium = *req.UTMMedium
+	}
+
+	// Validate vanity short codes before creating anything
+	shortCodes, err := s.prepareShortCodes(ctx, uuid.Nil, req.ShortCodes)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := s.campaignRepo.Create(ctx, campaign); err != nil {
@@ -76,7 +122,7 @@ func (s *CampaignService) CreateCampaign(ctx context.Context, req dto.CreateCamp
 			return nil, fmt.Errorf("failed to add products to campaign: %w", err)
 		}
 		// Automatically create links for products
-		if err := s.createLinksForProducts(ctx, campaign.ID, req.ProductIDs); err != nil {
+		if err := s.createLinksForProducts(ctx, campaign.ID, req.ProductIDs, shortCodes); err != nil {
 			s.logger.Warn("Failed to create links for products", logger.Error(err), logger.String("campaign_id", campaign.ID.String()))
 			// Don't fail campaign creation if link creation fails
 		}
@@ -84,13 +130,22 @@ func (s *CampaignService) CreateCampaign(ctx context.Context, req dto.CreateCamp
 
 	// Convert to response
 	response := &dto.CampaignResponse{
-		ID:          campaign.ID,
-		Name:        campaign.Name,
-		UTMCampaign: campaign.UTMCampaign,
-		StartAt:     campaign.StartAt,
-		EndAt:       campaign.EndAt,
-		CreatedAt:   campaign.CreatedAt,
-	}
+		ID:                campaign.ID,
+		Name:              campaign.Name,
+		UTMCampaign:       campaign.UTMCampaign,
+		UTMSource:         campaign.UTMSource,
+		UTMMedium:         campaign.UTMMedium,
+		UTMContent:        campaign.UTMContent,
+		UTMTerm:           campaign.UTMTerm,
+		StartAt:           campaign.StartAt,
+		EndAt:             campaign.EndAt,
+		CreatedAt:         campaign.CreatedAt,
+		OutOfWindowPolicy: string(campaign.OutOfWindowPolicy),
+		FallbackURL:       campaign.FallbackURL,
+		BestPriceLink:     campaign.BestPriceLink,
+	}
+
+	s.audit.Record(ctx, model.AuditActionCreate, model.AuditEntityCampaign, campaign.ID, nil, response)
 
 	return response, nil
 }
@@ -107,6 +162,11 @@ func (s *CampaignService) GetCampaignResponse(ctx context.Context, id uuid.UUID)
 		return nil, fmt.Errorf("campaign not found: %w", err)
 	}
 
+	return toCampaignResponse(campaign), nil
+}
+
+// toCampaignResponse converts a campaign model and its loaded products to DTO
+func toCampaignResponse(campaign *model.Campaign) *dto.CampaignResponse {
 	// Extract product IDs from campaign products
 	productIDs := make([]uuid.UUID, 0, len(campaign.CampaignProducts))
 	for _, cp := range campaign.CampaignProducts {
@@ -115,17 +175,22 @@ func (s *CampaignService) GetCampaignResponse(ctx context.Context, id uuid.UUID)
 		}
 	}
 
-	response := &dto.CampaignResponse{
-		ID:          campaign.ID,
-		Name:        campaign.Name,
-		UTMCampaign: campaign.UTMCampaign,
-		StartAt:     campaign.StartAt,
-		EndAt:       campaign.EndAt,
-		CreatedAt:   campaign.CreatedAt,
-		ProductIDs:  productIDs,
+	return &dto.CampaignResponse{
+		ID:                campaign.ID,
+		Name:              campaign.Name,
+		UTMCampaign:       campaign.UTMCampaign,
+		UTMSource:         campaign.UTMSource,
+		UTMMedium:         campaign.UTMMedium,
+		UTMContent:        campaign.UTMContent,
+		UTMTerm:           campaign.UTMTerm,
+		StartAt:           campaign.StartAt,
+		EndAt:             campaign.EndAt,
+		CreatedAt:         campaign.CreatedAt,
+		OutOfWindowPolicy: string(campaign.OutOfWindowPolicy),
+		FallbackURL:       campaign.FallbackURL,
+		BestPriceLink:     campaign.BestPriceLink,
+		ProductIDs:        productIDs,
 	}
-
-	return response, nil
 }
 
 // GetAllCampaigns gets all campaigns with pagination
@@ -140,34 +205,96 @@ func (s *CampaignService) GetAllCampaigns(ctx context.Context, limit, offset int
 	responses := make([]*dto.CampaignResponse, len(campaigns))
 	for i, campaign := range campaigns {
 		responses[i] = &dto.CampaignResponse{
-			ID:          campaign.ID,
-			Name:        campaign.Name,
-			UTMCampaign: campaign.UTMCampaign,
-			StartAt:     campaign.StartAt,
-			EndAt:       campaign.EndAt,
-			CreatedAt:   campaign.CreatedAt,
+			ID:                campaign.ID,
+			Name:              campaign.Name,
+			UTMCampaign:       campaign.UTMCampaign,
+			UTMSource:         campaign.UTMSource,
+			UTMMedium:         campaign.UTMMedium,
+			UTMContent:        campaign.UTMContent,
+			UTMTerm:           campaign.UTMTerm,
+			StartAt:           campaign.StartAt,
+			EndAt:             campaign.EndAt,
+			CreatedAt:         campaign.CreatedAt,
+			OutOfWindowPolicy: string(campaign.OutOfWindowPolicy),
+			FallbackURL:       campaign.FallbackURL,
+			BestPriceLink:     campaign.BestPriceLink,
 		}
 	}
 
 	return responses, nil
 }
 
-// DeleteCampaign deletes a campaign and all related data
-// CASCADE constraints will automatically delete:
-// - CampaignProducts (ON DELETE CASCADE)
-// - Links (ON DELETE CASCADE)
-// - Clicks (via links, ON DELETE CASCADE)
+// DeleteCampaign soft-deletes a campaign
+// The campaign, its public page and its links are hidden until it is restored;
+// its products, links and clicks are kept, so dashboard history still counts
+// them. PurgeCampaign removes it for good.
 func (s *CampaignService) DeleteCampaign(ctx context.Context, campaignID uuid.UUID) error {
 	// Check if campaign exists
-	_, err := s.campaignRepo.FindByID(ctx, campaignID)
+	campaign, err := s.campaignRepo.FindByID(ctx, campaignID)
 	if err != nil {
 		return fmt.Errorf("campaign not found: %w", err)
 	}
 
-	// Delete campaign (CASCADE will handle related data)
+	// Soft-delete campaign (related data is kept)
 	if err := s.campaignRepo.Delete(ctx, campaignID); err != nil {
 		return fmt.Errorf("failed to delete campaign: %w", err)
 	}
+	s.invalidateLinks(ctx, campaignID)
+
+	s.audit.Record(ctx, model.AuditActionDelete, model.AuditEntityCampaign, campaignID, toCampaignResponse(campaign), nil)
+
+	return nil
+}
+
+// RestoreCampaign undoes a campaign's soft deletion, bringing back its links
+func (s *CampaignService) RestoreCampaign(ctx context.Context, campaignID uuid.UUID) (*dto.CampaignResponse, error) {
+	if _, err := s.campaignRepo.FindDeletedByID(ctx, campaignID); err != nil {
+		return nil, fmt.Errorf("deleted campaign not found: %w", err)
+	}
+
+	if err := s.campaignRepo.Restore(ctx, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to restore campaign: %w", err)
+	}
+	s.invalidateLinks(ctx, campaignID)
+
+	// Reload to leave out products deleted in the meantime
+	campaign, err := s.campaignRepo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restored campaign: %w", err)
+	}
+
+	response := toCampaignResponse(campaign)
+	s.audit.Record(ctx, model.AuditActionRestore, model.AuditEntityCampaign, campaignID, nil, response)
+
+	return response, nil
+}
+
+// invalidateLinks drops the cached short codes of a campaign's links after it
+// is edited, or deleted or restored (which hides or brings back its links)
+func (s *CampaignService) invalidateLinks(ctx context.Context, campaignID uuid.UUID) {
+	if s.linkCache != nil {
+		s.linkCache.InvalidateCampaignLinks(ctx, campaignID)
+	}
+}
+
+// PurgeCampaign permanently deletes a soft-deleted campaign
+// confirm must repeat the campaign ID. CASCADE constraints delete its product
+// associations, links and their clicks, which drops them from dashboard history.
+func (s *CampaignService) PurgeCampaign(ctx context.Context, campaignID uuid.UUID, confirm string) error {
+	if confirmID, err := uuid.Parse(confirm); err != nil || confirmID != campaignID {
+		return fmt.Errorf("invalid confirmation: confirm must repeat the campaign ID")
+	}
+
+	campaign, err := s.campaignRepo.FindDeletedByID(ctx, campaignID)
+	if err != nil {
+		return fmt.Errorf("deleted campaign not found: %w", err)
+	}
+
+	if err := s.campaignRepo.Purge(ctx, campaignID); err != nil {
+		return fmt.Errorf("failed to purge campaign: %w", err)
+	}
+
+	s.audit.Record(ctx, model.AuditActionPurge, model.AuditEntityCampaign, campaignID, toCampaignResponse(campaign), nil)
 
 	return nil
 }
@@ -179,6 +306,7 @@ func (s *CampaignService) UpdateCampaign(ctx context.Context, campaignID uuid.UU
 	if err != nil {
 		return nil, fmt.Errorf("campaign not found: %w", err)
 	}
+	before := toCampaignResponse(campaign)
 
 	// Update campaign fields if provided
 	if req.Name != "" {
@@ -191,6 +319,28 @@ func (s *CampaignService) UpdateCampaign(ctx context.Context, campaignID uuid.UU
 		}
 		campaign.UTMCampaign = req.UTMCampaign
 	}
+	if err := validateUTMFields(map[string]*string{
+		"utm_campaign": &req.UTMCampaign,
+		"utm_source":   req.UTMSource,
+		"utm_medium":   req.UTMMedium,
+		"utm_content":  req.UTMContent,
+		"utm_term":     req.UTMTerm,
+	}); err != nil {
+		return nil, err
+	}
+	if req.UTMSource != nil {
+		campaign.UTMSource = *req.UTMSource
+	}
+	if req.UTMMedium != nil {
+		campaign.UTMMedium = *req.UTMMedium
+	}
+	if req.UTMContent != nil {
+		campaign.UTMContent = *req.UTMContent
+	}
+	if req.UTMTerm != nil {
+		campaign.UTMTerm = *req.UTMTerm
+	}
+	utmChanged := req.UTMCampaign != "" || req.UTMSource != nil || req.UTMMedium != nil || req.UTMContent != nil || req.UTMTerm != nil
 	if req.StartAt != nil {
 		campaign.StartAt = *req.StartAt
 	}
@@ -203,12 +353,39 @@ func (s *CampaignService) UpdateCampaign(ctx context.Context, campaignID uuid.UU
 		return nil, fmt.Errorf("end_at must be after start_at")
 	}
 
+	if req.OutOfWindowPolicy != nil {
+		policy, err := parseOutOfWindowPolicy(*req.OutOfWindowPolicy)
+		if err != nil {
+			return nil, err
+		}
+		campaign.OutOfWindowPolicy = policy
+	}
+	if req.FallbackURL != nil {
+		campaign.FallbackURL = strings.TrimSpace(*req.FallbackURL)
+	}
+	if err := validateCampaignPolicy(campaign.OutOfWindowPolicy, campaign.FallbackURL); err != nil {
+		return nil, err
+	}
+
+	bestPriceChanged := req.BestPriceLink != nil && *req.BestPriceLink != campaign.BestPriceLink
+	if req.BestPriceLink != nil {
+		campaign.BestPriceLink = *req.BestPriceLink
+	}
+
+	// Validate vanity short codes before updating anything
+	shortCodes, err := s.prepareShortCodes(ctx, campaignID, req.ShortCodes)
+	if err != nil {
+		return nil, err
+	}
+
 	// Update campaign
 	if err := s.campaignRepo.Update(ctx, campaign); err != nil {
 		return nil, fmt.Errorf("failed to update campaign: %w", err)
 	}
+	// Cached links carry the campaign's window, policy and UTM settings
+	s.invalidateLinks(ctx, campaignID)
 
-	// Get current product IDs for link sync (needed if UTM campaign changes)
+	// Get current product IDs for link sync (needed if UTM settings change)
 	var productIDsToSync []uuid.UUID
 
 	// Update products if provided
@@ -219,8 +396,8 @@ func (s *CampaignService) UpdateCampaign(ctx context.Context, campaignID uuid.UU
 		}
 		productIDsToSync = req.ProductIDs
 		s.logger.Info("Campaign products updated, starting link synchronization", logger.String("campaign_id", campaignID.String()))
-	} else if req.UTMCampaign != "" {
-		// If only UTM campaign changed, get current products to update their links
+	} else if utmChanged || bestPriceChanged || len(shortCodes) > 0 {
+		// If only UTM settings, the best-price link or short codes changed, get current products to update their links
 		currentCampaign, err := s.campaignRepo.FindByID(ctx, campaignID)
 		if err == nil {
 			productIDsToSync = make([]uuid.UUID, 0, len(currentCampaign.CampaignProducts))
@@ -232,13 +409,13 @@ func (s *CampaignService) UpdateCampaign(ctx context.Context, campaignID uuid.UU
 		}
 	}
 
-	// Sync links if products changed or UTM campaign changed
-	// This ensures target URLs are updated when UTM campaign changes
+	// Sync links if products, UTM settings, the best-price link or short codes changed
+	// This ensures target URLs are updated when UTM settings change
 	// Also sync if products were set to empty list (to delete all links)
-	if req.ProductIDs != nil || req.UTMCampaign != "" {
+	if req.ProductIDs != nil || utmChanged || bestPriceChanged || len(shortCodes) > 0 {
 		// Automatically sync links for products (add new, remove unused, update URLs)
 		// This will also handle the case where productIDsToSync is empty (delete all links)
-		if err := s.createLinksForProducts(ctx, campaignID, productIDsToSync); err != nil {
+		if err := s.createLinksForProducts(ctx, campaignID, productIDsToSync, shortCodes); err != nil {
 			s.logger.Warn("Failed to sync links for products", logger.Error(err), logger.String("campaign_id", campaignID.String()))
 			// Don't fail campaign update if link creation fails
 		}
@@ -250,14 +427,23 @@ func (s *CampaignService) UpdateCampaign(ctx context.Context, campaignID uuid.UU
 		return nil, fmt.Errorf("failed to get updated campaign: %w", err)
 	}
 
+	s.audit.Record(ctx, model.AuditActionUpdate, model.AuditEntityCampaign, campaignID, before, toCampaignResponse(updatedCampaign))
+
 	// Convert to response
 	response := &dto.CampaignResponse{
-		ID:          updatedCampaign.ID,
-		Name:        updatedCampaign.Name,
-		UTMCampaign: updatedCampaign.UTMCampaign,
-		StartAt:     updatedCampaign.StartAt,
-		EndAt:       updatedCampaign.EndAt,
-		CreatedAt:   updatedCampaign.CreatedAt,
+		ID:                updatedCampaign.ID,
+		Name:              updatedCampaign.Name,
+		UTMCampaign:       updatedCampaign.UTMCampaign,
+		UTMSource:         updatedCampaign.UTMSource,
+		UTMMedium:         updatedCampaign.UTMMedium,
+		UTMContent:        updatedCampaign.UTMContent,
+		UTMTerm:           updatedCampaign.UTMTerm,
+		StartAt:           updatedCampaign.StartAt,
+		EndAt:             updatedCampaign.EndAt,
+		CreatedAt:         updatedCampaign.CreatedAt,
+		OutOfWindowPolicy: string(updatedCampaign.OutOfWindowPolicy),
+		FallbackURL:       updatedCampaign.FallbackURL,
+		BestPriceLink:     updatedCampaign.BestPriceLink,
 	}
 
 	return response, nil
@@ -265,20 +451,31 @@ func (s *CampaignService) UpdateCampaign(ctx context.Context, campaignID uuid.UU
 
 // UpdateCampaignProducts updates the products in a campaign
 // This replaces all existing products with the new list
-func (s *CampaignService) UpdateCampaignProducts(ctx context.Context, campaignID uuid.UUID, productIDs []uuid.UUID) error {
+func (s *CampaignService) UpdateCampaignProducts(ctx context.Context, campaignID uuid.UUID, productIDs []uuid.UUID, shortCodeReqs []dto.LinkShortCode) error {
 	// Check if campaign exists
-	_, err := s.campaignRepo.FindByID(ctx, campaignID)
+	campaign, err := s.campaignRepo.FindByID(ctx, campaignID)
 	if err != nil {
 		return fmt.Errorf("campaign not found: %w", err)
 	}
 
+	// Validate vanity short codes before updating anything
+	shortCodes, err := s.prepareShortCodes(ctx, campaignID, shortCodeReqs)
+	if err != nil {
+		return err
+	}
+
 	// Update products
 	if err := s.campaignRepo.UpdateCampaignProducts(ctx, campaignID, productIDs); err != nil {
 		return fmt.Errorf("failed to update campaign products: %w", err)
 	}
 
+	before := toCampaignResponse(campaign)
+	after := *before
+	after.ProductIDs = productIDs
+	s.audit.Record(ctx, model.AuditActionUpdate, model.AuditEntityCampaign, campaignID, before, &after)
+
 	// Automatically create links for products
-	if err := s.createLinksForProducts(ctx, campaignID, productIDs); err != nil {
+	if err := s.createLinksForProducts(ctx, campaignID, productIDs, shortCodes); err != nil {
 		s.logger.Warn("Failed to create links for products", logger.Error(err), logger.String("campaign_id", campaignID.String()))
 		// Don't fail update if link creation fails
 	}
@@ -288,10 +485,12 @@ func (s *CampaignService) UpdateCampaignProducts(ctx context.Context, campaignID
 
 // createLinksForProducts creates affiliate links for products in a campaign
 // It synchronizes links: removes unused links and creates missing links
-func (s *CampaignService) createLinksForProducts(ctx context.Context, campaignID uuid.UUID, productIDs []uuid.UUID) error {
+// New links use the requested vanity short code if any; existing links are
+// renamed to it, keeping their previous code as an alias
+func (s *CampaignService) createLinksForProducts(ctx context.Context, campaignID uuid.UUID, productIDs []uuid.UUID, shortCodes map[linkSlot]string) error {
 	s.logger.Info("Starting link synchronization for campaign", logger.String("campaign_id", campaignID.String()), logger.Int("product_count", len(productIDs)))
 
-	// Get campaign to get UTM campaign name
+	// Get campaign to get UTM settings
 	campaign, err := s.campaignRepo.FindByID(ctx, campaignID)
 	if err != nil {
 		return fmt.Errorf("failed to get campaign: %w", err)
@@ -327,13 +526,14 @@ func (s *CampaignService) createLinksForProducts(ctx context.Context, campaignID
 
 		s.logger.Info("Existing links retrieved", logger.String("product_id", productID.String()), logger.Int("existing_links_count", len(existingLinks)))
 
-		// Build a map of marketplaces that have offers
+		// Build a map of the link marketplaces the product should have (one per offer, plus best price if enabled)
+		targets := campaignLinkTargets(campaign, offers)
 		offerMarketplaces := make(map[model.Marketplace]bool)
-		for _, offer := range offers {
-			offerMarketplaces[offer.Marketplace] = true
+		for _, target := range targets {
+			offerMarketplaces[target.marketplace] = true
 		}
 
-		// Remove links for marketplaces that no longer have offers
+		// Remove links for marketplaces that no longer have offers (and disabled best-price links)
 		for _, existingLink := range existingLinks {
 			if !offerMarketplaces[existingLink.Marketplace] {
 				s.logger.Info("Removing link for marketplace without offer", logger.String("product_id", productID.String()), logger.String("marketplace", string(existingLink.Marketplace)), logger.String("link_id", existingLink.ID.String()))
@@ -356,26 +556,31 @@ func (s *CampaignService) createLinksForProducts(ctx context.Context, campaignID
 			existingLinkMarketplacesMap[existingLink.Marketplace] = true
 		}
 
-		// Update existing links' target URLs if UTM campaign changed, or create new links
-		for _, offer := range offers {
-			if existingLinkMarketplacesMap[offer.Marketplace] {
+		// Update existing links' target URLs if UTM settings or the best price changed, or create new links
+		for _, target := range targets {
+			if existingLinkMarketplacesMap[target.marketplace] {
 				// Link exists - check if we need to update target URL
 				var existingLink *model.Link
 				for _, link := range existingLinks {
-					if link.Marketplace == offer.Marketplace {
+					if link.Marketplace == target.marketplace {
 						existingLink = link
 						break
 					}
 				}
 
 				if existingLink != nil {
-					// Build new target URL with current UTM campaign
-					baseURL := offer.MarketplaceProductURL
-					utmSource := "affiliate"
-					utmMedium := "affiliate"
-					utmCampaign := campaign.UTMCampaign
+					// Apply a requested vanity short code before building the URL ({{short_code}} may be used in UTMs)
+					slot := linkSlot{productID: productID, marketplace: target.marketplace}
+					if code, ok := shortCodes[slot]; ok && code != existingLink.ShortCode {
+						if err := s.linkRepo.ChangeShortCode(ctx, existingLink, code); err != nil {
+							s.logger.Warn("Failed to change link short code", logger.Error(err), logger.String("link_id", existingLink.ID.String()), logger.String("short_code", code))
+						} else {
+							s.logger.Info("Changed link short code", logger.String("link_id", existingLink.ID.String()), logger.String("short_code", code))
+						}
+					}
 
-					newTargetURL, err := buildTargetURL(baseURL, utmCampaign, utmSource, utmMedium)
+					// Build new target URL with current UTM settings (keeping the link's overrides)
+					newTargetURL, err := target.targetURL(campaign, existingLink)
 					if err != nil {
 						s.logger.Warn("Failed to build target URL for update", logger.Error(err))
 						continue
@@ -388,48 +593,46 @@ func (s *CampaignService) createLinksForProducts(ctx context.Context, campaignID
 							s.logger.Warn("Failed to update link target URL", logger.Error(err), logger.String("link_id", existingLink.ID.String()))
 							continue
 						}
-						s.logger.Info("Updated existing link target URL", logger.String("product_id", productID.String()), logger.String("marketplace", string(offer.Marketplace)), logger.String("link_id", existingLink.ID.String()))
+						s.logger.Info("Updated existing link target URL", logger.String("product_id", productID.String()), logger.String("marketplace", string(target.marketplace)), logger.String("link_id", existingLink.ID.String()))
 					} else {
-						s.logger.Info("Link already exists, skipping", logger.String("product_id", productID.String()), logger.String("marketplace", string(offer.Marketplace)))
+						s.logger.Info("Link already exists, skipping", logger.String("product_id", productID.String()), logger.String("marketplace", string(target.marketplace)))
 					}
 				}
 				continue // Link already exists (or was updated)
 			}
 
-			// Generate unique short code
-			shortCode, err := s.generateUniqueShortCode(ctx)
-			if err != nil {
-				s.logger.Warn("Failed to generate short code", logger.Error(err))
-				continue
+			// Use the requested vanity short code, or generate a unique one
+			shortCode, ok := shortCodes[linkSlot{productID: productID, marketplace: target.marketplace}]
+			if !ok {
+				shortCode, err = s.generateUniqueShortCode(ctx)
+				if err != nil {
+					s.logger.Warn("Failed to generate short code", logger.Error(err))
+					continue
+				}
 			}
 
-			// Build target URL with UTM parameters
-			baseURL := offer.MarketplaceProductURL
-			utmSource := "affiliate"
-			utmMedium := "affiliate"
-			utmCampaign := campaign.UTMCampaign
+			link := &model.Link{
+				ProductID:   productID,
+				CampaignID:  campaignID,
+				Marketplace: target.marketplace,
+				ShortCode:   shortCode,
+			}
 
-			targetURL, err := buildTargetURL(baseURL, utmCampaign, utmSource, utmMedium)
+			// Build target URL with UTM parameters
+			targetURL, err := target.targetURL(campaign, link)
 			if err != nil {
 				s.logger.Warn("Failed to build target URL", logger.Error(err))
 				continue
 			}
+			link.TargetURL = targetURL
 
 			// Create link
-			link := &model.Link{
-				ProductID:   productID,
-				CampaignID:  campaignID,
-				Marketplace: offer.Marketplace,
-				ShortCode:   shortCode,
-				TargetURL:   targetURL,
-			}
-
 			if err := s.linkRepo.Create(ctx, link); err != nil {
-				s.logger.Warn("Failed to create link", logger.Error(err), logger.String("product_id", productID.String()), logger.String("marketplace", string(offer.Marketplace)))
+				s.logger.Warn("Failed to create link", logger.Error(err), logger.String("product_id", productID.String()), logger.String("marketplace", string(target.marketplace)))
 				continue
 			}
 
-			s.logger.Info("Created new link", logger.String("product_id", productID.String()), logger.String("marketplace", string(offer.Marketplace)), logger.String("short_code", shortCode))
+			s.logger.Info("Created new link", logger.String("product_id", productID.String()), logger.String("marketplace", string(target.marketplace)), logger.String("short_code", shortCode))
 		}
 	}
 
@@ -459,3 +662,55 @@ func (s *CampaignService) generateUniqueShortCode(ctx context.Context) (string,
 
 	return "", fmt.Errorf("failed to generate unique short code after %d retries", maxRetries)
 }
+
+// linkSlot identifies a campaign link by product and marketplace
+type linkSlot struct {
+	productID   uuid.UUID
+	marketplace model.Marketplace
+}
+
+// prepareShortCodes validates requested vanity short codes for a campaign's links
+// A code already used by the same campaign link (as its code or an alias) is
+// allowed so repeated sync requests don't conflict. campaignID is uuid.Nil for
+// campaigns that don't exist yet.
+func (s *CampaignService) prepareShortCodes(ctx context.Context, campaignID uuid.UUID, reqs []dto.LinkShortCode) (map[linkSlot]string, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	shortCodes := make(map[linkSlot]string, len(reqs))
+	seen := make(map[string]bool, len(reqs))
+	for _, req := range reqs {
+		if req.ProductID == uuid.Nil || req.Marketplace == "" {
+			return nil, fmt.Errorf("invalid short_codes: product_id and marketplace are required")
+		}
+		code := normalizeShortCode(req.ShortCode)
+		if err := validateVanityShortCode(code); err != nil {
+			return nil, err
+		}
+
+		slot := linkSlot{productID: req.ProductID, marketplace: model.Marketplace(strings.ToLower(req.Marketplace))}
+		if _, ok := shortCodes[slot]; ok || seen[code] {
+			return nil, fmt.Errorf("invalid short_codes: each link and short code may only be listed once")
+		}
+		shortCodes[slot] = code
+		seen[code] = true
+
+		exists, err := s.linkRepo.ShortCodeExists(ctx, code)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check short code: %w", err)
+		}
+		if !exists {
+			continue
+		}
+		link, err := s.linkRepo.FindByShortCode(ctx, code)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to check short code: %w", err)
+		}
+		if link == nil || link.CampaignID != campaignID || link.ProductID != slot.productID || link.Marketplace != slot.marketplace {
+			return nil, fmt.Errorf("short code already in use: %s", code)
+		}
+	}
+
+	return shortCodes, nil
+}
@@ -15,10 +15,12 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/jonosize/affiliate-platform/internal/auth"
 	"github.com/jonosize/affiliate-platform/internal/config"
 	"github.com/jonosize/affiliate-platform/internal/database"
 	"github.com/jonosize/affiliate-platform/internal/dto"
 	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
 	"github.com/jonosize/affiliate-platform/internal/repository"
 	"github.com/jonosize/affiliate-platform/pkg/adapters/mock"
 )
@@ -36,6 +38,7 @@ type CampaignIntegrationTestSuite struct {
 	linkSvc     *LinkService
 	redirectSvc *RedirectService
 	clickSvc    *ClickService
+	auditSvc    *AuditService
 	productID   uuid.UUID
 	campaignID  uuid.UUID
 }
@@ -64,7 +67,9 @@ func (suite *CampaignIntegrationTestSuite) SetupSuite() {
 	require.NoError(suite.T(), err, "Failed to initialize database")
 	suite.db = db
 
-	suite.ctx = context.Background()
+	// Admin requests act in a workspace; repositories scope every query by it
+	suite.ctx = auth.WithWorkspace(context.Background(), model.DefaultWorkspaceID)
+	suite.ctx = auth.WithPrincipal(suite.ctx, &auth.Principal{Subject: "basic:integration", Name: "integration", Scopes: auth.AllScopes})
 }
 
 func (suite *CampaignIntegrationTestSuite) TearDownSuite() {
@@ -80,17 +85,22 @@ func (suite *CampaignIntegrationTestSuite) SetupTest() {
 	campaignRepo := repository.NewCampaignRepository(suite.db)
 	linkRepo := repository.NewLinkRepository(suite.db)
 	clickRepo := repository.NewClickRepository(suite.db)
+	priceHistoryRepo := repository.NewPriceHistoryRepository(suite.db)
 
 	// Initialize adapters
-	lazadaAdapter, shopeeAdapter, err := mock.GetMockAdapters()
+	registry, err := mock.NewRegistry()
 	require.NoError(suite.T(), err, "Failed to get mock adapters")
 
 	// Initialize services
+	suite.auditSvc = NewAuditService(repository.NewAuditEventRepository(suite.db), suite.logger)
+
 	suite.productSvc = NewProductService(
 		productRepo,
 		offerRepo,
-		lazadaAdapter,
-		shopeeAdapter,
+		priceHistoryRepo,
+		registry,
+		nil, // No link cache
+		suite.auditSvc,
 		suite.logger,
 	)
 
@@ -99,6 +109,8 @@ func (suite *CampaignIntegrationTestSuite) SetupTest() {
 		linkRepo,
 		offerRepo,
 		productRepo,
+		nil, // No link cache
+		suite.auditSvc,
 		suite.cfg,
 		suite.logger,
 	)
@@ -108,6 +120,10 @@ func (suite *CampaignIntegrationTestSuite) SetupTest() {
 		campaignRepo,
 		productRepo,
 		offerRepo,
+		repository.NewWorkspaceRepository(suite.db),
+		registry,
+		nil, // No QR logo
+		suite.auditSvc,
 		suite.cfg,
 		suite.logger,
 	)
@@ -115,23 +131,32 @@ func (suite *CampaignIntegrationTestSuite) SetupTest() {
 	suite.clickSvc = NewClickService(
 		clickRepo,
 		linkRepo,
+		nil, // Insert clicks synchronously
+		nil, // No bot filtering
+		nil, // No click de-duplication
 		suite.logger,
 	)
 
 	suite.redirectSvc = NewRedirectService(
 		linkRepo,
+		offerRepo,
 		suite.clickSvc,
+		registry,
+		nil, // No GeoIP database
+		suite.cfg,
 		suite.logger,
 	)
 }
 
 func (suite *CampaignIntegrationTestSuite) TearDownTest() {
-	// Clean up test data
+	// Clean up test data (deletes are soft; purging removes the rows)
 	if suite.campaignID != uuid.Nil {
 		_ = suite.campaignSvc.DeleteCampaign(suite.ctx, suite.campaignID)
+		_ = suite.campaignSvc.PurgeCampaign(suite.ctx, suite.campaignID, suite.campaignID.String())
 	}
 	if suite.productID != uuid.Nil {
 		_ = suite.productSvc.DeleteProduct(suite.ctx, suite.productID)
+		_ = suite.productSvc.PurgeProduct(suite.ctx, suite.productID, suite.productID.String())
 	}
 }
 
@@ -218,16 +243,19 @@ func (suite *CampaignIntegrationTestSuite) TestCampaignWorkflow() {
 
 	// Step 6: Test redirect (this will also track a click)
 	ipAddress := net.IP{192, 168, 1, 1} // Use a valid IP address
-	redirectURL, err := suite.redirectSvc.Redirect(
+	redirectTarget, err := suite.redirectSvc.Redirect(
 		suite.ctx,
 		link.ShortCode,
-		ipAddress,             // IP address
-		"Mozilla/5.0 (Test)",  // User agent
-		"https://example.com", // Referrer
+		dto.ClickMetadata{
+			IPAddress: ipAddress,
+			UserAgent: "Mozilla/5.0 (Test)",
+			Referrer:  "https://example.com",
+			Method:    "GET",
+		},
 	)
 	require.NoError(suite.T(), err, "Failed to redirect")
-	assert.NotEmpty(suite.T(), redirectURL)
-	assert.Contains(suite.T(), redirectURL, "utm_source=affiliate")
+	assert.NotEmpty(suite.T(), redirectTarget.URL)
+	assert.Contains(suite.T(), redirectTarget.URL, "utm_source=affiliate")
 
 	// Step 7: Verify click was tracked (give it a moment for async processing)
 	time.Sleep(100 * time.Millisecond)
@@ -235,6 +263,14 @@ func (suite *CampaignIntegrationTestSuite) TestCampaignWorkflow() {
 	require.NoError(suite.T(), err, "Failed to get click stats")
 	assert.GreaterOrEqual(suite.T(), clickCount, int64(1), "At least one click should be tracked")
 
+	linkStats, err := suite.clickSvc.GetLinkStats(suite.ctx, link.ID, dto.LinkStatsQueryParams{Interval: StatsIntervalHour})
+	require.NoError(suite.T(), err, "Failed to get link stats")
+	assert.Equal(suite.T(), clickCount, linkStats.TotalClicks)
+	assert.Equal(suite.T(), int64(1), linkStats.UniqueClicks)
+	require.NotEmpty(suite.T(), linkStats.Series)
+	require.NotEmpty(suite.T(), linkStats.Referrers)
+	assert.Equal(suite.T(), "example.com", linkStats.Referrers[0].Value)
+
 	// Step 8: Test updating campaign products
 	newProductReq := dto.CreateProductRequest{
 		LazadaURL: "https://www.lazada.co.th/products/another-product.html",
@@ -243,7 +279,7 @@ func (suite *CampaignIntegrationTestSuite) TestCampaignWorkflow() {
 	require.NoError(suite.T(), err, "Failed to create second product")
 
 	// Update campaign to include both products
-	err = suite.campaignSvc.UpdateCampaignProducts(suite.ctx, campaign.ID, []uuid.UUID{product.ID, newProduct.ID})
+	err = suite.campaignSvc.UpdateCampaignProducts(suite.ctx, campaign.ID, []uuid.UUID{product.ID, newProduct.ID}, nil)
 	require.NoError(suite.T(), err, "Failed to update campaign products")
 
 	// Verify campaign was updated
@@ -252,8 +288,37 @@ func (suite *CampaignIntegrationTestSuite) TestCampaignWorkflow() {
 	assert.Contains(suite.T(), updatedCampaign.ProductIDs, product.ID)
 	assert.Contains(suite.T(), updatedCampaign.ProductIDs, newProduct.ID)
 
+	// Step 9: Verify the audit log recorded the changes, newest first
+	events, err := suite.auditSvc.GetAuditEvents(suite.ctx, dto.AuditQueryParams{Entity: "campaign", EntityID: &campaign.ID}, 10, 0)
+	require.NoError(suite.T(), err, "Failed to get audit events")
+	require.Len(suite.T(), events, 2)
+	assert.Equal(suite.T(), "update", events[0].Action)
+	assert.Equal(suite.T(), "basic:integration", events[0].Actor)
+	assert.Contains(suite.T(), events[0].After, "product_ids")
+	assert.NotContains(suite.T(), events[0].After, "name", "Updates only keep changed fields")
+	assert.Equal(suite.T(), "create", events[1].Action)
+	assert.Equal(suite.T(), "Integration Test Campaign", events[1].After["name"])
+
+	// Step 10: Deleting the campaign hides it and stops its links, but keeps their clicks
+	require.NoError(suite.T(), suite.campaignSvc.DeleteCampaign(suite.ctx, campaign.ID))
+	_, err = suite.campaignSvc.GetCampaignResponse(suite.ctx, campaign.ID)
+	assert.Error(suite.T(), err, "Deleted campaign should not be found")
+	_, err = suite.redirectSvc.Redirect(suite.ctx, link.ShortCode, dto.ClickMetadata{IPAddress: ipAddress, Method: "GET"})
+	assert.Error(suite.T(), err, "Links of a deleted campaign should not redirect")
+	deletedClickCount, err := suite.clickSvc.GetClickStats(suite.ctx, link.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), clickCount, deletedClickCount, "Clicks of a deleted campaign should still count")
+
+	// Restoring brings the campaign and its links back
+	restored, err := suite.campaignSvc.RestoreCampaign(suite.ctx, campaign.ID)
+	require.NoError(suite.T(), err, "Failed to restore campaign")
+	assert.Contains(suite.T(), restored.ProductIDs, product.ID)
+	_, err = suite.redirectSvc.Redirect(suite.ctx, link.ShortCode, dto.ClickMetadata{IPAddress: ipAddress, Method: "GET"})
+	assert.NoError(suite.T(), err, "Links of a restored campaign should redirect")
+
 	// Clean up second product
 	_ = suite.productSvc.DeleteProduct(suite.ctx, newProduct.ID)
+	_ = suite.productSvc.PurgeProduct(suite.ctx, newProduct.ID, newProduct.ID.String())
 }
 
 func TestCampaignIntegrationTestSuite(t *testing.T) {
@@ -13,37 +13,51 @@ import (
 	"github.com/jonosize/affiliate-platform/internal/model"
 )
 
+// lowestPriceWindow is the lookback window for the "lowest in 30 days" badge
+const lowestPriceWindow = 30 * 24 * time.Hour
+
 // CampaignPublicService handles public campaign business logic
 type CampaignPublicService struct {
-	campaignRepo CampaignRepositoryInterface
-	productRepo  ProductRepositoryInterface
-	offerRepo    OfferRepositoryInterface
-	linkRepo     LinkRepositoryInterface
-	cfg          config.Config
-	logger       logger.Logger
+	campaignRepo     CampaignRepositoryInterface
+	productRepo      ProductRepositoryInterface
+	offerRepo        OfferRepositoryInterface
+	linkRepo         LinkRepositoryInterface
+	priceHistoryRepo PriceHistoryRepositoryInterface
+	workspaceRepo    WorkspaceRepositoryInterface
+	impressionSvc    *ImpressionService
+	cfg              config.Config
+	logger           logger.Logger
 }
 
 // NewCampaignPublicService creates a new public campaign service
+// When impressionSvc is nil, campaign views are not tracked
 func NewCampaignPublicService(
 	campaignRepo CampaignRepositoryInterface,
 	productRepo ProductRepositoryInterface,
 	offerRepo OfferRepositoryInterface,
 	linkRepo LinkRepositoryInterface,
+	priceHistoryRepo PriceHistoryRepositoryInterface,
+	workspaceRepo WorkspaceRepositoryInterface,
+	impressionSvc *ImpressionService,
 	cfg config.Config,
 	log logger.Logger,
 ) *CampaignPublicService {
 	return &CampaignPublicService{
-		campaignRepo: campaignRepo,
-		productRepo:  productRepo,
-		offerRepo:    offerRepo,
-		linkRepo:     linkRepo,
-		cfg:          cfg,
-		logger:       log,
+		campaignRepo:     campaignRepo,
+		productRepo:      productRepo,
+		offerRepo:        offerRepo,
+		linkRepo:         linkRepo,
+		priceHistoryRepo: priceHistoryRepo,
+		workspaceRepo:    workspaceRepo,
+		impressionSvc:    impressionSvc,
+		cfg:              cfg,
+		logger:           log,
 	}
 }
 
 // GetPublicCampaign gets a public campaign view with products and offers
-func (s *CampaignPublicService) GetPublicCampaign(ctx context.Context, campaignID uuid.UUID) (*dto.CampaignPublicResponse, error) {
+// and records impressions for the campaign and each rendered link
+func (s *CampaignPublicService) GetPublicCampaign(ctx context.Context, campaignID uuid.UUID, meta dto.ClickMetadata) (*dto.CampaignPublicResponse, error) {
 	// Get campaign
 	campaign, err := s.campaignRepo.FindByID(ctx, campaignID)
 	if err != nil {
@@ -65,6 +79,10 @@ func (s *CampaignPublicService) GetPublicCampaign(ctx context.Context, campaignI
 		EndAt:    campaign.EndAt,
 		Products: make([]dto.CampaignProduct, 0),
 	}
+	var renderedLinks []*model.Link
+
+	// Short links point at the workspace's redirect domain, if it has one
+	shortLinkBase := shortLinkBaseURL(loadWorkspace(ctx, s.workspaceRepo, campaign.WorkspaceID, s.logger), s.cfg)
 
 	// Get products for campaign
 	for _, cp := range campaign.CampaignProducts {
@@ -83,28 +101,20 @@ func (s *CampaignPublicService) GetPublicCampaign(ctx context.Context, campaignI
 
 		// Convert offers to DTO
 		offerResponses := make([]dto.OfferResponse, 0, len(offers))
-		var bestPrice *dto.BestPrice
-
-		if len(offers) > 0 {
-			// Sort by price and find best
-			bestOffer := offers[0]
-			for _, offer := range offers {
-				if offer.Price < bestOffer.Price {
-					bestOffer = offer
-				}
-				offerResponses = append(offerResponses, dto.OfferResponse{
-					ID:            offer.ID,
-					Marketplace:   string(offer.Marketplace),
-					StoreName:     offer.StoreName,
-					Price:         offer.Price,
-					LastCheckedAt: offer.LastCheckedAt,
-				})
-			}
+		for _, offer := range offers {
+			offerResponses = append(offerResponses, dto.OfferResponse{
+				ID:            offer.ID,
+				Marketplace:   string(offer.Marketplace),
+				StoreName:     offer.StoreName,
+				Price:         offer.Price,
+				LastCheckedAt: offer.LastCheckedAt,
+			})
+		}
 
-			bestPrice = &dto.BestPrice{
-				Marketplace: string(bestOffer.Marketplace),
-				Price:       bestOffer.Price,
-			}
+		// Find the best price
+		bestPrice := toBestPrice(offers)
+		if bestPrice != nil {
+			s.applyLowestPriceBadge(ctx, product.ID, bestPrice, now)
 		}
 
 		// Get links for this product in the campaign
@@ -125,27 +135,23 @@ func (s *CampaignPublicService) GetPublicCampaign(ctx context.Context, campaignI
 					continue
 				}
 
-				// Build target URL with UTM parameters
-				baseURL := offer.MarketplaceProductURL
-				utmSource := "affiliate"
-				utmMedium := "affiliate"
-				utmCampaign := campaign.UTMCampaign
-
-				targetURL, err := buildTargetURL(baseURL, utmCampaign, utmSource, utmMedium)
-				if err != nil {
-					s.logger.Warn("Failed to build target URL", logger.Error(err))
-					continue
-				}
-
-				// Create link
 				link := &model.Link{
+					WorkspaceID: campaign.WorkspaceID, // Public requests carry no workspace
 					ProductID:   product.ID,
 					CampaignID:  campaign.ID,
 					Marketplace: offer.Marketplace,
 					ShortCode:   shortCode,
-					TargetURL:   targetURL,
 				}
 
+				// Build target URL with the campaign's UTM settings
+				targetURL, err := buildLinkTargetURL(offer.MarketplaceProductURL, campaign, link)
+				if err != nil {
+					s.logger.Warn("Failed to build target URL", logger.Error(err))
+					continue
+				}
+				link.TargetURL = targetURL
+
+				// Create link
 				if err := s.linkRepo.Create(ctx, link); err != nil {
 					s.logger.Warn("Failed to create link", logger.Error(err))
 					continue
@@ -163,15 +169,15 @@ func (s *CampaignPublicService) GetPublicCampaign(ctx context.Context, campaignI
 
 		// Convert links to DTO
 		productLinks := make([]dto.ProductLink, len(links))
-		apiBaseURL := s.cfg.GetAPIBaseURL()
 		for i, link := range links {
-			fullURL := apiBaseURL + "/go/" + link.ShortCode
+			fullURL := shortLinkBase + "/go/" + link.ShortCode
 			productLinks[i] = dto.ProductLink{
 				Marketplace: string(link.Marketplace),
 				ShortCode:   link.ShortCode,
 				FullURL:     fullURL,
 			}
 		}
+		renderedLinks = append(renderedLinks, links...)
 
 		response.Products = append(response.Products, dto.CampaignProduct{
 			ID:        product.ID,
@@ -183,9 +189,39 @@ func (s *CampaignPublicService) GetPublicCampaign(ctx context.Context, campaignI
 		})
 	}
 
+	// Track impressions (a failure never fails the page)
+	if s.impressionSvc != nil {
+		if err := s.impressionSvc.TrackCampaignView(ctx, campaign.ID, renderedLinks, meta); err != nil {
+			s.logger.Warn("Failed to track campaign impressions", logger.Error(err), logger.String("campaign_id", campaign.ID.String()))
+		}
+	}
+
 	return response, nil
 }
 
+// applyLowestPriceBadge sets the lowest recorded price of the last 30 days on the best price
+// and flags it when the current best price matches or beats it
+func (s *CampaignPublicService) applyLowestPriceBadge(ctx context.Context, productID uuid.UUID, bestPrice *dto.BestPrice, now time.Time) {
+	results, err := s.priceHistoryRepo.FindMinPricesSince(ctx, productID, now.Add(-lowestPriceWindow))
+	if err != nil {
+		s.logger.Warn("Failed to get price history", logger.Error(err), logger.String("product_id", productID.String()))
+		return
+	}
+	if len(results) == 0 {
+		return
+	}
+
+	lowest := results[0].MinPrice
+	for _, r := range results {
+		if r.MinPrice < lowest {
+			lowest = r.MinPrice
+		}
+	}
+
+	bestPrice.Lowest30Days = &lowest
+	bestPrice.IsLowest30Days = bestPrice.Price <= lowest
+}
+
 // generateUniqueShortCode generates a unique short code, retrying on collision
 func (s *CampaignPublicService) generateUniqueShortCode(ctx context.Context) (string, error) {
 	maxRetries := 10
@@ -53,6 +53,24 @@ func (m *MockCampaignRepository) Delete(ctx context.Context, id uuid.UUID) error
 	return args.Error(0)
 }
 
+func (m *MockCampaignRepository) FindDeletedByID(ctx context.Context, id uuid.UUID) (*model.Campaign, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCampaignRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func (m *MockCampaignRepository) AddProducts(ctx context.Context, campaignID uuid.UUID, productIDs []uuid.UUID) error {
 	args := m.Called(ctx, campaignID, productIDs)
 	return args.Error(0)
@@ -86,6 +104,14 @@ func (m *MockLinkRepository) FindByID(ctx context.Context, id uuid.UUID) (*model
 	return args.Get(0).(*model.Link), args.Error(1)
 }
 
+func (m *MockLinkRepository) FindByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*model.Link, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Link), args.Error(1)
+}
+
 func (m *MockLinkRepository) FindByShortCode(ctx context.Context, shortCode string) (*model.Link, error) {
 	args := m.Called(ctx, shortCode)
 	if args.Get(0) == nil {
@@ -110,6 +136,14 @@ func (m *MockLinkRepository) FindByCampaignID(ctx context.Context, campaignID uu
 	return args.Get(0).([]*model.Link), args.Error(1)
 }
 
+func (m *MockLinkRepository) FindByProductID(ctx context.Context, productID uuid.UUID) ([]*model.Link, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Link), args.Error(1)
+}
+
 func (m *MockLinkRepository) Update(ctx context.Context, link *model.Link) error {
 	args := m.Called(ctx, link)
 	return args.Error(0)
@@ -135,11 +169,112 @@ func (m *MockLinkRepository) ShortCodeExists(ctx context.Context, shortCode stri
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockLinkRepository) FindAliasesByLinkID(ctx context.Context, linkID uuid.UUID) ([]*model.LinkAlias, error) {
+	args := m.Called(ctx, linkID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.LinkAlias), args.Error(1)
+}
+
+func (m *MockLinkRepository) CreateAlias(ctx context.Context, alias *model.LinkAlias) error {
+	args := m.Called(ctx, alias)
+	return args.Error(0)
+}
+
+func (m *MockLinkRepository) DeleteAlias(ctx context.Context, linkID uuid.UUID, shortCode string) error {
+	args := m.Called(ctx, linkID, shortCode)
+	return args.Error(0)
+}
+
+func (m *MockLinkRepository) ChangeShortCode(ctx context.Context, link *model.Link, shortCode string) error {
+	args := m.Called(ctx, link, shortCode)
+	if args.Error(0) == nil {
+		link.ShortCode = shortCode
+	}
+	return args.Error(0)
+}
+
+func (m *MockLinkRepository) FindRedirectRulesByLinkID(ctx context.Context, linkID uuid.UUID) ([]*model.RedirectRule, error) {
+	args := m.Called(ctx, linkID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.RedirectRule), args.Error(1)
+}
+
+func (m *MockLinkRepository) FindRedirectRule(ctx context.Context, linkID, ruleID uuid.UUID) (*model.RedirectRule, error) {
+	args := m.Called(ctx, linkID, ruleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RedirectRule), args.Error(1)
+}
+
+func (m *MockLinkRepository) CreateRedirectRule(ctx context.Context, rule *model.RedirectRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockLinkRepository) UpdateRedirectRule(ctx context.Context, rule *model.RedirectRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockLinkRepository) DeleteRedirectRule(ctx context.Context, linkID, ruleID uuid.UUID) error {
+	args := m.Called(ctx, linkID, ruleID)
+	return args.Error(0)
+}
+
+func (m *MockLinkRepository) FindVariantsByLinkID(ctx context.Context, linkID uuid.UUID) ([]*model.LinkVariant, error) {
+	args := m.Called(ctx, linkID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.LinkVariant), args.Error(1)
+}
+
+func (m *MockLinkRepository) FindVariant(ctx context.Context, linkID, variantID uuid.UUID) (*model.LinkVariant, error) {
+	args := m.Called(ctx, linkID, variantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LinkVariant), args.Error(1)
+}
+
+func (m *MockLinkRepository) CreateVariant(ctx context.Context, variant *model.LinkVariant) error {
+	args := m.Called(ctx, variant)
+	return args.Error(0)
+}
+
+func (m *MockLinkRepository) UpdateVariant(ctx context.Context, variant *model.LinkVariant) error {
+	args := m.Called(ctx, variant)
+	return args.Error(0)
+}
+
+func (m *MockLinkRepository) DeleteVariant(ctx context.Context, linkID, variantID uuid.UUID) error {
+	args := m.Called(ctx, linkID, variantID)
+	return args.Error(0)
+}
+
 func (m *MockLinkRepository) CountWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string) (int64, error) {
 	args := m.Called(ctx, campaignID, marketplace)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+// MockLinkCache is a mock implementation of LinkCache
+type MockLinkCache struct {
+	mock.Mock
+}
+
+func (m *MockLinkCache) InvalidateProductLinks(ctx context.Context, productID uuid.UUID) {
+	m.Called(ctx, productID)
+}
+
+func (m *MockLinkCache) InvalidateCampaignLinks(ctx context.Context, campaignID uuid.UUID) {
+	m.Called(ctx, campaignID)
+}
+
 // MockOfferRepository is a mock implementation of OfferRepositoryInterface
 type MockOfferRepository struct {
 	mock.Mock
@@ -217,37 +352,84 @@ func (m *MockProductRepository) Delete(ctx context.Context, id uuid.UUID) error
 	return args.Error(0)
 }
 
+func (m *MockProductRepository) FindDeletedByID(ctx context.Context, id uuid.UUID) (*model.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockConfig is a mock implementation of config.Config
 type MockConfig struct {
-	apiBaseURL string
-}
-
-func (m *MockConfig) GetDatabaseWriteHost() string           { return "" }
-func (m *MockConfig) GetDatabaseWritePort() int              { return 0 }
-func (m *MockConfig) GetDatabaseWriteUser() string           { return "" }
-func (m *MockConfig) GetDatabaseWritePassword() string       { return "" }
-func (m *MockConfig) GetDatabaseWriteDBName() string         { return "" }
-func (m *MockConfig) GetDatabaseWriteSSLMode() string        { return "" }
-func (m *MockConfig) GetDatabaseReadHost() string            { return "" }
-func (m *MockConfig) GetDatabaseReadPort() int               { return 0 }
-func (m *MockConfig) GetDatabaseReadUser() string            { return "" }
-func (m *MockConfig) GetDatabaseReadPassword() string        { return "" }
-func (m *MockConfig) GetDatabaseReadDBName() string          { return "" }
-func (m *MockConfig) GetDatabaseReadSSLMode() string         { return "" }
-func (m *MockConfig) GetDatabaseMaxOpenConns() int           { return 0 }
-func (m *MockConfig) GetDatabaseMaxIdleConns() int           { return 0 }
-func (m *MockConfig) GetDatabaseConnMaxLifetime() int        { return 0 }
-func (m *MockConfig) GetDatabaseWriteURL() string            { return "" }
-func (m *MockConfig) GetDatabaseReadURL() string             { return "" }
-func (m *MockConfig) GetRedisURL() string                    { return "" }
-func (m *MockConfig) GetServerPort() string                  { return "" }
-func (m *MockConfig) GetServerHost() string                  { return "" }
-func (m *MockConfig) GetAPIBaseURL() string                  { return m.apiBaseURL }
-func (m *MockConfig) GetPriceRefreshCron() string            { return "" }
-func (m *MockConfig) GetMockMode() bool                      { return false }
-func (m *MockConfig) GetBasicAuthUsername() string           { return "" }
-func (m *MockConfig) GetBasicAuthPassword() string           { return "" }
-func (m *MockConfig) GetAllSettings() map[string]interface{} { return nil }
+	apiBaseURL      string
+	postbackSecrets map[string]string
+}
+
+func (m *MockConfig) GetDatabaseWriteHost() string            { return "" }
+func (m *MockConfig) GetDatabaseWritePort() int               { return 0 }
+func (m *MockConfig) GetDatabaseWriteUser() string            { return "" }
+func (m *MockConfig) GetDatabaseWritePassword() string        { return "" }
+func (m *MockConfig) GetDatabaseWriteDBName() string          { return "" }
+func (m *MockConfig) GetDatabaseWriteSSLMode() string         { return "" }
+func (m *MockConfig) GetDatabaseReadHost() string             { return "" }
+func (m *MockConfig) GetDatabaseReadPort() int                { return 0 }
+func (m *MockConfig) GetDatabaseReadUser() string             { return "" }
+func (m *MockConfig) GetDatabaseReadPassword() string         { return "" }
+func (m *MockConfig) GetDatabaseReadDBName() string           { return "" }
+func (m *MockConfig) GetDatabaseReadSSLMode() string          { return "" }
+func (m *MockConfig) GetDatabaseMaxOpenConns() int            { return 0 }
+func (m *MockConfig) GetDatabaseMaxIdleConns() int            { return 0 }
+func (m *MockConfig) GetDatabaseConnMaxLifetime() int         { return 0 }
+func (m *MockConfig) GetDatabaseWriteURL() string             { return "" }
+func (m *MockConfig) GetDatabaseReadURL() string              { return "" }
+func (m *MockConfig) GetRedisURL() string                     { return "" }
+func (m *MockConfig) GetShortCodeCacheTTL() int               { return 0 }
+func (m *MockConfig) GetShortCodeCacheNegativeTTL() int       { return 0 }
+func (m *MockConfig) GetShortCodeCacheSize() int              { return 0 }
+func (m *MockConfig) GetBotExcludedIPRanges() []string        { return nil }
+func (m *MockConfig) GetVisitorSalt() string                  { return "" }
+func (m *MockConfig) GetVisitorCookieName() string            { return "" }
+func (m *MockConfig) GetClickDedupeWindow() int               { return 0 }
+func (m *MockConfig) GetClickDedupeCacheSize() int            { return 0 }
+func (m *MockConfig) GetGeoIPDatabasePath() string            { return "" }
+func (m *MockConfig) GetPostbackSecret(network string) string { return m.postbackSecrets[network] }
+func (m *MockConfig) GetBotUserAgentPatterns() []string       { return nil }
+func (m *MockConfig) GetServerPort() string                   { return "" }
+func (m *MockConfig) GetServerHost() string                   { return "" }
+func (m *MockConfig) GetAPIBaseURL() string                   { return m.apiBaseURL }
+func (m *MockConfig) GetQRLogoPath() string                   { return "" }
+func (m *MockConfig) GetPriceRefreshCron() string             { return "" }
+func (m *MockConfig) GetClickQueueSize() int                  { return 0 }
+func (m *MockConfig) GetClickIngestWorkers() int              { return 0 }
+func (m *MockConfig) GetClickBatchSize() int                  { return 0 }
+func (m *MockConfig) GetClickFlushInterval() int              { return 0 }
+func (m *MockConfig) GetClickEnqueueTimeout() int             { return 0 }
+func (m *MockConfig) GetMockMode() bool                       { return false }
+func (m *MockConfig) GetLazadaAppKey() string                 { return "" }
+func (m *MockConfig) GetLazadaAppSecret() string              { return "" }
+func (m *MockConfig) GetLazadaAccessToken() string            { return "" }
+func (m *MockConfig) GetLazadaAPIURL() string                 { return "" }
+func (m *MockConfig) GetShopeePartnerID() string              { return "" }
+func (m *MockConfig) GetShopeePartnerKey() string             { return "" }
+func (m *MockConfig) GetShopeeShopID() string                 { return "" }
+func (m *MockConfig) GetShopeeAccessToken() string            { return "" }
+func (m *MockConfig) GetShopeeAPIURL() string                 { return "" }
+func (m *MockConfig) GetBasicAuthUsername() string            { return "" }
+func (m *MockConfig) GetBasicAuthPassword() string            { return "" }
+func (m *MockConfig) GetSessionSecret() string                { return "" }
+func (m *MockConfig) GetSessionTTL() int                      { return 0 }
+func (m *MockConfig) GetAllSettings() map[string]interface{}  { return nil }
 
 // CampaignServiceTestSuite is the test suite for CampaignService
 type CampaignServiceTestSuite struct {
@@ -279,6 +461,8 @@ func (suite *CampaignServiceTestSuite) SetupTest() {
 		suite.linkRepo,
 		suite.offerRepo,
 		suite.productRepo,
+		nil, // No link cache
+		nil, // No audit log
 		suite.cfg,
 		suite.logger,
 	)
@@ -750,6 +934,139 @@ func (suite *CampaignServiceTestSuite) TestCampaignService_DeleteCampaign() {
 	}
 }
 
+// TestCampaignService_RestoreCampaign tests the RestoreCampaign method
+func (suite *CampaignServiceTestSuite) TestCampaignService_RestoreCampaign() {
+	campaignID := uuid.New()
+	productID := uuid.New()
+	campaign := &model.Campaign{
+		ID:               campaignID,
+		Name:             "Test Campaign",
+		CampaignProducts: []model.CampaignProduct{{CampaignID: campaignID, ProductID: productID}},
+	}
+
+	tests := []struct {
+		name        string
+		setupMock   func()
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "success",
+			setupMock: func() {
+				suite.campaignRepo.On("FindDeletedByID", suite.ctx, campaignID).
+					Return(campaign, nil).Once()
+				suite.campaignRepo.On("Restore", suite.ctx, campaignID).
+					Return(nil).Once()
+				suite.campaignRepo.On("FindByID", suite.ctx, campaignID).
+					Return(campaign, nil).Once()
+			},
+		},
+		{
+			name: "error when campaign is not deleted",
+			setupMock: func() {
+				suite.campaignRepo.On("FindDeletedByID", suite.ctx, campaignID).
+					Return(nil, errors.New("not found")).Once()
+			},
+			wantErr:     true,
+			errContains: "deleted campaign not found",
+		},
+		{
+			name: "error when Restore fails",
+			setupMock: func() {
+				suite.campaignRepo.On("FindDeletedByID", suite.ctx, campaignID).
+					Return(campaign, nil).Once()
+				suite.campaignRepo.On("Restore", suite.ctx, campaignID).
+					Return(errors.New("database error")).Once()
+			},
+			wantErr:     true,
+			errContains: "failed to restore campaign",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.campaignRepo.ExpectedCalls = nil
+			tt.setupMock()
+
+			response, err := suite.service.RestoreCampaign(suite.ctx, campaignID)
+
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+				assert.Contains(suite.T(), err.Error(), tt.errContains)
+			} else {
+				suite.Require().NoError(err)
+				assert.Equal(suite.T(), campaignID, response.ID)
+				assert.Equal(suite.T(), []uuid.UUID{productID}, response.ProductIDs)
+			}
+		})
+	}
+}
+
+// TestCampaignService_PurgeCampaign tests the PurgeCampaign method
+func (suite *CampaignServiceTestSuite) TestCampaignService_PurgeCampaign() {
+	campaignID := uuid.New()
+	campaign := &model.Campaign{ID: campaignID, Name: "Test Campaign"}
+
+	tests := []struct {
+		name        string
+		confirm     string
+		setupMock   func()
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "success",
+			confirm: campaignID.String(),
+			setupMock: func() {
+				suite.campaignRepo.On("FindDeletedByID", suite.ctx, campaignID).
+					Return(campaign, nil).Once()
+				suite.campaignRepo.On("Purge", suite.ctx, campaignID).
+					Return(nil).Once()
+			},
+		},
+		{
+			name:        "error without confirmation",
+			confirm:     "",
+			setupMock:   func() {},
+			wantErr:     true,
+			errContains: "invalid confirmation",
+		},
+		{
+			name:        "error when confirmation names another campaign",
+			confirm:     uuid.New().String(),
+			setupMock:   func() {},
+			wantErr:     true,
+			errContains: "invalid confirmation",
+		},
+		{
+			name:    "error when campaign is not deleted",
+			confirm: campaignID.String(),
+			setupMock: func() {
+				suite.campaignRepo.On("FindDeletedByID", suite.ctx, campaignID).
+					Return(nil, errors.New("not found")).Once()
+			},
+			wantErr:     true,
+			errContains: "deleted campaign not found",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.campaignRepo.ExpectedCalls = nil
+			tt.setupMock()
+
+			err := suite.service.PurgeCampaign(suite.ctx, campaignID, tt.confirm)
+
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+				assert.Contains(suite.T(), err.Error(), tt.errContains)
+			} else {
+				assert.NoError(suite.T(), err)
+			}
+		})
+	}
+}
+
 // TestCampaignService_UpdateCampaign tests the UpdateCampaign method
 func (suite *CampaignServiceTestSuite) TestCampaignService_UpdateCampaign() {
 	campaignID := uuid.New()
@@ -828,6 +1145,47 @@ func (suite *CampaignServiceTestSuite) TestCampaignService_UpdateCampaign() {
 			wantErr:     true,
 			errContains: "end_at must be after start_at",
 		},
+		{
+			name:       "error when utm template uses unknown placeholder",
+			campaignID: campaignID,
+			req: dto.UpdateCampaignRequest{
+				UTMTerm: strPtr("{{sku}}"),
+			},
+			setupMock: func() {
+				campaign := &model.Campaign{
+					ID:          campaignID,
+					Name:        "Test Campaign",
+					UTMCampaign: "test_campaign",
+					StartAt:     startAt,
+					EndAt:       endAt,
+				}
+				suite.campaignRepo.On("FindByID", suite.ctx, campaignID).
+					Return(campaign, nil).Once()
+			},
+			wantErr:     true,
+			errContains: "invalid utm_term",
+		},
+		{
+			name:       "error when fallback policy has no fallback url",
+			campaignID: campaignID,
+			req: dto.UpdateCampaignRequest{
+				OutOfWindowPolicy: strPtr("fallback"),
+			},
+			setupMock: func() {
+				campaign := &model.Campaign{
+					ID:                campaignID,
+					Name:              "Test Campaign",
+					UTMCampaign:       "test_campaign",
+					StartAt:           startAt,
+					EndAt:             endAt,
+					OutOfWindowPolicy: model.OutOfWindowRedirect,
+				}
+				suite.campaignRepo.On("FindByID", suite.ctx, campaignID).
+					Return(campaign, nil).Once()
+			},
+			wantErr:     true,
+			errContains: "invalid fallback_url: required by the fallback policy",
+		},
 	}
 
 	for _, tt := range tests {
@@ -859,6 +1217,146 @@ func (suite *CampaignServiceTestSuite) TestCampaignService_UpdateCampaign() {
 	}
 }
 
+// TestCampaignService_UpdateCampaign_InvalidatesLinkCache tests that policy edits reach cached links
+func (suite *CampaignServiceTestSuite) TestCampaignService_UpdateCampaign_InvalidatesLinkCache() {
+	campaignID := uuid.New()
+	campaign := &model.Campaign{
+		ID:                campaignID,
+		Name:              "Test Campaign",
+		UTMCampaign:       "test_campaign",
+		StartAt:           time.Now().Add(-24 * time.Hour),
+		EndAt:             time.Now().Add(30 * 24 * time.Hour),
+		OutOfWindowPolicy: model.OutOfWindowRedirect,
+	}
+	linkCache := new(MockLinkCache)
+	suite.service.linkCache = linkCache
+
+	suite.campaignRepo.On("FindByID", suite.ctx, campaignID).Return(campaign, nil)
+	suite.campaignRepo.On("Update", suite.ctx, mock.MatchedBy(func(c *model.Campaign) bool {
+		return c.OutOfWindowPolicy == model.OutOfWindowGone
+	})).Return(nil).Once()
+	linkCache.On("InvalidateCampaignLinks", suite.ctx, campaignID).Return().Once()
+
+	_, err := suite.service.UpdateCampaign(suite.ctx, campaignID, dto.UpdateCampaignRequest{OutOfWindowPolicy: strPtr("gone")})
+	suite.Require().NoError(err)
+	linkCache.AssertExpectations(suite.T())
+}
+
+// TestCampaignService_UpdateCampaignProducts_ShortCodes tests vanity short codes in campaign link sync
+func (suite *CampaignServiceTestSuite) TestCampaignService_UpdateCampaignProducts_ShortCodes() {
+	campaignID := uuid.New()
+	productID := uuid.New()
+	campaign := &model.Campaign{ID: campaignID, UTMCampaign: "summer", UTMSource: "affiliate", UTMMedium: "affiliate"}
+	offer := &model.Offer{ProductID: productID, Marketplace: model.MarketplaceLazada, MarketplaceProductURL: "https://www.lazada.co.th/products/matcha-i1.html"}
+	shortCodes := []dto.LinkShortCode{{ProductID: productID, Marketplace: "lazada", ShortCode: "Matcha-Summer"}}
+
+	suite.Run("new link uses the vanity code", func() {
+		suite.SetupTest()
+		suite.campaignRepo.On("FindByID", suite.ctx, campaignID).Return(campaign, nil)
+		suite.linkRepo.On("ShortCodeExists", suite.ctx, "matcha-summer").Return(false, nil)
+		suite.campaignRepo.On("UpdateCampaignProducts", suite.ctx, campaignID, []uuid.UUID{productID}).Return(nil)
+		suite.linkRepo.On("DeleteByCampaignIDAndNotInProducts", suite.ctx, campaignID, []uuid.UUID{productID}).Return(nil)
+		suite.offerRepo.On("FindByProductID", suite.ctx, productID).Return([]*model.Offer{offer}, nil)
+		suite.linkRepo.On("FindByProductIDAndCampaignID", suite.ctx, productID, campaignID).Return([]*model.Link{}, nil)
+		suite.linkRepo.On("Create", suite.ctx, mock.MatchedBy(func(l *model.Link) bool {
+			return l.ShortCode == "matcha-summer" && l.Marketplace == model.MarketplaceLazada
+		})).Return(nil).Once()
+
+		err := suite.service.UpdateCampaignProducts(suite.ctx, campaignID, []uuid.UUID{productID}, shortCodes)
+		assert.NoError(suite.T(), err)
+		suite.linkRepo.AssertExpectations(suite.T())
+	})
+
+	suite.Run("existing link is renamed", func() {
+		suite.SetupTest()
+		existing := &model.Link{ID: uuid.New(), ProductID: productID, CampaignID: campaignID, Marketplace: model.MarketplaceLazada, ShortCode: "Ab12Cd34"}
+		suite.campaignRepo.On("FindByID", suite.ctx, campaignID).Return(campaign, nil)
+		suite.linkRepo.On("ShortCodeExists", suite.ctx, "matcha-summer").Return(false, nil)
+		suite.campaignRepo.On("UpdateCampaignProducts", suite.ctx, campaignID, []uuid.UUID{productID}).Return(nil)
+		suite.linkRepo.On("DeleteByCampaignIDAndNotInProducts", suite.ctx, campaignID, []uuid.UUID{productID}).Return(nil)
+		suite.offerRepo.On("FindByProductID", suite.ctx, productID).Return([]*model.Offer{offer}, nil)
+		suite.linkRepo.On("FindByProductIDAndCampaignID", suite.ctx, productID, campaignID).Return([]*model.Link{existing}, nil)
+		suite.linkRepo.On("ChangeShortCode", suite.ctx, existing, "matcha-summer").Return(nil).Once()
+		suite.linkRepo.On("Update", suite.ctx, existing).Return(nil)
+
+		err := suite.service.UpdateCampaignProducts(suite.ctx, campaignID, []uuid.UUID{productID}, shortCodes)
+		assert.NoError(suite.T(), err)
+		suite.linkRepo.AssertCalled(suite.T(), "ChangeShortCode", suite.ctx, existing, "matcha-summer")
+		suite.linkRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+	})
+
+	suite.Run("code used by another link is a conflict", func() {
+		suite.SetupTest()
+		other := &model.Link{ID: uuid.New(), ProductID: uuid.New(), CampaignID: uuid.New(), Marketplace: model.MarketplaceLazada, ShortCode: "matcha-summer"}
+		suite.campaignRepo.On("FindByID", suite.ctx, campaignID).Return(campaign, nil)
+		suite.linkRepo.On("ShortCodeExists", suite.ctx, "matcha-summer").Return(true, nil)
+		suite.linkRepo.On("FindByShortCode", suite.ctx, "matcha-summer").Return(other, nil)
+
+		err := suite.service.UpdateCampaignProducts(suite.ctx, campaignID, []uuid.UUID{productID}, shortCodes)
+		assert.EqualError(suite.T(), err, "short code already in use: matcha-summer")
+		suite.campaignRepo.AssertNotCalled(suite.T(), "UpdateCampaignProducts", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	suite.Run("invalid code", func() {
+		suite.SetupTest()
+		suite.campaignRepo.On("FindByID", suite.ctx, campaignID).Return(campaign, nil)
+
+		err := suite.service.UpdateCampaignProducts(suite.ctx, campaignID, []uuid.UUID{productID},
+			[]dto.LinkShortCode{{ProductID: productID, Marketplace: "lazada", ShortCode: "go"}})
+		assert.ErrorContains(suite.T(), err, "invalid short_code")
+	})
+}
+
+func (suite *CampaignServiceTestSuite) TestCampaignService_UpdateCampaignProducts_BestPriceLink() {
+	campaignID := uuid.New()
+	productID := uuid.New()
+	offers := []*model.Offer{
+		{ProductID: productID, Marketplace: model.MarketplaceLazada, Price: 299, MarketplaceProductURL: "https://www.lazada.co.th/products/matcha-i1.html"},
+		{ProductID: productID, Marketplace: model.MarketplaceShopee, Price: 259, MarketplaceProductURL: "https://shopee.co.th/product/1/2"},
+	}
+
+	suite.Run("enabled campaigns sync a best-price link", func() {
+		suite.SetupTest()
+		campaign := &model.Campaign{ID: campaignID, UTMCampaign: "summer", UTMSource: "affiliate", UTMContent: "{{marketplace}}", BestPriceLink: true}
+		suite.campaignRepo.On("FindByID", suite.ctx, campaignID).Return(campaign, nil)
+		suite.campaignRepo.On("UpdateCampaignProducts", suite.ctx, campaignID, []uuid.UUID{productID}).Return(nil)
+		suite.linkRepo.On("DeleteByCampaignIDAndNotInProducts", suite.ctx, campaignID, []uuid.UUID{productID}).Return(nil)
+		suite.offerRepo.On("FindByProductID", suite.ctx, productID).Return(offers, nil)
+		suite.linkRepo.On("FindByProductIDAndCampaignID", suite.ctx, productID, campaignID).Return([]*model.Link{}, nil)
+		suite.linkRepo.On("ShortCodeExists", suite.ctx, mock.Anything).Return(false, nil)
+		suite.linkRepo.On("Create", suite.ctx, mock.Anything).Return(nil)
+
+		err := suite.service.UpdateCampaignProducts(suite.ctx, campaignID, []uuid.UUID{productID}, nil)
+		assert.NoError(suite.T(), err)
+		suite.linkRepo.AssertNumberOfCalls(suite.T(), "Create", 3)
+		suite.linkRepo.AssertCalled(suite.T(), "Create", suite.ctx, mock.MatchedBy(func(l *model.Link) bool {
+			return l.Marketplace == model.MarketplaceBestPrice &&
+				l.TargetURL == "https://shopee.co.th/product/1/2?utm_campaign=summer&utm_content=shopee&utm_source=affiliate"
+		}))
+	})
+
+	suite.Run("disabled campaigns remove it", func() {
+		suite.SetupTest()
+		campaign := &model.Campaign{ID: campaignID, UTMCampaign: "summer", UTMSource: "affiliate"}
+		existing := []*model.Link{
+			{ID: uuid.New(), Marketplace: model.MarketplaceLazada, TargetURL: "https://www.lazada.co.th/products/matcha-i1.html?utm_campaign=summer&utm_source=affiliate"},
+			{ID: uuid.New(), Marketplace: model.MarketplaceShopee, TargetURL: "https://shopee.co.th/product/1/2?utm_campaign=summer&utm_source=affiliate"},
+			{ID: uuid.New(), Marketplace: model.MarketplaceBestPrice, TargetURL: "https://shopee.co.th/product/1/2?utm_campaign=summer&utm_source=affiliate"},
+		}
+		suite.campaignRepo.On("FindByID", suite.ctx, campaignID).Return(campaign, nil)
+		suite.campaignRepo.On("UpdateCampaignProducts", suite.ctx, campaignID, []uuid.UUID{productID}).Return(nil)
+		suite.linkRepo.On("DeleteByCampaignIDAndNotInProducts", suite.ctx, campaignID, []uuid.UUID{productID}).Return(nil)
+		suite.offerRepo.On("FindByProductID", suite.ctx, productID).Return(offers, nil)
+		suite.linkRepo.On("FindByProductIDAndCampaignID", suite.ctx, productID, campaignID).Return(existing, nil)
+		suite.linkRepo.On("Delete", suite.ctx, existing[2].ID).Return(nil).Once()
+
+		err := suite.service.UpdateCampaignProducts(suite.ctx, campaignID, []uuid.UUID{productID}, nil)
+		assert.NoError(suite.T(), err)
+		suite.linkRepo.AssertExpectations(suite.T())
+		suite.linkRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+	})
+}
+
 func TestCampaignServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(CampaignServiceTestSuite))
 }
@@ -3,44 +3,97 @@ package service
 import (
 	"context"
 	"fmt"
-	"net"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/jonosize/affiliate-platform/internal/dto"
 	"github.com/jonosize/affiliate-platform/internal/logger"
 	"github.com/jonosize/affiliate-platform/internal/model"
 )
 
 // ClickService handles click tracking business logic
 type ClickService struct {
-	clickRepo ClickRepositoryInterface
-	linkRepo  LinkRepositoryInterface
-	logger    logger.Logger
+	clickRepo  ClickRepositoryInterface
+	linkRepo   LinkRepositoryInterface
+	clickQueue ClickQueue
+	botFilter  *BotFilter
+	dedupe     *ClickDeduplicator
+	logger     logger.Logger
 }
 
 // NewClickService creates a new click service
-func NewClickService(clickRepo ClickRepositoryInterface, linkRepo LinkRepositoryInterface, log logger.Logger) *ClickService {
+// When clickQueue is nil, clicks are inserted synchronously; when dedupe is nil,
+// every human click is counted as unique
+func NewClickService(clickRepo ClickRepositoryInterface, linkRepo LinkRepositoryInterface, clickQueue ClickQueue, botFilter *BotFilter, dedupe *ClickDeduplicator, log logger.Logger) *ClickService {
 	return &ClickService{
-		clickRepo: clickRepo,
-		linkRepo:  linkRepo,
-		logger:    log,
+		clickRepo:  clickRepo,
+		linkRepo:   linkRepo,
+		clickQueue: clickQueue,
+		botFilter:  botFilter,
+		dedupe:     dedupe,
+		logger:     log,
 	}
 }
 
 // TrackClick records a click event
-func (s *ClickService) TrackClick(ctx context.Context, linkID uuid.UUID, ipAddress net.IP, userAgent, referrer string) error {
+// With a click queue the click is enqueued for batched persistence and an
+// error means it was dropped; otherwise it is inserted immediately
+func (s *ClickService) TrackClick(ctx context.Context, linkID uuid.UUID, meta dto.ClickMetadata) error {
 	ipStr := ""
-	if ipAddress != nil {
-		ipStr = ipAddress.String()
+	if meta.IPAddress != nil {
+		ipStr = meta.IPAddress.String()
 	}
 
 	click := &model.Click{
-		LinkID:    linkID,
-		Timestamp: time.Now(),
-		IPAddress: ipStr,
-		UserAgent: userAgent,
-		Referrer:  referrer,
+		ID:          meta.ClickID, // Nil IDs are generated on insert
+		WorkspaceID: meta.WorkspaceID,
+		LinkID:      linkID,
+		Timestamp:   time.Now(),
+		IPAddress:   ipStr,
+		UserAgent:   meta.UserAgent,
+		Referrer:    meta.Referrer,
+		VisitorID:   meta.VisitorID,
+		Outcome:     model.ClickOutcomeRedirected,
+		VariantID:   meta.VariantID,
+		Source:      meta.Source,
+	}
+	if meta.Outcome != "" {
+		click.Outcome = model.ClickOutcome(meta.Outcome)
+		click.OutcomeReason = meta.OutcomeReason
+	}
+	if meta.Marketplace != "" {
+		marketplace := model.Marketplace(meta.Marketplace)
+		click.Marketplace = &marketplace
+	}
+
+	subIDs := make([]string, maxSubIDs)
+	copy(subIDs, meta.SubIDs)
+	click.Sub1, click.Sub2, click.Sub3, click.Sub4, click.Sub5 = subIDs[0], subIDs[1], subIDs[2], subIDs[3], subIDs[4]
+
+	// Classify bot traffic (stored, but excluded from analytics by default)
+	if s.botFilter != nil {
+		click.IsBot, click.BotReason = s.botFilter.Classify(meta)
+	}
+
+	// Bots never count as unique and don't open a dedupe window
+	click.IsUnique = !click.IsBot
+	if click.IsUnique && s.dedupe != nil {
+		unique, err := s.dedupe.IsUnique(ctx, linkID, meta.VisitorID)
+		if err != nil {
+			// Fail open: a store outage must not hide clicks from unique counts
+			s.logger.Warn("Failed to check click dedupe window", logger.Error(err), logger.String("link_id", linkID.String()))
+		} else {
+			click.IsUnique = unique
+		}
+	}
+
+	if s.clickQueue != nil {
+		if err := s.clickQueue.Enqueue(click); err != nil {
+			return fmt.Errorf("failed to track click: %w", err)
+		}
+		return nil
 	}
 
 	if err := s.clickRepo.Create(ctx, click); err != nil {
@@ -54,3 +107,152 @@ func (s *ClickService) TrackClick(ctx context.Context, linkID uuid.UUID, ipAddre
 func (s *ClickService) GetClickStats(ctx context.Context, linkID uuid.UUID) (int64, error) {
 	return s.clickRepo.CountByLinkID(ctx, linkID)
 }
+
+const (
+	// StatsIntervalHour buckets link stats by hour
+	StatsIntervalHour = "hour"
+	// StatsIntervalDay buckets link stats by day
+	StatsIntervalDay = "day"
+
+	// defaultStatsWindow is the time range used when from is not provided
+	defaultStatsWindow = 30 * 24 * time.Hour
+	// maxHourlyStatsWindow caps hourly series to a reasonable number of buckets
+	maxHourlyStatsWindow = 31 * 24 * time.Hour
+	// topReferrersLimit is the number of referrer hosts returned in link stats
+	topReferrersLimit = 10
+)
+
+// GetLinkStats returns click statistics for a link: totals, a bucketed time series,
+// and referrer, browser and device breakdowns
+func (s *ClickService) GetLinkStats(ctx context.Context, linkID uuid.UUID, params dto.LinkStatsQueryParams) (*dto.LinkStatsResponse, error) {
+	link, err := s.linkRepo.FindByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	interval := params.Interval
+	if interval == "" {
+		interval = StatsIntervalDay
+	}
+	if interval != StatsIntervalHour && interval != StatsIntervalDay {
+		return nil, fmt.Errorf("invalid interval: must be %s or %s", StatsIntervalHour, StatsIntervalDay)
+	}
+
+	to := time.Now()
+	if params.To != nil {
+		to = *params.To
+	}
+	from := to.Add(-defaultStatsWindow)
+	if params.From != nil {
+		from = *params.From
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("invalid date range: to must be after from")
+	}
+	if interval == StatsIntervalHour && to.Sub(from) > maxHourlyStatsWindow {
+		return nil, fmt.Errorf("invalid date range: hourly stats are limited to 31 days")
+	}
+
+	totalClicks, err := s.clickRepo.CountByLinkIDAndTimeRange(ctx, linkID, from, to, params.IncludeBots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count clicks: %w", err)
+	}
+
+	uniqueClicks, err := s.clickRepo.CountUniqueByLinkIDAndTimeRange(ctx, linkID, from, to, params.IncludeBots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unique clicks: %w", err)
+	}
+
+	buckets, err := s.clickRepo.CountByLinkIDGroupedByInterval(ctx, linkID, interval, from, to, params.IncludeBots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get click series: %w", err)
+	}
+
+	referrers, err := s.clickRepo.CountByReferrerForLink(ctx, linkID, from, to, topReferrersLimit, params.IncludeBots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referrer breakdown: %w", err)
+	}
+
+	userAgents, err := s.clickRepo.CountByUserAgentForLink(ctx, linkID, from, to, params.IncludeBots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user agent breakdown: %w", err)
+	}
+
+	outcomes, err := s.clickRepo.CountByOutcomeForLink(ctx, linkID, from, to, params.IncludeBots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outcome breakdown: %w", err)
+	}
+
+	sources, err := s.clickRepo.CountBySourceForLink(ctx, linkID, from, to, params.IncludeBots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source breakdown: %w", err)
+	}
+
+	response := &dto.LinkStatsResponse{
+		LinkID:       link.ID,
+		ShortCode:    link.ShortCode,
+		Marketplace:  string(link.Marketplace),
+		From:         from,
+		To:           to,
+		Interval:     interval,
+		TotalClicks:  totalClicks,
+		UniqueClicks: uniqueClicks,
+		Series:       make([]dto.ClickTimeBucket, len(buckets)),
+		Referrers:    toClickBreakdowns(referrers, totalClicks),
+		Outcomes:     toClickBreakdowns(outcomes, totalClicks),
+		Sources:      toClickBreakdowns(sources, totalClicks),
+	}
+
+	for i, b := range buckets {
+		response.Series[i] = dto.ClickTimeBucket{
+			Bucket:       b.Bucket,
+			Clicks:       b.Clicks,
+			UniqueClicks: b.UniqueClicks,
+		}
+	}
+
+	// Collapse raw user agents into browser families and device types
+	browsers := make(map[string]int64)
+	devices := make(map[string]int64)
+	for _, ua := range userAgents {
+		browser, device := classifyUserAgent(ua.Value)
+		browsers[browser] += ua.Clicks
+		devices[device] += ua.Clicks
+	}
+	response.Browsers = toClickBreakdowns(sortedBreakdownResults(browsers), totalClicks)
+	response.Devices = toClickBreakdowns(sortedBreakdownResults(devices), totalClicks)
+
+	return response, nil
+}
+
+// sortedBreakdownResults converts aggregated counts into results ordered by clicks, then value
+func sortedBreakdownResults(counts map[string]int64) []model.ClickBreakdownResult {
+	results := make([]model.ClickBreakdownResult, 0, len(counts))
+	for value, clicks := range counts {
+		results = append(results, model.ClickBreakdownResult{Value: value, Clicks: clicks})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Clicks != results[j].Clicks {
+			return results[i].Clicks > results[j].Clicks
+		}
+		return results[i].Value < results[j].Value
+	})
+	return results
+}
+
+// toClickBreakdowns converts breakdown results into DTOs with their share of total clicks
+func toClickBreakdowns(results []model.ClickBreakdownResult, total int64) []dto.ClickBreakdown {
+	breakdowns := make([]dto.ClickBreakdown, len(results))
+	for i, r := range results {
+		percentage := 0.0
+		if total > 0 {
+			percentage = (float64(r.Clicks) / float64(total)) * 100.0
+		}
+		breakdowns[i] = dto.ClickBreakdown{
+			Value:      r.Value,
+			Clicks:     r.Clicks,
+			Percentage: percentage,
+		}
+	}
+	return breakdowns
+}
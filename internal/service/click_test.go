@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// MockClickRepository is a mock implementation of ClickRepositoryInterface
+type MockClickRepository struct {
+	mock.Mock
+}
+
+func (m *MockClickRepository) Create(ctx context.Context, click *model.Click) error {
+	args := m.Called(ctx, click)
+	return args.Error(0)
+}
+
+func (m *MockClickRepository) CreateBatch(ctx context.Context, clicks []*model.Click) error {
+	args := m.Called(ctx, clicks)
+	return args.Error(0)
+}
+
+func (m *MockClickRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Click, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Click), args.Error(1)
+}
+
+func (m *MockClickRepository) CountByLinkID(ctx context.Context, linkID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, linkID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockClickRepository) CountByLinkIDAndTimeRange(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) (int64, error) {
+	args := m.Called(ctx, linkID, startAt, endAt, includeBots)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockClickRepository) CountUniqueByLinkIDAndTimeRange(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) (int64, error) {
+	args := m.Called(ctx, linkID, startAt, endAt, includeBots)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockClickRepository) CountByLinkIDGroupedByInterval(ctx context.Context, linkID uuid.UUID, interval string, startAt, endAt time.Time, includeBots bool) ([]model.ClickTimeBucketResult, error) {
+	args := m.Called(ctx, linkID, interval, startAt, endAt, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ClickTimeBucketResult), args.Error(1)
+}
+
+func (m *MockClickRepository) CountByReferrerForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, limit int, includeBots bool) ([]model.ClickBreakdownResult, error) {
+	args := m.Called(ctx, linkID, startAt, endAt, limit, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ClickBreakdownResult), args.Error(1)
+}
+
+func (m *MockClickRepository) CountByOutcomeForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.ClickBreakdownResult, error) {
+	args := m.Called(ctx, linkID, startAt, endAt, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ClickBreakdownResult), args.Error(1)
+}
+
+func (m *MockClickRepository) CountBySourceForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.ClickBreakdownResult, error) {
+	args := m.Called(ctx, linkID, startAt, endAt, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ClickBreakdownResult), args.Error(1)
+}
+
+func (m *MockClickRepository) CountByVariantForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.VariantClickResult, error) {
+	args := m.Called(ctx, linkID, startAt, endAt, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.VariantClickResult), args.Error(1)
+}
+
+func (m *MockClickRepository) CountByUserAgentForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.ClickBreakdownResult, error) {
+	args := m.Called(ctx, linkID, startAt, endAt, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ClickBreakdownResult), args.Error(1)
+}
+
+func (m *MockClickRepository) CountByCampaignID(ctx context.Context, campaignID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, campaignID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockClickRepository) FindRecentClicks(ctx context.Context, limit int, includeBots bool) ([]model.Click, error) {
+	args := m.Called(ctx, limit, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Click), args.Error(1)
+}
+
+func (m *MockClickRepository) CountWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) (int64, error) {
+	args := m.Called(ctx, campaignID, marketplace, startDate, endDate, includeBots)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockClickRepository) CountUniqueWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) (int64, error) {
+	args := m.Called(ctx, campaignID, marketplace, startDate, endDate, includeBots)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockClickRepository) CountByCampaignWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.CampaignStatResult, error) {
+	args := m.Called(ctx, campaignID, marketplace, startDate, endDate, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CampaignStatResult), args.Error(1)
+}
+
+func (m *MockClickRepository) CountByMarketplaceWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.MarketplaceStatResult, error) {
+	args := m.Called(ctx, campaignID, marketplace, startDate, endDate, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.MarketplaceStatResult), args.Error(1)
+}
+
+func (m *MockClickRepository) CountBySourceWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.SourceStatResult, error) {
+	args := m.Called(ctx, campaignID, marketplace, startDate, endDate, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SourceStatResult), args.Error(1)
+}
+
+func (m *MockClickRepository) FindTopProductsWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, limit int, includeBots bool) ([]model.TopProductResult, error) {
+	args := m.Called(ctx, campaignID, marketplace, startDate, endDate, limit, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TopProductResult), args.Error(1)
+}
+
+func TestClassifyUserAgent(t *testing.T) {
+	tests := []struct {
+		name        string
+		userAgent   string
+		wantBrowser string
+		wantDevice  string
+	}{
+		{
+			name:        "chrome on windows",
+			userAgent:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			wantBrowser: "Chrome",
+			wantDevice:  "desktop",
+		},
+		{
+			name:        "safari on iphone",
+			userAgent:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+			wantBrowser: "Safari",
+			wantDevice:  "mobile",
+		},
+		{
+			name:        "facebook in-app browser on android",
+			userAgent:   "Mozilla/5.0 (Linux; Android 13; SM-S911B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36 [FB_IAB/FB4A;FBAV/444.0.0.0;]",
+			wantBrowser: "Facebook",
+			wantDevice:  "mobile",
+		},
+		{
+			name:        "line in-app browser",
+			userAgent:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 Safari Line/13.20.0",
+			wantBrowser: "LINE",
+			wantDevice:  "mobile",
+		},
+		{
+			name:        "edge is not chrome",
+			userAgent:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			wantBrowser: "Edge",
+			wantDevice:  "desktop",
+		},
+		{
+			name:        "android tablet",
+			userAgent:   "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			wantBrowser: "Chrome",
+			wantDevice:  "tablet",
+		},
+		{
+			name:        "crawler",
+			userAgent:   "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			wantBrowser: "Other",
+			wantDevice:  "bot",
+		},
+		{
+			name:        "empty",
+			userAgent:   "",
+			wantBrowser: "Unknown",
+			wantDevice:  "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			browser, device := classifyUserAgent(tt.userAgent)
+			assert.Equal(t, tt.wantBrowser, browser)
+			assert.Equal(t, tt.wantDevice, device)
+		})
+	}
+}
+
+func TestClickService_GetLinkStats(t *testing.T) {
+	ctx := context.Background()
+	linkID := uuid.New()
+	link := &model.Link{ID: linkID, ShortCode: "abc123", Marketplace: model.MarketplaceLazada}
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+
+	t.Run("aggregates totals, series and breakdowns", func(t *testing.T) {
+		clickRepo := new(MockClickRepository)
+		linkRepo := new(MockLinkRepository)
+		svc := NewClickService(clickRepo, linkRepo, nil, nil, nil, log)
+
+		linkRepo.On("FindByID", ctx, linkID).Return(link, nil)
+		clickRepo.On("CountByLinkIDAndTimeRange", ctx, linkID, from, to, false).Return(int64(4), nil)
+		clickRepo.On("CountUniqueByLinkIDAndTimeRange", ctx, linkID, from, to, false).Return(int64(3), nil)
+		clickRepo.On("CountByLinkIDGroupedByInterval", ctx, linkID, StatsIntervalDay, from, to, false).Return([]model.ClickTimeBucketResult{
+			{Bucket: from, Clicks: 3, UniqueClicks: 2},
+			{Bucket: from.AddDate(0, 0, 1), Clicks: 1, UniqueClicks: 1},
+		}, nil)
+		clickRepo.On("CountByReferrerForLink", ctx, linkID, from, to, topReferrersLimit, false).Return([]model.ClickBreakdownResult{
+			{Value: "www.facebook.com", Clicks: 3},
+			{Value: "(direct)", Clicks: 1},
+		}, nil)
+		clickRepo.On("CountByUserAgentForLink", ctx, linkID, from, to, false).Return([]model.ClickBreakdownResult{
+			{Value: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1", Clicks: 2},
+			{Value: "Mozilla/5.0 (iPhone; CPU iPhone OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1", Clicks: 1},
+			{Value: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", Clicks: 1},
+		}, nil)
+		clickRepo.On("CountByOutcomeForLink", ctx, linkID, from, to, false).Return([]model.ClickBreakdownResult{
+			{Value: "redirected", Clicks: 3},
+			{Value: "gone", Clicks: 1},
+		}, nil)
+		clickRepo.On("CountBySourceForLink", ctx, linkID, from, to, false).Return([]model.ClickBreakdownResult{
+			{Value: "(none)", Clicks: 3},
+			{Value: "qr", Clicks: 1},
+		}, nil)
+
+		stats, err := svc.GetLinkStats(ctx, linkID, dto.LinkStatsQueryParams{From: &from, To: &to})
+		require.NoError(t, err)
+
+		assert.Equal(t, "abc123", stats.ShortCode)
+		assert.Equal(t, StatsIntervalDay, stats.Interval)
+		assert.Equal(t, int64(4), stats.TotalClicks)
+		assert.Equal(t, int64(3), stats.UniqueClicks)
+		require.Len(t, stats.Series, 2)
+		assert.Equal(t, int64(3), stats.Series[0].Clicks)
+		require.Len(t, stats.Referrers, 2)
+		assert.Equal(t, 75.0, stats.Referrers[0].Percentage)
+		assert.Equal(t, []dto.ClickBreakdown{
+			{Value: "Safari", Clicks: 3, Percentage: 75},
+			{Value: "Chrome", Clicks: 1, Percentage: 25},
+		}, stats.Browsers)
+		assert.Equal(t, []dto.ClickBreakdown{
+			{Value: "mobile", Clicks: 3, Percentage: 75},
+			{Value: "desktop", Clicks: 1, Percentage: 25},
+		}, stats.Devices)
+		assert.Equal(t, []dto.ClickBreakdown{
+			{Value: "redirected", Clicks: 3, Percentage: 75},
+			{Value: "gone", Clicks: 1, Percentage: 25},
+		}, stats.Outcomes)
+		assert.Equal(t, []dto.ClickBreakdown{
+			{Value: "(none)", Clicks: 3, Percentage: 75},
+			{Value: "qr", Clicks: 1, Percentage: 25},
+		}, stats.Sources)
+		clickRepo.AssertExpectations(t)
+	})
+
+	t.Run("link not found", func(t *testing.T) {
+		linkRepo := new(MockLinkRepository)
+		svc := NewClickService(new(MockClickRepository), linkRepo, nil, nil, nil, log)
+		linkRepo.On("FindByID", ctx, linkID).Return(nil, errors.New("record not found"))
+
+		_, err := svc.GetLinkStats(ctx, linkID, dto.LinkStatsQueryParams{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "link not found")
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		linkRepo := new(MockLinkRepository)
+		svc := NewClickService(new(MockClickRepository), linkRepo, nil, nil, nil, log)
+		linkRepo.On("FindByID", ctx, linkID).Return(link, nil)
+
+		_, err := svc.GetLinkStats(ctx, linkID, dto.LinkStatsQueryParams{Interval: "week"})
+		assert.ErrorContains(t, err, "invalid interval")
+
+		_, err = svc.GetLinkStats(ctx, linkID, dto.LinkStatsQueryParams{From: &to, To: &from})
+		assert.ErrorContains(t, err, "invalid date range")
+
+		longFrom := to.AddDate(0, -2, 0)
+		_, err = svc.GetLinkStats(ctx, linkID, dto.LinkStatsQueryParams{Interval: StatsIntervalHour, From: &longFrom, To: &to})
+		assert.ErrorContains(t, err, "invalid date range")
+	})
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/config"
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// PostbackSignatureParam is the query/form parameter carrying the postback signature
+// (networks that can't send custom headers use it instead of PostbackHeaderSignature)
+const PostbackSignatureParam = "signature"
+
+// PostbackHeaderSignature is the header carrying the postback signature
+const PostbackHeaderSignature = "X-Postback-Signature"
+
+// postbackNetworkPattern restricts network names, which are used as config keys
+var postbackNetworkPattern = regexp.MustCompile(`^[a-z0-9_-]{1,50}$`)
+
+// ConversionService handles conversion postback business logic
+type ConversionService struct {
+	conversionRepo ConversionRepositoryInterface
+	clickRepo      ClickRepositoryInterface
+	linkRepo       LinkRepositoryInterface
+	config         config.Config
+	logger         logger.Logger
+}
+
+// NewConversionService creates a new conversion service
+func NewConversionService(
+	conversionRepo ConversionRepositoryInterface,
+	clickRepo ClickRepositoryInterface,
+	linkRepo LinkRepositoryInterface,
+	cfg config.Config,
+	log logger.Logger,
+) *ConversionService {
+	return &ConversionService{
+		conversionRepo: conversionRepo,
+		clickRepo:      clickRepo,
+		linkRepo:       linkRepo,
+		config:         cfg,
+		logger:         log,
+	}
+}
+
+// HandlePostback verifies and records a conversion postback from an affiliate network
+// The first postback for an order creates the conversion; later ones update it
+// while it is pending. Approved and rejected conversions are final.
+func (s *ConversionService) HandlePostback(ctx context.Context, network string, params url.Values, signature string) (*dto.ConversionResponse, error) {
+	network = strings.ToLower(network)
+	if !postbackNetworkPattern.MatchString(network) {
+		return nil, fmt.Errorf("postback network not found: %s", network)
+	}
+	secret := s.config.GetPostbackSecret(network)
+	if secret == "" {
+		return nil, fmt.Errorf("postback network not found: %s", network)
+	}
+
+	// Verify before parsing so unsigned requests learn nothing about the payload
+	if signature == "" {
+		signature = params.Get(PostbackSignatureParam)
+	}
+	if !VerifyPostbackSignature(secret, params, signature) {
+		return nil, fmt.Errorf("postback signature mismatch")
+	}
+
+	postback, err := parsePostback(params)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.conversionRepo.FindByNetworkAndOrderID(ctx, network, postback.orderID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get conversion: %w", err)
+	}
+
+	if existing != nil {
+		return s.updateConversion(ctx, existing, postback)
+	}
+
+	conversion := &model.Conversion{
+		Network:         network,
+		OrderID:         postback.orderID,
+		ClickRef:        postback.clickRef,
+		Amount:          postback.amount,
+		Commission:      postback.commission,
+		Currency:        postback.currency,
+		Status:          postback.status,
+		StatusUpdatedAt: time.Now(),
+	}
+	s.attribute(ctx, conversion)
+
+	if err := s.conversionRepo.Create(ctx, conversion); err != nil {
+		return nil, fmt.Errorf("failed to create conversion: %w", err)
+	}
+
+	s.logger.Info("Conversion recorded",
+		logger.String("network", network),
+		logger.String("order_id", conversion.OrderID),
+		logger.String("status", string(conversion.Status)),
+		logger.Bool("attributed", conversion.LinkID != nil),
+	)
+
+	return toConversionResponse(conversion), nil
+}
+
+// updateConversion applies a repeated postback to an existing conversion
+func (s *ConversionService) updateConversion(ctx context.Context, conversion *model.Conversion, postback *postbackParams) (*dto.ConversionResponse, error) {
+	if conversion.Status != model.ConversionPending {
+		// Networks retry postbacks; a repeat of the final status is not an error
+		if postback.status == conversion.Status {
+			return toConversionResponse(conversion), nil
+		}
+		return nil, fmt.Errorf("status transition not allowed: %s to %s", conversion.Status, postback.status)
+	}
+
+	if postback.status != conversion.Status {
+		conversion.Status = postback.status
+		conversion.StatusUpdatedAt = time.Now()
+	}
+	// Networks may adjust amounts until the order is final (e.g. partial refunds)
+	conversion.Amount = postback.amount
+	conversion.Commission = postback.commission
+	if postback.currency != "" {
+		conversion.Currency = postback.currency
+	}
+	if conversion.LinkID == nil && postback.clickRef != "" {
+		conversion.ClickRef = postback.clickRef
+		s.attribute(ctx, conversion)
+	}
+
+	if err := s.conversionRepo.Update(ctx, conversion); err != nil {
+		return nil, fmt.Errorf("failed to update conversion: %w", err)
+	}
+
+	s.logger.Info("Conversion updated",
+		logger.String("network", conversion.Network),
+		logger.String("order_id", conversion.OrderID),
+		logger.String("status", string(conversion.Status)),
+	)
+
+	return toConversionResponse(conversion), nil
+}
+
+// attribute resolves the conversion's click reference to a click, or to a link
+// for networks that only echo a per-link sub ID, and copies link, campaign,
+// product and marketplace from it. Unmatched references are kept unattributed.
+func (s *ConversionService) attribute(ctx context.Context, conversion *model.Conversion) {
+	ref, err := uuid.Parse(conversion.ClickRef)
+	if err != nil {
+		return
+	}
+
+	linkID := ref
+	var clickMarketplace *model.Marketplace
+	if click, err := s.clickRepo.FindByID(ctx, ref); err == nil {
+		conversion.ClickID = &click.ID
+		linkID = click.LinkID
+		clickMarketplace = click.Marketplace
+	}
+
+	// Deleted links still earn: orders often arrive after a campaign is retired
+	link, err := s.linkRepo.FindByIDIncludingDeleted(ctx, linkID)
+	if err != nil {
+		s.logger.Warn("Conversion click reference not matched",
+			logger.String("network", conversion.Network),
+			logger.String("order_id", conversion.OrderID),
+			logger.String("click_ref", conversion.ClickRef),
+		)
+		conversion.ClickID = nil
+		return
+	}
+
+	// Best-price links record the marketplace each click was sent to
+	marketplace := link.Marketplace
+	if clickMarketplace != nil {
+		marketplace = *clickMarketplace
+	}
+	conversion.LinkID = &link.ID
+	conversion.CampaignID = &link.CampaignID
+	conversion.ProductID = &link.ProductID
+	conversion.Marketplace = &marketplace
+}
+
+// postbackParams holds the validated fields of a postback
+type postbackParams struct {
+	orderID    string
+	clickRef   string
+	amount     float64
+	commission float64
+	currency   string
+	status     model.ConversionStatus
+}
+
+// parsePostback validates postback parameters
+// The click reference is read from click_id, falling back to sub_id
+func parsePostback(params url.Values) (*postbackParams, error) {
+	p := &postbackParams{
+		orderID:  strings.TrimSpace(params.Get("order_id")),
+		clickRef: strings.TrimSpace(params.Get("click_id")),
+		currency: strings.ToUpper(strings.TrimSpace(params.Get("currency"))),
+		status:   model.ConversionPending,
+	}
+
+	if p.orderID == "" {
+		return nil, fmt.Errorf("invalid postback: order_id is required")
+	}
+	if len(p.orderID) > 100 {
+		return nil, fmt.Errorf("invalid postback: order_id must be at most 100 characters")
+	}
+	if p.clickRef == "" {
+		p.clickRef = strings.TrimSpace(params.Get("sub_id"))
+	}
+	if len(p.clickRef) > 100 {
+		return nil, fmt.Errorf("invalid postback: click_id must be at most 100 characters")
+	}
+	if p.currency != "" && len(p.currency) != 3 {
+		return nil, fmt.Errorf("invalid postback: currency must be a 3-letter code")
+	}
+
+	var err error
+	if p.amount, err = parsePostbackAmount(params, "amount"); err != nil {
+		return nil, err
+	}
+	if p.commission, err = parsePostbackAmount(params, "commission"); err != nil {
+		return nil, err
+	}
+
+	if status := strings.ToLower(strings.TrimSpace(params.Get("status"))); status != "" {
+		switch model.ConversionStatus(status) {
+		case model.ConversionPending, model.ConversionApproved, model.ConversionRejected:
+			p.status = model.ConversionStatus(status)
+		default:
+			return nil, fmt.Errorf("invalid postback: status must be pending, approved or rejected")
+		}
+	}
+
+	return p, nil
+}
+
+// parsePostbackAmount parses an optional non-negative money parameter
+func parsePostbackAmount(params url.Values, name string) (float64, error) {
+	raw := strings.TrimSpace(params.Get(name))
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid postback: %s must be a non-negative number", name)
+	}
+	return value, nil
+}
+
+// SignPostback computes the hex HMAC-SHA256 of the postback parameters with the network secret
+// Parameters are canonicalized as a sorted, URL-encoded query string without the signature itself
+func SignPostback(secret string, params url.Values) string {
+	unsigned := make(url.Values, len(params))
+	for key, values := range params {
+		if key != PostbackSignatureParam {
+			unsigned[key] = values
+		}
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned.Encode()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyPostbackSignature reports whether signature (optionally "sha256=" prefixed)
+// matches the parameters, using a constant-time comparison
+func VerifyPostbackSignature(secret string, params url.Values, signature string) bool {
+	signature = strings.TrimPrefix(strings.TrimSpace(signature), "sha256=")
+	if signature == "" {
+		return false
+	}
+	expected := SignPostback(secret, params)
+	return hmac.Equal([]byte(strings.ToLower(signature)), []byte(expected))
+}
+
+// toConversionResponse converts a conversion model to DTO
+func toConversionResponse(c *model.Conversion) *dto.ConversionResponse {
+	var marketplace *string
+	if c.Marketplace != nil {
+		m := string(*c.Marketplace)
+		marketplace = &m
+	}
+	return &dto.ConversionResponse{
+		ID:          c.ID,
+		Network:     c.Network,
+		OrderID:     c.OrderID,
+		ClickRef:    c.ClickRef,
+		ClickID:     c.ClickID,
+		LinkID:      c.LinkID,
+		CampaignID:  c.CampaignID,
+		Marketplace: marketplace,
+		Attributed:  c.LinkID != nil,
+		Amount:      c.Amount,
+		Commission:  c.Commission,
+		Currency:    c.Currency,
+		Status:      string(c.Status),
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
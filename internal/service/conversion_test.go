@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// MockConversionRepository is a mock implementation of ConversionRepositoryInterface
+type MockConversionRepository struct {
+	mock.Mock
+}
+
+func (m *MockConversionRepository) Create(ctx context.Context, conversion *model.Conversion) error {
+	args := m.Called(ctx, conversion)
+	return args.Error(0)
+}
+
+func (m *MockConversionRepository) Update(ctx context.Context, conversion *model.Conversion) error {
+	args := m.Called(ctx, conversion)
+	return args.Error(0)
+}
+
+func (m *MockConversionRepository) FindByNetworkAndOrderID(ctx context.Context, network, orderID string) (*model.Conversion, error) {
+	args := m.Called(ctx, network, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Conversion), args.Error(1)
+}
+
+func (m *MockConversionRepository) SumWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time) (*model.ConversionSummaryResult, error) {
+	args := m.Called(ctx, campaignID, marketplace, startDate, endDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ConversionSummaryResult), args.Error(1)
+}
+
+func (m *MockConversionRepository) SumByCampaignWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time) ([]model.CampaignConversionResult, error) {
+	args := m.Called(ctx, campaignID, marketplace, startDate, endDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CampaignConversionResult), args.Error(1)
+}
+
+func (m *MockConversionRepository) SumByVariantForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.VariantConversionResult, error) {
+	args := m.Called(ctx, linkID, startAt, endAt, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.VariantConversionResult), args.Error(1)
+}
+
+const testPostbackSecret = "postback-secret"
+
+func newTestConversionService(t *testing.T) (*ConversionService, *MockConversionRepository, *MockClickRepository, *MockLinkRepository) {
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+
+	conversionRepo := new(MockConversionRepository)
+	clickRepo := new(MockClickRepository)
+	linkRepo := new(MockLinkRepository)
+	cfg := &MockConfig{postbackSecrets: map[string]string{"lazada": testPostbackSecret}}
+	return NewConversionService(conversionRepo, clickRepo, linkRepo, cfg, log), conversionRepo, clickRepo, linkRepo
+}
+
+func TestVerifyPostbackSignature(t *testing.T) {
+	params := url.Values{"order_id": {"ORD-1"}, "amount": {"100.00"}, "status": {"pending"}}
+	signature := SignPostback(testPostbackSecret, params)
+
+	assert.True(t, VerifyPostbackSignature(testPostbackSecret, params, signature))
+	assert.True(t, VerifyPostbackSignature(testPostbackSecret, params, "sha256="+signature))
+
+	// The signature parameter itself is not signed
+	withSignature := url.Values{"order_id": {"ORD-1"}, "amount": {"100.00"}, "status": {"pending"}, "signature": {signature}}
+	assert.True(t, VerifyPostbackSignature(testPostbackSecret, withSignature, signature))
+
+	tampered := url.Values{"order_id": {"ORD-1"}, "amount": {"999.00"}, "status": {"pending"}}
+	assert.False(t, VerifyPostbackSignature(testPostbackSecret, tampered, signature))
+	assert.False(t, VerifyPostbackSignature("other-secret", params, signature))
+	assert.False(t, VerifyPostbackSignature(testPostbackSecret, params, ""))
+}
+
+func TestConversionService_HandlePostback_Attributes(t *testing.T) {
+	ctx := context.Background()
+	svc, conversionRepo, clickRepo, linkRepo := newTestConversionService(t)
+
+	click := &model.Click{ID: uuid.New(), LinkID: uuid.New()}
+	link := &model.Link{ID: click.LinkID, CampaignID: uuid.New(), ProductID: uuid.New(), Marketplace: model.MarketplaceLazada}
+
+	params := url.Values{
+		"order_id":   {"ORD-1"},
+		"amount":     {"1299.00"},
+		"commission": {"64.95"},
+		"currency":   {"thb"},
+		"sub_id":     {click.ID.String()},
+	}
+	signature := SignPostback(testPostbackSecret, params)
+
+	conversionRepo.On("FindByNetworkAndOrderID", ctx, "lazada", "ORD-1").Return(nil, gorm.ErrRecordNotFound)
+	clickRepo.On("FindByID", ctx, click.ID).Return(click, nil)
+	linkRepo.On("FindByIDIncludingDeleted", ctx, link.ID).Return(link, nil)
+	conversionRepo.On("Create", ctx, mock.MatchedBy(func(c *model.Conversion) bool {
+		return *c.ClickID == click.ID && *c.LinkID == link.ID && *c.CampaignID == link.CampaignID &&
+			*c.ProductID == link.ProductID && *c.Marketplace == model.MarketplaceLazada &&
+			c.Status == model.ConversionPending && c.Commission == 64.95 && c.Currency == "THB"
+	})).Return(nil)
+
+	resp, err := svc.HandlePostback(ctx, "Lazada", params, signature)
+	require.NoError(t, err)
+	assert.True(t, resp.Attributed)
+	assert.Equal(t, "pending", resp.Status)
+
+	conversionRepo.AssertExpectations(t)
+	clickRepo.AssertExpectations(t)
+	linkRepo.AssertExpectations(t)
+}
+
+func TestConversionService_HandlePostback_BestPriceClick(t *testing.T) {
+	ctx := context.Background()
+	svc, conversionRepo, clickRepo, linkRepo := newTestConversionService(t)
+
+	shopee := model.MarketplaceShopee
+	click := &model.Click{ID: uuid.New(), LinkID: uuid.New(), Marketplace: &shopee}
+	link := &model.Link{ID: click.LinkID, CampaignID: uuid.New(), ProductID: uuid.New(), Marketplace: model.MarketplaceBestPrice}
+
+	params := url.Values{"order_id": {"ORD-2"}, "amount": {"259.00"}, "commission": {"12.95"}, "sub_id": {click.ID.String()}}
+	signature := SignPostback(testPostbackSecret, params)
+
+	conversionRepo.On("FindByNetworkAndOrderID", ctx, "lazada", "ORD-2").Return(nil, gorm.ErrRecordNotFound)
+	clickRepo.On("FindByID", ctx, click.ID).Return(click, nil)
+	linkRepo.On("FindByIDIncludingDeleted", ctx, link.ID).Return(link, nil)
+	conversionRepo.On("Create", ctx, mock.MatchedBy(func(c *model.Conversion) bool {
+		return *c.LinkID == link.ID && *c.Marketplace == model.MarketplaceShopee
+	})).Return(nil)
+
+	_, err := svc.HandlePostback(ctx, "lazada", params, signature)
+	require.NoError(t, err)
+	conversionRepo.AssertExpectations(t)
+}
+
+func TestConversionService_HandlePostback_Unattributed(t *testing.T) {
+	ctx := context.Background()
+	svc, conversionRepo, clickRepo, linkRepo := newTestConversionService(t)
+
+	ref := uuid.New()
+	params := url.Values{"order_id": {"ORD-2"}, "click_id": {ref.String()}}
+
+	conversionRepo.On("FindByNetworkAndOrderID", ctx, "lazada", "ORD-2").Return(nil, gorm.ErrRecordNotFound)
+	clickRepo.On("FindByID", ctx, ref).Return(nil, gorm.ErrRecordNotFound)
+	linkRepo.On("FindByIDIncludingDeleted", ctx, ref).Return(nil, gorm.ErrRecordNotFound)
+	conversionRepo.On("Create", ctx, mock.MatchedBy(func(c *model.Conversion) bool {
+		return c.ClickID == nil && c.LinkID == nil && c.ClickRef == ref.String()
+	})).Return(nil)
+
+	resp, err := svc.HandlePostback(ctx, "lazada", params, SignPostback(testPostbackSecret, params))
+	require.NoError(t, err)
+	assert.False(t, resp.Attributed)
+	conversionRepo.AssertExpectations(t)
+}
+
+func TestConversionService_HandlePostback_Rejects(t *testing.T) {
+	ctx := context.Background()
+	svc, conversionRepo, _, _ := newTestConversionService(t)
+	conversionRepo.On("FindByNetworkAndOrderID", ctx, "lazada", mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+	params := url.Values{"order_id": {"ORD-3"}}
+	signature := SignPostback(testPostbackSecret, params)
+
+	_, err := svc.HandlePostback(ctx, "shopee", params, signature)
+	assert.ErrorContains(t, err, "postback network not found")
+
+	_, err = svc.HandlePostback(ctx, "lazada", params, "deadbeef")
+	assert.ErrorContains(t, err, "signature mismatch")
+
+	bad := url.Values{"order_id": {"ORD-3"}, "status": {"paid"}}
+	_, err = svc.HandlePostback(ctx, "lazada", bad, SignPostback(testPostbackSecret, bad))
+	assert.ErrorContains(t, err, "invalid postback")
+
+	missing := url.Values{"amount": {"10"}}
+	_, err = svc.HandlePostback(ctx, "lazada", missing, SignPostback(testPostbackSecret, missing))
+	assert.ErrorContains(t, err, "invalid postback: order_id is required")
+
+	conversionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestConversionService_HandlePostback_StatusTransitions(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    model.ConversionStatus
+		next       string
+		wantErr    string
+		wantUpdate bool
+	}{
+		{name: "pending to approved", current: model.ConversionPending, next: "approved", wantUpdate: true},
+		{name: "pending to rejected", current: model.ConversionPending, next: "rejected", wantUpdate: true},
+		{name: "pending repeated", current: model.ConversionPending, next: "pending", wantUpdate: true},
+		{name: "approved repeated", current: model.ConversionApproved, next: "approved"},
+		{name: "approved to rejected", current: model.ConversionApproved, next: "rejected", wantErr: "status transition not allowed"},
+		{name: "rejected to pending", current: model.ConversionRejected, next: "pending", wantErr: "status transition not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc, conversionRepo, _, _ := newTestConversionService(t)
+
+			linkID := uuid.New()
+			existing := &model.Conversion{ID: uuid.New(), Network: "lazada", OrderID: "ORD-4", LinkID: &linkID, Status: tt.current, Commission: 10}
+			conversionRepo.On("FindByNetworkAndOrderID", ctx, "lazada", "ORD-4").Return(existing, nil)
+			conversionRepo.On("Update", ctx, existing).Return(nil)
+
+			params := url.Values{"order_id": {"ORD-4"}, "status": {tt.next}, "commission": {"12.50"}}
+			resp, err := svc.HandlePostback(ctx, "lazada", params, SignPostback(testPostbackSecret, params))
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				conversionRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.next, resp.Status)
+			if tt.wantUpdate {
+				conversionRepo.AssertCalled(t, "Update", ctx, existing)
+				assert.Equal(t, 12.50, existing.Commission)
+			} else {
+				conversionRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestConversionService_HandlePostback_LookupError(t *testing.T) {
+	ctx := context.Background()
+	svc, conversionRepo, _, _ := newTestConversionService(t)
+	conversionRepo.On("FindByNetworkAndOrderID", ctx, "lazada", "ORD-5").Return(nil, errors.New("connection refused"))
+
+	params := url.Values{"order_id": {"ORD-5"}}
+	_, err := svc.HandlePostback(ctx, "lazada", params, SignPostback(testPostbackSecret, params))
+	assert.ErrorContains(t, err, "failed to get conversion")
+}
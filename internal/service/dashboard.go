@@ -9,36 +9,52 @@ import (
 
 	"github.com/jonosize/affiliate-platform/internal/dto"
 	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	"github.com/jonosize/affiliate-platform/pkg/adapters"
 )
 
 // DashboardService handles dashboard analytics business logic
 type DashboardService struct {
-	clickRepo    ClickRepositoryInterface
-	linkRepo     LinkRepositoryInterface
-	campaignRepo CampaignRepositoryInterface
-	productRepo  ProductRepositoryInterface
-	logger       logger.Logger
+	clickRepo      ClickRepositoryInterface
+	impressionRepo ImpressionRepositoryInterface
+	conversionRepo ConversionRepositoryInterface
+	linkRepo       LinkRepositoryInterface
+	campaignRepo   CampaignRepositoryInterface
+	productRepo    ProductRepositoryInterface
+	registry       *adapters.Registry
+	logger         logger.Logger
 }
 
 // NewDashboardService creates a new dashboard service
 func NewDashboardService(
 	clickRepo ClickRepositoryInterface,
+	impressionRepo ImpressionRepositoryInterface,
+	conversionRepo ConversionRepositoryInterface,
 	linkRepo LinkRepositoryInterface,
 	campaignRepo CampaignRepositoryInterface,
 	productRepo ProductRepositoryInterface,
+	registry *adapters.Registry,
 	log logger.Logger,
 ) *DashboardService {
 	return &DashboardService{
-		clickRepo:    clickRepo,
-		linkRepo:     linkRepo,
-		campaignRepo: campaignRepo,
-		productRepo:  productRepo,
-		logger:       log,
+		clickRepo:      clickRepo,
+		impressionRepo: impressionRepo,
+		conversionRepo: conversionRepo,
+		linkRepo:       linkRepo,
+		campaignRepo:   campaignRepo,
+		productRepo:    productRepo,
+		registry:       registry,
+		logger:         log,
 	}
 }
 
 // GetDashboardStats returns aggregated dashboard statistics
 func (s *DashboardService) GetDashboardStats(ctx context.Context, params dto.DashboardQueryParams) (*dto.DashboardStatsResponse, error) {
+	// Validate marketplace filter
+	if params.Marketplace != nil && !s.registry.Has(adapters.Marketplace(*params.Marketplace)) {
+		return nil, fmt.Errorf("invalid marketplace: %s is not supported", *params.Marketplace)
+	}
+
 	// Build query conditions
 	startDate := time.Time{}
 	endDate := time.Now()
@@ -55,16 +71,28 @@ func (s *DashboardService) GetDashboardStats(ctx context.Context, params dto.Das
 		return nil, fmt.Errorf("failed to get total clicks: %w", err)
 	}
 
+	// Get unique clicks (repeat clicks within the dedupe window excluded)
+	uniqueClicks, err := s.clickRepo.CountUniqueWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate, params.IncludeBots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unique clicks: %w", err)
+	}
+
 	// Get total links
 	totalLinks, err := s.getTotalLinks(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total links: %w", err)
 	}
 
-	// Calculate CTR (Click-Through Rate)
-	ctr := 0.0
-	if totalLinks > 0 {
-		ctr = (float64(totalClicks) / float64(totalLinks)) * 100.0
+	// Get impressions (campaign page views and rendered links)
+	impressions, err := s.impressionRepo.CountWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate, params.IncludeBots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get impressions: %w", err)
+	}
+
+	// Get conversions (rejected orders excluded)
+	conversions, err := s.conversionRepo.SumWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversions: %w", err)
 	}
 
 	// Get campaign stats
@@ -79,6 +107,12 @@ func (s *DashboardService) GetDashboardStats(ctx context.Context, params dto.Das
 		return nil, fmt.Errorf("failed to get marketplace stats: %w", err)
 	}
 
+	// Get source stats (QR code scans vs other clicks)
+	sourceStats, err := s.getSourceStats(ctx, params, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source stats: %w", err)
+	}
+
 	// Get top products
 	topProducts, err := s.getTopProducts(ctx, params, startDate, endDate)
 	if err != nil {
@@ -93,10 +127,18 @@ func (s *DashboardService) GetDashboardStats(ctx context.Context, params dto.Das
 
 	return &dto.DashboardStatsResponse{
 		TotalClicks:      totalClicks,
+		UniqueClicks:     uniqueClicks,
 		TotalLinks:       totalLinks,
-		CTR:              ctr,
+		PageViews:        impressions.PageViews,
+		TotalImpressions: impressions.LinkImpressions,
+		CTR:              clickThroughRate(totalClicks, impressions.LinkImpressions),
+		Conversions:      conversions.Conversions,
+		Revenue:          conversions.Revenue,
+		EPC:              earningsPerClick(conversions.Revenue, totalClicks),
+		ConversionRate:   conversionRate(conversions.Conversions, totalClicks),
 		CampaignStats:    campaignStats,
 		MarketplaceStats: marketplaceStats,
+		SourceStats:      sourceStats,
 		TopProducts:      topProducts,
 		RecentClicks:     recentClicks,
 	}, nil
@@ -104,7 +146,7 @@ func (s *DashboardService) GetDashboardStats(ctx context.Context, params dto.Das
 
 // getTotalClicks gets total click count with filters
 func (s *DashboardService) getTotalClicks(ctx context.Context, params dto.DashboardQueryParams, startDate, endDate time.Time) (int64, error) {
-	return s.clickRepo.CountWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate)
+	return s.clickRepo.CountWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate, params.IncludeBots)
 }
 
 // getTotalLinks gets total link count with filters
@@ -114,17 +156,45 @@ func (s *DashboardService) getTotalLinks(ctx context.Context, params dto.Dashboa
 
 // getCampaignStats gets click statistics grouped by campaign
 func (s *DashboardService) getCampaignStats(ctx context.Context, params dto.DashboardQueryParams, startDate, endDate time.Time) ([]dto.CampaignStat, error) {
-	results, err := s.clickRepo.CountByCampaignWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate)
+	results, err := s.clickRepo.CountByCampaignWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate, params.IncludeBots)
+	if err != nil {
+		return nil, err
+	}
+
+	impressions, err := s.impressionRepo.CountByCampaignWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate, params.IncludeBots)
+	if err != nil {
+		return nil, err
+	}
+	impressionsByCampaign := make(map[uuid.UUID]model.CampaignImpressionResult, len(impressions))
+	for _, r := range impressions {
+		impressionsByCampaign[r.CampaignID] = r
+	}
+
+	conversions, err := s.conversionRepo.SumByCampaignWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
+	conversionsByCampaign := make(map[uuid.UUID]model.CampaignConversionResult, len(conversions))
+	for _, r := range conversions {
+		conversionsByCampaign[r.CampaignID] = r
+	}
 
 	stats := make([]dto.CampaignStat, len(results))
 	for i, r := range results {
+		imp := impressionsByCampaign[r.CampaignID]
+		conv := conversionsByCampaign[r.CampaignID]
 		stats[i] = dto.CampaignStat{
-			CampaignID:   r.CampaignID,
-			CampaignName: r.CampaignName,
-			Clicks:       r.Clicks,
+			CampaignID:     r.CampaignID,
+			CampaignName:   r.CampaignName,
+			Clicks:         r.Clicks,
+			UniqueClicks:   r.UniqueClicks,
+			PageViews:      imp.PageViews,
+			Impressions:    imp.LinkImpressions,
+			CTR:            clickThroughRate(r.Clicks, imp.LinkImpressions),
+			Conversions:    conv.Conversions,
+			Revenue:        conv.Revenue,
+			EPC:            earningsPerClick(conv.Revenue, r.Clicks),
+			ConversionRate: conversionRate(conv.Conversions, r.Clicks),
 		}
 	}
 
@@ -133,10 +203,19 @@ func (s *DashboardService) getCampaignStats(ctx context.Context, params dto.Dash
 
 // getMarketplaceStats gets click statistics grouped by marketplace
 func (s *DashboardService) getMarketplaceStats(ctx context.Context, params dto.DashboardQueryParams, startDate, endDate time.Time) ([]dto.MarketplaceStat, error) {
-	results, err := s.clickRepo.CountByMarketplaceWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate)
+	results, err := s.clickRepo.CountByMarketplaceWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate, params.IncludeBots)
+	if err != nil {
+		return nil, err
+	}
+
+	impressions, err := s.impressionRepo.CountByMarketplaceWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate, params.IncludeBots)
 	if err != nil {
 		return nil, err
 	}
+	impressionsByMarketplace := make(map[string]int64, len(impressions))
+	for _, r := range impressions {
+		impressionsByMarketplace[r.Marketplace] = r.Impressions
+	}
 
 	// Calculate total for percentage
 	total := int64(0)
@@ -151,9 +230,42 @@ func (s *DashboardService) getMarketplaceStats(ctx context.Context, params dto.D
 			percentage = (float64(r.Clicks) / float64(total)) * 100.0
 		}
 		stats[i] = dto.MarketplaceStat{
-			Marketplace: r.Marketplace,
-			Clicks:      r.Clicks,
-			Percentage:  percentage,
+			Marketplace:  r.Marketplace,
+			Clicks:       r.Clicks,
+			UniqueClicks: r.UniqueClicks,
+			Percentage:   percentage,
+			Impressions:  impressionsByMarketplace[r.Marketplace],
+			CTR:          clickThroughRate(r.Clicks, impressionsByMarketplace[r.Marketplace]),
+		}
+	}
+
+	return stats, nil
+}
+
+// getSourceStats gets click statistics grouped by ?src= marker
+func (s *DashboardService) getSourceStats(ctx context.Context, params dto.DashboardQueryParams, startDate, endDate time.Time) ([]dto.SourceStat, error) {
+	results, err := s.clickRepo.CountBySourceWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate, params.IncludeBots)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate total for percentage
+	total := int64(0)
+	for _, r := range results {
+		total += r.Clicks
+	}
+
+	stats := make([]dto.SourceStat, len(results))
+	for i, r := range results {
+		percentage := 0.0
+		if total > 0 {
+			percentage = (float64(r.Clicks) / float64(total)) * 100.0
+		}
+		stats[i] = dto.SourceStat{
+			Source:       r.Source,
+			Clicks:       r.Clicks,
+			UniqueClicks: r.UniqueClicks,
+			Percentage:   percentage,
 		}
 	}
 
@@ -162,18 +274,38 @@ func (s *DashboardService) getMarketplaceStats(ctx context.Context, params dto.D
 
 // getTopProducts gets top-performing products by click count
 func (s *DashboardService) getTopProducts(ctx context.Context, params dto.DashboardQueryParams, startDate, endDate time.Time) ([]dto.TopProduct, error) {
-	results, err := s.clickRepo.FindTopProductsWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate, 10)
+	results, err := s.clickRepo.FindTopProductsWithFilters(ctx, params.CampaignID, params.Marketplace, startDate, endDate, 10, params.IncludeBots)
+	if err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(results))
+	for _, r := range results {
+		productIDs = append(productIDs, r.ProductID)
+	}
+	impressions, err := s.impressionRepo.CountByProductsWithFilters(ctx, productIDs, params.CampaignID, params.Marketplace, startDate, endDate, params.IncludeBots)
 	if err != nil {
 		return nil, err
 	}
+	type productMarketplace struct {
+		productID   uuid.UUID
+		marketplace string
+	}
+	impressionsByProduct := make(map[productMarketplace]int64, len(impressions))
+	for _, r := range impressions {
+		impressionsByProduct[productMarketplace{r.ProductID, r.Marketplace}] = r.Impressions
+	}
 
 	topProducts := make([]dto.TopProduct, len(results))
 	for i, r := range results {
+		productImpressions := impressionsByProduct[productMarketplace{r.ProductID, r.Marketplace}]
 		topProducts[i] = dto.TopProduct{
 			ProductID:   r.ProductID,
 			ProductName: r.ProductName,
 			Clicks:      r.Clicks,
 			Marketplace: r.Marketplace,
+			Impressions: productImpressions,
+			CTR:         clickThroughRate(r.Clicks, productImpressions),
 		}
 	}
 
@@ -183,7 +315,7 @@ func (s *DashboardService) getTopProducts(ctx context.Context, params dto.Dashbo
 // getRecentClicks gets the most recent clicks with related data
 func (s *DashboardService) getRecentClicks(ctx context.Context, params dto.DashboardQueryParams) ([]dto.RecentClick, error) {
 	// Get recent clicks from repository
-	clicks, err := s.clickRepo.FindRecentClicks(ctx, 10)
+	clicks, err := s.clickRepo.FindRecentClicks(ctx, 10, params.IncludeBots)
 	if err != nil {
 		return nil, err
 	}
@@ -196,8 +328,14 @@ func (s *DashboardService) getRecentClicks(ctx context.Context, params dto.Dashb
 			continue
 		}
 
+		// Best-price clicks record the marketplace they were sent to, like the aggregates
+		marketplace := click.Link.Marketplace
+		if click.Marketplace != nil {
+			marketplace = *click.Marketplace
+		}
+
 		// Apply filters if specified
-		if params.Marketplace != nil && string(click.Link.Marketplace) != *params.Marketplace {
+		if params.Marketplace != nil && string(marketplace) != *params.Marketplace {
 			continue
 		}
 		if params.CampaignID != nil && click.Link.CampaignID != *params.CampaignID {
@@ -220,7 +358,7 @@ func (s *DashboardService) getRecentClicks(ctx context.Context, params dto.Dashb
 			DateTime:     click.Timestamp,
 			ProductID:    click.Link.ProductID,
 			ProductName:  productName,
-			Marketplace:  string(click.Link.Marketplace),
+			Marketplace:  string(marketplace),
 			CampaignID:   click.Link.CampaignID,
 			CampaignName: campaignName,
 		}
@@ -230,3 +368,30 @@ func (s *DashboardService) getRecentClicks(ctx context.Context, params dto.Dashb
 
 	return recentClicks, nil
 }
+
+// clickThroughRate returns clicks per impression as a percentage (0 without impressions)
+// Clicks on links shared outside campaign pages have no impression, so it can exceed 100
+func clickThroughRate(clicks, impressions int64) float64 {
+	if impressions == 0 {
+		return 0
+	}
+	return (float64(clicks) / float64(impressions)) * 100.0
+}
+
+// earningsPerClick returns revenue divided by clicks (0 without clicks)
+func earningsPerClick(revenue float64, clicks int64) float64 {
+	if clicks == 0 {
+		return 0
+	}
+	return revenue / float64(clicks)
+}
+
+// conversionRate returns conversions per click as a percentage (0 without clicks)
+// Conversions are counted when reported, so a conversion can fall outside the
+// date range of the click that led to it
+func conversionRate(conversions, clicks int64) float64 {
+	if clicks == 0 {
+		return 0
+	}
+	return (float64(conversions) / float64(clicks)) * 100.0
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+func TestDashboardService_GetDashboardStats_Rates(t *testing.T) {
+	ctx := context.Background()
+	campaignID := uuid.New()
+	productID := uuid.New()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	params := dto.DashboardQueryParams{StartDate: &start, EndDate: &end}
+	var noCampaign *uuid.UUID
+	var noMarketplace *string
+
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+
+	clickRepo := new(MockClickRepository)
+	impressionRepo := new(MockImpressionRepository)
+	conversionRepo := new(MockConversionRepository)
+	linkRepo := new(MockLinkRepository)
+	svc := NewDashboardService(clickRepo, impressionRepo, conversionRepo, linkRepo, new(MockCampaignRepository), new(MockProductRepository), nil, log)
+
+	clickRepo.On("CountWithFilters", ctx, noCampaign, noMarketplace, start, end, false).Return(int64(30), nil)
+	clickRepo.On("CountUniqueWithFilters", ctx, noCampaign, noMarketplace, start, end, false).Return(int64(25), nil)
+	clickRepo.On("CountByCampaignWithFilters", ctx, noCampaign, noMarketplace, start, end, false).Return([]model.CampaignStatResult{
+		{CampaignID: campaignID, CampaignName: "Summer", Clicks: 30, UniqueClicks: 25},
+	}, nil)
+	clickRepo.On("CountByMarketplaceWithFilters", ctx, noCampaign, noMarketplace, start, end, false).Return([]model.MarketplaceStatResult{
+		{Marketplace: "lazada", Clicks: 20, UniqueClicks: 16},
+		{Marketplace: "shopee", Clicks: 10, UniqueClicks: 9},
+	}, nil)
+	clickRepo.On("CountBySourceWithFilters", ctx, noCampaign, noMarketplace, start, end, false).Return([]model.SourceStatResult{
+		{Source: "(none)", Clicks: 24, UniqueClicks: 20},
+		{Source: "qr", Clicks: 6, UniqueClicks: 5},
+	}, nil)
+	clickRepo.On("FindTopProductsWithFilters", ctx, noCampaign, noMarketplace, start, end, 10, false).Return([]model.TopProductResult{
+		{ProductID: productID, ProductName: "Phone", Marketplace: "lazada", Clicks: 20},
+	}, nil)
+	clickRepo.On("FindRecentClicks", ctx, 10, false).Return([]model.Click{}, nil)
+	linkRepo.On("CountWithFilters", ctx, noCampaign, noMarketplace).Return(int64(2), nil)
+
+	impressionRepo.On("CountWithFilters", ctx, noCampaign, noMarketplace, start, end, false).Return(&model.ImpressionCountResult{PageViews: 500, LinkImpressions: 1000}, nil)
+	impressionRepo.On("CountByCampaignWithFilters", ctx, noCampaign, noMarketplace, start, end, false).Return([]model.CampaignImpressionResult{
+		{CampaignID: campaignID, PageViews: 500, LinkImpressions: 1000},
+	}, nil)
+	impressionRepo.On("CountByMarketplaceWithFilters", ctx, noCampaign, noMarketplace, start, end, false).Return([]model.MarketplaceImpressionResult{
+		{Marketplace: "lazada", Impressions: 500},
+		{Marketplace: "shopee", Impressions: 500},
+	}, nil)
+	impressionRepo.On("CountByProductsWithFilters", ctx, []uuid.UUID{productID}, noCampaign, noMarketplace, start, end, false).Return([]model.ProductImpressionResult{
+		{ProductID: productID, Marketplace: "lazada", Impressions: 400},
+		{ProductID: productID, Marketplace: "shopee", Impressions: 400},
+	}, nil)
+
+	conversionRepo.On("SumWithFilters", ctx, noCampaign, noMarketplace, start, end).Return(&model.ConversionSummaryResult{Conversions: 3, Revenue: 60}, nil)
+	conversionRepo.On("SumByCampaignWithFilters", ctx, noCampaign, noMarketplace, start, end).Return([]model.CampaignConversionResult{
+		{CampaignID: campaignID, Conversions: 3, Revenue: 60},
+	}, nil)
+
+	stats, err := svc.GetDashboardStats(ctx, params)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(30), stats.TotalClicks)
+	assert.Equal(t, int64(25), stats.UniqueClicks)
+	assert.Equal(t, int64(500), stats.PageViews)
+	assert.Equal(t, int64(1000), stats.TotalImpressions)
+	assert.InDelta(t, 3.0, stats.CTR, 0.001)
+	assert.Equal(t, int64(3), stats.Conversions)
+	assert.InDelta(t, 60.0, stats.Revenue, 0.001)
+	assert.InDelta(t, 2.0, stats.EPC, 0.001)
+	assert.InDelta(t, 10.0, stats.ConversionRate, 0.001)
+
+	require.Len(t, stats.CampaignStats, 1)
+	assert.Equal(t, int64(1000), stats.CampaignStats[0].Impressions)
+	assert.InDelta(t, 3.0, stats.CampaignStats[0].CTR, 0.001)
+	assert.InDelta(t, 2.0, stats.CampaignStats[0].EPC, 0.001)
+	assert.InDelta(t, 10.0, stats.CampaignStats[0].ConversionRate, 0.001)
+
+	require.Len(t, stats.MarketplaceStats, 2)
+	assert.InDelta(t, 4.0, stats.MarketplaceStats[0].CTR, 0.001)
+	assert.InDelta(t, 2.0, stats.MarketplaceStats[1].CTR, 0.001)
+
+	assert.Equal(t, []dto.SourceStat{
+		{Source: "(none)", Clicks: 24, UniqueClicks: 20, Percentage: 80},
+		{Source: "qr", Clicks: 6, UniqueClicks: 5, Percentage: 20},
+	}, stats.SourceStats)
+
+	require.Len(t, stats.TopProducts, 1)
+	assert.Equal(t, int64(400), stats.TopProducts[0].Impressions)
+	assert.InDelta(t, 5.0, stats.TopProducts[0].CTR, 0.001)
+
+	clickRepo.AssertExpectations(t)
+	impressionRepo.AssertExpectations(t)
+	conversionRepo.AssertExpectations(t)
+	linkRepo.AssertExpectations(t)
+}
+
+func TestDashboardService_RecentClicks_BestPrice(t *testing.T) {
+	ctx := context.Background()
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+
+	shopee := model.MarketplaceShopee
+	bestPriceLink := model.Link{ID: uuid.New(), Marketplace: model.MarketplaceBestPrice}
+	lazadaLink := model.Link{ID: uuid.New(), Marketplace: model.MarketplaceLazada}
+	clickRepo := new(MockClickRepository)
+	clickRepo.On("FindRecentClicks", ctx, 10, false).Return([]model.Click{
+		{LinkID: bestPriceLink.ID, Link: bestPriceLink, Marketplace: &shopee},
+		{LinkID: bestPriceLink.ID, Link: bestPriceLink}, // Never reached a marketplace
+		{LinkID: lazadaLink.ID, Link: lazadaLink},
+	}, nil)
+	svc := NewDashboardService(clickRepo, nil, nil, nil, nil, nil, nil, log)
+
+	t.Run("clicks show the marketplace they were sent to", func(t *testing.T) {
+		clicks, err := svc.getRecentClicks(ctx, dto.DashboardQueryParams{})
+		require.NoError(t, err)
+		require.Len(t, clicks, 3)
+		assert.Equal(t, "shopee", clicks[0].Marketplace)
+		assert.Equal(t, "best_price", clicks[1].Marketplace)
+		assert.Equal(t, "lazada", clicks[2].Marketplace)
+	})
+
+	t.Run("marketplace filter matches the chosen marketplace", func(t *testing.T) {
+		filter := "shopee"
+		clicks, err := svc.getRecentClicks(ctx, dto.DashboardQueryParams{Marketplace: &filter})
+		require.NoError(t, err)
+		require.Len(t, clicks, 1)
+		assert.Equal(t, "shopee", clicks[0].Marketplace)
+	})
+}
+
+func TestClickThroughRate(t *testing.T) {
+	assert.Equal(t, 0.0, clickThroughRate(5, 0))
+	assert.Equal(t, 50.0, clickThroughRate(1, 2))
+}
+
+func TestEarningsPerClickAndConversionRate(t *testing.T) {
+	assert.Equal(t, 0.0, earningsPerClick(10, 0))
+	assert.Equal(t, 2.5, earningsPerClick(10, 4))
+	assert.Equal(t, 0.0, conversionRate(1, 0))
+	assert.Equal(t, 25.0, conversionRate(1, 4))
+}
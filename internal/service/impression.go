@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// ImpressionService handles impression tracking business logic
+type ImpressionService struct {
+	impressionRepo ImpressionRepositoryInterface
+	botFilter      *BotFilter
+	logger         logger.Logger
+}
+
+// NewImpressionService creates a new impression service
+func NewImpressionService(impressionRepo ImpressionRepositoryInterface, botFilter *BotFilter, log logger.Logger) *ImpressionService {
+	return &ImpressionService{
+		impressionRepo: impressionRepo,
+		botFilter:      botFilter,
+		logger:         log,
+	}
+}
+
+// TrackCampaignView records one campaign impression plus one impression per
+// rendered link, written with a single multi-row insert
+func (s *ImpressionService) TrackCampaignView(ctx context.Context, campaignID uuid.UUID, links []*model.Link, meta dto.ClickMetadata) error {
+	isBot := false
+	if s.botFilter != nil {
+		isBot, _ = s.botFilter.Classify(meta)
+	}
+
+	now := time.Now()
+	impressions := make([]*model.Impression, 0, len(links)+1)
+	impressions = append(impressions, &model.Impression{
+		Kind:       model.ImpressionKindCampaign,
+		CampaignID: campaignID,
+		Timestamp:  now,
+		IsBot:      isBot,
+	})
+	for _, link := range links {
+		productID := link.ProductID
+		linkID := link.ID
+		marketplace := link.Marketplace
+		impressions = append(impressions, &model.Impression{
+			Kind:        model.ImpressionKindLink,
+			CampaignID:  campaignID,
+			ProductID:   &productID,
+			LinkID:      &linkID,
+			Marketplace: &marketplace,
+			Timestamp:   now,
+			IsBot:       isBot,
+		})
+	}
+
+	if err := s.impressionRepo.CreateBatch(ctx, impressions); err != nil {
+		return fmt.Errorf("failed to track impressions: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// MockImpressionRepository is a mock implementation of ImpressionRepositoryInterface
+type MockImpressionRepository struct {
+	mock.Mock
+}
+
+func (m *MockImpressionRepository) CreateBatch(ctx context.Context, impressions []*model.Impression) error {
+	args := m.Called(ctx, impressions)
+	return args.Error(0)
+}
+
+func (m *MockImpressionRepository) CountWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) (*model.ImpressionCountResult, error) {
+	args := m.Called(ctx, campaignID, marketplace, startDate, endDate, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImpressionCountResult), args.Error(1)
+}
+
+func (m *MockImpressionRepository) CountByCampaignWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.CampaignImpressionResult, error) {
+	args := m.Called(ctx, campaignID, marketplace, startDate, endDate, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CampaignImpressionResult), args.Error(1)
+}
+
+func (m *MockImpressionRepository) CountByMarketplaceWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.MarketplaceImpressionResult, error) {
+	args := m.Called(ctx, campaignID, marketplace, startDate, endDate, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.MarketplaceImpressionResult), args.Error(1)
+}
+
+func (m *MockImpressionRepository) CountByProductsWithFilters(ctx context.Context, productIDs []uuid.UUID, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.ProductImpressionResult, error) {
+	args := m.Called(ctx, productIDs, campaignID, marketplace, startDate, endDate, includeBots)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductImpressionResult), args.Error(1)
+}
+
+func TestImpressionService_TrackCampaignView(t *testing.T) {
+	ctx := context.Background()
+	campaignID := uuid.New()
+	productID := uuid.New()
+	links := []*model.Link{
+		{ID: uuid.New(), ProductID: productID, CampaignID: campaignID, Marketplace: model.MarketplaceLazada},
+		{ID: uuid.New(), ProductID: productID, CampaignID: campaignID, Marketplace: model.MarketplaceShopee},
+	}
+
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	filter, err := NewBotFilter(nil, nil)
+	require.NoError(t, err)
+
+	t.Run("records campaign view and rendered links in one batch", func(t *testing.T) {
+		impressionRepo := new(MockImpressionRepository)
+		svc := NewImpressionService(impressionRepo, filter, log)
+
+		var batch []*model.Impression
+		impressionRepo.On("CreateBatch", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			batch = args.Get(1).([]*model.Impression)
+		}).Return(nil).Once()
+
+		require.NoError(t, svc.TrackCampaignView(ctx, campaignID, links, dto.ClickMetadata{Method: "GET", UserAgent: chromeAndroidUA}))
+
+		require.Len(t, batch, 3)
+		assert.Equal(t, model.ImpressionKindCampaign, batch[0].Kind)
+		assert.Nil(t, batch[0].LinkID)
+		for i, link := range links {
+			imp := batch[i+1]
+			assert.Equal(t, model.ImpressionKindLink, imp.Kind)
+			assert.Equal(t, campaignID, imp.CampaignID)
+			assert.Equal(t, link.ID, *imp.LinkID)
+			assert.Equal(t, productID, *imp.ProductID)
+			assert.Equal(t, link.Marketplace, *imp.Marketplace)
+			assert.False(t, imp.IsBot)
+		}
+	})
+
+	t.Run("flags bot views", func(t *testing.T) {
+		impressionRepo := new(MockImpressionRepository)
+		svc := NewImpressionService(impressionRepo, filter, log)
+
+		impressionRepo.On("CreateBatch", ctx, mock.MatchedBy(func(batch []*model.Impression) bool {
+			return len(batch) == 1 && batch[0].IsBot
+		})).Return(nil).Once()
+
+		require.NoError(t, svc.TrackCampaignView(ctx, campaignID, nil, dto.ClickMetadata{Method: "GET", UserAgent: "facebookexternalhit/1.1"}))
+		impressionRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// redirectOutcome decides what a redirect does with a link at t, returning the
+// outcome and the reason it deviated from a normal in-window redirect (if any)
+// Paused, archived and expired links never reach the marketplace: they use the
+// campaign's fallback URL when the fallback policy is set, otherwise they are gone.
+// Outside the campaign window the campaign's policy applies as configured.
+func redirectOutcome(link *model.Link, now time.Time) (model.ClickOutcome, string) {
+	campaign := &link.Campaign
+
+	if reason := inactiveLinkReason(link, now); reason != "" {
+		if campaign.OutOfWindowPolicy == model.OutOfWindowFallback && campaign.FallbackURL != "" {
+			return model.ClickOutcomeFallback, reason
+		}
+		return model.ClickOutcomeGone, reason
+	}
+
+	// A link resolved without its campaign has no window to enforce
+	if campaign.EndAt.IsZero() || campaign.InWindow(now) {
+		return model.ClickOutcomeRedirected, ""
+	}
+
+	reason := model.OutcomeReasonCampaignEnded
+	if now.Before(campaign.StartAt) {
+		reason = model.OutcomeReasonCampaignNotStarted
+	}
+
+	switch campaign.OutOfWindowPolicy {
+	case model.OutOfWindowFallback:
+		if campaign.FallbackURL != "" {
+			return model.ClickOutcomeFallback, reason
+		}
+		return model.ClickOutcomeGone, reason
+	case model.OutOfWindowGone:
+		return model.ClickOutcomeGone, reason
+	default:
+		return model.ClickOutcomeRedirected, reason
+	}
+}
+
+// inactiveLinkReason returns why a link does not redirect at t, or "" if it is active
+func inactiveLinkReason(link *model.Link, t time.Time) string {
+	switch link.Status {
+	case model.LinkStatusPaused:
+		return model.OutcomeReasonLinkPaused
+	case model.LinkStatusArchived:
+		return model.OutcomeReasonLinkArchived
+	}
+	if !link.IsActive(t) {
+		return model.OutcomeReasonLinkExpired
+	}
+	return ""
+}
+
+// parseLinkStatus validates a link status
+func parseLinkStatus(status string) (model.LinkStatus, error) {
+	switch s := model.LinkStatus(strings.ToLower(strings.TrimSpace(status))); s {
+	case model.LinkStatusActive, model.LinkStatusPaused, model.LinkStatusArchived:
+		return s, nil
+	default:
+		return "", fmt.Errorf("invalid status: must be active, paused or archived")
+	}
+}
+
+// parseOutOfWindowPolicy validates an out-of-window policy ("" means redirect)
+func parseOutOfWindowPolicy(policy string) (model.OutOfWindowPolicy, error) {
+	switch p := model.OutOfWindowPolicy(strings.ToLower(strings.TrimSpace(policy))); p {
+	case "":
+		return model.OutOfWindowRedirect, nil
+	case model.OutOfWindowRedirect, model.OutOfWindowFallback, model.OutOfWindowGone:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid out_of_window_policy: must be redirect, fallback or gone")
+	}
+}
+
+// validateCampaignPolicy checks a campaign's fallback URL and that the fallback policy has one
+func validateCampaignPolicy(policy model.OutOfWindowPolicy, fallbackURL string) error {
+	if fallbackURL != "" {
+		u, err := url.Parse(fallbackURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid fallback_url: must be an absolute http(s) URL")
+		}
+	}
+	if policy == model.OutOfWindowFallback && fallbackURL == "" {
+		return fmt.Errorf("invalid fallback_url: required by the fallback policy")
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+func TestRedirectOutcome(t *testing.T) {
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	campaign := func(policy model.OutOfWindowPolicy, fallbackURL string, startAt, endAt time.Time) model.Campaign {
+		return model.Campaign{StartAt: startAt, EndAt: endAt, OutOfWindowPolicy: policy, FallbackURL: fallbackURL}
+	}
+	running := now.Add(-24 * time.Hour)
+	ending := now.Add(24 * time.Hour)
+
+	tests := []struct {
+		name        string
+		link        model.Link
+		wantOutcome model.ClickOutcome
+		wantReason  string
+	}{
+		{
+			name:        "active link in window",
+			link:        model.Link{Status: model.LinkStatusActive, Campaign: campaign(model.OutOfWindowGone, "", running, ending)},
+			wantOutcome: model.ClickOutcomeRedirected,
+		},
+		{
+			name:        "campaign not loaded",
+			link:        model.Link{},
+			wantOutcome: model.ClickOutcomeRedirected,
+		},
+		{
+			name:        "ended campaign with the default policy still redirects",
+			link:        model.Link{Campaign: campaign("", "", running, past)},
+			wantOutcome: model.ClickOutcomeRedirected,
+			wantReason:  model.OutcomeReasonCampaignEnded,
+		},
+		{
+			name:        "ended campaign with the gone policy",
+			link:        model.Link{Campaign: campaign(model.OutOfWindowGone, "", running, past)},
+			wantOutcome: model.ClickOutcomeGone,
+			wantReason:  model.OutcomeReasonCampaignEnded,
+		},
+		{
+			name:        "campaign not started with the fallback policy",
+			link:        model.Link{Campaign: campaign(model.OutOfWindowFallback, "https://shop.example.com", future, ending)},
+			wantOutcome: model.ClickOutcomeFallback,
+			wantReason:  model.OutcomeReasonCampaignNotStarted,
+		},
+		{
+			name:        "paused link never reaches the marketplace",
+			link:        model.Link{Status: model.LinkStatusPaused, Campaign: campaign(model.OutOfWindowRedirect, "", running, ending)},
+			wantOutcome: model.ClickOutcomeGone,
+			wantReason:  model.OutcomeReasonLinkPaused,
+		},
+		{
+			name:        "archived link with the fallback policy",
+			link:        model.Link{Status: model.LinkStatusArchived, Campaign: campaign(model.OutOfWindowFallback, "https://shop.example.com", running, ending)},
+			wantOutcome: model.ClickOutcomeFallback,
+			wantReason:  model.OutcomeReasonLinkArchived,
+		},
+		{
+			name:        "expired link",
+			link:        model.Link{Status: model.LinkStatusActive, ExpiresAt: &past, Campaign: campaign("", "", running, ending)},
+			wantOutcome: model.ClickOutcomeGone,
+			wantReason:  model.OutcomeReasonLinkExpired,
+		},
+		{
+			name:        "expiry in the future",
+			link:        model.Link{ExpiresAt: &future, Campaign: campaign("", "", running, ending)},
+			wantOutcome: model.ClickOutcomeRedirected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome, reason := redirectOutcome(&tt.link, now)
+			assert.Equal(t, tt.wantOutcome, outcome)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func TestValidateCampaignPolicy(t *testing.T) {
+	policy, err := parseOutOfWindowPolicy("")
+	assert.NoError(t, err)
+	assert.Equal(t, model.OutOfWindowRedirect, policy)
+
+	policy, err = parseOutOfWindowPolicy(" Fallback ")
+	assert.NoError(t, err)
+	assert.Equal(t, model.OutOfWindowFallback, policy)
+
+	_, err = parseOutOfWindowPolicy("404")
+	assert.EqualError(t, err, "invalid out_of_window_policy: must be redirect, fallback or gone")
+
+	assert.NoError(t, validateCampaignPolicy(model.OutOfWindowFallback, "https://shop.example.com/offers"))
+	assert.NoError(t, validateCampaignPolicy(model.OutOfWindowGone, ""))
+	assert.EqualError(t, validateCampaignPolicy(model.OutOfWindowFallback, ""), "invalid fallback_url: required by the fallback policy")
+	assert.EqualError(t, validateCampaignPolicy(model.OutOfWindowRedirect, "javascript:alert(1)"), "invalid fallback_url: must be an absolute http(s) URL")
+	assert.EqualError(t, validateCampaignPolicy(model.OutOfWindowRedirect, "/offers"), "invalid fallback_url: must be an absolute http(s) URL")
+}
@@ -2,22 +2,32 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
 	"github.com/jonosize/affiliate-platform/internal/config"
 	"github.com/jonosize/affiliate-platform/internal/dto"
 	"github.com/jonosize/affiliate-platform/internal/logger"
 	"github.com/jonosize/affiliate-platform/internal/model"
+	"github.com/jonosize/affiliate-platform/internal/qrcode"
+	"github.com/jonosize/affiliate-platform/pkg/adapters"
 )
 
 // LinkService handles link business logic
 type LinkService struct {
-	linkRepo     LinkRepositoryInterface
-	campaignRepo CampaignRepositoryInterface
-	productRepo  ProductRepositoryInterface
-	offerRepo    OfferRepositoryInterface
-	logger       logger.Logger
-	cfg          config.Config
+	linkRepo      LinkRepositoryInterface
+	campaignRepo  CampaignRepositoryInterface
+	productRepo   ProductRepositoryInterface
+	offerRepo     OfferRepositoryInterface
+	workspaceRepo WorkspaceRepositoryInterface
+	registry      *adapters.Registry
+	qrLogo        *qrcode.Logo // Drawn on QR codes requested with a logo; nil disables
+	audit         *AuditService
+	logger        logger.Logger
+	cfg           config.Config
 }
 
 // NewLinkService creates a new link service
@@ -26,16 +36,24 @@ func NewLinkService(
 	campaignRepo CampaignRepositoryInterface,
 	productRepo ProductRepositoryInterface,
 	offerRepo OfferRepositoryInterface,
+	workspaceRepo WorkspaceRepositoryInterface,
+	registry *adapters.Registry,
+	qrLogo *qrcode.Logo,
+	audit *AuditService,
 	cfg config.Config,
 	log logger.Logger,
 ) *LinkService {
 	return &LinkService{
-		linkRepo:     linkRepo,
-		campaignRepo: campaignRepo,
-		productRepo:  productRepo,
-		offerRepo:    offerRepo,
-		logger:       log,
-		cfg:          cfg,
+		linkRepo:      linkRepo,
+		campaignRepo:  campaignRepo,
+		productRepo:   productRepo,
+		offerRepo:     offerRepo,
+		workspaceRepo: workspaceRepo,
+		registry:      registry,
+		qrLogo:        qrLogo,
+		audit:         audit,
+		logger:        log,
+		cfg:           cfg,
 	}
 }
 
@@ -65,8 +83,8 @@ func (s *LinkService) generateUniqueShortCode(ctx context.Context) (string, erro
 func (s *LinkService) CreateLink(ctx context.Context, req dto.CreateLinkRequest) (*dto.LinkResponse, error) {
 	// Validate marketplace
 	marketplace := model.Marketplace(req.Marketplace)
-	if marketplace != model.MarketplaceLazada && marketplace != model.MarketplaceShopee {
-		return nil, fmt.Errorf("invalid marketplace: must be 'lazada' or 'shopee'")
+	if !s.registry.Has(adapters.Marketplace(marketplace)) {
+		return nil, fmt.Errorf("invalid marketplace: %s is not supported", req.Marketplace)
 	}
 
 	// Verify product exists
@@ -74,6 +92,16 @@ func (s *LinkService) CreateLink(ctx context.Context, req dto.CreateLinkRequest)
 		return nil, fmt.Errorf("product not found: %w", err)
 	}
 
+	// Validate UTM overrides
+	if err := validateUTMFields(map[string]*string{
+		"utm_source":  req.UTMSource,
+		"utm_medium":  req.UTMMedium,
+		"utm_content": req.UTMContent,
+		"utm_term":    req.UTMTerm,
+	}); err != nil {
+		return nil, err
+	}
+
 	// Verify campaign exists
 	campaign, err := s.campaignRepo.FindByID(ctx, req.CampaignID)
 	if err != nil {
@@ -91,50 +119,276 @@ func (s *LinkService) CreateLink(ctx context.Context, req dto.CreateLinkRequest)
 		return nil, fmt.Errorf("offer marketplace mismatch: expected %s, got %s", marketplace, offer.Marketplace)
 	}
 
-	// Generate unique short code
-	shortCode, err := s.generateUniqueShortCode(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate short code: %w", err)
-	}
-
-	// Build target URL with UTM parameters
-	baseURL := offer.MarketplaceProductURL
-	utmSource := "affiliate"
-	utmMedium := "affiliate"
-	utmCampaign := campaign.UTMCampaign
-
-	targetURL, err := buildTargetURL(baseURL, utmCampaign, utmSource, utmMedium)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build target URL: %w", err)
+	// Use the requested vanity short code, or generate a unique one
+	var shortCode string
+	if req.ShortCode != "" {
+		shortCode = normalizeShortCode(req.ShortCode)
+		if err := validateVanityShortCode(shortCode); err != nil {
+			return nil, err
+		}
+		if err := ensureShortCodeAvailable(ctx, s.linkRepo, shortCode); err != nil {
+			return nil, err
+		}
+	} else {
+		shortCode, err = s.generateUniqueShortCode(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate short code: %w", err)
+		}
 	}
 
-	// Create link
 	link := &model.Link{
+		WorkspaceID: campaign.WorkspaceID,
 		ProductID:   req.ProductID,
 		CampaignID:  req.CampaignID,
 		Marketplace: marketplace,
 		ShortCode:   shortCode,
-		TargetURL:   targetURL,
+		UTMSource:   req.UTMSource,
+		UTMMedium:   req.UTMMedium,
+		UTMContent:  req.UTMContent,
+		UTMTerm:     req.UTMTerm,
 	}
 
+	// Build target URL with the campaign's UTM settings and the link's overrides
+	targetURL, err := buildLinkTargetURL(offer.MarketplaceProductURL, campaign, link)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build target URL: %w", err)
+	}
+	link.TargetURL = targetURL
+
+	// Create link
 	if err := s.linkRepo.Create(ctx, link); err != nil {
+		// A concurrent request may have taken the vanity code since it was checked
+		if req.ShortCode != "" {
+			if conflict := ensureShortCodeAvailable(ctx, s.linkRepo, shortCode); conflict != nil {
+				return nil, conflict
+			}
+		}
 		return nil, fmt.Errorf("failed to create link: %w", err)
 	}
 
-	// Build full URL
-	apiBaseURL := s.cfg.GetAPIBaseURL()
-	if apiBaseURL == "" {
-		apiBaseURL = "http://localhost:8080"
+	response := s.toLinkResponse(ctx, link, nil)
+	s.audit.Record(ctx, model.AuditActionCreate, model.AuditEntityLink, link.ID, nil, response)
+
+	return response, nil
+}
+
+// UpdateLink changes a link's short code, status or expiry
+// A changed short code keeps the previous one as an alias, so it keeps redirecting,
+// and rebuilds the target URL, whose UTM templates may use {{short_code}}
+func (s *LinkService) UpdateLink(ctx context.Context, id uuid.UUID, req dto.UpdateLinkRequest) (*dto.LinkResponse, error) {
+	link, err := s.linkRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	// Validate the lifecycle fields before changing anything
+	var status model.LinkStatus
+	if req.Status != nil {
+		if status, err = parseLinkStatus(*req.Status); err != nil {
+			return nil, err
+		}
+	}
+	if req.ExpiresAt != nil && req.ClearExpiresAt {
+		return nil, fmt.Errorf("invalid expires_at: cannot be set and cleared at once")
+	}
+
+	aliases, err := s.linkRepo.FindAliasesByLinkID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link aliases: %w", err)
+	}
+	before := s.toLinkResponse(ctx, link, aliases)
+
+	targetURLChanged := false
+	if shortCode := normalizeShortCode(req.ShortCode); shortCode != "" && shortCode != link.ShortCode {
+		if err := validateVanityShortCode(shortCode); err != nil {
+			return nil, err
+		}
+
+		// Promoting one of the link's own aliases is not a conflict
+		if !hasAlias(aliases, shortCode) {
+			if err := ensureShortCodeAvailable(ctx, s.linkRepo, shortCode); err != nil {
+				return nil, err
+			}
+		}
+
+		// Build the target URL for the new code before changing anything
+		renamed := *link
+		renamed.ShortCode = shortCode
+		targetURL, err := s.linkTargetURL(ctx, &renamed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build target URL: %w", err)
+		}
+
+		if err := s.linkRepo.ChangeShortCode(ctx, link, shortCode); err != nil {
+			return nil, fmt.Errorf("failed to change short code: %w", err)
+		}
+		s.logger.Info("Link short code changed", logger.String("link_id", id.String()), logger.String("short_code", shortCode))
+
+		targetURLChanged = targetURL != link.TargetURL
+		link.TargetURL = targetURL
+	}
+
+	if req.Status != nil || req.ExpiresAt != nil || req.ClearExpiresAt || targetURLChanged {
+		if req.Status != nil {
+			link.Status = status
+		}
+		if req.ExpiresAt != nil {
+			link.ExpiresAt = req.ExpiresAt
+		}
+		if req.ClearExpiresAt {
+			link.ExpiresAt = nil
+		}
+
+		if err := s.linkRepo.Update(ctx, link); err != nil {
+			return nil, fmt.Errorf("failed to update link: %w", err)
+		}
+		s.logger.Info("Link lifecycle updated", logger.String("link_id", id.String()), logger.String("status", string(link.Status)))
+	}
+
+	response, err := s.linkResponseWithAliases(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, model.AuditActionUpdate, model.AuditEntityLink, id, before, response)
+
+	return response, nil
+}
+
+// linkTargetURL builds a link's target URL from its campaign and product offer
+// Best-price links use the cheapest offer, as campaign link sync does
+func (s *LinkService) linkTargetURL(ctx context.Context, link *model.Link) (string, error) {
+	if link.Marketplace == model.MarketplaceBestPrice {
+		offers, err := s.offerRepo.FindByProductID(ctx, link.ProductID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get offers: %w", err)
+		}
+		best := bestOffer(offers)
+		if best == nil {
+			return "", fmt.Errorf("product has no offers")
+		}
+		return linkTargetURLFor(best.MarketplaceProductURL, best.Marketplace, &link.Campaign, link)
+	}
+
+	offer, err := s.offerRepo.FindByProductIDAndMarketplace(ctx, link.ProductID, link.Marketplace)
+	if err != nil {
+		return "", fmt.Errorf("offer not found for product and marketplace: %w", err)
+	}
+	return buildLinkTargetURL(offer.MarketplaceProductURL, &link.Campaign, link)
+}
+
+// AddLinkAlias adds another short code that redirects to a link
+func (s *LinkService) AddLinkAlias(ctx context.Context, id uuid.UUID, req dto.CreateLinkAliasRequest) (*dto.LinkResponse, error) {
+	link, err := s.linkRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	shortCode := normalizeShortCode(req.ShortCode)
+	if err := validateVanityShortCode(shortCode); err != nil {
+		return nil, err
+	}
+	if err := ensureShortCodeAvailable(ctx, s.linkRepo, shortCode); err != nil {
+		return nil, err
+	}
+
+	if err := s.linkRepo.CreateAlias(ctx, &model.LinkAlias{LinkID: id, ShortCode: shortCode}); err != nil {
+		if conflict := ensureShortCodeAvailable(ctx, s.linkRepo, shortCode); conflict != nil {
+			return nil, conflict
+		}
+		return nil, fmt.Errorf("failed to create link alias: %w", err)
+	}
+
+	response, err := s.linkResponseWithAliases(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	before := *response
+	before.Aliases = removeAlias(response.Aliases, shortCode)
+	s.audit.Record(ctx, model.AuditActionUpdate, model.AuditEntityLink, id, &before, response)
+
+	return response, nil
+}
+
+// DeleteLinkAlias removes one of a link's aliases; the code stops redirecting
+func (s *LinkService) DeleteLinkAlias(ctx context.Context, id uuid.UUID, shortCode string) error {
+	link, err := s.linkRepo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("link not found: %w", err)
+	}
+	before, err := s.linkResponseWithAliases(ctx, link)
+	if err != nil {
+		return err
+	}
+
+	shortCode = normalizeShortCode(shortCode)
+	if err := s.linkRepo.DeleteAlias(ctx, id, shortCode); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("link alias not found: %s", shortCode)
+		}
+		return fmt.Errorf("failed to delete link alias: %w", err)
+	}
+
+	after := *before
+	after.Aliases = removeAlias(before.Aliases, shortCode)
+	s.audit.Record(ctx, model.AuditActionUpdate, model.AuditEntityLink, id, before, &after)
+
+	return nil
+}
+
+// removeAlias returns aliases without shortCode
+func removeAlias(aliases []string, shortCode string) []string {
+	remaining := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		if alias != shortCode {
+			remaining = append(remaining, alias)
+		}
 	}
-	fullURL := fmt.Sprintf("%s/go/%s", apiBaseURL, shortCode)
+	return remaining
+}
 
-	// Convert to response
+// linkResponseWithAliases loads a link's aliases and converts it to DTO
+func (s *LinkService) linkResponseWithAliases(ctx context.Context, link *model.Link) (*dto.LinkResponse, error) {
+	aliases, err := s.linkRepo.FindAliasesByLinkID(ctx, link.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link aliases: %w", err)
+	}
+	return s.toLinkResponse(ctx, link, aliases), nil
+}
+
+// toLinkResponse converts a link model to DTO
+func (s *LinkService) toLinkResponse(ctx context.Context, link *model.Link, aliases []*model.LinkAlias) *dto.LinkResponse {
 	response := &dto.LinkResponse{
 		ID:        link.ID,
 		ShortCode: link.ShortCode,
 		TargetURL: link.TargetURL,
-		FullURL:   fullURL,
+		FullURL:   s.fullURL(ctx, link),
+		Status:    string(link.Status),
+		ExpiresAt: link.ExpiresAt,
 	}
+	if response.Status == "" {
+		response.Status = string(model.LinkStatusActive)
+	}
+	for _, alias := range aliases {
+		response.Aliases = append(response.Aliases, alias.ShortCode)
+	}
+	return response
+}
 
-	return response, nil
+// fullURL builds the public short link for a link, on its workspace's redirect domain if it has one
+func (s *LinkService) fullURL(ctx context.Context, link *model.Link) string {
+	workspace := link.Workspace
+	if workspace == nil {
+		workspace = loadWorkspace(ctx, s.workspaceRepo, link.WorkspaceID, s.logger)
+	}
+	return fmt.Sprintf("%s/go/%s", shortLinkBaseURL(workspace, s.cfg), link.ShortCode)
+}
+
+// hasAlias reports whether aliases contains shortCode
+func hasAlias(aliases []*model.LinkAlias, shortCode string) bool {
+	for _, alias := range aliases {
+		if alias.ShortCode == shortCode {
+			return true
+		}
+	}
+	return false
 }
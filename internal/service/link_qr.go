@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/qrcode"
+)
+
+// QR code size and quiet zone limits
+const (
+	defaultQRSize   = 512 // pixels
+	minQRSize       = 64
+	maxQRSize       = 2048
+	defaultQRMargin = 4 // modules; the QR specification's quiet zone
+	maxQRMargin     = 16
+)
+
+// GetLinkQRCode renders a link's short URL as a QR code
+// The encoded URL carries ?src=qr so scans are reported separately from other clicks
+func (s *LinkService) GetLinkQRCode(ctx context.Context, id uuid.UUID, params dto.LinkQRCodeQueryParams) (*dto.LinkQRCode, error) {
+	opts, err := s.qrCodeOptions(params)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.linkRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("link not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get link: %w", err)
+	}
+
+	data, err := qrcode.Encode(s.fullURL(ctx, link)+"?src="+ClickSourceQR, opts)
+	if err != nil {
+		if errors.Is(err, qrcode.ErrTooSmall) {
+			return nil, fmt.Errorf("invalid size: %w", err)
+		}
+		return nil, fmt.Errorf("failed to render QR code: %w", err)
+	}
+
+	contentType := "image/png"
+	if opts.Format == qrcode.FormatSVG {
+		contentType = "image/svg+xml"
+	}
+	return &dto.LinkQRCode{
+		ContentType: contentType,
+		Filename:    fmt.Sprintf("%s.%s", link.ShortCode, opts.Format),
+		Data:        data,
+	}, nil
+}
+
+// qrCodeOptions validates QR code parameters and applies defaults
+// Logos cover part of the symbol, so they default to (and need) high error correction
+func (s *LinkService) qrCodeOptions(params dto.LinkQRCodeQueryParams) (qrcode.Options, error) {
+	opts := qrcode.Options{
+		Format: qrcode.Format(strings.ToLower(params.Format)),
+		Size:   params.Size,
+		Margin: defaultQRMargin,
+		Level:  qrcode.Level(strings.ToUpper(params.ECC)),
+	}
+
+	switch opts.Format {
+	case "":
+		opts.Format = qrcode.FormatPNG
+	case qrcode.FormatPNG, qrcode.FormatSVG:
+	default:
+		return opts, fmt.Errorf("invalid format: must be png or svg")
+	}
+
+	if opts.Size == 0 {
+		opts.Size = defaultQRSize
+	}
+	if opts.Size < minQRSize || opts.Size > maxQRSize {
+		return opts, fmt.Errorf("invalid size: must be between %d and %d pixels", minQRSize, maxQRSize)
+	}
+
+	if params.Margin != nil {
+		opts.Margin = *params.Margin
+	}
+	if opts.Margin < 0 || opts.Margin > maxQRMargin {
+		return opts, fmt.Errorf("invalid margin: must be between 0 and %d modules", maxQRMargin)
+	}
+
+	switch opts.Level {
+	case "":
+		opts.Level = qrcode.LevelMedium
+		if params.Logo {
+			opts.Level = qrcode.LevelHigh
+		}
+	case qrcode.LevelLow, qrcode.LevelMedium, qrcode.LevelQuartile, qrcode.LevelHigh:
+	default:
+		return opts, fmt.Errorf("invalid ecc: must be l, m, q or h")
+	}
+
+	if params.Logo {
+		if s.qrLogo == nil {
+			return opts, fmt.Errorf("invalid logo: no QR logo is configured")
+		}
+		if opts.Level != qrcode.LevelQuartile && opts.Level != qrcode.LevelHigh {
+			return opts, fmt.Errorf("invalid ecc: a logo needs q or h error correction")
+		}
+		opts.Logo = s.qrLogo
+	}
+
+	return opts, nil
+}
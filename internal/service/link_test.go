@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"image"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	"github.com/jonosize/affiliate-platform/internal/qrcode"
+	mockadapter "github.com/jonosize/affiliate-platform/pkg/adapters/mock"
+)
+
+func newTestLinkService(t *testing.T) (*LinkService, *MockLinkRepository, *MockCampaignRepository, *MockProductRepository, *MockOfferRepository) {
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	registry, err := mockadapter.NewRegistry()
+	require.NoError(t, err)
+
+	linkRepo := new(MockLinkRepository)
+	campaignRepo := new(MockCampaignRepository)
+	productRepo := new(MockProductRepository)
+	offerRepo := new(MockOfferRepository)
+	workspaceRepo := new(MockWorkspaceRepository)
+	workspaceRepo.On("FindByID", mock.Anything, mock.Anything).Return(&model.Workspace{ID: model.DefaultWorkspaceID}, nil).Maybe()
+	cfg := &MockConfig{apiBaseURL: "https://api.example.com"}
+	return NewLinkService(linkRepo, campaignRepo, productRepo, offerRepo, workspaceRepo, registry, nil, nil, cfg, log), linkRepo, campaignRepo, productRepo, offerRepo
+}
+
+func TestLinkService_CreateLink_VanityShortCode(t *testing.T) {
+	ctx := context.Background()
+	productID, campaignID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name      string
+		shortCode string
+		taken     bool
+		wantErr   string
+		wantCode  string
+	}{
+		{name: "normalized and used", shortCode: " Matcha-Summer ", wantCode: "matcha-summer"},
+		{name: "conflict", shortCode: "matcha-summer", taken: true, wantErr: "short code already in use: matcha-summer"},
+		{name: "reserved", shortCode: "admin", wantErr: "invalid short_code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, linkRepo, campaignRepo, productRepo, offerRepo := newTestLinkService(t)
+			productRepo.On("FindByID", ctx, productID).Return(&model.Product{ID: productID}, nil)
+			campaignRepo.On("FindByID", ctx, campaignID).Return(&model.Campaign{ID: campaignID, UTMCampaign: "summer"}, nil)
+			offerRepo.On("FindByProductIDAndMarketplace", ctx, productID, model.MarketplaceLazada).
+				Return(&model.Offer{Marketplace: model.MarketplaceLazada, MarketplaceProductURL: "https://www.lazada.co.th/products/matcha-i1.html"}, nil)
+			linkRepo.On("ShortCodeExists", ctx, mock.Anything).Return(tt.taken, nil)
+			linkRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+			resp, err := svc.CreateLink(ctx, dto.CreateLinkRequest{
+				ProductID:   productID,
+				CampaignID:  campaignID,
+				Marketplace: "lazada",
+				ShortCode:   tt.shortCode,
+			})
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				linkRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCode, resp.ShortCode)
+			assert.Equal(t, "https://api.example.com/go/"+tt.wantCode, resp.FullURL)
+		})
+	}
+}
+
+func TestLinkService_UpdateLink(t *testing.T) {
+	ctx := context.Background()
+	linkID := uuid.New()
+
+	productID := uuid.New()
+	offerURL := "https://www.lazada.co.th/products/matcha-i1.html"
+	newLink := func(shortCode string) *model.Link {
+		link := &model.Link{ID: linkID, ProductID: productID, Marketplace: model.MarketplaceLazada, ShortCode: shortCode}
+		link.Campaign = model.Campaign{UTMCampaign: "summer", UTMContent: "{{short_code}}"}
+		link.TargetURL = offerURL + "?utm_campaign=summer&utm_content=" + shortCode
+		return link
+	}
+
+	t.Run("keeps the previous code as an alias", func(t *testing.T) {
+		svc, linkRepo, _, _, offerRepo := newTestLinkService(t)
+		link := newLink("Ab12Cd34")
+		linkRepo.On("FindByID", ctx, linkID).Return(link, nil)
+		linkRepo.On("FindAliasesByLinkID", ctx, linkID).Return([]*model.LinkAlias{}, nil).Once()
+		linkRepo.On("ShortCodeExists", ctx, "matcha-summer").Return(false, nil)
+		offerRepo.On("FindByProductIDAndMarketplace", ctx, productID, model.MarketplaceLazada).
+			Return(&model.Offer{Marketplace: model.MarketplaceLazada, MarketplaceProductURL: offerURL}, nil)
+		linkRepo.On("ChangeShortCode", ctx, link, "matcha-summer").Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*model.Link).ShortCode = "matcha-summer"
+		})
+		linkRepo.On("Update", ctx, link).Return(nil)
+		linkRepo.On("FindAliasesByLinkID", ctx, linkID).Return([]*model.LinkAlias{{LinkID: linkID, ShortCode: "Ab12Cd34"}}, nil).Once()
+
+		resp, err := svc.UpdateLink(ctx, linkID, dto.UpdateLinkRequest{ShortCode: "matcha-summer"})
+		require.NoError(t, err)
+		assert.Equal(t, "matcha-summer", resp.ShortCode)
+		assert.Equal(t, []string{"Ab12Cd34"}, resp.Aliases)
+		// {{short_code}} in the UTM templates now sends the new code to the marketplace
+		assert.Equal(t, offerURL+"?utm_campaign=summer&utm_content=matcha-summer", link.TargetURL)
+		linkRepo.AssertCalled(t, "Update", ctx, link)
+	})
+
+	t.Run("target URLs without the short code are not rewritten", func(t *testing.T) {
+		svc, linkRepo, _, _, offerRepo := newTestLinkService(t)
+		link := newLink("Ab12Cd34")
+		link.Campaign.UTMContent = ""
+		link.TargetURL = offerURL + "?utm_campaign=summer"
+		linkRepo.On("FindByID", ctx, linkID).Return(link, nil)
+		linkRepo.On("FindAliasesByLinkID", ctx, linkID).Return([]*model.LinkAlias{}, nil)
+		linkRepo.On("ShortCodeExists", ctx, "matcha-summer").Return(false, nil)
+		offerRepo.On("FindByProductIDAndMarketplace", ctx, productID, model.MarketplaceLazada).
+			Return(&model.Offer{Marketplace: model.MarketplaceLazada, MarketplaceProductURL: offerURL}, nil)
+		linkRepo.On("ChangeShortCode", ctx, link, "matcha-summer").Return(nil)
+
+		_, err := svc.UpdateLink(ctx, linkID, dto.UpdateLinkRequest{ShortCode: "matcha-summer"})
+		require.NoError(t, err)
+		linkRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing offer leaves the short code unchanged", func(t *testing.T) {
+		svc, linkRepo, _, _, offerRepo := newTestLinkService(t)
+		linkRepo.On("FindByID", ctx, linkID).Return(newLink("Ab12Cd34"), nil)
+		linkRepo.On("FindAliasesByLinkID", ctx, linkID).Return([]*model.LinkAlias{}, nil)
+		linkRepo.On("ShortCodeExists", ctx, "matcha-summer").Return(false, nil)
+		offerRepo.On("FindByProductIDAndMarketplace", ctx, productID, model.MarketplaceLazada).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.UpdateLink(ctx, linkID, dto.UpdateLinkRequest{ShortCode: "matcha-summer"})
+		assert.ErrorContains(t, err, "failed to build target URL")
+		linkRepo.AssertNotCalled(t, "ChangeShortCode", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("promoting an own alias is not a conflict", func(t *testing.T) {
+		svc, linkRepo, _, _, offerRepo := newTestLinkService(t)
+		link := newLink("matcha-summer")
+		linkRepo.On("FindByID", ctx, linkID).Return(link, nil)
+		linkRepo.On("FindAliasesByLinkID", ctx, linkID).Return([]*model.LinkAlias{{LinkID: linkID, ShortCode: "matcha"}}, nil)
+		offerRepo.On("FindByProductIDAndMarketplace", ctx, productID, model.MarketplaceLazada).
+			Return(&model.Offer{Marketplace: model.MarketplaceLazada, MarketplaceProductURL: offerURL}, nil)
+		linkRepo.On("ChangeShortCode", ctx, link, "matcha").Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*model.Link).ShortCode = "matcha"
+		})
+		linkRepo.On("Update", ctx, link).Return(nil)
+
+		_, err := svc.UpdateLink(ctx, linkID, dto.UpdateLinkRequest{ShortCode: "matcha"})
+		require.NoError(t, err)
+		linkRepo.AssertNotCalled(t, "ShortCodeExists", mock.Anything, mock.Anything)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		svc, linkRepo, _, _, _ := newTestLinkService(t)
+		linkRepo.On("FindByID", ctx, linkID).Return(&model.Link{ID: linkID, ShortCode: "Ab12Cd34"}, nil)
+		linkRepo.On("FindAliasesByLinkID", ctx, linkID).Return([]*model.LinkAlias{}, nil)
+		linkRepo.On("ShortCodeExists", ctx, "taken").Return(true, nil)
+
+		_, err := svc.UpdateLink(ctx, linkID, dto.UpdateLinkRequest{ShortCode: "taken"})
+		assert.EqualError(t, err, "short code already in use: taken")
+		linkRepo.AssertNotCalled(t, "ChangeShortCode", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("pauses and clears the expiry without touching the short code", func(t *testing.T) {
+		svc, linkRepo, _, _, _ := newTestLinkService(t)
+		expiresAt := time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)
+		link := &model.Link{ID: linkID, ShortCode: "matcha-summer", Status: model.LinkStatusActive, ExpiresAt: &expiresAt}
+		linkRepo.On("FindByID", ctx, linkID).Return(link, nil)
+		linkRepo.On("Update", ctx, mock.MatchedBy(func(l *model.Link) bool {
+			return l.Status == model.LinkStatusPaused && l.ExpiresAt == nil
+		})).Return(nil)
+		linkRepo.On("FindAliasesByLinkID", ctx, linkID).Return([]*model.LinkAlias{}, nil)
+
+		resp, err := svc.UpdateLink(ctx, linkID, dto.UpdateLinkRequest{Status: strPtr("Paused"), ClearExpiresAt: true})
+		require.NoError(t, err)
+		assert.Equal(t, "paused", resp.Status)
+		assert.Nil(t, resp.ExpiresAt)
+		linkRepo.AssertNotCalled(t, "ChangeShortCode", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		svc, linkRepo, _, _, _ := newTestLinkService(t)
+		linkRepo.On("FindByID", ctx, linkID).Return(&model.Link{ID: linkID, ShortCode: "matcha-summer"}, nil)
+
+		_, err := svc.UpdateLink(ctx, linkID, dto.UpdateLinkRequest{Status: strPtr("deleted")})
+		assert.EqualError(t, err, "invalid status: must be active, paused or archived")
+		linkRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("link not found", func(t *testing.T) {
+		svc, linkRepo, _, _, _ := newTestLinkService(t)
+		linkRepo.On("FindByID", ctx, linkID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.UpdateLink(ctx, linkID, dto.UpdateLinkRequest{ShortCode: "matcha"})
+		assert.ErrorContains(t, err, "link not found")
+	})
+}
+
+func TestLinkService_LinkAliases(t *testing.T) {
+	ctx := context.Background()
+	linkID := uuid.New()
+
+	t.Run("add", func(t *testing.T) {
+		svc, linkRepo, _, _, _ := newTestLinkService(t)
+		linkRepo.On("FindByID", ctx, linkID).Return(&model.Link{ID: linkID, ShortCode: "matcha-summer"}, nil)
+		linkRepo.On("ShortCodeExists", ctx, "matcha-ig").Return(false, nil)
+		linkRepo.On("CreateAlias", ctx, mock.MatchedBy(func(a *model.LinkAlias) bool {
+			return a.LinkID == linkID && a.ShortCode == "matcha-ig"
+		})).Return(nil)
+		linkRepo.On("FindAliasesByLinkID", ctx, linkID).Return([]*model.LinkAlias{{LinkID: linkID, ShortCode: "matcha-ig"}}, nil)
+
+		resp, err := svc.AddLinkAlias(ctx, linkID, dto.CreateLinkAliasRequest{ShortCode: "Matcha-IG"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"matcha-ig"}, resp.Aliases)
+	})
+
+	t.Run("add conflict", func(t *testing.T) {
+		svc, linkRepo, _, _, _ := newTestLinkService(t)
+		linkRepo.On("FindByID", ctx, linkID).Return(&model.Link{ID: linkID, ShortCode: "matcha-summer"}, nil)
+		linkRepo.On("ShortCodeExists", ctx, "matcha-summer").Return(true, nil)
+
+		_, err := svc.AddLinkAlias(ctx, linkID, dto.CreateLinkAliasRequest{ShortCode: "matcha-summer"})
+		assert.EqualError(t, err, "short code already in use: matcha-summer")
+	})
+
+	t.Run("delete unknown alias", func(t *testing.T) {
+		svc, linkRepo, _, _, _ := newTestLinkService(t)
+		linkRepo.On("FindByID", ctx, linkID).Return(&model.Link{ID: linkID}, nil)
+		linkRepo.On("FindAliasesByLinkID", ctx, linkID).Return([]*model.LinkAlias{}, nil)
+		linkRepo.On("DeleteAlias", ctx, linkID, "nope").Return(gorm.ErrRecordNotFound)
+
+		err := svc.DeleteLinkAlias(ctx, linkID, "nope")
+		assert.EqualError(t, err, "link alias not found: nope")
+	})
+}
+
+func TestLinkService_GetLinkQRCode(t *testing.T) {
+	ctx := context.Background()
+	linkID := uuid.New()
+	link := &model.Link{ID: linkID, ShortCode: "matcha-summer"}
+	const qrURL = "https://api.example.com/go/matcha-summer?src=qr"
+
+	logo, err := qrcode.NewLogo(image.NewRGBA(image.Rect(0, 0, 10, 10)))
+	require.NoError(t, err)
+
+	t.Run("png defaults encode the marked short URL", func(t *testing.T) {
+		svc, linkRepo, _, _, _ := newTestLinkService(t)
+		linkRepo.On("FindByID", ctx, linkID).Return(link, nil)
+
+		qr, err := svc.GetLinkQRCode(ctx, linkID, dto.LinkQRCodeQueryParams{})
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", qr.ContentType)
+		assert.Equal(t, "matcha-summer.png", qr.Filename)
+
+		want, err := qrcode.Encode(qrURL, qrcode.Options{Format: qrcode.FormatPNG, Size: defaultQRSize, Margin: defaultQRMargin, Level: qrcode.LevelMedium})
+		require.NoError(t, err)
+		assert.Equal(t, want, qr.Data)
+	})
+
+	t.Run("svg with logo defaults to high error correction", func(t *testing.T) {
+		svc, linkRepo, _, _, _ := newTestLinkService(t)
+		svc.qrLogo = logo
+		linkRepo.On("FindByID", ctx, linkID).Return(link, nil)
+		margin := 0
+
+		qr, err := svc.GetLinkQRCode(ctx, linkID, dto.LinkQRCodeQueryParams{Format: "SVG", Size: 300, Margin: &margin, Logo: true})
+		require.NoError(t, err)
+		assert.Equal(t, "image/svg+xml", qr.ContentType)
+
+		want, err := qrcode.Encode(qrURL, qrcode.Options{Format: qrcode.FormatSVG, Size: 300, Level: qrcode.LevelHigh, Logo: logo})
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(qr.Data))
+	})
+
+	wideMargin := maxQRMargin + 1
+	invalid := []struct {
+		name    string
+		params  dto.LinkQRCodeQueryParams
+		logo    *qrcode.Logo
+		wantErr string
+	}{
+		{name: "format", params: dto.LinkQRCodeQueryParams{Format: "gif"}, wantErr: "invalid format"},
+		{name: "size", params: dto.LinkQRCodeQueryParams{Size: 4096}, wantErr: "invalid size"},
+		{name: "margin", params: dto.LinkQRCodeQueryParams{Margin: &wideMargin}, wantErr: "invalid margin"},
+		{name: "ecc", params: dto.LinkQRCodeQueryParams{ECC: "x"}, wantErr: "invalid ecc"},
+		{name: "logo not configured", params: dto.LinkQRCodeQueryParams{Logo: true}, wantErr: "invalid logo"},
+		{name: "logo with low ecc", params: dto.LinkQRCodeQueryParams{Logo: true, ECC: "l"}, logo: logo, wantErr: "invalid ecc"},
+	}
+	for _, tt := range invalid {
+		t.Run("invalid "+tt.name, func(t *testing.T) {
+			svc, linkRepo, _, _, _ := newTestLinkService(t)
+			svc.qrLogo = tt.logo
+			linkRepo.On("FindByID", ctx, linkID).Return(link, nil)
+
+			_, err := svc.GetLinkQRCode(ctx, linkID, tt.params)
+			require.Error(t, err)
+			assert.True(t, strings.HasPrefix(err.Error(), tt.wantErr), err.Error())
+		})
+	}
+
+	t.Run("size too small for the symbol", func(t *testing.T) {
+		svc, linkRepo, _, _, _ := newTestLinkService(t)
+		linkRepo.On("FindByID", ctx, linkID).Return(link, nil)
+		margin := maxQRMargin
+
+		_, err := svc.GetLinkQRCode(ctx, linkID, dto.LinkQRCodeQueryParams{Size: minQRSize, Margin: &margin})
+		require.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "invalid size"), err.Error())
+	})
+
+	t.Run("link not found", func(t *testing.T) {
+		svc, linkRepo, _, _, _ := newTestLinkService(t)
+		linkRepo.On("FindByID", ctx, linkID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.GetLinkQRCode(ctx, linkID, dto.LinkQRCodeQueryParams{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "link not found")
+	})
+}
@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	"github.com/jonosize/affiliate-platform/pkg/adapters"
+)
+
+// maxVariantWeight bounds a variant's relative weight
+const maxVariantWeight = 10000
+
+// variantConfidenceLevel and variantZScore set the width of variant stats intervals
+const (
+	variantConfidenceLevel = 0.95
+	variantZScore          = 1.959964
+)
+
+// LinkVariantService handles A/B split link business logic
+type LinkVariantService struct {
+	linkRepo       LinkRepositoryInterface
+	campaignRepo   CampaignRepositoryInterface
+	offerRepo      OfferRepositoryInterface
+	clickRepo      ClickRepositoryInterface
+	conversionRepo ConversionRepositoryInterface
+	registry       *adapters.Registry
+	logger         logger.Logger
+}
+
+// NewLinkVariantService creates a new link variant service
+func NewLinkVariantService(
+	linkRepo LinkRepositoryInterface,
+	campaignRepo CampaignRepositoryInterface,
+	offerRepo OfferRepositoryInterface,
+	clickRepo ClickRepositoryInterface,
+	conversionRepo ConversionRepositoryInterface,
+	registry *adapters.Registry,
+	log logger.Logger,
+) *LinkVariantService {
+	return &LinkVariantService{
+		linkRepo:       linkRepo,
+		campaignRepo:   campaignRepo,
+		offerRepo:      offerRepo,
+		clickRepo:      clickRepo,
+		conversionRepo: conversionRepo,
+		registry:       registry,
+		logger:         log,
+	}
+}
+
+// GetVariants gets a link's A/B variants in creation order
+func (s *LinkVariantService) GetVariants(ctx context.Context, linkID uuid.UUID) ([]*dto.LinkVariantResponse, error) {
+	if _, err := s.linkRepo.FindByID(ctx, linkID); err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	variants, err := s.linkRepo.FindVariantsByLinkID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variants: %w", err)
+	}
+
+	responses := make([]*dto.LinkVariantResponse, len(variants))
+	for i, variant := range variants {
+		responses[i] = toLinkVariantResponse(variant)
+	}
+	return responses, nil
+}
+
+// CreateVariant adds an A/B variant to a link
+func (s *LinkVariantService) CreateVariant(ctx context.Context, linkID uuid.UUID, req dto.CreateLinkVariantRequest) (*dto.LinkVariantResponse, error) {
+	link, err := s.linkRepo.FindByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+	if req.Weight == nil {
+		return nil, fmt.Errorf("invalid weight: required")
+	}
+
+	variant := &model.LinkVariant{
+		LinkID:    linkID,
+		Name:      strings.TrimSpace(req.Name),
+		Weight:    *req.Weight,
+		TargetURL: strings.TrimSpace(req.TargetURL),
+	}
+	if req.Marketplace != "" {
+		m := model.Marketplace(strings.ToLower(req.Marketplace))
+		variant.Marketplace = &m
+	}
+
+	if err := s.prepareVariant(ctx, link, variant); err != nil {
+		return nil, err
+	}
+
+	if err := s.linkRepo.CreateVariant(ctx, variant); err != nil {
+		return nil, fmt.Errorf("failed to create variant: %w", err)
+	}
+
+	s.logger.Info("Link variant created",
+		logger.String("link_id", linkID.String()),
+		logger.String("variant_id", variant.ID.String()),
+		logger.Int("weight", variant.Weight),
+	)
+
+	return toLinkVariantResponse(variant), nil
+}
+
+// UpdateVariant updates one of a link's A/B variants
+func (s *LinkVariantService) UpdateVariant(ctx context.Context, linkID, variantID uuid.UUID, req dto.UpdateLinkVariantRequest) (*dto.LinkVariantResponse, error) {
+	link, err := s.linkRepo.FindByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+	variant, err := s.linkRepo.FindVariant(ctx, linkID, variantID)
+	if err != nil {
+		return nil, fmt.Errorf("variant not found: %w", err)
+	}
+
+	// Update fields if provided
+	if req.Name != nil {
+		variant.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Weight != nil {
+		variant.Weight = *req.Weight
+	}
+	if req.Marketplace != nil {
+		variant.Marketplace = nil
+		if *req.Marketplace != "" {
+			m := model.Marketplace(strings.ToLower(*req.Marketplace))
+			variant.Marketplace = &m
+		}
+	}
+	if req.TargetURL != nil {
+		variant.TargetURL = strings.TrimSpace(*req.TargetURL)
+	}
+
+	if err := s.prepareVariant(ctx, link, variant); err != nil {
+		return nil, err
+	}
+
+	if err := s.linkRepo.UpdateVariant(ctx, variant); err != nil {
+		return nil, fmt.Errorf("failed to update variant: %w", err)
+	}
+
+	return toLinkVariantResponse(variant), nil
+}
+
+// DeleteVariant deletes one of a link's A/B variants; its clicks are kept without a variant
+func (s *LinkVariantService) DeleteVariant(ctx context.Context, linkID, variantID uuid.UUID) error {
+	if _, err := s.linkRepo.FindByID(ctx, linkID); err != nil {
+		return fmt.Errorf("link not found: %w", err)
+	}
+
+	if err := s.linkRepo.DeleteVariant(ctx, linkID, variantID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("variant not found: %s", variantID)
+		}
+		return fmt.Errorf("failed to delete variant: %w", err)
+	}
+
+	return nil
+}
+
+// GetVariantStats compares a link's A/B variants: clicks, conversions and revenue of
+// the clicks sent to each variant in the time range, with confidence intervals for
+// conversion rates and for each variant's difference from the control (first) variant
+func (s *LinkVariantService) GetVariantStats(ctx context.Context, linkID uuid.UUID, params dto.LinkVariantStatsQueryParams) (*dto.LinkVariantStatsResponse, error) {
+	link, err := s.linkRepo.FindByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	to := time.Now()
+	if params.To != nil {
+		to = *params.To
+	}
+	from := to.Add(-defaultStatsWindow)
+	if params.From != nil {
+		from = *params.From
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("invalid date range: to must be after from")
+	}
+
+	variants, err := s.linkRepo.FindVariantsByLinkID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variants: %w", err)
+	}
+
+	clickResults, err := s.clickRepo.CountByVariantForLink(ctx, linkID, from, to, params.IncludeBots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count variant clicks: %w", err)
+	}
+	conversionResults, err := s.conversionRepo.SumByVariantForLink(ctx, linkID, from, to, params.IncludeBots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum variant conversions: %w", err)
+	}
+
+	clicksByVariant := make(map[uuid.UUID]model.VariantClickResult, len(clickResults))
+	var totalClicks int64
+	for _, r := range clickResults {
+		clicksByVariant[r.VariantID] = r
+		totalClicks += r.Clicks
+	}
+	conversionsByVariant := make(map[uuid.UUID]model.VariantConversionResult, len(conversionResults))
+	for _, r := range conversionResults {
+		conversionsByVariant[r.VariantID] = r
+	}
+	totalWeight := 0
+	for _, v := range variants {
+		totalWeight += v.Weight
+	}
+
+	response := &dto.LinkVariantStatsResponse{
+		LinkID:          link.ID,
+		ShortCode:       link.ShortCode,
+		From:            from,
+		To:              to,
+		ConfidenceLevel: variantConfidenceLevel,
+		Variants:        make([]dto.LinkVariantStats, len(variants)),
+	}
+
+	for i, v := range variants {
+		clicks := clicksByVariant[v.ID]
+		conversions := conversionsByVariant[v.ID]
+
+		stats := dto.LinkVariantStats{
+			VariantID:    v.ID,
+			Name:         v.Name,
+			Weight:       v.Weight,
+			Clicks:       clicks.Clicks,
+			UniqueClicks: clicks.UniqueClicks,
+			Conversions:  conversions.Conversions,
+			Revenue:      conversions.Revenue,
+		}
+		if v.Marketplace != nil {
+			m := string(*v.Marketplace)
+			stats.Marketplace = &m
+		}
+		if totalWeight > 0 {
+			stats.WeightShare = float64(v.Weight) / float64(totalWeight) * 100.0
+		}
+		if totalClicks > 0 {
+			stats.ClickShare = float64(clicks.Clicks) / float64(totalClicks) * 100.0
+		}
+		if clicks.Clicks > 0 {
+			stats.ConversionRate = float64(conversions.Conversions) / float64(clicks.Clicks) * 100.0
+			stats.EPC = conversions.Revenue / float64(clicks.Clicks)
+		}
+		lower, upper := wilsonInterval(conversions.Conversions, clicks.Clicks, variantZScore)
+		stats.ConversionRateInterval = dto.ConfidenceInterval{Lower: lower * 100.0, Upper: upper * 100.0}
+
+		if i > 0 {
+			control := response.Variants[0]
+			controlConversions := conversionsByVariant[variants[0].ID].Conversions
+			diff, lower, upper := differenceInterval(conversions.Conversions, clicks.Clicks, controlConversions, control.Clicks, variantZScore)
+			stats.VsControl = &dto.VariantComparison{
+				Difference:  diff * 100.0,
+				Interval:    dto.ConfidenceInterval{Lower: lower * 100.0, Upper: upper * 100.0},
+				Significant: lower > 0 || upper < 0,
+			}
+		}
+
+		response.Variants[i] = stats
+	}
+
+	return response, nil
+}
+
+// prepareVariant validates a variant, resolving the target URL of marketplace variants
+func (s *LinkVariantService) prepareVariant(ctx context.Context, link *model.Link, variant *model.LinkVariant) error {
+	if variant.Name == "" || len(variant.Name) > 100 {
+		return fmt.Errorf("invalid name: must be 1-100 characters")
+	}
+	if variant.Weight < 0 || variant.Weight > maxVariantWeight {
+		return fmt.Errorf("invalid weight: must be between 0 and %d", maxVariantWeight)
+	}
+
+	siblings, err := s.linkRepo.FindVariantsByLinkID(ctx, link.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get variants: %w", err)
+	}
+	for _, sibling := range siblings {
+		if sibling.ID != variant.ID && strings.EqualFold(sibling.Name, variant.Name) {
+			return fmt.Errorf("invalid name: the link already has a variant named %q", variant.Name)
+		}
+	}
+
+	if variant.Marketplace == nil {
+		if !isAbsoluteHTTPURL(variant.TargetURL) {
+			return fmt.Errorf("invalid target_url: landing URL variants need an absolute http(s) URL")
+		}
+		return nil
+	}
+
+	if !s.registry.Has(adapters.Marketplace(*variant.Marketplace)) {
+		return fmt.Errorf("invalid marketplace: must be one of the supported marketplaces")
+	}
+	offer, err := s.offerRepo.FindByProductIDAndMarketplace(ctx, link.ProductID, *variant.Marketplace)
+	if err != nil {
+		return fmt.Errorf("invalid marketplace: product has no %s offer", *variant.Marketplace)
+	}
+	// Resolved when the variant is saved, like a link's own target URL
+	targetURL, err := offerTargetURL(ctx, s.campaignRepo, link, offer.MarketplaceProductURL, *variant.Marketplace)
+	if err != nil {
+		return err
+	}
+	variant.TargetURL = targetURL
+	return nil
+}
+
+// variantKey is the key a visitor is assigned to a variant by
+// Clicks without a visitor ID (not expected from the redirect handler) are assigned at random
+func variantKey(linkID uuid.UUID, meta dto.ClickMetadata) string {
+	if meta.VisitorID == "" {
+		return meta.ClickID.String()
+	}
+	return linkID.String() + ":" + meta.VisitorID
+}
+
+// selectVariant picks the variant for key, in proportion to the variants' weights
+// The same key always gets the same variant while the variants are unchanged, so
+// visitors keep their variant without any stored assignment. Returns nil if no
+// variant has weight.
+func selectVariant(variants []model.LinkVariant, key string) *model.LinkVariant {
+	total := 0
+	for _, v := range variants {
+		if v.Weight > 0 {
+			total += v.Weight
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	bucket := int(h.Sum64() % uint64(total))
+	for i := range variants {
+		if variants[i].Weight <= 0 {
+			continue
+		}
+		if bucket < variants[i].Weight {
+			return &variants[i]
+		}
+		bucket -= variants[i].Weight
+	}
+	return nil
+}
+
+// wilsonInterval returns the Wilson score interval for a proportion of successes in trials
+// Returns 0, 0 without trials
+func wilsonInterval(successes, trials int64, z float64) (float64, float64) {
+	if trials <= 0 {
+		return 0, 0
+	}
+	// Conversions can outnumber clicks when a network reports several orders per click
+	if successes > trials {
+		successes = trials
+	}
+
+	n := float64(trials)
+	p := float64(successes) / n
+	z2 := z * z
+	denominator := 1 + z2/n
+	center := (p + z2/(2*n)) / denominator
+	halfWidth := z * math.Sqrt(p*(1-p)/n+z2/(4*n*n)) / denominator
+	return math.Max(0, center-halfWidth), math.Min(1, center+halfWidth)
+}
+
+// differenceInterval returns the difference between two proportions (a - b) with
+// Newcombe's hybrid score interval, built from each proportion's Wilson interval
+func differenceInterval(successesA, trialsA, successesB, trialsB int64, z float64) (float64, float64, float64) {
+	pA, pB := proportion(successesA, trialsA), proportion(successesB, trialsB)
+	lA, uA := wilsonInterval(successesA, trialsA, z)
+	lB, uB := wilsonInterval(successesB, trialsB, z)
+	if trialsA <= 0 {
+		lA, uA = 0, 1
+	}
+	if trialsB <= 0 {
+		lB, uB = 0, 1
+	}
+
+	diff := pA - pB
+	lower := diff - math.Sqrt((pA-lA)*(pA-lA)+(uB-pB)*(uB-pB))
+	upper := diff + math.Sqrt((uA-pA)*(uA-pA)+(pB-lB)*(pB-lB))
+	return diff, lower, upper
+}
+
+// proportion returns successes / trials, capped at 1, or 0 without trials
+func proportion(successes, trials int64) float64 {
+	if trials <= 0 {
+		return 0
+	}
+	return math.Min(1, float64(successes)/float64(trials))
+}
+
+// toLinkVariantResponse converts a link variant model to DTO
+func toLinkVariantResponse(variant *model.LinkVariant) *dto.LinkVariantResponse {
+	response := &dto.LinkVariantResponse{
+		ID:        variant.ID,
+		LinkID:    variant.LinkID,
+		Name:      variant.Name,
+		Weight:    variant.Weight,
+		TargetURL: variant.TargetURL,
+		CreatedAt: variant.CreatedAt,
+		UpdatedAt: variant.UpdatedAt,
+	}
+	if variant.Marketplace != nil {
+		m := string(*variant.Marketplace)
+		response.Marketplace = &m
+	}
+	return response
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	mockadapter "github.com/jonosize/affiliate-platform/pkg/adapters/mock"
+)
+
+func TestSelectVariant(t *testing.T) {
+	variants := []model.LinkVariant{
+		{ID: uuid.New(), Name: "lazada", Weight: 70},
+		{ID: uuid.New(), Name: "paused", Weight: 0},
+		{ID: uuid.New(), Name: "shopee", Weight: 30},
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		key := fmt.Sprintf("link:visitor-%d", i)
+		v := selectVariant(variants, key)
+		require.NotNil(t, v)
+		counts[v.Name]++
+
+		assert.Equal(t, v, selectVariant(variants, key), "assignment is sticky per key")
+	}
+
+	assert.InDelta(t, 7000, counts["lazada"], 300)
+	assert.InDelta(t, 3000, counts["shopee"], 300)
+	assert.Zero(t, counts["paused"], "zero-weight variants get no traffic")
+
+	assert.Nil(t, selectVariant(nil, "key"))
+	assert.Nil(t, selectVariant([]model.LinkVariant{{Weight: 0}}, "key"))
+}
+
+func TestWilsonInterval(t *testing.T) {
+	lower, upper := wilsonInterval(10, 100, variantZScore)
+	assert.InDelta(t, 0.0552, lower, 0.0001)
+	assert.InDelta(t, 0.1744, upper, 0.0001)
+
+	lower, upper = wilsonInterval(0, 10, variantZScore)
+	assert.Equal(t, 0.0, lower)
+	assert.InDelta(t, 0.2775, upper, 0.0001)
+
+	lower, upper = wilsonInterval(3, 2, variantZScore)
+	assert.InDelta(t, 0.3424, lower, 0.0001, "capped at one conversion per click")
+	assert.Equal(t, 1.0, upper)
+
+	lower, upper = wilsonInterval(0, 0, variantZScore)
+	assert.Equal(t, 0.0, lower)
+	assert.Equal(t, 0.0, upper)
+}
+
+func TestDifferenceInterval(t *testing.T) {
+	// Newcombe (1998), example (a): 56/70 vs 48/80
+	diff, lower, upper := differenceInterval(56, 70, 48, 80, variantZScore)
+	assert.InDelta(t, 0.2, diff, 0.0001)
+	assert.InDelta(t, 0.0524, lower, 0.0001)
+	assert.InDelta(t, 0.3339, upper, 0.0001)
+
+	// Without data the difference is unknown, not significant
+	diff, lower, upper = differenceInterval(0, 0, 0, 0, variantZScore)
+	assert.Equal(t, 0.0, diff)
+	assert.Less(t, lower, 0.0)
+	assert.Greater(t, upper, 0.0)
+}
+
+func TestRedirectService_Redirect_Variants(t *testing.T) {
+	ctx := context.Background()
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	registry, err := mockadapter.NewRegistry()
+	require.NoError(t, err)
+
+	shopee := model.MarketplaceShopee
+	shopeeVariant := model.LinkVariant{
+		ID:          uuid.New(),
+		Name:        "shopee",
+		Weight:      1,
+		Marketplace: &shopee,
+		TargetURL:   "https://shopee.co.th/product/123456/789012?utm_source=affiliate",
+	}
+	landingVariant := model.LinkVariant{
+		ID:        uuid.New(),
+		Name:      "landing",
+		Weight:    1,
+		TargetURL: "https://brand.example.com/matcha?utm_source=ab",
+	}
+	iosRule := model.RedirectRule{
+		ID:         uuid.New(),
+		OS:         []string{osIOS},
+		TargetType: model.RedirectTargetWeb,
+		TargetURL:  "https://brand.example.com/ios",
+		IsActive:   true,
+	}
+
+	redirect := func(t *testing.T, variants []model.LinkVariant, userAgent string) (*dto.RedirectTarget, *model.Click) {
+		link := &model.Link{
+			ID:            uuid.New(),
+			Marketplace:   model.MarketplaceLazada,
+			ShortCode:     "abc123",
+			TargetURL:     "https://www.lazada.co.th/products/matcha-i123456.html",
+			RedirectRules: []model.RedirectRule{iosRule},
+			Variants:      variants,
+		}
+		linkRepo := new(MockLinkRepository)
+		linkRepo.On("FindByShortCode", ctx, "abc123").Return(link, nil)
+
+		var tracked *model.Click
+		clickRepo := new(MockClickRepository)
+		clickRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			tracked = args.Get(1).(*model.Click)
+		}).Return(nil)
+
+		svc := NewRedirectService(linkRepo, nil, NewClickService(clickRepo, linkRepo, nil, nil, nil, log), registry, nil, nil, log)
+		target, err := svc.Redirect(ctx, "abc123", dto.ClickMetadata{Method: "GET", UserAgent: userAgent, VisitorID: "visitor-1"})
+		require.NoError(t, err)
+		require.NotNil(t, tracked)
+		return target, tracked
+	}
+
+	t.Run("marketplace variant gets its marketplace's sub-IDs", func(t *testing.T) {
+		target, click := redirect(t, []model.LinkVariant{shopeeVariant}, chromeAndroidUA)
+
+		u, err := url.Parse(target.URL)
+		require.NoError(t, err)
+		assert.Equal(t, "shopee.co.th", u.Host)
+		assert.Equal(t, FormatClickID(click.ID), u.Query().Get("sub_id"))
+		require.NotNil(t, click.VariantID)
+		assert.Equal(t, shopeeVariant.ID, *click.VariantID)
+	})
+
+	t.Run("landing URL variant", func(t *testing.T) {
+		target, click := redirect(t, []model.LinkVariant{landingVariant}, chromeAndroidUA)
+
+		assert.Equal(t, landingVariant.TargetURL, target.URL)
+		require.NotNil(t, click.VariantID)
+		assert.Equal(t, landingVariant.ID, *click.VariantID)
+	})
+
+	t.Run("matching redirect rules take precedence", func(t *testing.T) {
+		iPhoneUA := "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"
+		target, click := redirect(t, []model.LinkVariant{shopeeVariant}, iPhoneUA)
+
+		assert.Equal(t, "https://brand.example.com/ios", target.URL)
+		assert.Nil(t, click.VariantID, "the click is not part of the experiment")
+	})
+
+	t.Run("link without variants", func(t *testing.T) {
+		target, click := redirect(t, nil, chromeAndroidUA)
+
+		assert.Contains(t, target.URL, "www.lazada.co.th")
+		assert.Nil(t, click.VariantID)
+	})
+}
+
+func newTestLinkVariantService(t *testing.T) (*LinkVariantService, *MockLinkRepository, *MockCampaignRepository, *MockOfferRepository, *MockClickRepository, *MockConversionRepository) {
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	registry, err := mockadapter.NewRegistry()
+	require.NoError(t, err)
+
+	linkRepo := new(MockLinkRepository)
+	campaignRepo := new(MockCampaignRepository)
+	offerRepo := new(MockOfferRepository)
+	clickRepo := new(MockClickRepository)
+	conversionRepo := new(MockConversionRepository)
+	svc := NewLinkVariantService(linkRepo, campaignRepo, offerRepo, clickRepo, conversionRepo, registry, log)
+	return svc, linkRepo, campaignRepo, offerRepo, clickRepo, conversionRepo
+}
+
+func TestLinkVariantService_CreateVariant(t *testing.T) {
+	ctx := context.Background()
+	link := &model.Link{
+		ID:          uuid.New(),
+		ProductID:   uuid.New(),
+		CampaignID:  uuid.New(),
+		Marketplace: model.MarketplaceLazada,
+		ShortCode:   "abc123",
+	}
+	weight := func(w int) *int { return &w }
+
+	t.Run("marketplace variants resolve their target URL from the offer", func(t *testing.T) {
+		svc, linkRepo, campaignRepo, offerRepo, _, _ := newTestLinkVariantService(t)
+		linkRepo.On("FindByID", ctx, link.ID).Return(link, nil)
+		linkRepo.On("FindVariantsByLinkID", ctx, link.ID).Return([]*model.LinkVariant{{ID: uuid.New(), Name: "lazada"}}, nil)
+		offerRepo.On("FindByProductIDAndMarketplace", ctx, link.ProductID, model.MarketplaceShopee).
+			Return(&model.Offer{MarketplaceProductURL: "https://shopee.co.th/product/123456/789012"}, nil)
+		campaignRepo.On("FindByID", ctx, link.CampaignID).
+			Return(&model.Campaign{ID: link.CampaignID, UTMCampaign: "summer", UTMSource: "{{marketplace}}"}, nil)
+		linkRepo.On("CreateVariant", ctx, mock.Anything).Return(nil)
+
+		variant, err := svc.CreateVariant(ctx, link.ID, dto.CreateLinkVariantRequest{Name: " shopee ", Weight: weight(30), Marketplace: "Shopee"})
+		require.NoError(t, err)
+		assert.Equal(t, "shopee", variant.Name)
+		assert.Equal(t, 30, variant.Weight)
+		require.NotNil(t, variant.Marketplace)
+		assert.Equal(t, "shopee", *variant.Marketplace)
+
+		u, err := url.Parse(variant.TargetURL)
+		require.NoError(t, err)
+		assert.Equal(t, "shopee.co.th", u.Host)
+		assert.Equal(t, "shopee", u.Query().Get("utm_source"), "placeholders expand for the variant's marketplace")
+	})
+
+	invalid := []struct {
+		name    string
+		req     dto.CreateLinkVariantRequest
+		wantErr string
+	}{
+		{
+			name:    "missing weight",
+			req:     dto.CreateLinkVariantRequest{Name: "b", TargetURL: "https://brand.example.com"},
+			wantErr: "invalid weight: required",
+		},
+		{
+			name:    "negative weight",
+			req:     dto.CreateLinkVariantRequest{Name: "b", Weight: weight(-1), TargetURL: "https://brand.example.com"},
+			wantErr: "invalid weight: must be between 0 and 10000",
+		},
+		{
+			name:    "duplicate name",
+			req:     dto.CreateLinkVariantRequest{Name: "Lazada", Weight: weight(50), TargetURL: "https://brand.example.com"},
+			wantErr: `invalid name: the link already has a variant named "Lazada"`,
+		},
+		{
+			name:    "landing URL must be http(s)",
+			req:     dto.CreateLinkVariantRequest{Name: "b", Weight: weight(50), TargetURL: "javascript:alert(1)"},
+			wantErr: "invalid target_url: landing URL variants need an absolute http(s) URL",
+		},
+		{
+			name:    "unknown marketplace",
+			req:     dto.CreateLinkVariantRequest{Name: "b", Weight: weight(50), Marketplace: "amazon"},
+			wantErr: "invalid marketplace: must be one of the supported marketplaces",
+		},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, linkRepo, _, _, _, _ := newTestLinkVariantService(t)
+			linkRepo.On("FindByID", ctx, link.ID).Return(link, nil)
+			linkRepo.On("FindVariantsByLinkID", ctx, link.ID).Return([]*model.LinkVariant{{ID: uuid.New(), Name: "lazada"}}, nil)
+
+			_, err := svc.CreateVariant(ctx, link.ID, tt.req)
+			assert.EqualError(t, err, tt.wantErr)
+			linkRepo.AssertNotCalled(t, "CreateVariant", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestLinkVariantService_GetVariantStats(t *testing.T) {
+	ctx := context.Background()
+	svc, linkRepo, _, _, clickRepo, conversionRepo := newTestLinkVariantService(t)
+
+	link := &model.Link{ID: uuid.New(), ShortCode: "abc123"}
+	lazada, shopee := model.MarketplaceLazada, model.MarketplaceShopee
+	control := &model.LinkVariant{ID: uuid.New(), Name: "lazada", Weight: 70, Marketplace: &lazada}
+	challenger := &model.LinkVariant{ID: uuid.New(), Name: "shopee", Weight: 30, Marketplace: &shopee}
+
+	to := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -30)
+
+	linkRepo.On("FindByID", ctx, link.ID).Return(link, nil)
+	linkRepo.On("FindVariantsByLinkID", ctx, link.ID).Return([]*model.LinkVariant{control, challenger}, nil)
+	clickRepo.On("CountByVariantForLink", ctx, link.ID, from, to, false).Return([]model.VariantClickResult{
+		{VariantID: control.ID, Clicks: 700, UniqueClicks: 600},
+		{VariantID: challenger.ID, Clicks: 300, UniqueClicks: 280},
+	}, nil)
+	conversionRepo.On("SumByVariantForLink", ctx, link.ID, from, to, false).Return([]model.VariantConversionResult{
+		{VariantID: control.ID, Conversions: 14, Revenue: 700},
+		{VariantID: challenger.ID, Conversions: 18, Revenue: 450},
+	}, nil)
+
+	stats, err := svc.GetVariantStats(ctx, link.ID, dto.LinkVariantStatsQueryParams{From: &from, To: &to})
+	require.NoError(t, err)
+	assert.Equal(t, variantConfidenceLevel, stats.ConfidenceLevel)
+	require.Len(t, stats.Variants, 2)
+
+	first := stats.Variants[0]
+	assert.Equal(t, "lazada", first.Name)
+	assert.InDelta(t, 70.0, first.WeightShare, 0.001)
+	assert.InDelta(t, 70.0, first.ClickShare, 0.001)
+	assert.InDelta(t, 2.0, first.ConversionRate, 0.001)
+	assert.InDelta(t, 1.0, first.EPC, 0.001)
+	assert.Less(t, first.ConversionRateInterval.Lower, 2.0)
+	assert.Greater(t, first.ConversionRateInterval.Upper, 2.0)
+	assert.Nil(t, first.VsControl, "the control is not compared with itself")
+
+	second := stats.Variants[1]
+	assert.Equal(t, int64(280), second.UniqueClicks)
+	assert.InDelta(t, 6.0, second.ConversionRate, 0.001)
+	require.NotNil(t, second.VsControl)
+	assert.InDelta(t, 4.0, second.VsControl.Difference, 0.001)
+	assert.Greater(t, second.VsControl.Interval.Lower, 0.0)
+	assert.True(t, second.VsControl.Significant)
+
+	_, err = svc.GetVariantStats(ctx, link.ID, dto.LinkVariantStatsQueryParams{From: &to, To: &from})
+	assert.EqualError(t, err, "invalid date range: to must be after from")
+}
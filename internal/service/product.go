@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,93 +14,88 @@ import (
 	"github.com/jonosize/affiliate-platform/internal/model"
 	"github.com/jonosize/affiliate-platform/internal/validator"
 	"github.com/jonosize/affiliate-platform/pkg/adapters"
-	"github.com/jonosize/affiliate-platform/pkg/adapters/mock"
 )
 
 // ProductService handles product business logic
 type ProductService struct {
-	productRepo   ProductRepositoryInterface
-	offerRepo     OfferRepositoryInterface
-	lazadaAdapter adapters.MarketplaceAdapter
-	shopeeAdapter adapters.MarketplaceAdapter
-	logger        logger.Logger
+	productRepo      ProductRepositoryInterface
+	offerRepo        OfferRepositoryInterface
+	priceHistoryRepo PriceHistoryRepositoryInterface
+	registry         *adapters.Registry
+	linkCache        LinkCache
+	audit            *AuditService
+	logger           logger.Logger
 }
 
 // NewProductService creates a new product service
 func NewProductService(
 	productRepo ProductRepositoryInterface,
 	offerRepo OfferRepositoryInterface,
-	lazadaAdapter adapters.MarketplaceAdapter,
-	shopeeAdapter adapters.MarketplaceAdapter,
+	priceHistoryRepo PriceHistoryRepositoryInterface,
+	registry *adapters.Registry,
+	linkCache LinkCache,
+	audit *AuditService,
 	log logger.Logger,
 ) *ProductService {
 	return &ProductService{
-		productRepo:   productRepo,
-		offerRepo:     offerRepo,
-		lazadaAdapter: lazadaAdapter,
-		shopeeAdapter: shopeeAdapter,
-		logger:        log,
+		productRepo:      productRepo,
+		offerRepo:        offerRepo,
+		priceHistoryRepo: priceHistoryRepo,
+		registry:         registry,
+		linkCache:        linkCache,
+		audit:            audit,
+		logger:           log,
 	}
 }
 
-// CreateProduct creates a product from Lazada and/or Shopee URLs
+// marketplaceURL is a product URL provided for a specific marketplace
+type marketplaceURL struct {
+	marketplace adapters.Marketplace
+	url         string
+}
+
+// CreateProduct creates a product from one or more marketplace URLs
 func (s *ProductService) CreateProduct(ctx context.Context, req dto.CreateProductRequest) (*dto.ProductResponse, error) {
-	if req.LazadaURL == "" && req.ShopeeURL == "" {
-		return nil, fmt.Errorf("at least one URL (Lazada or Shopee) must be provided")
+	sources, err := s.collectMarketplaceURLs(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("at least one marketplace URL must be provided")
 	}
 
-	var productTitle string
-	var productImageURL string
-	var primaryProductURL string
-	var randomSourceID int // To store the source_id if a random product is selected
-
-	// Try to fetch product data from Lazada first if URL is provided
-	if req.LazadaURL != "" {
-		if _, _, err := validator.ValidateProductURL(req.LazadaURL); err != nil {
-			return nil, fmt.Errorf("invalid Lazada URL: %w", err)
+	// Validate every URL before fetching anything
+	for _, src := range sources {
+		marketplace, _, err := validator.ValidateProductURL(s.registry, src.url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s URL: %w", src.marketplace, err)
 		}
-		productData, err := s.lazadaAdapter.FetchProduct(ctx, req.LazadaURL, adapters.SourceTypeURL)
-		if err == nil && productData != nil {
-			productTitle = productData.Title
-			productImageURL = productData.ImageURL
-			primaryProductURL = productData.MarketplaceProductURL
-			randomSourceID = productData.SourceID // Capture source ID from mock adapter
-		} else {
-			s.logger.Warn("Failed to fetch Lazada product data", logger.Error(err), logger.String("url", req.LazadaURL))
+		if marketplace != src.marketplace {
+			return nil, fmt.Errorf("invalid %s URL: URL belongs to %s", src.marketplace, marketplace)
 		}
 	}
 
-	// If no Lazada URL or fetching failed, try Shopee if URL is provided
-	if productTitle == "" && req.ShopeeURL != "" {
-		if _, _, err := validator.ValidateProductURL(req.ShopeeURL); err != nil {
-			return nil, fmt.Errorf("invalid Shopee URL: %w", err)
+	var productTitle string
+	var productImageURL string
+	var sourceID int // Source ID reported by the adapter, if any (e.g. mock catalog)
+
+	// Fetch product data from the first marketplace that succeeds, in registration order
+	for _, src := range sources {
+		adapter, err := s.registry.Get(src.marketplace)
+		if err != nil {
+			return nil, err
 		}
-		productData, err := s.shopeeAdapter.FetchProduct(ctx, req.ShopeeURL, adapters.SourceTypeURL)
+		productData, err := adapter.FetchProduct(ctx, src.url, adapters.SourceTypeURL)
 		if err == nil && productData != nil {
 			productTitle = productData.Title
 			productImageURL = productData.ImageURL
-			primaryProductURL = productData.MarketplaceProductURL
-			randomSourceID = productData.SourceID // Capture source ID from mock adapter
-		} else {
-			s.logger.Warn("Failed to fetch Shopee product data", logger.Error(err), logger.String("url", req.ShopeeURL))
+			sourceID = productData.SourceID
+			break
 		}
+		s.logger.Warn("Failed to fetch product data", logger.Error(err), logger.String("marketplace", string(src.marketplace)), logger.String("url", src.url))
 	}
 
-	// If no specific URL was provided, but we still need a product (e.g., for random selection)
-	if productTitle == "" && req.LazadaURL == "" && req.ShopeeURL == "" {
-		// Call FetchProduct with a dummy URL to trigger random selection in mock adapter
-		// The actual URL doesn't matter here, as mock adapter will pick a random product
-		productData, err := s.lazadaAdapter.FetchProduct(ctx, "https://www.lazada.co.th/products/random", adapters.SourceTypeURL)
-		if err == nil && productData != nil {
-			productTitle = productData.Title
-			productImageURL = productData.ImageURL
-			primaryProductURL = productData.MarketplaceProductURL
-			randomSourceID = productData.SourceID // Capture source ID from mock adapter
-		} else {
-			s.logger.Error("Failed to fetch random product data", logger.Error(err))
-			return nil, fmt.Errorf("failed to fetch product data from any provided URL or random selection")
-		}
-	} else if productTitle == "" {
+	if productTitle == "" {
 		return nil, fmt.Errorf("failed to fetch product data from any provided URL")
 	}
 
@@ -112,110 +109,23 @@ func (s *ProductService) CreateProduct(ctx context.Context, req dto.CreateProduc
 		return nil, fmt.Errorf("failed to create product: %w", err)
 	}
 
-	// Fetch offers from adapters
-	offers := make([]*model.Offer, 0)
-
-	// Determine which marketplaces to fetch offers for
-	// If specific URLs are provided, use those; otherwise try both
-	hasLazadaURL := req.LazadaURL != ""
-	hasShopeeURL := req.ShopeeURL != ""
-
-	// Check if source_id is available (from FetchProduct)
-	// If yes, use FetchOfferBySourceID to get offers for the random product
-	hasSourceID := randomSourceID > 0
-
-	// Fetch Lazada offer if:
-	// 1. Lazada URL is explicitly provided, OR
-	// 2. No URLs are provided (fetch both platforms)
-	if hasLazadaURL || (!hasLazadaURL && !hasShopeeURL) {
-		var offerData *adapters.OfferData
-		var err error
-
-		// If we have source_id from FetchProduct, use FetchOfferBySourceID
-		// Otherwise, try FetchOffer with URL
-		if hasSourceID {
-			// Cast to MockAdapter to access FetchOfferBySourceID
-			if mockAdapter, ok := s.lazadaAdapter.(*mock.MockAdapter); ok {
-				offerData, err = mockAdapter.FetchOfferBySourceID(ctx, randomSourceID, adapters.MarketplaceLazada)
-			} else {
-				// Fallback to FetchOffer if not MockAdapter
-				var lazadaOfferURL string
-				if hasLazadaURL {
-					lazadaOfferURL = req.LazadaURL
-				} else {
-					lazadaOfferURL = primaryProductURL
-				}
-				offerData, err = s.lazadaAdapter.FetchOffer(ctx, lazadaOfferURL)
-			}
-		} else {
-			// Use URL-based FetchOffer
-			var lazadaOfferURL string
-			if hasLazadaURL {
-				lazadaOfferURL = req.LazadaURL
-			} else {
-				lazadaOfferURL = primaryProductURL
-			}
-			offerData, err = s.lazadaAdapter.FetchOffer(ctx, lazadaOfferURL)
-		}
-
-		if err == nil && offerData != nil {
-			offer := &model.Offer{
-				ProductID:             product.ID,
-				Marketplace:           model.Marketplace(adapters.MarketplaceLazada),
-				StoreName:             offerData.StoreName,
-				Price:                 offerData.Price,
-				MarketplaceProductURL: offerData.MarketplaceProductURL,
-				LastCheckedAt:         time.Now(),
-			}
-			offers = append(offers, offer)
-		}
-	}
-
-	// Fetch Shopee offer if:
-	// 1. Shopee URL is explicitly provided, OR
-	// 2. No URLs are provided (fetch both platforms)
-	if hasShopeeURL || (!hasLazadaURL && !hasShopeeURL) {
-		var offerData *adapters.OfferData
-		var err error
-
-		// If we have source_id from FetchProduct, use FetchOfferBySourceID
-		// Otherwise, try FetchOffer with URL
-		if hasSourceID {
-			// Cast to MockAdapter to access FetchOfferBySourceID
-			if mockAdapter, ok := s.shopeeAdapter.(*mock.MockAdapter); ok {
-				offerData, err = mockAdapter.FetchOfferBySourceID(ctx, randomSourceID, adapters.MarketplaceShopee)
-			} else {
-				// Fallback to FetchOffer if not MockAdapter
-				var shopeeOfferURL string
-				if hasShopeeURL {
-					shopeeOfferURL = req.ShopeeURL
-				} else {
-					shopeeOfferURL = primaryProductURL
-				}
-				offerData, err = s.shopeeAdapter.FetchOffer(ctx, shopeeOfferURL)
-			}
-		} else {
-			// Use URL-based FetchOffer
-			var shopeeOfferURL string
-			if hasShopeeURL {
-				shopeeOfferURL = req.ShopeeURL
-			} else {
-				shopeeOfferURL = primaryProductURL
-			}
-			offerData, err = s.shopeeAdapter.FetchOffer(ctx, shopeeOfferURL)
+	// Fetch an offer for every marketplace a URL was provided for
+	offers := make([]*model.Offer, 0, len(sources))
+	for _, src := range sources {
+		offerData, err := s.fetchOffer(ctx, src, sourceID)
+		if err != nil || offerData == nil {
+			s.logger.Warn("Failed to fetch offer", logger.Error(err), logger.String("marketplace", string(src.marketplace)))
+			continue
 		}
 
-		if err == nil && offerData != nil {
-			offer := &model.Offer{
-				ProductID:             product.ID,
-				Marketplace:           model.Marketplace(adapters.MarketplaceShopee),
-				StoreName:             offerData.StoreName,
-				Price:                 offerData.Price,
-				MarketplaceProductURL: offerData.MarketplaceProductURL,
-				LastCheckedAt:         time.Now(),
-			}
-			offers = append(offers, offer)
-		}
+		offers = append(offers, &model.Offer{
+			ProductID:             product.ID,
+			Marketplace:           model.Marketplace(src.marketplace),
+			StoreName:             offerData.StoreName,
+			Price:                 offerData.Price,
+			MarketplaceProductURL: offerData.MarketplaceProductURL,
+			LastCheckedAt:         time.Now(),
+		})
 	}
 
 	// Save offers
@@ -223,6 +133,12 @@ func (s *ProductService) CreateProduct(ctx context.Context, req dto.CreateProduc
 		if err := s.offerRepo.Upsert(ctx, offer); err != nil {
 			s.logger.Error("Failed to save offer", logger.Error(err), logger.String("marketplace", string(offer.Marketplace)))
 			// Continue with other offers
+			continue
+		}
+
+		// Record the initial price point
+		if err := s.priceHistoryRepo.Create(ctx, model.NewOfferPriceHistory(offer)); err != nil {
+			s.logger.Warn("Failed to record price history", logger.Error(err), logger.String("marketplace", string(offer.Marketplace)))
 		}
 	}
 
@@ -249,9 +165,60 @@ func (s *ProductService) CreateProduct(ctx context.Context, req dto.CreateProduc
 		}
 	}
 
+	s.audit.Record(ctx, model.AuditActionCreate, model.AuditEntityProduct, product.ID, nil, response)
+
 	return response, nil
 }
 
+// collectMarketplaceURLs gathers the marketplace URLs from the request in registry order
+// Dedicated fields (lazada_url, shopee_url) take precedence over marketplace_urls
+func (s *ProductService) collectMarketplaceURLs(req dto.CreateProductRequest) ([]marketplaceURL, error) {
+	urls := make(map[adapters.Marketplace]string)
+	for key, rawURL := range req.MarketplaceURLs {
+		if rawURL != "" {
+			urls[adapters.Marketplace(strings.ToLower(strings.TrimSpace(key)))] = rawURL
+		}
+	}
+	if req.LazadaURL != "" {
+		urls[adapters.MarketplaceLazada] = req.LazadaURL
+	}
+	if req.ShopeeURL != "" {
+		urls[adapters.MarketplaceShopee] = req.ShopeeURL
+	}
+
+	for marketplace := range urls {
+		if !s.registry.Has(marketplace) {
+			return nil, fmt.Errorf("unsupported marketplace: %s", marketplace)
+		}
+	}
+
+	sources := make([]marketplaceURL, 0, len(urls))
+	for _, marketplace := range s.registry.Marketplaces() {
+		if rawURL, ok := urls[marketplace]; ok {
+			sources = append(sources, marketplaceURL{marketplace: marketplace, url: rawURL})
+		}
+	}
+
+	return sources, nil
+}
+
+// fetchOffer fetches the offer for a marketplace URL
+// Adapters that can look up offers by source ID (mock catalog) use it so offers match the product
+func (s *ProductService) fetchOffer(ctx context.Context, src marketplaceURL, sourceID int) (*adapters.OfferData, error) {
+	adapter, err := s.registry.Get(src.marketplace)
+	if err != nil {
+		return nil, err
+	}
+
+	if sourceID > 0 {
+		if fetcher, ok := adapter.(adapters.SourceIDOfferFetcher); ok {
+			return fetcher.FetchOfferBySourceID(ctx, sourceID, src.marketplace)
+		}
+	}
+
+	return adapter.FetchOffer(ctx, src.url)
+}
+
 // GetProductOffers gets offers for a product
 func (s *ProductService) GetProductOffers(ctx context.Context, productID uuid.UUID) (*dto.ProductOffersResponse, error) {
 	// Check if product exists
@@ -283,22 +250,90 @@ func (s *ProductService) GetProductOffers(ctx context.Context, productID uuid.UU
 	}
 
 	// Calculate best price
-	if len(offers) > 0 {
-		bestOffer := offers[0] // Already sorted by price ASC
-		for _, offer := range offers {
-			if offer.Price < bestOffer.Price {
-				bestOffer = offer
-			}
-		}
-		response.BestPrice = &dto.BestPrice{
-			Marketplace: string(bestOffer.Marketplace),
-			Price:       bestOffer.Price,
+	response.BestPrice = toBestPrice(offers)
+
+	return response, nil
+}
+
+// GetPriceHistory gets the price history series of a product with min/max/avg
+func (s *ProductService) GetPriceHistory(ctx context.Context, productID uuid.UUID, params dto.PriceHistoryQueryParams) (*dto.PriceHistoryResponse, error) {
+	// Check if product exists
+	if _, err := s.productRepo.FindByID(ctx, productID); err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+
+	// Validate marketplace filter
+	if params.Marketplace != nil && !s.registry.Has(adapters.Marketplace(*params.Marketplace)) {
+		return nil, fmt.Errorf("invalid marketplace: %s is not supported", *params.Marketplace)
+	}
+
+	from := time.Time{}
+	to := time.Time{}
+	if params.From != nil {
+		from = *params.From
+	}
+	if params.To != nil {
+		to = *params.To
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return nil, fmt.Errorf("invalid date range: to must be after from")
+	}
+
+	entries, err := s.priceHistoryRepo.FindByProductID(ctx, productID, params.Marketplace, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price history: %w", err)
+	}
+
+	response := &dto.PriceHistoryResponse{
+		ProductID: productID,
+		From:      params.From,
+		To:        params.To,
+		Series:    make([]dto.PriceHistoryPoint, len(entries)),
+		Stats:     priceHistoryStats(entries),
+	}
+	if params.Marketplace != nil {
+		response.Marketplace = *params.Marketplace
+	}
+
+	for i, e := range entries {
+		response.Series[i] = dto.PriceHistoryPoint{
+			Marketplace: string(e.Marketplace),
+			StoreName:   e.StoreName,
+			Price:       e.Price,
+			RecordedAt:  e.RecordedAt,
 		}
 	}
 
 	return response, nil
 }
 
+// priceHistoryStats calculates min/max/avg over a price series, nil when empty
+func priceHistoryStats(entries []*model.OfferPriceHistory) *dto.PriceHistoryStats {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	stats := &dto.PriceHistoryStats{
+		Min:   entries[0].Price,
+		Max:   entries[0].Price,
+		Count: len(entries),
+	}
+
+	sum := 0.0
+	for _, e := range entries {
+		if e.Price < stats.Min {
+			stats.Min = e.Price
+		}
+		if e.Price > stats.Max {
+			stats.Max = e.Price
+		}
+		sum += e.Price
+	}
+	stats.Avg = math.Round(sum/float64(len(entries))*100) / 100
+
+	return stats
+}
+
 // GetAllProducts gets all products with pagination
 func (s *ProductService) GetAllProducts(ctx context.Context, limit, offset int) ([]*dto.ProductResponse, error) {
 	// Get products from repository
@@ -310,48 +345,105 @@ func (s *ProductService) GetAllProducts(ctx context.Context, limit, offset int)
 	// Convert to response
 	responses := make([]*dto.ProductResponse, len(products))
 	for i, product := range products {
-		responses[i] = &dto.ProductResponse{
-			ID:        product.ID,
-			Title:     product.Title,
-			ImageURL:  product.ImageURL,
-			CreatedAt: product.CreatedAt,
-		}
+		responses[i] = toProductResponse(product)
+	}
+
+	return responses, nil
+}
 
-		// Convert offers
-		if len(product.Offers) > 0 {
-			responses[i].Offers = make([]dto.OfferResponse, len(product.Offers))
-			for j, offer := range product.Offers {
-				responses[i].Offers[j] = dto.OfferResponse{
-					ID:            offer.ID,
-					Marketplace:   string(offer.Marketplace),
-					StoreName:     offer.StoreName,
-					Price:         offer.Price,
-					LastCheckedAt: offer.LastCheckedAt,
-				}
+// toProductResponse converts a product model and its loaded offers to DTO
+func toProductResponse(product *model.Product) *dto.ProductResponse {
+	response := &dto.ProductResponse{
+		ID:        product.ID,
+		Title:     product.Title,
+		ImageURL:  product.ImageURL,
+		CreatedAt: product.CreatedAt,
+	}
+
+	// Convert offers
+	if len(product.Offers) > 0 {
+		response.Offers = make([]dto.OfferResponse, len(product.Offers))
+		for i, offer := range product.Offers {
+			response.Offers[i] = dto.OfferResponse{
+				ID:            offer.ID,
+				Marketplace:   string(offer.Marketplace),
+				StoreName:     offer.StoreName,
+				Price:         offer.Price,
+				LastCheckedAt: offer.LastCheckedAt,
 			}
 		}
 	}
 
-	return responses, nil
+	return response
 }
 
-// DeleteProduct deletes a product and all related data
-// CASCADE constraints will automatically delete:
-// - Offers (ON DELETE CASCADE)
-// - Links (ON DELETE CASCADE)
-// - CampaignProducts (ON DELETE CASCADE)
-// - Clicks (via links, ON DELETE CASCADE)
+// DeleteProduct soft-deletes a product
+// The product, its links and its place in campaigns are hidden until it is
+// restored; its offers, links and clicks are kept, so dashboard history still
+// counts them. PurgeProduct removes it for good.
 func (s *ProductService) DeleteProduct(ctx context.Context, productID uuid.UUID) error {
 	// Check if product exists
-	_, err := s.productRepo.FindByID(ctx, productID)
+	product, err := s.productRepo.FindByID(ctx, productID)
 	if err != nil {
 		return fmt.Errorf("product not found: %w", err)
 	}
 
-	// Delete product (CASCADE will handle related data)
+	// Soft-delete product (related data is kept)
 	if err := s.productRepo.Delete(ctx, productID); err != nil {
 		return fmt.Errorf("failed to delete product: %w", err)
 	}
+	s.invalidateLinks(ctx, productID)
+
+	s.audit.Record(ctx, model.AuditActionDelete, model.AuditEntityProduct, productID, toProductResponse(product), nil)
+
+	return nil
+}
+
+// RestoreProduct undoes a product's soft deletion, bringing back its links
+func (s *ProductService) RestoreProduct(ctx context.Context, productID uuid.UUID) (*dto.ProductResponse, error) {
+	product, err := s.productRepo.FindDeletedByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("deleted product not found: %w", err)
+	}
+
+	if err := s.productRepo.Restore(ctx, productID); err != nil {
+		return nil, fmt.Errorf("failed to restore product: %w", err)
+	}
+	s.invalidateLinks(ctx, productID)
+
+	response := toProductResponse(product)
+	s.audit.Record(ctx, model.AuditActionRestore, model.AuditEntityProduct, productID, nil, response)
+
+	return response, nil
+}
+
+// invalidateLinks drops the cached short codes of a product's links, which its
+// deletion hides and its restore brings back
+func (s *ProductService) invalidateLinks(ctx context.Context, productID uuid.UUID) {
+	if s.linkCache != nil {
+		s.linkCache.InvalidateProductLinks(ctx, productID)
+	}
+}
+
+// PurgeProduct permanently deletes a soft-deleted product
+// confirm must repeat the product ID. CASCADE constraints delete its offers,
+// campaign associations, links and their clicks, which drops them from
+// dashboard history.
+func (s *ProductService) PurgeProduct(ctx context.Context, productID uuid.UUID, confirm string) error {
+	if confirmID, err := uuid.Parse(confirm); err != nil || confirmID != productID {
+		return fmt.Errorf("invalid confirmation: confirm must repeat the product ID")
+	}
+
+	product, err := s.productRepo.FindDeletedByID(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("deleted product not found: %w", err)
+	}
+
+	if err := s.productRepo.Purge(ctx, productID); err != nil {
+		return fmt.Errorf("failed to purge product: %w", err)
+	}
+
+	s.audit.Record(ctx, model.AuditActionPurge, model.AuditEntityProduct, productID, toProductResponse(product), nil)
 
 	return nil
 }
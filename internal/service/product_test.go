@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+func TestPriceHistoryStats(t *testing.T) {
+	assert.Nil(t, priceHistoryStats(nil))
+
+	entries := []*model.OfferPriceHistory{
+		{Marketplace: model.MarketplaceLazada, Price: 299},
+		{Marketplace: model.MarketplaceShopee, Price: 279},
+		{Marketplace: model.MarketplaceLazada, Price: 349.5},
+	}
+
+	stats := priceHistoryStats(entries)
+	assert.Equal(t, 279.0, stats.Min)
+	assert.Equal(t, 349.5, stats.Max)
+	assert.Equal(t, 309.17, stats.Avg)
+	assert.Equal(t, 3, stats.Count)
+}
+
+func TestProductService_RestoreAndPurge(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+	product := &model.Product{ID: productID, Title: "Headphones"}
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+
+	newService := func() (*ProductService, *MockProductRepository, *MockLinkCache, *MockAuditEventRepository) {
+		audit, auditRepo := newTestAuditService(t)
+		productRepo := new(MockProductRepository)
+		linkCache := new(MockLinkCache)
+		return NewProductService(productRepo, nil, nil, nil, linkCache, audit, log), productRepo, linkCache, auditRepo
+	}
+
+	t.Run("restore", func(t *testing.T) {
+		svc, productRepo, linkCache, auditRepo := newService()
+		productRepo.On("FindDeletedByID", ctx, productID).Return(product, nil)
+		productRepo.On("Restore", ctx, productID).Return(nil)
+		linkCache.On("InvalidateProductLinks", ctx, productID).Return()
+		var event *model.AuditEvent
+		auditRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.AuditEvent")).
+			Run(func(args mock.Arguments) { event = args.Get(1).(*model.AuditEvent) }).
+			Return(nil)
+
+		response, err := svc.RestoreProduct(ctx, productID)
+		require.NoError(t, err)
+		assert.Equal(t, "Headphones", response.Title)
+		require.NotNil(t, event)
+		assert.Equal(t, model.AuditActionRestore, event.Action)
+		assert.Equal(t, "Headphones", event.After["title"])
+		productRepo.AssertExpectations(t)
+		linkCache.AssertExpectations(t) // Drops negative entries for the links it brings back
+	})
+
+	t.Run("restore of a product that is not deleted", func(t *testing.T) {
+		svc, productRepo, _, _ := newService()
+		productRepo.On("FindDeletedByID", ctx, productID).Return(nil, errors.New("record not found"))
+
+		_, err := svc.RestoreProduct(ctx, productID)
+		assert.ErrorContains(t, err, "deleted product not found")
+		productRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		svc, productRepo, _, auditRepo := newService()
+		productRepo.On("FindDeletedByID", ctx, productID).Return(product, nil)
+		productRepo.On("Purge", ctx, productID).Return(nil)
+		var event *model.AuditEvent
+		auditRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.AuditEvent")).
+			Run(func(args mock.Arguments) { event = args.Get(1).(*model.AuditEvent) }).
+			Return(nil)
+
+		require.NoError(t, svc.PurgeProduct(ctx, productID, productID.String()))
+		require.NotNil(t, event)
+		assert.Equal(t, model.AuditActionPurge, event.Action)
+		assert.Equal(t, "Headphones", event.Before["title"])
+		productRepo.AssertExpectations(t)
+	})
+
+	t.Run("purge needs the product ID as confirmation", func(t *testing.T) {
+		svc, productRepo, _, _ := newService()
+
+		for _, confirm := range []string{"", "yes", uuid.New().String()} {
+			err := svc.PurgeProduct(ctx, productID, confirm)
+			assert.ErrorContains(t, err, "invalid confirmation", confirm)
+		}
+		productRepo.AssertNotCalled(t, "Purge", mock.Anything, mock.Anything)
+	})
+}
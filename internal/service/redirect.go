@@ -2,49 +2,241 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net"
+	"time"
 
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/config"
+	"github.com/jonosize/affiliate-platform/internal/dto"
 	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
 	"github.com/jonosize/affiliate-platform/internal/validator"
+	"github.com/jonosize/affiliate-platform/pkg/adapters"
 )
 
+// ErrLinkNotFound is returned by Redirect for unknown short codes and for codes
+// not served on the request's host, which are indistinguishable to callers
+var ErrLinkNotFound = errors.New("link not found")
+
 // RedirectService handles redirect business logic
 type RedirectService struct {
-	linkRepo LinkRepositoryInterface
-	clickSvc *ClickService
-	logger   logger.Logger
+	linkRepo  LinkRepositoryInterface
+	offerRepo OfferRepositoryInterface
+	clickSvc  *ClickService
+	registry  *adapters.Registry
+	geo       CountryResolver
+	cfg       config.Config
+	logger    logger.Logger
 }
 
 // NewRedirectService creates a new redirect service
-func NewRedirectService(linkRepo LinkRepositoryInterface, clickSvc *ClickService, log logger.Logger) *RedirectService {
+// geo may be nil, in which case country redirect rules never match
+func NewRedirectService(linkRepo LinkRepositoryInterface, offerRepo OfferRepositoryInterface, clickSvc *ClickService, registry *adapters.Registry, geo CountryResolver, cfg config.Config, log logger.Logger) *RedirectService {
 	return &RedirectService{
-		linkRepo: linkRepo,
-		clickSvc: clickSvc,
-		logger:   log,
+		linkRepo:  linkRepo,
+		offerRepo: offerRepo,
+		clickSvc:  clickSvc,
+		registry:  registry,
+		geo:       geo,
+		cfg:       cfg,
+		logger:    log,
 	}
 }
 
-// Redirect handles redirect logic: finds link, validates URL, tracks click
-func (s *RedirectService) Redirect(ctx context.Context, shortCode string, ipAddress net.IP, userAgent, referrer string) (string, error) {
+// Redirect handles redirect logic: finds link, applies the link's lifecycle and
+// the campaign window policy, validates URL, mints a click ID, tracks the click
+// and returns the target URL with the marketplace's sub-ID parameters
+// The link's first redirect rule matching the visitor's OS, country and language
+// replaces the default target; otherwise split links send the visitor to their
+// A/B variant, recorded with the click. Best-price links target the product's
+// cheapest offer at click time and record its marketplace with the click.
+// Clicks that end on the fallback URL or the "offer ended" page are tracked with
+// their outcome; the latter returns an "offer ended" error. Links of a workspace
+// with its own redirect domain are not found on other hosts (see servedOnHost).
+func (s *RedirectService) Redirect(ctx context.Context, shortCode string, meta dto.ClickMetadata) (*dto.RedirectTarget, error) {
 	// Find link by short code
 	link, err := s.linkRepo.FindByShortCode(ctx, shortCode)
 	if err != nil {
-		return "", fmt.Errorf("link not found: %w", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to find link: %w", err)
+	}
+	if !servedOnHost(link.Workspace, meta.Host, s.cfg) {
+		// Answer as for unknown codes so other workspaces' codes can't be probed
+		return nil, ErrLinkNotFound
+	}
+
+	outcome, reason := redirectOutcome(link, time.Now())
+	meta.Outcome = string(outcome)
+	meta.OutcomeReason = reason
+
+	switch outcome {
+	case model.ClickOutcomeFallback:
+		// The fallback URL is configured by campaign admins, so it is neither
+		// whitelisted nor given marketplace sub-IDs
+		s.trackClick(ctx, link, meta)
+		return &dto.RedirectTarget{URL: link.Campaign.FallbackURL}, nil
+	case model.ClickOutcomeGone:
+		s.trackClick(ctx, link, meta)
+		return nil, fmt.Errorf("offer ended: %s", reason)
+	}
+
+	// Mint the click ID up front so the marketplace can echo it back in conversion reports
+	meta.ClickID = uuid.New()
+
+	if link.Marketplace == model.MarketplaceBestPrice {
+		link = s.resolveBestPrice(ctx, link)
+		meta.Marketplace = string(link.Marketplace)
+	}
+
+	// Redirect rules target specific visitors, so they take precedence over A/B variants
+	rule := matchRedirectRule(link.RedirectRules, s.visitor(link, meta))
+	var variant *model.LinkVariant
+	if rule == nil {
+		variant = selectVariant(link.Variants, variantKey(link.ID, meta))
+	}
+
+	targetURL, err := s.defaultTargetURL(link, variant, meta)
+	if err != nil {
+		return nil, err
+	}
+	if variant != nil {
+		meta.VariantID = &variant.ID
+	}
+
+	target := &dto.RedirectTarget{URL: targetURL}
+	if rule != nil {
+		ruleTarget, err := s.ruleTarget(rule, targetURL, meta)
+		if err != nil {
+			// A broken rule must not break the link: use the default target
+			s.logger.Warn("Failed to apply redirect rule", logger.Error(err),
+				logger.String("short_code", shortCode), logger.String("rule_id", rule.ID.String()))
+		} else {
+			target = ruleTarget
+		}
+	}
+
+	s.trackClick(ctx, link, meta)
+
+	return target, nil
+}
+
+// defaultTargetURL returns the link's target URL, or its A/B variant's, with the click's sub-IDs
+// Marketplace URLs are validated against the whitelist; landing URL variants are
+// admin-configured, like web redirect rules
+func (s *RedirectService) defaultTargetURL(link *model.Link, variant *model.LinkVariant, meta dto.ClickMetadata) (string, error) {
+	baseURL, marketplace := link.TargetURL, link.Marketplace
+	if variant != nil {
+		if variant.Marketplace == nil {
+			return s.withSubIDs(variant.TargetURL, meta), nil
+		}
+		baseURL, marketplace = variant.TargetURL, *variant.Marketplace
 	}
 
 	// Validate redirect URL (whitelist domains)
-	if !validator.ValidateRedirectURL(link.TargetURL) {
-		s.logger.Error("Invalid redirect URL", logger.String("url", link.TargetURL), logger.String("short_code", shortCode))
+	if !validator.ValidateRedirectURL(s.registry, baseURL) {
+		s.logger.Error("Invalid redirect URL", logger.String("url", baseURL), logger.String("short_code", link.ShortCode))
+		return "", fmt.Errorf("invalid redirect URL")
+	}
+
+	targetURL, err := s.registry.AppendSubIDs(adapters.Marketplace(marketplace), baseURL, FormatClickID(meta.ClickID), meta.SubIDs)
+	if err != nil {
+		s.logger.Error("Failed to append sub IDs", logger.Error(err), logger.String("short_code", link.ShortCode))
 		return "", fmt.Errorf("invalid redirect URL")
 	}
+	return targetURL, nil
+}
+
+// resolveBestPrice returns a copy of a best-price link targeting the product's cheapest offer
+// When the offers can't be loaded, the link keeps the target of its last campaign sync
+func (s *RedirectService) resolveBestPrice(ctx context.Context, link *model.Link) *model.Link {
+	resolved := *link
+
+	offers, err := s.offerRepo.FindByProductID(ctx, link.ProductID)
+	if err != nil {
+		s.logger.Warn("Failed to get offers for best-price link", logger.Error(err), logger.String("link_id", link.ID.String()))
+	} else if offer := bestOffer(offers); offer != nil {
+		targetURL, err := linkTargetURLFor(offer.MarketplaceProductURL, offer.Marketplace, &link.Campaign, link)
+		if err == nil {
+			resolved.Marketplace = offer.Marketplace
+			resolved.TargetURL = targetURL
+			return &resolved
+		}
+		s.logger.Warn("Failed to build best-price target URL", logger.Error(err), logger.String("link_id", link.ID.String()))
+	}
+
+	// An unknown host is rejected by the whitelist check
+	if marketplace, err := s.registry.MatchURL(link.TargetURL); err == nil {
+		resolved.Marketplace = model.Marketplace(marketplace)
+	}
+	return &resolved
+}
+
+// visitor resolves the attributes redirect rules match on
+// The country lookup is skipped for links without rules
+func (s *RedirectService) visitor(link *model.Link, meta dto.ClickMetadata) redirectVisitor {
+	v := redirectVisitor{
+		os:       classifyOS(meta.UserAgent),
+		language: primaryLanguage(meta.AcceptLanguage),
+	}
+	if s.geo != nil && len(link.RedirectRules) > 0 && meta.IPAddress != nil {
+		v.country = s.geo.Country(meta.IPAddress)
+	}
+	return v
+}
 
-	// Track click (async - don't block redirect)
-	go func() {
-		if err := s.clickSvc.TrackClick(context.Background(), link.ID, ipAddress, userAgent, referrer); err != nil {
-			s.logger.Error("Failed to track click", logger.String("error", err.Error()), logger.String("link_id", link.ID.String()))
+// ruleTarget builds the redirect target of a matching rule
+// Marketplace URLs (including web targets on a marketplace) get the click's sub-IDs;
+// deep links fall back to the rule's fallback URL, or to the link's default target.
+func (s *RedirectService) ruleTarget(rule *model.RedirectRule, defaultURL string, meta dto.ClickMetadata) (*dto.RedirectTarget, error) {
+	switch rule.TargetType {
+	case model.RedirectTargetWeb:
+		return &dto.RedirectTarget{URL: s.withSubIDs(rule.TargetURL, meta)}, nil
+
+	case model.RedirectTargetMarketplace:
+		if rule.Marketplace == nil || !validator.ValidateRedirectURL(s.registry, rule.TargetURL) {
+			return nil, fmt.Errorf("invalid marketplace target URL")
+		}
+		targetURL, err := s.registry.AppendSubIDs(adapters.Marketplace(*rule.Marketplace), rule.TargetURL, FormatClickID(meta.ClickID), meta.SubIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to append sub IDs: %w", err)
+		}
+		return &dto.RedirectTarget{URL: targetURL}, nil
+
+	case model.RedirectTargetDeepLink:
+		fallbackURL := defaultURL
+		if rule.FallbackURL != "" {
+			fallbackURL = s.withSubIDs(rule.FallbackURL, meta)
 		}
-	}()
+		return &dto.RedirectTarget{URL: fallbackURL, AppURL: rule.TargetURL}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown target type %q", rule.TargetType)
+	}
+}
 
-	return link.TargetURL, nil
+// withSubIDs adds the click's sub-IDs to a URL on a known marketplace
+// Other URLs are returned unchanged
+func (s *RedirectService) withSubIDs(rawURL string, meta dto.ClickMetadata) string {
+	marketplace, err := s.registry.MatchURL(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	withSubIDs, err := s.registry.AppendSubIDs(marketplace, rawURL, FormatClickID(meta.ClickID), meta.SubIDs)
+	if err != nil {
+		return rawURL
+	}
+	return withSubIDs
+}
+
+// trackClick tracks a click (enqueued for batched persistence - a dropped click never fails the redirect)
+func (s *RedirectService) trackClick(ctx context.Context, link *model.Link, meta dto.ClickMetadata) {
+	meta.WorkspaceID = link.WorkspaceID
+	if err := s.clickSvc.TrackClick(ctx, link.ID, meta); err != nil {
+		s.logger.Warn("Failed to track click", logger.Error(err), logger.String("link_id", link.ID.String()))
+	}
 }
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	"github.com/jonosize/affiliate-platform/pkg/adapters"
+)
+
+var (
+	// countryCodePattern matches ISO 3166-1 alpha-2 country codes (after upper-casing)
+	countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+	// languagePattern matches primary language subtags (after lower-casing)
+	languagePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+)
+
+// blockedDeepLinkSchemes can run script or read local data in the browser
+var blockedDeepLinkSchemes = map[string]bool{
+	"javascript": true,
+	"data":       true,
+	"vbscript":   true,
+	"file":       true,
+	"blob":       true,
+}
+
+// RedirectRuleService handles redirect rule business logic
+type RedirectRuleService struct {
+	linkRepo     LinkRepositoryInterface
+	campaignRepo CampaignRepositoryInterface
+	offerRepo    OfferRepositoryInterface
+	registry     *adapters.Registry
+	logger       logger.Logger
+}
+
+// NewRedirectRuleService creates a new redirect rule service
+func NewRedirectRuleService(
+	linkRepo LinkRepositoryInterface,
+	campaignRepo CampaignRepositoryInterface,
+	offerRepo OfferRepositoryInterface,
+	registry *adapters.Registry,
+	log logger.Logger,
+) *RedirectRuleService {
+	return &RedirectRuleService{
+		linkRepo:     linkRepo,
+		campaignRepo: campaignRepo,
+		offerRepo:    offerRepo,
+		registry:     registry,
+		logger:       log,
+	}
+}
+
+// GetRedirectRules gets a link's redirect rules in evaluation order
+func (s *RedirectRuleService) GetRedirectRules(ctx context.Context, linkID uuid.UUID) ([]*dto.RedirectRuleResponse, error) {
+	if _, err := s.linkRepo.FindByID(ctx, linkID); err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	rules, err := s.linkRepo.FindRedirectRulesByLinkID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get redirect rules: %w", err)
+	}
+
+	responses := make([]*dto.RedirectRuleResponse, len(rules))
+	for i, rule := range rules {
+		responses[i] = toRedirectRuleResponse(rule)
+	}
+	return responses, nil
+}
+
+// CreateRedirectRule adds a redirect rule to a link
+func (s *RedirectRuleService) CreateRedirectRule(ctx context.Context, linkID uuid.UUID, req dto.CreateRedirectRuleRequest) (*dto.RedirectRuleResponse, error) {
+	link, err := s.linkRepo.FindByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+
+	rule := &model.RedirectRule{
+		LinkID:      linkID,
+		Name:        strings.TrimSpace(req.Name),
+		Priority:    req.Priority,
+		OS:          req.OS,
+		Countries:   req.Countries,
+		Languages:   req.Languages,
+		TargetType:  model.RedirectTargetType(req.TargetType),
+		TargetURL:   strings.TrimSpace(req.TargetURL),
+		FallbackURL: strings.TrimSpace(req.FallbackURL),
+		IsActive:    true,
+	}
+	if req.Marketplace != "" {
+		m := model.Marketplace(strings.ToLower(req.Marketplace))
+		rule.Marketplace = &m
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := s.prepareRedirectRule(ctx, link, rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.linkRepo.CreateRedirectRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create redirect rule: %w", err)
+	}
+
+	s.logger.Info("Redirect rule created",
+		logger.String("link_id", linkID.String()),
+		logger.String("rule_id", rule.ID.String()),
+		logger.String("target_type", string(rule.TargetType)),
+	)
+
+	return toRedirectRuleResponse(rule), nil
+}
+
+// UpdateRedirectRule updates one of a link's redirect rules
+func (s *RedirectRuleService) UpdateRedirectRule(ctx context.Context, linkID, ruleID uuid.UUID, req dto.UpdateRedirectRuleRequest) (*dto.RedirectRuleResponse, error) {
+	link, err := s.linkRepo.FindByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+	rule, err := s.linkRepo.FindRedirectRule(ctx, linkID, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("redirect rule not found: %w", err)
+	}
+
+	// Update fields if provided
+	if req.Name != nil {
+		rule.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Priority != nil {
+		rule.Priority = *req.Priority
+	}
+	if req.OS != nil {
+		rule.OS = req.OS
+	}
+	if req.Countries != nil {
+		rule.Countries = req.Countries
+	}
+	if req.Languages != nil {
+		rule.Languages = req.Languages
+	}
+	if req.TargetType != nil {
+		rule.TargetType = model.RedirectTargetType(*req.TargetType)
+	}
+	if req.TargetURL != nil {
+		rule.TargetURL = strings.TrimSpace(*req.TargetURL)
+	}
+	if req.FallbackURL != nil {
+		rule.FallbackURL = strings.TrimSpace(*req.FallbackURL)
+	}
+	if req.Marketplace != nil {
+		rule.Marketplace = nil
+		if *req.Marketplace != "" {
+			m := model.Marketplace(strings.ToLower(*req.Marketplace))
+			rule.Marketplace = &m
+		}
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := s.prepareRedirectRule(ctx, link, rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.linkRepo.UpdateRedirectRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to update redirect rule: %w", err)
+	}
+
+	return toRedirectRuleResponse(rule), nil
+}
+
+// DeleteRedirectRule deletes one of a link's redirect rules
+func (s *RedirectRuleService) DeleteRedirectRule(ctx context.Context, linkID, ruleID uuid.UUID) error {
+	if _, err := s.linkRepo.FindByID(ctx, linkID); err != nil {
+		return fmt.Errorf("link not found: %w", err)
+	}
+
+	if err := s.linkRepo.DeleteRedirectRule(ctx, linkID, ruleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("redirect rule not found: %s", ruleID)
+		}
+		return fmt.Errorf("failed to delete redirect rule: %w", err)
+	}
+
+	return nil
+}
+
+// prepareRedirectRule normalizes and validates a rule, resolving the target URL of marketplace targets
+func (s *RedirectRuleService) prepareRedirectRule(ctx context.Context, link *model.Link, rule *model.RedirectRule) error {
+	if len(rule.Name) > 200 {
+		return fmt.Errorf("invalid redirect rule: name must be 200 characters or less")
+	}
+
+	var err error
+	if rule.OS, err = normalizeRuleValues(rule.OS, "os", strings.ToLower, func(v string) bool {
+		for _, os := range deviceOSes {
+			if v == os {
+				return true
+			}
+		}
+		return false
+	}); err != nil {
+		return err
+	}
+	if rule.Countries, err = normalizeRuleValues(rule.Countries, "countries", strings.ToUpper, countryCodePattern.MatchString); err != nil {
+		return err
+	}
+	if rule.Languages, err = normalizeRuleValues(rule.Languages, "languages", strings.ToLower, languagePattern.MatchString); err != nil {
+		return err
+	}
+
+	switch rule.TargetType {
+	case model.RedirectTargetWeb:
+		if !isAbsoluteHTTPURL(rule.TargetURL) {
+			return fmt.Errorf("invalid redirect rule: target_url must be an absolute http(s) URL")
+		}
+		rule.FallbackURL = ""
+		rule.Marketplace = nil
+
+	case model.RedirectTargetDeepLink:
+		u, err := url.Parse(rule.TargetURL)
+		if err != nil || u.Scheme == "" || blockedDeepLinkSchemes[strings.ToLower(u.Scheme)] {
+			return fmt.Errorf("invalid redirect rule: target_url must be an app deep link (e.g. lazada://...) or an http(s) URL")
+		}
+		if rule.FallbackURL != "" && !isAbsoluteHTTPURL(rule.FallbackURL) {
+			return fmt.Errorf("invalid redirect rule: fallback_url must be an absolute http(s) URL")
+		}
+		rule.Marketplace = nil
+
+	case model.RedirectTargetMarketplace:
+		if rule.Marketplace == nil || !s.registry.Has(adapters.Marketplace(*rule.Marketplace)) {
+			return fmt.Errorf("invalid redirect rule: marketplace must be one of the supported marketplaces")
+		}
+		if *rule.Marketplace == link.Marketplace {
+			return fmt.Errorf("invalid redirect rule: marketplace must differ from the link's marketplace")
+		}
+		offer, err := s.offerRepo.FindByProductIDAndMarketplace(ctx, link.ProductID, *rule.Marketplace)
+		if err != nil {
+			return fmt.Errorf("invalid redirect rule: product has no %s offer", *rule.Marketplace)
+		}
+		// Resolved when the rule is saved, like a link's own target URL
+		targetURL, err := offerTargetURL(ctx, s.campaignRepo, link, offer.MarketplaceProductURL, *rule.Marketplace)
+		if err != nil {
+			return err
+		}
+		rule.TargetURL = targetURL
+		rule.FallbackURL = ""
+
+	default:
+		return fmt.Errorf("invalid redirect rule: target_type must be web, deep_link or marketplace")
+	}
+
+	return nil
+}
+
+// normalizeRuleValues trims and normalizes a rule's match list, rejecting invalid values
+// The result is never nil so it is stored as an empty JSON array
+func normalizeRuleValues(values []string, field string, normalize func(string) string, valid func(string) bool) ([]string, error) {
+	normalized := make([]string, 0, len(values))
+	for _, v := range values {
+		v = normalize(strings.TrimSpace(v))
+		if !valid(v) {
+			return nil, fmt.Errorf("invalid redirect rule: unsupported %s value %s", field, strconv.Quote(v))
+		}
+		normalized = append(normalized, v)
+	}
+	return normalized, nil
+}
+
+// redirectVisitor holds the visitor attributes redirect rules match on
+type redirectVisitor struct {
+	os       string
+	country  string
+	language string
+}
+
+// matchRedirectRule returns the first active rule matching the visitor, or nil
+// Rules must already be in evaluation order
+func matchRedirectRule(rules []model.RedirectRule, visitor redirectVisitor) *model.RedirectRule {
+	for i := range rules {
+		rule := &rules[i]
+		if rule.IsActive &&
+			matchesRuleValue(rule.OS, visitor.os) &&
+			matchesRuleValue(rule.Countries, visitor.country) &&
+			matchesRuleValue(rule.Languages, visitor.language) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// matchesRuleValue reports whether value is in values; an empty list matches any value
+func matchesRuleValue(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryLanguage returns the primary subtag of the most preferred language in
+// an Accept-Language header (e.g. "th" for "th-TH,th;q=0.9,en;q=0.8"), or ""
+func primaryLanguage(acceptLanguage string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag, _, _ = strings.Cut(strings.TrimSpace(tag), "-")
+		tag = strings.ToLower(tag)
+		if !languagePattern.MatchString(tag) {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		// Ties keep the earlier entry
+		if q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
+
+// isAbsoluteHTTPURL reports whether raw is an absolute http(s) URL
+func isAbsoluteHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// toRedirectRuleResponse converts a redirect rule model to DTO
+func toRedirectRuleResponse(rule *model.RedirectRule) *dto.RedirectRuleResponse {
+	response := &dto.RedirectRuleResponse{
+		ID:          rule.ID,
+		LinkID:      rule.LinkID,
+		Name:        rule.Name,
+		Priority:    rule.Priority,
+		OS:          rule.OS,
+		Countries:   rule.Countries,
+		Languages:   rule.Languages,
+		TargetType:  string(rule.TargetType),
+		TargetURL:   rule.TargetURL,
+		FallbackURL: rule.FallbackURL,
+		IsActive:    rule.IsActive,
+		CreatedAt:   rule.CreatedAt,
+		UpdatedAt:   rule.UpdatedAt,
+	}
+	if rule.Marketplace != nil {
+		m := string(*rule.Marketplace)
+		response.Marketplace = &m
+	}
+	return response
+}
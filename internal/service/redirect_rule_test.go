@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	mockadapter "github.com/jonosize/affiliate-platform/pkg/adapters/mock"
+)
+
+func TestClassifyOS(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{"iPhone Safari", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1", osIOS},
+		{"iPad Safari", "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1", osIOS},
+		{"iPhone Facebook in-app", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_3_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 [FBAN/FBIOS;FBAV/450.0.0.38.108;FBBV/564431005;FBDV/iPhone15,2;FBMD/iPhone;FBSN/iOS;FBSV/17.3.1;FBSS/3;FBID/phone;FBLC/th_TH;FBOP/5]", osIOS},
+		{"iPhone LINE in-app", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 Safari Line/13.21.0", osIOS},
+		{"Android Chrome", chromeAndroidUA, osAndroid},
+		{"Samsung Internet", "Mozilla/5.0 (Linux; Android 13; SAMSUNG SM-S911B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36", osAndroid},
+		{"Windows Chrome", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", osWindows},
+		{"Mac Safari", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15", osMacOS},
+		{"Linux Firefox", "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", osLinux},
+		{"ChromeOS", "Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", osLinux},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyOS(tt.userAgent))
+		})
+	}
+}
+
+func TestPrimaryLanguage(t *testing.T) {
+	assert.Equal(t, "th", primaryLanguage("th-TH,th;q=0.9,en-US;q=0.8,en;q=0.7"))
+	assert.Equal(t, "en", primaryLanguage("th;q=0.5, en-GB;q=0.8"))
+	assert.Equal(t, "en", primaryLanguage("EN-us"))
+	assert.Equal(t, "ja", primaryLanguage("ja,th"), "ties keep the first entry")
+	assert.Equal(t, "th", primaryLanguage("*, th;q=0.1"))
+	assert.Equal(t, "", primaryLanguage("en;q=0"))
+	assert.Equal(t, "", primaryLanguage(""))
+}
+
+func TestMatchRedirectRule(t *testing.T) {
+	rules := []model.RedirectRule{
+		{Name: "inactive", OS: []string{osIOS}, IsActive: false},
+		{Name: "ios in TH", OS: []string{osIOS}, Countries: []string{"TH"}, IsActive: true},
+		{Name: "thai speakers", Languages: []string{"th"}, IsActive: true},
+		{Name: "android", OS: []string{osAndroid}, IsActive: true},
+	}
+
+	match := func(v redirectVisitor) string {
+		if rule := matchRedirectRule(rules, v); rule != nil {
+			return rule.Name
+		}
+		return ""
+	}
+
+	assert.Equal(t, "ios in TH", match(redirectVisitor{os: osIOS, country: "TH", language: "en"}))
+	assert.Equal(t, "thai speakers", match(redirectVisitor{os: osIOS, country: "SG", language: "th"}))
+	assert.Equal(t, "thai speakers", match(redirectVisitor{os: osAndroid, language: "th"}), "earlier rules win")
+	assert.Equal(t, "android", match(redirectVisitor{os: osAndroid, language: "en"}))
+	assert.Equal(t, "", match(redirectVisitor{os: osIOS, language: "en"}), "unknown country never matches a country list")
+	assert.Nil(t, matchRedirectRule(nil, redirectVisitor{os: osIOS}))
+}
+
+// fakeCountryResolver resolves every IP to one country
+type fakeCountryResolver string
+
+func (f fakeCountryResolver) Country(net.IP) string { return string(f) }
+
+func TestRedirectService_Redirect_Rules(t *testing.T) {
+	const iPhoneUA = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"
+	shopee := model.MarketplaceShopee
+
+	rules := []model.RedirectRule{
+		{
+			ID:          uuid.New(),
+			OS:          []string{osIOS},
+			TargetType:  model.RedirectTargetDeepLink,
+			TargetURL:   "lazada://th/d?p=i123456",
+			FallbackURL: "https://www.lazada.co.th/products/matcha-i123456.html?utm_source=app",
+			IsActive:    true,
+		},
+		{
+			ID:          uuid.New(),
+			Countries:   []string{"TH"},
+			TargetType:  model.RedirectTargetMarketplace,
+			TargetURL:   "https://shopee.co.th/product/123456/789012?utm_source=affiliate",
+			Marketplace: &shopee,
+			IsActive:    true,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		userAgent  string
+		geo        CountryResolver
+		wantURL    string
+		wantParams func(clickID string) url.Values
+		wantAppURL string
+	}{
+		{
+			name:      "iOS gets the app deep link with a tracked web fallback",
+			userAgent: iPhoneUA,
+			wantURL:   "https://www.lazada.co.th/products/matcha-i123456.html",
+			wantParams: func(clickID string) url.Values {
+				return url.Values{"utm_source": {"app"}, "sub_aff_id": {clickID}}
+			},
+			wantAppURL: "lazada://th/d?p=i123456",
+		},
+		{
+			name:      "Thai visitors go to the Shopee offer",
+			userAgent: chromeAndroidUA,
+			geo:       fakeCountryResolver("TH"),
+			wantURL:   "https://shopee.co.th/product/123456/789012",
+			wantParams: func(clickID string) url.Values {
+				return url.Values{"utm_source": {"affiliate"}, "sub_id": {clickID}}
+			},
+		},
+		{
+			name:      "no GeoIP database: country rules never match",
+			userAgent: chromeAndroidUA,
+			wantURL:   "https://www.lazada.co.th/products/matcha-i123456.html",
+			wantParams: func(clickID string) url.Values {
+				return url.Values{"utm_source": {"affiliate"}, "sub_aff_id": {clickID}}
+			},
+		},
+		{
+			name:      "no matching rule uses the default target",
+			userAgent: chromeAndroidUA,
+			geo:       fakeCountryResolver("SG"),
+			wantURL:   "https://www.lazada.co.th/products/matcha-i123456.html",
+			wantParams: func(clickID string) url.Values {
+				return url.Values{"utm_source": {"affiliate"}, "sub_aff_id": {clickID}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			log, err := logger.NewZapLogger("info")
+			require.NoError(t, err)
+			registry, err := mockadapter.NewRegistry()
+			require.NoError(t, err)
+
+			link := &model.Link{
+				ID:            uuid.New(),
+				Marketplace:   model.MarketplaceLazada,
+				ShortCode:     "abc123",
+				TargetURL:     "https://www.lazada.co.th/products/matcha-i123456.html?utm_source=affiliate",
+				RedirectRules: rules,
+			}
+			linkRepo := new(MockLinkRepository)
+			linkRepo.On("FindByShortCode", ctx, "abc123").Return(link, nil)
+
+			var tracked *model.Click
+			clickRepo := new(MockClickRepository)
+			clickRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				tracked = args.Get(1).(*model.Click)
+			}).Return(nil)
+
+			svc := NewRedirectService(linkRepo, nil, NewClickService(clickRepo, linkRepo, nil, nil, nil, log), registry, tt.geo, nil, log)
+			target, err := svc.Redirect(ctx, "abc123", dto.ClickMetadata{
+				Method:         "GET",
+				UserAgent:      tt.userAgent,
+				IPAddress:      net.ParseIP("203.0.113.7"),
+				AcceptLanguage: "th-TH,th;q=0.9",
+			})
+			require.NoError(t, err)
+			require.NotNil(t, tracked)
+
+			u, err := url.Parse(target.URL)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantParams(FormatClickID(tracked.ID)), u.Query())
+			u.RawQuery = ""
+			assert.Equal(t, tt.wantURL, u.String())
+			assert.Equal(t, tt.wantAppURL, target.AppURL)
+		})
+	}
+}
+
+func newTestRedirectRuleService(t *testing.T) (*RedirectRuleService, *MockLinkRepository, *MockCampaignRepository, *MockOfferRepository) {
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	registry, err := mockadapter.NewRegistry()
+	require.NoError(t, err)
+
+	linkRepo := new(MockLinkRepository)
+	campaignRepo := new(MockCampaignRepository)
+	offerRepo := new(MockOfferRepository)
+	return NewRedirectRuleService(linkRepo, campaignRepo, offerRepo, registry, log), linkRepo, campaignRepo, offerRepo
+}
+
+func TestRedirectRuleService_CreateRedirectRule(t *testing.T) {
+	ctx := context.Background()
+	link := &model.Link{
+		ID:          uuid.New(),
+		ProductID:   uuid.New(),
+		CampaignID:  uuid.New(),
+		Marketplace: model.MarketplaceLazada,
+		ShortCode:   "abc123",
+	}
+
+	t.Run("normalizes match lists", func(t *testing.T) {
+		svc, linkRepo, _, _ := newTestRedirectRuleService(t)
+		linkRepo.On("FindByID", ctx, link.ID).Return(link, nil)
+		linkRepo.On("CreateRedirectRule", ctx, mock.Anything).Return(nil)
+
+		rule, err := svc.CreateRedirectRule(ctx, link.ID, dto.CreateRedirectRuleRequest{
+			Name:        " iOS app ",
+			OS:          []string{"iOS"},
+			Languages:   []string{" TH "},
+			TargetType:  "deep_link",
+			TargetURL:   "lazada://th/d?p=i123456",
+			FallbackURL: "https://www.lazada.co.th/products/matcha-i123456.html",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "iOS app", rule.Name)
+		assert.Equal(t, []string{"ios"}, rule.OS)
+		assert.Equal(t, []string{}, rule.Countries, "stored as an empty list, not null")
+		assert.Equal(t, []string{"th"}, rule.Languages)
+		assert.True(t, rule.IsActive)
+	})
+
+	t.Run("resolves marketplace targets from the offer", func(t *testing.T) {
+		svc, linkRepo, campaignRepo, offerRepo := newTestRedirectRuleService(t)
+		linkRepo.On("FindByID", ctx, link.ID).Return(link, nil)
+		offerRepo.On("FindByProductIDAndMarketplace", ctx, link.ProductID, model.MarketplaceShopee).
+			Return(&model.Offer{MarketplaceProductURL: "https://shopee.co.th/product/123456/789012"}, nil)
+		campaignRepo.On("FindByID", ctx, link.CampaignID).
+			Return(&model.Campaign{ID: link.CampaignID, UTMCampaign: "summer"}, nil)
+		linkRepo.On("CreateRedirectRule", ctx, mock.Anything).Return(nil)
+
+		rule, err := svc.CreateRedirectRule(ctx, link.ID, dto.CreateRedirectRuleRequest{
+			Countries:   []string{"th"},
+			TargetType:  "marketplace",
+			TargetURL:   "https://ignored.example.com",
+			Marketplace: "Shopee",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"TH"}, rule.Countries)
+		require.NotNil(t, rule.Marketplace)
+		assert.Equal(t, "shopee", *rule.Marketplace)
+
+		u, err := url.Parse(rule.TargetURL)
+		require.NoError(t, err)
+		assert.Equal(t, "shopee.co.th", u.Host)
+		assert.Equal(t, "summer", u.Query().Get("utm_campaign"))
+	})
+
+	invalid := []struct {
+		name    string
+		req     dto.CreateRedirectRuleRequest
+		wantErr string
+	}{
+		{
+			name:    "unknown OS",
+			req:     dto.CreateRedirectRuleRequest{OS: []string{"symbian"}, TargetType: "web", TargetURL: "https://example.com"},
+			wantErr: `invalid redirect rule: unsupported os value "symbian"`,
+		},
+		{
+			name:    "bad country code",
+			req:     dto.CreateRedirectRuleRequest{Countries: []string{"THA"}, TargetType: "web", TargetURL: "https://example.com"},
+			wantErr: `invalid redirect rule: unsupported countries value "THA"`,
+		},
+		{
+			name:    "script deep link",
+			req:     dto.CreateRedirectRuleRequest{TargetType: "deep_link", TargetURL: "javascript:alert(1)"},
+			wantErr: "invalid redirect rule: target_url must be an app deep link (e.g. lazada://...) or an http(s) URL",
+		},
+		{
+			name:    "relative web target",
+			req:     dto.CreateRedirectRuleRequest{TargetType: "web", TargetURL: "/offers"},
+			wantErr: "invalid redirect rule: target_url must be an absolute http(s) URL",
+		},
+		{
+			name:    "same marketplace as the link",
+			req:     dto.CreateRedirectRuleRequest{TargetType: "marketplace", Marketplace: "lazada"},
+			wantErr: "invalid redirect rule: marketplace must differ from the link's marketplace",
+		},
+		{
+			name:    "unknown target type",
+			req:     dto.CreateRedirectRuleRequest{TargetType: "sms", TargetURL: "https://example.com"},
+			wantErr: "invalid redirect rule: target_type must be web, deep_link or marketplace",
+		},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, linkRepo, _, _ := newTestRedirectRuleService(t)
+			linkRepo.On("FindByID", ctx, link.ID).Return(link, nil)
+
+			_, err := svc.CreateRedirectRule(ctx, link.ID, tt.req)
+			assert.EqualError(t, err, tt.wantErr)
+			linkRepo.AssertNotCalled(t, "CreateRedirectRule", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("product without an offer on the marketplace", func(t *testing.T) {
+		svc, linkRepo, _, offerRepo := newTestRedirectRuleService(t)
+		linkRepo.On("FindByID", ctx, link.ID).Return(link, nil)
+		offerRepo.On("FindByProductIDAndMarketplace", ctx, link.ProductID, model.MarketplaceShopee).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.CreateRedirectRule(ctx, link.ID, dto.CreateRedirectRuleRequest{TargetType: "marketplace", Marketplace: "shopee"})
+		assert.EqualError(t, err, "invalid redirect rule: product has no shopee offer")
+	})
+
+	t.Run("link not found", func(t *testing.T) {
+		svc, linkRepo, _, _ := newTestRedirectRuleService(t)
+		linkRepo.On("FindByID", ctx, link.ID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.CreateRedirectRule(ctx, link.ID, dto.CreateRedirectRuleRequest{TargetType: "web", TargetURL: "https://example.com"})
+		assert.ErrorContains(t, err, "link not found")
+	})
+}
+
+func TestRedirectRuleService_UpdateAndDeleteRedirectRule(t *testing.T) {
+	ctx := context.Background()
+	link := &model.Link{ID: uuid.New(), Marketplace: model.MarketplaceLazada}
+	rule := &model.RedirectRule{
+		ID:         uuid.New(),
+		LinkID:     link.ID,
+		OS:         []string{osAndroid},
+		Countries:  []string{"TH"},
+		Languages:  []string{},
+		TargetType: model.RedirectTargetWeb,
+		TargetURL:  "https://example.com/android",
+		IsActive:   true,
+	}
+
+	svc, linkRepo, _, _ := newTestRedirectRuleService(t)
+	linkRepo.On("FindByID", ctx, link.ID).Return(link, nil)
+	linkRepo.On("FindRedirectRule", ctx, link.ID, rule.ID).Return(rule, nil)
+	linkRepo.On("UpdateRedirectRule", ctx, rule).Return(nil)
+
+	active := false
+	updated, err := svc.UpdateRedirectRule(ctx, link.ID, rule.ID, dto.UpdateRedirectRuleRequest{
+		Countries: []string{},
+		IsActive:  &active,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{osAndroid}, updated.OS, "omitted lists are kept")
+	assert.Equal(t, []string{}, updated.Countries, "an empty list clears the condition")
+	assert.False(t, updated.IsActive)
+
+	missing := uuid.New()
+	linkRepo.On("FindRedirectRule", ctx, link.ID, missing).Return(nil, gorm.ErrRecordNotFound)
+	_, err = svc.UpdateRedirectRule(ctx, link.ID, missing, dto.UpdateRedirectRuleRequest{})
+	assert.ErrorContains(t, err, "redirect rule not found")
+
+	linkRepo.On("DeleteRedirectRule", ctx, link.ID, missing).Return(gorm.ErrRecordNotFound)
+	assert.ErrorContains(t, svc.DeleteRedirectRule(ctx, link.ID, missing), "redirect rule not found")
+}
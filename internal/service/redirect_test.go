@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	mockadapter "github.com/jonosize/affiliate-platform/pkg/adapters/mock"
+)
+
+func TestRedirectService_Redirect_SubIDs(t *testing.T) {
+	tests := []struct {
+		name        string
+		marketplace model.Marketplace
+		targetURL   string
+		subIDs      []string
+		wantParams  func(clickID string) url.Values
+	}{
+		{
+			name:        "lazada",
+			marketplace: model.MarketplaceLazada,
+			targetURL:   "https://www.lazada.co.th/products/matcha-i123456.html?utm_source=affiliate",
+			subIDs:      []string{"fb", "", "story"},
+			wantParams: func(clickID string) url.Values {
+				return url.Values{"utm_source": {"affiliate"}, "sub_aff_id": {clickID}, "sub_id1": {"fb"}, "sub_id3": {"story"}}
+			},
+		},
+		{
+			name:        "shopee",
+			marketplace: model.MarketplaceShopee,
+			targetURL:   "https://shopee.co.th/product/123456/789012?utm_source=affiliate",
+			subIDs:      []string{"fb", "", "story", "a", "dropped"},
+			wantParams: func(clickID string) url.Values {
+				return url.Values{"utm_source": {"affiliate"}, "sub_id": {clickID + "-fb--story-a"}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			log, err := logger.NewZapLogger("info")
+			require.NoError(t, err)
+			registry, err := mockadapter.NewRegistry()
+			require.NoError(t, err)
+
+			link := &model.Link{ID: uuid.New(), Marketplace: tt.marketplace, ShortCode: "abc123", TargetURL: tt.targetURL}
+			linkRepo := new(MockLinkRepository)
+			linkRepo.On("FindByShortCode", ctx, "abc123").Return(link, nil)
+
+			var tracked *model.Click
+			clickRepo := new(MockClickRepository)
+			clickRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				tracked = args.Get(1).(*model.Click)
+			}).Return(nil)
+
+			svc := NewRedirectService(linkRepo, nil, NewClickService(clickRepo, linkRepo, nil, nil, nil, log), registry, nil, nil, log)
+			target, err := svc.Redirect(ctx, "abc123", dto.ClickMetadata{Method: "GET", UserAgent: chromeAndroidUA, SubIDs: tt.subIDs})
+			require.NoError(t, err)
+
+			require.NotNil(t, tracked)
+			require.NotEqual(t, uuid.Nil, tracked.ID, "click ID is minted before tracking")
+			assert.Equal(t, "fb", tracked.Sub1)
+			assert.Equal(t, "", tracked.Sub2)
+			assert.Equal(t, "story", tracked.Sub3)
+
+			u, err := url.Parse(target.URL)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantParams(FormatClickID(tracked.ID)), u.Query())
+
+			// Conversion postbacks echo the compact click ID back
+			parsed, err := uuid.Parse(FormatClickID(tracked.ID))
+			require.NoError(t, err)
+			assert.Equal(t, tracked.ID, parsed)
+		})
+	}
+}
+
+func TestRedirectService_Redirect_NotFound(t *testing.T) {
+	ctx := context.Background()
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+
+	linkRepo := new(MockLinkRepository)
+	linkRepo.On("FindByShortCode", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+	linkRepo.On("FindByShortCode", ctx, "broken").Return(nil, errors.New("connection refused"))
+
+	svc := NewRedirectService(linkRepo, nil, nil, nil, nil, nil, log)
+
+	_, err = svc.Redirect(ctx, "missing", dto.ClickMetadata{Method: "GET"})
+	assert.ErrorIs(t, err, ErrLinkNotFound)
+
+	_, err = svc.Redirect(ctx, "broken", dto.ClickMetadata{Method: "GET"})
+	assert.NotErrorIs(t, err, ErrLinkNotFound, "lookup failures are not reported as unknown codes")
+}
+
+func TestParseSubIDs(t *testing.T) {
+	assert.Nil(t, ParseSubIDs(url.Values{"utm_source": {"x"}}))
+	assert.Equal(t, []string{"fb", "", "ad1_x"}, ParseSubIDs(url.Values{"sub1": {"fb"}, "sub3": {"ad-1_x"}, "sub3x": {"ignored"}}))
+	assert.Equal(t, []string{"", "scriptalert1script"}, ParseSubIDs(url.Values{"sub2": {"<script>alert(1)</script>"}}))
+
+	long := ParseSubIDs(url.Values{"sub1": {"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}})
+	require.Len(t, long, 1)
+	assert.Len(t, long[0], maxSubIDLength)
+}
+
+func TestParseClickSource(t *testing.T) {
+	assert.Equal(t, ClickSourceQR, ParseClickSource("QR"))
+	assert.Equal(t, "print-flyer_2", ParseClickSource("print-flyer_2"))
+	assert.Equal(t, "scriptalert1script", ParseClickSource("<script>alert(1)</script>"))
+	assert.Equal(t, "", ParseClickSource(""))
+	assert.Len(t, ParseClickSource("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"), maxClickSourceLength)
+}
+
+func TestRedirectService_Redirect_Lifecycle(t *testing.T) {
+	now := time.Now()
+	running := model.Campaign{StartAt: now.Add(-24 * time.Hour), EndAt: now.Add(24 * time.Hour)}
+	ended := model.Campaign{StartAt: now.Add(-48 * time.Hour), EndAt: now.Add(-24 * time.Hour)}
+	withPolicy := func(c model.Campaign, policy model.OutOfWindowPolicy, fallbackURL string) model.Campaign {
+		c.OutOfWindowPolicy = policy
+		c.FallbackURL = fallbackURL
+		return c
+	}
+
+	tests := []struct {
+		name        string
+		status      model.LinkStatus
+		campaign    model.Campaign
+		wantTarget  string
+		wantErr     string
+		wantOutcome model.ClickOutcome
+		wantReason  string
+	}{
+		{
+			name:        "active link in window",
+			status:      model.LinkStatusActive,
+			campaign:    running,
+			wantTarget:  "https://www.lazada.co.th/products/matcha-i123456.html",
+			wantOutcome: model.ClickOutcomeRedirected,
+		},
+		{
+			name:        "ended campaign redirects by default",
+			campaign:    withPolicy(ended, model.OutOfWindowRedirect, ""),
+			wantTarget:  "https://www.lazada.co.th/products/matcha-i123456.html",
+			wantOutcome: model.ClickOutcomeRedirected,
+			wantReason:  model.OutcomeReasonCampaignEnded,
+		},
+		{
+			name:        "ended campaign uses the fallback URL",
+			campaign:    withPolicy(ended, model.OutOfWindowFallback, "https://shop.example.com/offers"),
+			wantTarget:  "https://shop.example.com/offers",
+			wantOutcome: model.ClickOutcomeFallback,
+			wantReason:  model.OutcomeReasonCampaignEnded,
+		},
+		{
+			name:        "paused link is gone",
+			status:      model.LinkStatusPaused,
+			campaign:    running,
+			wantErr:     "offer ended: link_paused",
+			wantOutcome: model.ClickOutcomeGone,
+			wantReason:  model.OutcomeReasonLinkPaused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			log, err := logger.NewZapLogger("info")
+			require.NoError(t, err)
+			registry, err := mockadapter.NewRegistry()
+			require.NoError(t, err)
+
+			link := &model.Link{
+				ID:          uuid.New(),
+				Marketplace: model.MarketplaceLazada,
+				ShortCode:   "abc123",
+				TargetURL:   "https://www.lazada.co.th/products/matcha-i123456.html",
+				Status:      tt.status,
+				Campaign:    tt.campaign,
+			}
+			linkRepo := new(MockLinkRepository)
+			linkRepo.On("FindByShortCode", ctx, "abc123").Return(link, nil)
+
+			var tracked *model.Click
+			clickRepo := new(MockClickRepository)
+			clickRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				tracked = args.Get(1).(*model.Click)
+			}).Return(nil)
+
+			svc := NewRedirectService(linkRepo, nil, NewClickService(clickRepo, linkRepo, nil, nil, nil, log), registry, nil, nil, log)
+			target, err := svc.Redirect(ctx, "abc123", dto.ClickMetadata{Method: "GET", UserAgent: chromeAndroidUA})
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				u, err := url.Parse(target.URL)
+				require.NoError(t, err)
+				u.RawQuery = ""
+				assert.Equal(t, tt.wantTarget, u.String())
+			}
+
+			// Every outcome is tracked so ended campaigns still show their traffic
+			require.NotNil(t, tracked)
+			assert.Equal(t, tt.wantOutcome, tracked.Outcome)
+			assert.Equal(t, tt.wantReason, tracked.OutcomeReason)
+		})
+	}
+}
+
+func TestRedirectService_Redirect_BestPrice(t *testing.T) {
+	now := time.Now()
+	productID := uuid.New()
+	offers := []*model.Offer{
+		{ProductID: productID, Marketplace: model.MarketplaceLazada, Price: 299, MarketplaceProductURL: "https://www.lazada.co.th/products/matcha-i123456.html"},
+		{ProductID: productID, Marketplace: model.MarketplaceShopee, Price: 259, MarketplaceProductURL: "https://shopee.co.th/product/123456/789012"},
+	}
+
+	tests := []struct {
+		name            string
+		offers          []*model.Offer
+		offersErr       error
+		wantHost        string
+		wantUTMSource   string
+		wantMarketplace model.Marketplace
+	}{
+		{
+			name:            "cheapest offer at click time",
+			offers:          offers,
+			wantHost:        "shopee.co.th",
+			wantUTMSource:   "shopee",
+			wantMarketplace: model.MarketplaceShopee,
+		},
+		{
+			name:            "offer lookup failure keeps the synced target",
+			offersErr:       errors.New("connection refused"),
+			wantHost:        "www.lazada.co.th",
+			wantUTMSource:   "lazada",
+			wantMarketplace: model.MarketplaceLazada,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			log, err := logger.NewZapLogger("info")
+			require.NoError(t, err)
+			registry, err := mockadapter.NewRegistry()
+			require.NoError(t, err)
+
+			link := &model.Link{
+				ID:          uuid.New(),
+				ProductID:   productID,
+				Marketplace: model.MarketplaceBestPrice,
+				ShortCode:   "abc123",
+				TargetURL:   "https://www.lazada.co.th/products/matcha-i123456.html?utm_campaign=summer&utm_source=lazada",
+				Campaign:    model.Campaign{UTMCampaign: "summer", UTMSource: "{{marketplace}}", StartAt: now.Add(-time.Hour), EndAt: now.Add(time.Hour)},
+			}
+			linkRepo := new(MockLinkRepository)
+			linkRepo.On("FindByShortCode", ctx, "abc123").Return(link, nil)
+			offerRepo := new(MockOfferRepository)
+			offerRepo.On("FindByProductID", ctx, productID).Return(tt.offers, tt.offersErr)
+
+			var tracked *model.Click
+			clickRepo := new(MockClickRepository)
+			clickRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				tracked = args.Get(1).(*model.Click)
+			}).Return(nil)
+
+			svc := NewRedirectService(linkRepo, offerRepo, NewClickService(clickRepo, linkRepo, nil, nil, nil, log), registry, nil, nil, log)
+			target, err := svc.Redirect(ctx, "abc123", dto.ClickMetadata{Method: "GET", UserAgent: chromeAndroidUA})
+			require.NoError(t, err)
+
+			u, err := url.Parse(target.URL)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantHost, u.Host)
+			assert.Equal(t, tt.wantUTMSource, u.Query().Get("utm_source"))
+
+			require.NotNil(t, tracked)
+			require.NotNil(t, tracked.Marketplace, "the chosen marketplace is recorded with the click")
+			assert.Equal(t, tt.wantMarketplace, *tracked.Marketplace)
+
+			subIDParam := map[model.Marketplace]string{model.MarketplaceLazada: "sub_aff_id", model.MarketplaceShopee: "sub_id"}[tt.wantMarketplace]
+			assert.Equal(t, FormatClickID(tracked.ID), u.Query().Get(subIDParam), "sub-IDs follow the chosen marketplace")
+		})
+	}
+}
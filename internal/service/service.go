@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"net"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +17,9 @@ type ProductRepositoryInterface interface {
 	FindAll(ctx context.Context, limit, offset int) ([]*model.Product, int64, error)
 	Update(ctx context.Context, product *model.Product) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	FindDeletedByID(ctx context.Context, id uuid.UUID) (*model.Product, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	Purge(ctx context.Context, id uuid.UUID) error
 }
 
 // OfferRepositoryInterface defines the interface for offer repository operations
@@ -28,6 +32,13 @@ type OfferRepositoryInterface interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
+// PriceHistoryRepositoryInterface defines the interface for offer price history repository operations
+type PriceHistoryRepositoryInterface interface {
+	Create(ctx context.Context, entry *model.OfferPriceHistory) error
+	FindByProductID(ctx context.Context, productID uuid.UUID, marketplace *string, from, to time.Time) ([]*model.OfferPriceHistory, error)
+	FindMinPricesSince(ctx context.Context, productID uuid.UUID, since time.Time) ([]model.MarketplaceMinPriceResult, error)
+}
+
 // CampaignRepositoryInterface defines the interface for campaign repository operations
 type CampaignRepositoryInterface interface {
 	Create(ctx context.Context, campaign *model.Campaign) error
@@ -35,6 +46,9 @@ type CampaignRepositoryInterface interface {
 	FindAll(ctx context.Context, limit, offset int) ([]*model.Campaign, int64, error)
 	Update(ctx context.Context, campaign *model.Campaign) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	FindDeletedByID(ctx context.Context, id uuid.UUID) (*model.Campaign, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	Purge(ctx context.Context, id uuid.UUID) error
 	AddProducts(ctx context.Context, campaignID uuid.UUID, productIDs []uuid.UUID) error
 	RemoveProducts(ctx context.Context, campaignID uuid.UUID, productIDs []uuid.UUID) error
 	UpdateCampaignProducts(ctx context.Context, campaignID uuid.UUID, productIDs []uuid.UUID) error
@@ -43,10 +57,27 @@ type CampaignRepositoryInterface interface {
 // LinkRepositoryInterface defines the interface for link repository operations
 type LinkRepositoryInterface interface {
 	Create(ctx context.Context, link *model.Link) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Link, error)
+	FindByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*model.Link, error)
 	FindByShortCode(ctx context.Context, shortCode string) (*model.Link, error)
 	FindByProductIDAndCampaignID(ctx context.Context, productID, campaignID uuid.UUID) ([]*model.Link, error)
 	FindByCampaignID(ctx context.Context, campaignID uuid.UUID) ([]*model.Link, error)
+	FindByProductID(ctx context.Context, productID uuid.UUID) ([]*model.Link, error)
 	ShortCodeExists(ctx context.Context, shortCode string) (bool, error)
+	FindAliasesByLinkID(ctx context.Context, linkID uuid.UUID) ([]*model.LinkAlias, error)
+	CreateAlias(ctx context.Context, alias *model.LinkAlias) error
+	DeleteAlias(ctx context.Context, linkID uuid.UUID, shortCode string) error
+	ChangeShortCode(ctx context.Context, link *model.Link, shortCode string) error
+	FindRedirectRulesByLinkID(ctx context.Context, linkID uuid.UUID) ([]*model.RedirectRule, error)
+	FindRedirectRule(ctx context.Context, linkID, ruleID uuid.UUID) (*model.RedirectRule, error)
+	CreateRedirectRule(ctx context.Context, rule *model.RedirectRule) error
+	UpdateRedirectRule(ctx context.Context, rule *model.RedirectRule) error
+	DeleteRedirectRule(ctx context.Context, linkID, ruleID uuid.UUID) error
+	FindVariantsByLinkID(ctx context.Context, linkID uuid.UUID) ([]*model.LinkVariant, error)
+	FindVariant(ctx context.Context, linkID, variantID uuid.UUID) (*model.LinkVariant, error)
+	CreateVariant(ctx context.Context, variant *model.LinkVariant) error
+	UpdateVariant(ctx context.Context, variant *model.LinkVariant) error
+	DeleteVariant(ctx context.Context, linkID, variantID uuid.UUID) error
 	Update(ctx context.Context, link *model.Link) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	DeleteByProductIDAndCampaignID(ctx context.Context, productID, campaignID uuid.UUID) error
@@ -57,12 +88,125 @@ type LinkRepositoryInterface interface {
 // ClickRepositoryInterface defines the interface for click repository operations
 type ClickRepositoryInterface interface {
 	Create(ctx context.Context, click *model.Click) error
+	CreateBatch(ctx context.Context, clicks []*model.Click) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Click, error)
 	CountByLinkID(ctx context.Context, linkID uuid.UUID) (int64, error)
-	CountByLinkIDAndTimeRange(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time) (int64, error)
+	CountByLinkIDAndTimeRange(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) (int64, error)
+	CountUniqueByLinkIDAndTimeRange(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) (int64, error)
+	CountByLinkIDGroupedByInterval(ctx context.Context, linkID uuid.UUID, interval string, startAt, endAt time.Time, includeBots bool) ([]model.ClickTimeBucketResult, error)
+	CountByReferrerForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, limit int, includeBots bool) ([]model.ClickBreakdownResult, error)
+	CountByUserAgentForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.ClickBreakdownResult, error)
+	CountByOutcomeForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.ClickBreakdownResult, error)
+	CountBySourceForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.ClickBreakdownResult, error)
+	CountByVariantForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.VariantClickResult, error)
 	CountByCampaignID(ctx context.Context, campaignID uuid.UUID) (int64, error)
-	FindRecentClicks(ctx context.Context, limit int) ([]model.Click, error)
-	CountWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time) (int64, error)
-	CountByCampaignWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time) ([]model.CampaignStatResult, error)
-	CountByMarketplaceWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time) ([]model.MarketplaceStatResult, error)
-	FindTopProductsWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, limit int) ([]model.TopProductResult, error)
+	FindRecentClicks(ctx context.Context, limit int, includeBots bool) ([]model.Click, error)
+	CountWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) (int64, error)
+	CountUniqueWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) (int64, error)
+	CountByCampaignWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.CampaignStatResult, error)
+	CountByMarketplaceWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.MarketplaceStatResult, error)
+	CountBySourceWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.SourceStatResult, error)
+	FindTopProductsWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, limit int, includeBots bool) ([]model.TopProductResult, error)
+}
+
+// ImpressionRepositoryInterface defines the interface for impression repository operations
+type ImpressionRepositoryInterface interface {
+	CreateBatch(ctx context.Context, impressions []*model.Impression) error
+	CountWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) (*model.ImpressionCountResult, error)
+	CountByCampaignWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.CampaignImpressionResult, error)
+	CountByMarketplaceWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.MarketplaceImpressionResult, error)
+	CountByProductsWithFilters(ctx context.Context, productIDs []uuid.UUID, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time, includeBots bool) ([]model.ProductImpressionResult, error)
+}
+
+// ConversionRepositoryInterface defines the interface for conversion repository operations
+type ConversionRepositoryInterface interface {
+	Create(ctx context.Context, conversion *model.Conversion) error
+	Update(ctx context.Context, conversion *model.Conversion) error
+	FindByNetworkAndOrderID(ctx context.Context, network, orderID string) (*model.Conversion, error)
+	SumWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time) (*model.ConversionSummaryResult, error)
+	SumByCampaignWithFilters(ctx context.Context, campaignID *uuid.UUID, marketplace *string, startDate, endDate time.Time) ([]model.CampaignConversionResult, error)
+	SumByVariantForLink(ctx context.Context, linkID uuid.UUID, startAt, endAt time.Time, includeBots bool) ([]model.VariantConversionResult, error)
+}
+
+// DedupeStore remembers keys for a fixed window (implemented by cache.Store)
+type DedupeStore interface {
+	Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+}
+
+// LinkCache invalidates cached short codes of links hidden or brought back without
+// a link write, by soft-deleting or restoring their product or campaign (implemented
+// by cache.CachedLinkRepository)
+type LinkCache interface {
+	InvalidateProductLinks(ctx context.Context, productID uuid.UUID)
+	InvalidateCampaignLinks(ctx context.Context, campaignID uuid.UUID)
+}
+
+// CountryResolver resolves an IP address to an ISO country code, or "" if unknown (implemented by geoip.Reader)
+type CountryResolver interface {
+	Country(ip net.IP) string
+}
+
+// ClickQueue accepts click events for asynchronous, batched persistence
+type ClickQueue interface {
+	Enqueue(click *model.Click) error
+}
+
+// AlertRuleRepositoryInterface defines the interface for alert rule repository operations
+type AlertRuleRepositoryInterface interface {
+	Create(ctx context.Context, rule *model.AlertRule) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.AlertRule, error)
+	FindAll(ctx context.Context, limit, offset int) ([]*model.AlertRule, int64, error)
+	FindActiveForOffer(ctx context.Context, productID uuid.UUID, marketplace model.Marketplace) ([]*model.AlertRule, error)
+	Update(ctx context.Context, rule *model.AlertRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// APIKeyRepositoryInterface defines the interface for API key repository operations
+type APIKeyRepositoryInterface interface {
+	Create(ctx context.Context, key *model.APIKey) error
+	FindByKeyHash(ctx context.Context, keyHash string) (*model.APIKey, error)
+	FindAll(ctx context.Context, limit, offset int) ([]*model.APIKey, int64, error)
+	UpdateLastUsedAt(ctx context.Context, id uuid.UUID, at time.Time) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// UserRepositoryInterface defines the interface for admin user repository operations
+type UserRepositoryInterface interface {
+	Create(ctx context.Context, user *model.User) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.User, error)
+	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	FindAll(ctx context.Context, limit, offset int) ([]*model.User, int64, error)
+	Update(ctx context.Context, user *model.User) error
+	UpdateLastLoginAt(ctx context.Context, id uuid.UUID, at time.Time) error
+	CountActiveByRole(ctx context.Context, roleName string) (int64, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// WorkspaceRepositoryInterface defines the interface for workspace repository operations
+type WorkspaceRepositoryInterface interface {
+	Create(ctx context.Context, workspace *model.Workspace) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Workspace, error)
+	FindBySlug(ctx context.Context, slug string) (*model.Workspace, error)
+	FindByRedirectDomain(ctx context.Context, domain string) (*model.Workspace, error)
+	FindAll(ctx context.Context, limit, offset int) ([]*model.Workspace, int64, error)
+	Update(ctx context.Context, workspace *model.Workspace) error
+}
+
+// AuditEventRepositoryInterface defines the interface for audit event repository operations
+type AuditEventRepositoryInterface interface {
+	Create(ctx context.Context, event *model.AuditEvent) error
+	FindAll(ctx context.Context, filter model.AuditEventFilter, limit, offset int) ([]*model.AuditEvent, int64, error)
+}
+
+// RoleRepositoryInterface defines the interface for role repository operations
+type RoleRepositoryInterface interface {
+	FindAll(ctx context.Context) ([]*model.Role, error)
+	FindByName(ctx context.Context, name string) (*model.Role, error)
+}
+
+// AlertDeliveryRepositoryInterface defines the interface for alert delivery log repository operations
+type AlertDeliveryRepositoryInterface interface {
+	Create(ctx context.Context, delivery *model.AlertDelivery) error
+	Update(ctx context.Context, delivery *model.AlertDelivery) error
+	FindByAlertRuleID(ctx context.Context, alertRuleID uuid.UUID, limit, offset int) ([]*model.AlertDelivery, int64, error)
 }
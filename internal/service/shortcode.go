@@ -1,9 +1,12 @@
 package service
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
 	"math/big"
+	"regexp"
+	"strings"
 )
 
 const (
@@ -12,6 +15,39 @@ const (
 	shortCodeMaxLen   = 12
 )
 
+// Vanity short code length limits (links.short_code is VARCHAR(32))
+const (
+	vanityShortCodeMinLen = 3
+	vanityShortCodeMaxLen = 32
+)
+
+// vanityShortCodePattern allows lowercase letters and digits in hyphen-separated words
+var vanityShortCodePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// reservedShortCodes can't be used as vanity codes: they name routes, or could be
+// mistaken for them, on the redirect domain
+var reservedShortCodes = map[string]bool{
+	"about": true, "admin": true, "api": true, "app": true, "assets": true,
+	"auth": true, "campaigns": true, "dashboard": true, "docs": true, "go": true,
+	"health": true, "healthz": true, "help": true, "links": true, "login": true,
+	"logout": true, "metrics": true, "null": true, "postbacks": true, "products": true,
+	"qr": true, "signup": true, "static": true, "support": true, "swagger": true,
+	"undefined": true, "www": true,
+}
+
+// blockedShortCodeWords are rejected as any hyphen-separated word of a vanity code
+// (ignoring digits, so "damn2025" is caught) and as the whole code without hyphens
+var blockedShortCodeWords = map[string]bool{
+	"anal": true, "arse": true, "ass": true, "asshole": true, "bastard": true,
+	"bitch": true, "bollocks": true, "boobs": true, "bullshit": true, "cock": true,
+	"crap": true, "cunt": true, "damn": true, "dick": true, "dickhead": true,
+	"dildo": true, "fag": true, "faggot": true, "fuck": true, "fucker": true,
+	"fucking": true, "motherfucker": true, "nigga": true, "nigger": true, "penis": true,
+	"piss": true, "porn": true, "pussy": true, "retard": true, "sex": true,
+	"shit": true, "slut": true, "twat": true, "wank": true, "wanker": true,
+	"whore": true,
+}
+
 // generateShortCode generates a random alphanumeric short code (8-12 chars)
 func generateShortCode() (string, error) {
 	// Random length between 8-12
@@ -34,3 +70,48 @@ func generateShortCode() (string, error) {
 
 	return string(bytes), nil
 }
+
+// normalizeShortCode trims and lowercases a requested vanity short code
+func normalizeShortCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// validateVanityShortCode checks a normalized vanity short code's charset, length,
+// and the reserved word and blocked word lists
+func validateVanityShortCode(code string) error {
+	if len(code) < vanityShortCodeMinLen || len(code) > vanityShortCodeMaxLen {
+		return fmt.Errorf("invalid short_code: must be %d-%d characters", vanityShortCodeMinLen, vanityShortCodeMaxLen)
+	}
+	if !vanityShortCodePattern.MatchString(code) {
+		return fmt.Errorf("invalid short_code: only letters, digits and single hyphens between them are allowed")
+	}
+	if reservedShortCodes[code] {
+		return fmt.Errorf("invalid short_code: %q is reserved", code)
+	}
+
+	stripDigits := func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return -1
+		}
+		return r
+	}
+	words := append(strings.Split(code, "-"), strings.ReplaceAll(code, "-", ""))
+	for _, word := range words {
+		if blockedShortCodeWords[strings.Map(stripDigits, word)] {
+			return fmt.Errorf("invalid short_code: contains a blocked word")
+		}
+	}
+	return nil
+}
+
+// ensureShortCodeAvailable returns a conflict error if any link or alias already uses the code
+func ensureShortCodeAvailable(ctx context.Context, linkRepo LinkRepositoryInterface, code string) error {
+	exists, err := linkRepo.ShortCodeExists(ctx, code)
+	if err != nil {
+		return fmt.Errorf("failed to check short code: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("short code already in use: %s", code)
+	}
+	return nil
+}
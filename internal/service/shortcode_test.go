@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateVanityShortCode(t *testing.T) {
+	tests := []struct {
+		code    string
+		wantErr string
+	}{
+		{code: "matcha-summer"},
+		{code: "sale2025"},
+		{code: "a1b"},
+		{code: "class-act"}, // Blocked words only match whole words
+		{code: "ab", wantErr: "must be 3-32 characters"},
+		{code: "this-vanity-code-is-far-too-long-to-use", wantErr: "must be 3-32 characters"},
+		{code: "matcha_summer", wantErr: "only letters, digits and single hyphens"},
+		{code: "-matcha", wantErr: "only letters, digits and single hyphens"},
+		{code: "matcha--summer", wantErr: "only letters, digits and single hyphens"},
+		{code: "Matcha", wantErr: "only letters, digits and single hyphens"}, // Callers normalize first
+		{code: "api", wantErr: `"api" is reserved`},
+		{code: "swagger", wantErr: `"swagger" is reserved`},
+		{code: "shit-deals", wantErr: "contains a blocked word"},
+		{code: "damn2025", wantErr: "contains a blocked word"},
+		{code: "f-u-c-k", wantErr: "contains a blocked word"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			err := validateVanityShortCode(tt.code)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, "invalid short_code: "+tt.wantErr)
+		})
+	}
+}
+
+func TestNormalizeShortCode(t *testing.T) {
+	assert.Equal(t, "matcha-summer", normalizeShortCode("  Matcha-Summer "))
+}
+
+func TestEnsureShortCodeAvailable(t *testing.T) {
+	ctx := context.Background()
+	linkRepo := new(MockLinkRepository)
+	linkRepo.On("ShortCodeExists", ctx, "free").Return(false, nil)
+	linkRepo.On("ShortCodeExists", ctx, "taken").Return(true, nil)
+	linkRepo.On("ShortCodeExists", ctx, "broken").Return(false, errors.New("connection refused"))
+
+	assert.NoError(t, ensureShortCodeAvailable(ctx, linkRepo, "free"))
+	assert.EqualError(t, ensureShortCodeAvailable(ctx, linkRepo, "taken"), "short code already in use: taken")
+	assert.ErrorContains(t, ensureShortCodeAvailable(ctx, linkRepo, "broken"), "failed to check short code")
+}
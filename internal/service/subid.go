@@ -0,0 +1,76 @@
+package service
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// maxSubIDs is the number of publisher sub IDs accepted on /go/:short_code (sub1..sub5)
+const maxSubIDs = 5
+
+// maxSubIDLength is the maximum length of a single publisher sub ID
+const maxSubIDLength = 50
+
+// maxClickSourceLength is the maximum length of a ?src= click source marker
+const maxClickSourceLength = 20
+
+// ClickSourceQR marks clicks from scans of a link's QR code
+const ClickSourceQR = "qr"
+
+// FormatClickID returns the compact (hyphen-free) form of a click ID used as a
+// marketplace sub-ID; Shopee uses hyphens to separate sub-ID values
+// uuid.Parse accepts it, so conversion postbacks can echo it back as-is
+func FormatClickID(id uuid.UUID) string {
+	return strings.ReplaceAll(id.String(), "-", "")
+}
+
+// ParseSubIDs reads publisher sub IDs from the sub1..sub5 query parameters
+// Values are reduced to letters, digits and underscores (marketplaces reject
+// other characters) and truncated; the result is positional with trailing
+// empty slots removed
+func ParseSubIDs(query url.Values) []string {
+	subIDs := make([]string, maxSubIDs)
+	last := -1
+	for i := range subIDs {
+		subIDs[i] = sanitizeSubID(query.Get("sub" + strconv.Itoa(i+1)))
+		if subIDs[i] != "" {
+			last = i
+		}
+	}
+	if last < 0 {
+		return nil
+	}
+	return subIDs[:last+1]
+}
+
+// sanitizeSubID keeps letters, digits and underscores and truncates to maxSubIDLength
+func sanitizeSubID(raw string) string {
+	var b strings.Builder
+	for _, c := range raw {
+		if b.Len() >= maxSubIDLength {
+			break
+		}
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// ParseClickSource normalizes the ?src= marker on /go/:short_code (e.g. qr for
+// QR code scans): lower-cased letters, digits, underscores and hyphens, truncated
+func ParseClickSource(raw string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(raw) {
+		if b.Len() >= maxClickSourceLength {
+			break
+		}
+		if c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
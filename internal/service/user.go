@@ -0,0 +1,443 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/config"
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+const (
+	// sessionIssuer is the iss claim of session tokens
+	sessionIssuer = "affiliate-platform"
+
+	// defaultSessionTTL applies when auth.session.ttl_minutes is not positive
+	defaultSessionTTL = 12 * time.Hour
+
+	// Password length limits; bcrypt ignores bytes after the 72nd
+	minPasswordLength = 8
+	maxPasswordLength = 72
+)
+
+var (
+	// ErrInvalidLogin is returned for unknown emails, wrong passwords and inactive users alike
+	ErrInvalidLogin = errors.New("invalid email or password")
+
+	// ErrInvalidSession is returned for malformed, expired and revoked session tokens
+	ErrInvalidSession = errors.New("invalid session token")
+)
+
+// dummyPasswordHash is compared against on logins with an unknown email,
+// so they take as long as logins with a wrong password
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// sessionClaims are the claims of a session token (an HS256 JWT)
+type sessionClaims struct {
+	jwt.RegisteredClaims
+	// PasswordFingerprint ties the token to the password it was issued for,
+	// so changing the password signs the user out everywhere
+	PasswordFingerprint string `json:"pwh"`
+}
+
+// UserService manages admin users, logs them in and authenticates their session tokens
+type UserService struct {
+	userRepo UserRepositoryInterface
+	roleRepo RoleRepositoryInterface
+	secret   []byte
+	ttl      time.Duration
+	logger   logger.Logger
+}
+
+// NewUserService creates a new user service
+// Session tokens are signed with auth.session.secret, or with a random per-process
+// secret when it is empty (tokens then stop working on restart and across instances)
+func NewUserService(userRepo UserRepositoryInterface, roleRepo RoleRepositoryInterface, cfg config.Config, log logger.Logger) *UserService {
+	secret := []byte(cfg.GetSessionSecret())
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic(fmt.Sprintf("failed to generate session secret: %v", err))
+		}
+	}
+	ttl := time.Duration(cfg.GetSessionTTL()) * time.Minute
+	if ttl <= 0 {
+		ttl = defaultSessionTTL
+	}
+
+	return &UserService{
+		userRepo: userRepo,
+		roleRepo: roleRepo,
+		secret:   secret,
+		ttl:      ttl,
+		logger:   log,
+	}
+}
+
+// Login checks a user's email and password and issues a session token
+func (s *UserService) Login(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
+	user, err := s.userRepo.FindByEmail(ctx, normalizeEmail(req.Email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(req.Password))
+			return nil, ErrInvalidLogin
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil || !user.IsActive {
+		return nil, ErrInvalidLogin
+	}
+
+	now := time.Now()
+	token, expiresAt, err := s.issueToken(user, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue session token: %w", err)
+	}
+
+	// Best effort: a failed write must not reject a valid login
+	if err := s.userRepo.UpdateLastLoginAt(ctx, user.ID, now); err != nil {
+		s.logger.Warn("Failed to record user login", logger.String("user_id", user.ID.String()), logger.Error(err))
+	}
+	user.LastLoginAt = &now
+
+	return &dto.LoginResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		User:      *toUserResponse(user),
+	}, nil
+}
+
+// Authenticate resolves a session token to the principal of its user
+// The user is reloaded on every request, so deactivation and role changes apply immediately
+// Invalid, expired and revoked tokens return ErrInvalidSession
+func (s *UserService) Authenticate(ctx context.Context, token string) (*auth.Principal, error) {
+	var claims sessionClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(sessionIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, ErrInvalidSession
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidSession
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidSession
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.IsActive || subtle.ConstantTimeCompare([]byte(claims.PasswordFingerprint), []byte(passwordFingerprint(user.PasswordHash))) != 1 {
+		return nil, ErrInvalidSession
+	}
+
+	principal := &auth.Principal{
+		Subject:     "user:" + user.ID.String(),
+		Name:        user.Email,
+		Role:        user.RoleName,
+		WorkspaceID: user.WorkspaceID,
+	}
+	if user.Role != nil {
+		// Role scopes are seeded by migration; unknown names grant nothing
+		for _, name := range user.Role.Scopes {
+			principal.Scopes = append(principal.Scopes, auth.Scope(name))
+		}
+	}
+	return principal, nil
+}
+
+// CreateUser creates an admin user
+func (s *UserService) CreateUser(ctx context.Context, req dto.CreateUserRequest) (*dto.UserResponse, error) {
+	email := normalizeEmail(req.Email)
+	if err := validateEmail(email); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if err := validateUserName(name); err != nil {
+		return nil, err
+	}
+	if err := validatePassword(req.Password); err != nil {
+		return nil, err
+	}
+	role, err := s.findRole(ctx, req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.FindByEmail(ctx, email); err == nil {
+		return nil, fmt.Errorf("email already in use: %s", email)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check email: %w", err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &model.User{
+		Email:        email,
+		Name:         name,
+		PasswordHash: string(hash),
+		RoleName:     role.Name,
+		IsActive:     true,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return toUserResponse(user), nil
+}
+
+// GetUser retrieves an admin user by ID
+func (s *UserService) GetUser(ctx context.Context, id uuid.UUID) (*dto.UserResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return toUserResponse(user), nil
+}
+
+// GetAllUsers lists admin users
+func (s *UserService) GetAllUsers(ctx context.Context, limit, offset int) ([]*dto.UserResponse, error) {
+	users, _, err := s.userRepo.FindAll(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	responses := make([]*dto.UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = toUserResponse(user)
+	}
+	return responses, nil
+}
+
+// UpdateUser updates an admin user's name, password, role or active flag
+// The last active admin can't be demoted or deactivated
+func (s *UserService) UpdateUser(ctx context.Context, id uuid.UUID, req dto.UpdateUserRequest) (*dto.UserResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := validateUserName(name); err != nil {
+			return nil, err
+		}
+		user.Name = name
+	}
+	if req.Password != nil {
+		if err := validatePassword(*req.Password); err != nil {
+			return nil, err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(*req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+		user.PasswordHash = string(hash)
+	}
+
+	wasActiveAdmin := user.IsActive && user.RoleName == model.RoleAdmin
+	if req.Role != nil {
+		role, err := s.findRole(ctx, *req.Role)
+		if err != nil {
+			return nil, err
+		}
+		user.RoleName = role.Name
+	}
+	if req.IsActive != nil {
+		user.IsActive = *req.IsActive
+	}
+	if wasActiveAdmin && (!user.IsActive || user.RoleName != model.RoleAdmin) {
+		if err := s.ensureAnotherAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return toUserResponse(user), nil
+}
+
+// DeleteUser deletes an admin user
+// The last active admin can't be deleted
+func (s *UserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("user not found: %w", err)
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.IsActive && user.RoleName == model.RoleAdmin {
+		if err := s.ensureAnotherAdmin(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := s.userRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("user not found: %w", err)
+		}
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
+
+// GetRoles lists roles and the scopes they grant
+func (s *UserService) GetRoles(ctx context.Context) ([]*dto.RoleResponse, error) {
+	roles, err := s.roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+
+	responses := make([]*dto.RoleResponse, len(roles))
+	for i, role := range roles {
+		responses[i] = &dto.RoleResponse{
+			Name:        role.Name,
+			Description: role.Description,
+			Scopes:      role.Scopes,
+		}
+	}
+	return responses, nil
+}
+
+// issueToken signs a session token for user
+func (s *UserService) issueToken(user *model.User, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(s.ttl)
+	claims := sessionClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    sessionIssuer,
+			Subject:   user.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		PasswordFingerprint: passwordFingerprint(user.PasswordHash),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// findRole finds a role by name, rejecting unknown ones as invalid input
+func (s *UserService) findRole(ctx context.Context, name string) (*model.Role, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil, fmt.Errorf("invalid user: role is required")
+	}
+	role, err := s.roleRepo.FindByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("invalid user: unknown role: %s", name)
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+	return role, nil
+}
+
+// ensureAnotherAdmin rejects changes that would leave no active admin
+// Called before demoting, deactivating or deleting an active admin
+func (s *UserService) ensureAnotherAdmin(ctx context.Context) error {
+	count, err := s.userRepo.CountActiveByRole(ctx, model.RoleAdmin)
+	if err != nil {
+		return fmt.Errorf("failed to count admins: %w", err)
+	}
+	if count <= 1 {
+		return fmt.Errorf("invalid user: the last active admin can't be removed, demoted or deactivated")
+	}
+	return nil
+}
+
+// normalizeEmail lower-cases an email for storage and lookup
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// validateEmail checks a normalized email address
+func validateEmail(email string) error {
+	if email == "" {
+		return fmt.Errorf("invalid user: email is required")
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || len(email) > 255 {
+		return fmt.Errorf("invalid user: email is not a valid address")
+	}
+	return nil
+}
+
+// validateUserName checks a trimmed display name
+func validateUserName(name string) error {
+	if name == "" {
+		return fmt.Errorf("invalid user: name is required")
+	}
+	if len(name) > 100 {
+		return fmt.Errorf("invalid user: name must be at most 100 characters")
+	}
+	return nil
+}
+
+// validatePassword checks password length
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return fmt.Errorf("invalid user: password must be %d-%d bytes", minPasswordLength, maxPasswordLength)
+	}
+	return nil
+}
+
+// passwordFingerprint returns a short digest of a password hash for session tokens
+// The hash itself never leaves the database
+func passwordFingerprint(passwordHash string) string {
+	sum := sha256.Sum256([]byte(passwordHash))
+	return hex.EncodeToString(sum[:8])
+}
+
+// toUserResponse converts a user to a response (without the password hash)
+func toUserResponse(user *model.User) *dto.UserResponse {
+	return &dto.UserResponse{
+		ID:          user.ID,
+		Email:       user.Email,
+		Name:        user.Name,
+		Role:        user.RoleName,
+		IsActive:    user.IsActive,
+		LastLoginAt: user.LastLoginAt,
+		CreatedAt:   user.CreatedAt,
+	}
+}
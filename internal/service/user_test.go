@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// MockUserRepository is a mock implementation of UserRepositoryInterface
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, user *model.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindAll(ctx context.Context, limit, offset int) ([]*model.User, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, user *model.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateLastLoginAt(ctx context.Context, id uuid.UUID, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) CountActiveByRole(ctx context.Context, roleName string) (int64, error) {
+	args := m.Called(ctx, roleName)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockRoleRepository is a mock implementation of RoleRepositoryInterface
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) FindAll(ctx context.Context) ([]*model.Role, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByName(ctx context.Context, name string) (*model.Role, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Role), args.Error(1)
+}
+
+// sessionConfig sets the session secret of MockConfig
+type sessionConfig struct {
+	MockConfig
+}
+
+func (c *sessionConfig) GetSessionSecret() string { return "test-session-secret" }
+func (c *sessionConfig) GetSessionTTL() int       { return 60 }
+
+var (
+	testAnalystRole = &model.Role{Name: model.RoleAnalyst, Scopes: []string{"read"}}
+	testAdminRole   = &model.Role{Name: model.RoleAdmin, Scopes: []string{"read", "write", "worker"}}
+)
+
+func newTestUserService(t *testing.T) (*UserService, *MockUserRepository, *MockRoleRepository) {
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	userRepo := new(MockUserRepository)
+	roleRepo := new(MockRoleRepository)
+	return NewUserService(userRepo, roleRepo, &sessionConfig{}, log), userRepo, roleRepo
+}
+
+// newTestUser returns an active user with password "password123"
+func newTestUser(t *testing.T, role *model.Role) *model.User {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	return &model.User{
+		ID:           uuid.New(),
+		Email:        role.Name + "@example.com",
+		Name:         role.Name,
+		PasswordHash: string(hash),
+		RoleName:     role.Name,
+		IsActive:     true,
+		Role:         role,
+	}
+}
+
+func TestUserService_LoginAndAuthenticate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("session grants the role's scopes", func(t *testing.T) {
+		svc, userRepo, _ := newTestUserService(t)
+		user := newTestUser(t, testAnalystRole)
+		userRepo.On("FindByEmail", ctx, "analyst@example.com").Return(user, nil)
+		userRepo.On("UpdateLastLoginAt", ctx, user.ID, mock.Anything).Return(nil)
+		userRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+
+		session, err := svc.Login(ctx, dto.LoginRequest{Email: " Analyst@Example.com ", Password: "password123"})
+		require.NoError(t, err)
+		assert.Equal(t, "analyst", session.User.Role)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), session.ExpiresAt, time.Minute)
+
+		principal, err := svc.Authenticate(ctx, session.Token)
+		require.NoError(t, err)
+		assert.Equal(t, "user:"+user.ID.String(), principal.Subject)
+		assert.Equal(t, "analyst@example.com", principal.Name)
+		assert.Equal(t, "analyst", principal.Role)
+		assert.True(t, principal.HasScope(auth.ScopeRead))
+		assert.False(t, principal.HasScope(auth.ScopeWrite))
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		svc, userRepo, _ := newTestUserService(t)
+		user := newTestUser(t, testAnalystRole)
+		userRepo.On("FindByEmail", ctx, user.Email).Return(user, nil)
+
+		_, err := svc.Login(ctx, dto.LoginRequest{Email: user.Email, Password: "password124"})
+		assert.ErrorIs(t, err, ErrInvalidLogin)
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		svc, userRepo, _ := newTestUserService(t)
+		userRepo.On("FindByEmail", ctx, "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.Login(ctx, dto.LoginRequest{Email: "nobody@example.com", Password: "password123"})
+		assert.ErrorIs(t, err, ErrInvalidLogin)
+	})
+
+	t.Run("inactive user", func(t *testing.T) {
+		svc, userRepo, _ := newTestUserService(t)
+		user := newTestUser(t, testAnalystRole)
+		user.IsActive = false
+		userRepo.On("FindByEmail", ctx, user.Email).Return(user, nil)
+
+		_, err := svc.Login(ctx, dto.LoginRequest{Email: user.Email, Password: "password123"})
+		assert.ErrorIs(t, err, ErrInvalidLogin)
+	})
+
+	t.Run("session ends when the user is deactivated or changes password", func(t *testing.T) {
+		svc, userRepo, _ := newTestUserService(t)
+		user := newTestUser(t, testAnalystRole)
+		token, _, err := svc.issueToken(user, time.Now())
+		require.NoError(t, err)
+
+		deactivated := *user
+		deactivated.IsActive = false
+		userRepo.On("FindByID", ctx, user.ID).Return(&deactivated, nil).Once()
+		_, err = svc.Authenticate(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidSession)
+
+		newPassword := *user
+		newPassword.PasswordHash = "$2a$04$different"
+		userRepo.On("FindByID", ctx, user.ID).Return(&newPassword, nil).Once()
+		_, err = svc.Authenticate(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidSession)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		svc, userRepo, _ := newTestUserService(t)
+		user := newTestUser(t, testAnalystRole)
+		token, _, err := svc.issueToken(user, time.Now().Add(-2*time.Hour))
+		require.NoError(t, err)
+
+		_, err = svc.Authenticate(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidSession)
+		userRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("token signed with another secret", func(t *testing.T) {
+		svc, _, _ := newTestUserService(t)
+		user := newTestUser(t, testAdminRole)
+		claims := sessionClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    sessionIssuer,
+				Subject:   user.ID.String(),
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+			PasswordFingerprint: passwordFingerprint(user.PasswordHash),
+		}
+		forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("guessed"))
+		require.NoError(t, err)
+
+		_, err = svc.Authenticate(ctx, forged)
+		assert.ErrorIs(t, err, ErrInvalidSession)
+	})
+
+	t.Run("unsigned token", func(t *testing.T) {
+		svc, _, _ := newTestUserService(t)
+		unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+			Issuer:    sessionIssuer,
+			Subject:   uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+
+		_, err = svc.Authenticate(ctx, unsigned)
+		assert.ErrorIs(t, err, ErrInvalidSession)
+	})
+}
+
+func TestUserService_CreateUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores a bcrypt hash", func(t *testing.T) {
+		svc, userRepo, roleRepo := newTestUserService(t)
+		roleRepo.On("FindByName", ctx, "analyst").Return(testAnalystRole, nil)
+		userRepo.On("FindByEmail", ctx, "ana@example.com").Return(nil, gorm.ErrRecordNotFound)
+		var stored *model.User
+		userRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*model.User)
+		}).Return(nil)
+
+		resp, err := svc.CreateUser(ctx, dto.CreateUserRequest{Email: "Ana@Example.com", Name: " Ana ", Password: "password123", Role: "Analyst"})
+		require.NoError(t, err)
+		assert.Equal(t, "ana@example.com", resp.Email)
+		assert.Equal(t, "Ana", stored.Name)
+		assert.Equal(t, "analyst", stored.RoleName)
+		assert.True(t, stored.IsActive)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(stored.PasswordHash), []byte("password123")))
+	})
+
+	t.Run("email in use", func(t *testing.T) {
+		svc, userRepo, roleRepo := newTestUserService(t)
+		roleRepo.On("FindByName", ctx, "analyst").Return(testAnalystRole, nil)
+		userRepo.On("FindByEmail", ctx, "analyst@example.com").Return(newTestUser(t, testAnalystRole), nil)
+
+		_, err := svc.CreateUser(ctx, dto.CreateUserRequest{Email: "analyst@example.com", Name: "x", Password: "password123", Role: "analyst"})
+		assert.EqualError(t, err, "email already in use: analyst@example.com")
+	})
+
+	invalid := []struct {
+		name    string
+		req     dto.CreateUserRequest
+		wantErr string
+	}{
+		{name: "email", req: dto.CreateUserRequest{Email: "not-an-email", Name: "x", Password: "password123", Role: "analyst"}, wantErr: "invalid user: email is not a valid address"},
+		{name: "name", req: dto.CreateUserRequest{Email: "a@example.com", Password: "password123", Role: "analyst"}, wantErr: "invalid user: name is required"},
+		{name: "short password", req: dto.CreateUserRequest{Email: "a@example.com", Name: "x", Password: "short", Role: "analyst"}, wantErr: "invalid user: password must be 8-72 bytes"},
+		{name: "no role", req: dto.CreateUserRequest{Email: "a@example.com", Name: "x", Password: "password123"}, wantErr: "invalid user: role is required"},
+	}
+	for _, tt := range invalid {
+		t.Run("invalid "+tt.name, func(t *testing.T) {
+			svc, _, _ := newTestUserService(t)
+			_, err := svc.CreateUser(ctx, tt.req)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("unknown role", func(t *testing.T) {
+		svc, _, roleRepo := newTestUserService(t)
+		roleRepo.On("FindByName", ctx, "superuser").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.CreateUser(ctx, dto.CreateUserRequest{Email: "a@example.com", Name: "x", Password: "password123", Role: "superuser"})
+		assert.EqualError(t, err, "invalid user: unknown role: superuser")
+	})
+}
+
+func TestUserService_LastAdmin(t *testing.T) {
+	ctx := context.Background()
+	analyst := model.RoleAnalyst
+	inactive := false
+
+	t.Run("can't demote the last admin", func(t *testing.T) {
+		svc, userRepo, roleRepo := newTestUserService(t)
+		admin := newTestUser(t, testAdminRole)
+		userRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		roleRepo.On("FindByName", ctx, analyst).Return(testAnalystRole, nil)
+		userRepo.On("CountActiveByRole", ctx, model.RoleAdmin).Return(int64(1), nil)
+
+		_, err := svc.UpdateUser(ctx, admin.ID, dto.UpdateUserRequest{Role: &analyst})
+		require.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "invalid user: the last active admin"))
+		userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("can deactivate an admin when another remains", func(t *testing.T) {
+		svc, userRepo, _ := newTestUserService(t)
+		admin := newTestUser(t, testAdminRole)
+		userRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		userRepo.On("CountActiveByRole", ctx, model.RoleAdmin).Return(int64(2), nil)
+		userRepo.On("Update", ctx, admin).Return(nil)
+
+		resp, err := svc.UpdateUser(ctx, admin.ID, dto.UpdateUserRequest{IsActive: &inactive})
+		require.NoError(t, err)
+		assert.False(t, resp.IsActive)
+	})
+
+	t.Run("can't delete the last admin", func(t *testing.T) {
+		svc, userRepo, _ := newTestUserService(t)
+		admin := newTestUser(t, testAdminRole)
+		userRepo.On("FindByID", ctx, admin.ID).Return(admin, nil)
+		userRepo.On("CountActiveByRole", ctx, model.RoleAdmin).Return(int64(1), nil)
+
+		err := svc.DeleteUser(ctx, admin.ID)
+		require.Error(t, err)
+		userRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("deleting other users skips the check", func(t *testing.T) {
+		svc, userRepo, _ := newTestUserService(t)
+		user := newTestUser(t, testAnalystRole)
+		userRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		userRepo.On("Delete", ctx, user.ID).Return(nil)
+
+		require.NoError(t, svc.DeleteUser(ctx, user.ID))
+		userRepo.AssertNotCalled(t, "CountActiveByRole", mock.Anything, mock.Anything)
+	})
+}
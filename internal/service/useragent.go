@@ -0,0 +1,93 @@
+package service
+
+import (
+	"strings"
+)
+
+// browserFamilies maps user agent tokens to browser families, checked in order
+// In-app browsers come first since they also carry Chrome/Safari tokens, and
+// Chromium-based browsers come before Chrome for the same reason
+var browserFamilies = []struct {
+	tokens []string
+	family string
+}{
+	{[]string{"FBAN", "FBAV", "FB_IAB"}, "Facebook"},
+	{[]string{"Instagram"}, "Instagram"},
+	{[]string{"Line/"}, "LINE"},
+	{[]string{"musical_ly", "BytedanceWebview", "TikTok"}, "TikTok"},
+	{[]string{"SamsungBrowser"}, "Samsung Internet"},
+	{[]string{"Edg/", "EdgA/", "EdgiOS/"}, "Edge"},
+	{[]string{"OPR/", "Opera"}, "Opera"},
+	{[]string{"Firefox/", "FxiOS/"}, "Firefox"},
+	{[]string{"CriOS/", "Chrome/"}, "Chrome"},
+	{[]string{"Safari/"}, "Safari"},
+}
+
+// Device operating systems matched by redirect rules
+const (
+	osIOS     = "ios"
+	osAndroid = "android"
+	osWindows = "windows"
+	osMacOS   = "macos"
+	osLinux   = "linux"
+)
+
+// deviceOSes are the operating systems redirect rules may match
+var deviceOSes = []string{osIOS, osAndroid, osWindows, osMacOS, osLinux}
+
+// classifyOS derives the operating system of a user agent, or "" if unknown
+// iPadOS in desktop mode sends a macOS user agent, so those iPads match macos
+func classifyOS(userAgent string) string {
+	switch {
+	case containsAny(userAgent, "iPhone", "iPad", "iPod"):
+		return osIOS
+	case strings.Contains(userAgent, "Android"):
+		return osAndroid
+	case strings.Contains(userAgent, "Windows"):
+		return osWindows
+	case containsAny(userAgent, "Macintosh", "Mac OS X"):
+		return osMacOS
+	case containsAny(userAgent, "Linux", "X11", "CrOS"):
+		return osLinux
+	default:
+		return ""
+	}
+}
+
+// classifyUserAgent derives the browser family and device type of a user agent
+func classifyUserAgent(userAgent string) (browser, device string) {
+	if strings.TrimSpace(userAgent) == "" {
+		return "Unknown", "unknown"
+	}
+
+	browser = "Other"
+	for _, f := range browserFamilies {
+		if containsAny(userAgent, f.tokens...) {
+			browser = f.family
+			break
+		}
+	}
+
+	switch {
+	case matchBotUserAgent(userAgent) != "":
+		device = "bot"
+	case containsAny(userAgent, "iPad", "Tablet") || (strings.Contains(userAgent, "Android") && !strings.Contains(userAgent, "Mobile")):
+		device = "tablet"
+	case containsAny(userAgent, "Mobi", "iPhone", "iPod", "Android"):
+		device = "mobile"
+	default:
+		device = "desktop"
+	}
+
+	return browser, device
+}
+
+// containsAny reports whether s contains any of the substrings
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,22 +1,165 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// Default UTM values for campaigns that don't configure them
+const (
+	defaultUTMSource = "affiliate"
+	defaultUTMMedium = "affiliate"
 )
 
-// buildTargetURL builds a target URL with UTM parameters
-func buildTargetURL(baseURL, utmCampaign, utmSource, utmMedium string) (string, error) {
+// maxUTMLength is the maximum length of a UTM value (before template expansion)
+const maxUTMLength = 100
+
+// utmPlaceholderPattern matches template placeholders such as {{product_id}}
+var utmPlaceholderPattern = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// utmPlaceholders are the template placeholders UTM values may use
+var utmPlaceholders = map[string]bool{
+	"product_id":  true,
+	"campaign_id": true,
+	"marketplace": true,
+	"short_code":  true,
+}
+
+// utmParams holds the UTM values of a link (empty values are omitted from the URL)
+type utmParams struct {
+	Source   string
+	Medium   string
+	Campaign string
+	Content  string
+	Term     string
+}
+
+// linkUTMParams resolves a link's UTM values: the campaign's settings with the
+// link's overrides applied (a nil override inherits, an empty one omits the parameter)
+func linkUTMParams(campaign *model.Campaign, link *model.Link) utmParams {
+	utm := utmParams{
+		Source:   campaign.UTMSource,
+		Medium:   campaign.UTMMedium,
+		Campaign: campaign.UTMCampaign,
+		Content:  campaign.UTMContent,
+		Term:     campaign.UTMTerm,
+	}
+	if link.UTMSource != nil {
+		utm.Source = *link.UTMSource
+	}
+	if link.UTMMedium != nil {
+		utm.Medium = *link.UTMMedium
+	}
+	if link.UTMContent != nil {
+		utm.Content = *link.UTMContent
+	}
+	if link.UTMTerm != nil {
+		utm.Term = *link.UTMTerm
+	}
+	return utm
+}
+
+// offerTargetURL builds the target URL the link would have on another marketplace's
+// offer for its product, using the link's campaign and UTM overrides
+func offerTargetURL(ctx context.Context, campaignRepo CampaignRepositoryInterface, link *model.Link, offerURL string, marketplace model.Marketplace) (string, error) {
+	campaign, err := campaignRepo.FindByID(ctx, link.CampaignID)
+	if err != nil {
+		return "", fmt.Errorf("campaign not found: %w", err)
+	}
+
+	targetURL, err := linkTargetURLFor(offerURL, marketplace, campaign, link)
+	if err != nil {
+		return "", fmt.Errorf("failed to build target URL: %w", err)
+	}
+	return targetURL, nil
+}
+
+// linkTargetURLFor builds the target URL the link would have on an offer of the
+// given marketplace, so {{marketplace}} expands to that marketplace
+func linkTargetURLFor(offerURL string, marketplace model.Marketplace, campaign *model.Campaign, link *model.Link) (string, error) {
+	alternate := *link
+	alternate.Marketplace = marketplace
+	return buildLinkTargetURL(offerURL, campaign, &alternate)
+}
+
+// buildLinkTargetURL builds a link's target URL from the offer URL, expanding
+// UTM templates with the link's product, campaign, marketplace and short code
+func buildLinkTargetURL(baseURL string, campaign *model.Campaign, link *model.Link) (string, error) {
+	vars := map[string]string{
+		"product_id":  link.ProductID.String(),
+		"campaign_id": campaign.ID.String(),
+		"marketplace": string(link.Marketplace),
+		"short_code":  link.ShortCode,
+	}
+	return buildTargetURL(baseURL, linkUTMParams(campaign, link), vars)
+}
+
+// buildTargetURL builds a target URL with UTM parameters, expanding {{placeholder}} templates from vars
+func buildTargetURL(baseURL string, utm utmParams, vars map[string]string) (string, error) {
 	u, err := url.Parse(baseURL)
 	if err != nil {
 		return "", fmt.Errorf("invalid base URL: %w", err)
 	}
 
 	q := u.Query()
-	q.Set("utm_source", utmSource)
-	q.Set("utm_medium", utmMedium)
-	q.Set("utm_campaign", utmCampaign)
+	for _, p := range []struct {
+		key   string
+		value string
+	}{
+		{"utm_source", utm.Source},
+		{"utm_medium", utm.Medium},
+		{"utm_campaign", utm.Campaign},
+		{"utm_content", utm.Content},
+		{"utm_term", utm.Term},
+	} {
+		if value := expandUTMTemplate(p.value, vars); value != "" {
+			q.Set(p.key, value)
+		} else {
+			q.Del(p.key)
+		}
+	}
 	u.RawQuery = q.Encode()
 
 	return u.String(), nil
 }
+
+// expandUTMTemplate replaces {{placeholder}} occurrences with their values
+func expandUTMTemplate(value string, vars map[string]string) string {
+	if !strings.Contains(value, "{{") {
+		return value
+	}
+	return utmPlaceholderPattern.ReplaceAllStringFunc(value, func(match string) string {
+		name := utmPlaceholderPattern.FindStringSubmatch(match)[1]
+		return vars[name]
+	})
+}
+
+// validateUTMValue checks a UTM value's length and that it only uses known placeholders
+func validateUTMValue(field, value string) error {
+	if len(value) > maxUTMLength {
+		return fmt.Errorf("invalid %s: must be %d characters or less", field, maxUTMLength)
+	}
+	for _, match := range utmPlaceholderPattern.FindAllStringSubmatch(value, -1) {
+		if !utmPlaceholders[match[1]] {
+			return fmt.Errorf("invalid %s: unknown placeholder {{%s}} (supported: {{product_id}}, {{campaign_id}}, {{marketplace}}, {{short_code}})", field, match[1])
+		}
+	}
+	return nil
+}
+
+// validateUTMFields validates optional UTM values keyed by field name (nil values are skipped)
+func validateUTMFields(fields map[string]*string) error {
+	for _, field := range []string{"utm_source", "utm_medium", "utm_campaign", "utm_content", "utm_term"} {
+		if value := fields[field]; value != nil {
+			if err := validateUTMValue(field, *value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestBuildLinkTargetURL(t *testing.T) {
+	campaign := &model.Campaign{
+		ID:          uuid.New(),
+		UTMCampaign: "summer_2025",
+		UTMSource:   "facebook",
+		UTMMedium:   "social",
+		UTMContent:  "{{marketplace}}",
+		UTMTerm:     "product_{{product_id}}",
+	}
+	productID := uuid.New()
+
+	tests := []struct {
+		name string
+		link *model.Link
+		want url.Values
+	}{
+		{
+			name: "inherits campaign settings and expands templates",
+			link: &model.Link{ProductID: productID, Marketplace: model.MarketplaceLazada, ShortCode: "abc123"},
+			want: url.Values{
+				"id":           {"42"},
+				"utm_source":   {"facebook"},
+				"utm_medium":   {"social"},
+				"utm_campaign": {"summer_2025"},
+				"utm_content":  {"lazada"},
+				"utm_term":     {"product_" + productID.String()},
+			},
+		},
+		{
+			name: "link overrides replace campaign values and empty ones omit the parameter",
+			link: &model.Link{
+				ProductID:   productID,
+				Marketplace: model.MarketplaceShopee,
+				ShortCode:   "xyz789",
+				UTMSource:   strPtr("line"),
+				UTMContent:  strPtr("{{short_code}}_{{campaign_id}}"),
+				UTMTerm:     strPtr(""),
+			},
+			want: url.Values{
+				"id":           {"42"},
+				"utm_source":   {"line"},
+				"utm_medium":   {"social"},
+				"utm_campaign": {"summer_2025"},
+				"utm_content":  {"xyz789_" + campaign.ID.String()},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildLinkTargetURL("https://example.com/p?id=42&utm_term=stale", campaign, tt.link)
+			require.NoError(t, err)
+
+			u, err := url.Parse(got)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, u.Query())
+		})
+	}
+}
+
+func TestBuildLinkTargetURL_InvalidBaseURL(t *testing.T) {
+	_, err := buildLinkTargetURL("://bad", &model.Campaign{}, &model.Link{})
+	assert.ErrorContains(t, err, "invalid base URL")
+}
+
+func TestValidateUTMFields(t *testing.T) {
+	long := string(make([]byte, maxUTMLength+1))
+
+	assert.NoError(t, validateUTMFields(map[string]*string{
+		"utm_source":  strPtr("{{ marketplace }}"),
+		"utm_content": strPtr(""),
+		"utm_term":    nil,
+	}))
+	assert.EqualError(t, validateUTMFields(map[string]*string{"utm_medium": &long}),
+		"invalid utm_medium: must be 100 characters or less")
+	assert.ErrorContains(t, validateUTMFields(map[string]*string{"utm_term": strPtr("{{sku}}")}),
+		"invalid utm_term: unknown placeholder {{sku}}")
+}
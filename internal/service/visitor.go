@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+)
+
+// visitorIDLength is the length of a visitor ID (hex-encoded, 128 bits)
+const visitorIDLength = 32
+
+// clickDedupeKeyPrefix namespaces dedupe window entries in the dedupe store
+const clickDedupeKeyPrefix = "click:dedupe:"
+
+// VisitorIdentifier derives anonymous, stable visitor IDs for click de-duplication.
+// A visitor ID from the first-party cookie wins; otherwise the ID is a salted
+// hash of IP address and user agent, so raw values never leave the clicks table.
+type VisitorIdentifier struct {
+	salt []byte
+}
+
+// NewVisitorIdentifier creates a visitor identifier
+// An empty salt is replaced by a random one, which keeps fingerprints private
+// but changes them on every restart and differs between API instances
+func NewVisitorIdentifier(salt string) *VisitorIdentifier {
+	if salt == "" {
+		random := make([]byte, 32)
+		_, _ = rand.Read(random)
+		return &VisitorIdentifier{salt: random}
+	}
+	return &VisitorIdentifier{salt: []byte(salt)}
+}
+
+// Identify returns the visitor ID for a click: the cookie value when valid,
+// otherwise the IP + user agent fingerprint
+func (v *VisitorIdentifier) Identify(meta dto.ClickMetadata) string {
+	if IsValidVisitorID(meta.VisitorCookie) {
+		return meta.VisitorCookie
+	}
+
+	ipStr := ""
+	if meta.IPAddress != nil {
+		ipStr = meta.IPAddress.String()
+	}
+
+	mac := hmac.New(sha256.New, v.salt)
+	mac.Write([]byte(ipStr))
+	mac.Write([]byte{0})
+	mac.Write([]byte(meta.UserAgent))
+	return hex.EncodeToString(mac.Sum(nil))[:visitorIDLength]
+}
+
+// IsValidVisitorID reports whether s has the shape of a visitor ID
+// Cookie values are client-controlled, so anything else is ignored
+func IsValidVisitorID(s string) bool {
+	if len(s) != visitorIDLength {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// ClickDeduplicator flags a click as unique when it is the visitor's first
+// click on the link within the dedupe window
+type ClickDeduplicator struct {
+	store  DedupeStore
+	window time.Duration
+}
+
+// NewClickDeduplicator creates a click deduplicator
+// A non-positive window disables de-duplication (every human click is unique)
+func NewClickDeduplicator(store DedupeStore, window time.Duration) *ClickDeduplicator {
+	return &ClickDeduplicator{
+		store:  store,
+		window: window,
+	}
+}
+
+// IsUnique reports whether the visitor has not clicked the link within the window,
+// starting a new window when it has not
+func (d *ClickDeduplicator) IsUnique(ctx context.Context, linkID uuid.UUID, visitorID string) (bool, error) {
+	if d.window <= 0 || visitorID == "" {
+		return true, nil
+	}
+	return d.store.Add(ctx, clickDedupeKeyPrefix+linkID.String()+":"+visitorID, []byte{1}, d.window)
+}
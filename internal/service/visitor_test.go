@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// fakeDedupeStore is an in-memory DedupeStore without expiry
+type fakeDedupeStore struct {
+	mu   sync.Mutex
+	keys map[string]time.Duration
+}
+
+func (f *fakeDedupeStore) Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.keys == nil {
+		f.keys = make(map[string]time.Duration)
+	}
+	if _, ok := f.keys[key]; ok {
+		return false, nil
+	}
+	f.keys[key] = ttl
+	return true, nil
+}
+
+func TestVisitorIdentifier_Identify(t *testing.T) {
+	visitors := NewVisitorIdentifier("test-salt")
+	meta := dto.ClickMetadata{IPAddress: net.ParseIP("198.51.100.1"), UserAgent: chromeAndroidUA}
+
+	id := visitors.Identify(meta)
+	assert.True(t, IsValidVisitorID(id))
+	assert.Equal(t, id, visitors.Identify(meta), "fingerprint is stable")
+
+	otherUA := meta
+	otherUA.UserAgent = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X)"
+	assert.NotEqual(t, id, visitors.Identify(otherUA))
+
+	assert.NotEqual(t, id, NewVisitorIdentifier("other-salt").Identify(meta), "fingerprint depends on the salt")
+
+	// A valid cookie wins over the fingerprint; tampered cookies are ignored
+	withCookie := meta
+	withCookie.VisitorCookie = "0123456789abcdef0123456789abcdef"
+	assert.Equal(t, withCookie.VisitorCookie, visitors.Identify(withCookie))
+
+	withCookie.VisitorCookie = "<script>"
+	assert.Equal(t, id, visitors.Identify(withCookie))
+}
+
+func TestClickService_TrackClick_Dedupe(t *testing.T) {
+	ctx := context.Background()
+	linkID := uuid.New()
+	otherLinkID := uuid.New()
+
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	filter, err := NewBotFilter(nil, nil)
+	require.NoError(t, err)
+	store := &fakeDedupeStore{}
+
+	var clicks []*model.Click
+	clickRepo := new(MockClickRepository)
+	clickRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		clicks = append(clicks, args.Get(1).(*model.Click))
+	}).Return(nil)
+
+	svc := NewClickService(clickRepo, new(MockLinkRepository), nil, filter, NewClickDeduplicator(store, 30*time.Minute), log)
+
+	human := dto.ClickMetadata{Method: "GET", UserAgent: chromeAndroidUA, VisitorID: "0123456789abcdef0123456789abcdef"}
+	bot := dto.ClickMetadata{Method: "GET", UserAgent: "facebookexternalhit/1.1", VisitorID: "fedcba9876543210fedcba9876543210"}
+
+	require.NoError(t, svc.TrackClick(ctx, linkID, bot))
+	require.NoError(t, svc.TrackClick(ctx, linkID, human))
+	require.NoError(t, svc.TrackClick(ctx, linkID, human))
+	require.NoError(t, svc.TrackClick(ctx, otherLinkID, human))
+
+	require.Len(t, clicks, 4)
+	assert.False(t, clicks[0].IsUnique, "bots are never unique")
+	assert.True(t, clicks[1].IsUnique)
+	assert.False(t, clicks[2].IsUnique, "repeat click within the window")
+	assert.True(t, clicks[3].IsUnique, "windows are per link")
+	assert.Equal(t, human.VisitorID, clicks[1].VisitorID)
+	assert.Len(t, store.keys, 2)
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/config"
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+var (
+	// workspaceSlugPattern matches lowercase slugs like "gadget-deals"
+	workspaceSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+	// redirectDomainPattern matches a bare host name: no scheme, port or path
+	redirectDomainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,63}$`)
+)
+
+// WorkspaceService manages publisher workspaces
+type WorkspaceService struct {
+	workspaceRepo WorkspaceRepositoryInterface
+	logger        logger.Logger
+}
+
+// NewWorkspaceService creates a new workspace service
+func NewWorkspaceService(workspaceRepo WorkspaceRepositoryInterface, log logger.Logger) *WorkspaceService {
+	return &WorkspaceService{
+		workspaceRepo: workspaceRepo,
+		logger:        log,
+	}
+}
+
+// CreateWorkspace creates a workspace
+func (s *WorkspaceService) CreateWorkspace(ctx context.Context, req dto.CreateWorkspaceRequest) (*dto.WorkspaceResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := validateWorkspaceName(name); err != nil {
+		return nil, err
+	}
+	slug := strings.TrimSpace(req.Slug)
+	if len(slug) > 50 || !workspaceSlugPattern.MatchString(slug) {
+		return nil, fmt.Errorf("invalid workspace: slug must be 1-50 lowercase letters, digits and single hyphens")
+	}
+	domain, err := normalizeRedirectDomain(req.RedirectDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.workspaceRepo.FindBySlug(ctx, slug); err == nil {
+		return nil, fmt.Errorf("slug already in use: %s", slug)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check slug: %w", err)
+	}
+	if err := s.ensureRedirectDomainAvailable(ctx, domain, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	workspace := &model.Workspace{
+		Name:           name,
+		Slug:           slug,
+		RedirectDomain: domain,
+	}
+	if err := s.workspaceRepo.Create(ctx, workspace); err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	return toWorkspaceResponse(workspace), nil
+}
+
+// GetAllWorkspaces lists workspaces by name
+func (s *WorkspaceService) GetAllWorkspaces(ctx context.Context, limit, offset int) ([]*dto.WorkspaceResponse, error) {
+	workspaces, _, err := s.workspaceRepo.FindAll(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspaces: %w", err)
+	}
+
+	responses := make([]*dto.WorkspaceResponse, len(workspaces))
+	for i, workspace := range workspaces {
+		responses[i] = toWorkspaceResponse(workspace)
+	}
+	return responses, nil
+}
+
+// GetCurrentWorkspace returns the caller's workspace
+func (s *WorkspaceService) GetCurrentWorkspace(ctx context.Context) (*dto.WorkspaceResponse, error) {
+	id, ok := auth.WorkspaceFrom(ctx)
+	if !ok {
+		return nil, fmt.Errorf("workspace not found: no workspace in request context")
+	}
+	workspace, err := s.findWorkspace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toWorkspaceResponse(workspace), nil
+}
+
+// UpdateWorkspace updates a workspace's name and redirect domain
+// The slug can't change: operators and scripts refer to workspaces by it
+func (s *WorkspaceService) UpdateWorkspace(ctx context.Context, id uuid.UUID, req dto.UpdateWorkspaceRequest) (*dto.WorkspaceResponse, error) {
+	workspace, err := s.findWorkspace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := validateWorkspaceName(name); err != nil {
+			return nil, err
+		}
+		workspace.Name = name
+	}
+	if req.RedirectDomain != nil {
+		domain, err := normalizeRedirectDomain(*req.RedirectDomain)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.ensureRedirectDomainAvailable(ctx, domain, workspace.ID); err != nil {
+			return nil, err
+		}
+		workspace.RedirectDomain = domain
+	}
+
+	if err := s.workspaceRepo.Update(ctx, workspace); err != nil {
+		return nil, fmt.Errorf("failed to update workspace: %w", err)
+	}
+
+	return toWorkspaceResponse(workspace), nil
+}
+
+// findWorkspace loads a workspace, mapping a missing row to "workspace not found"
+func (s *WorkspaceService) findWorkspace(ctx context.Context, id uuid.UUID) (*model.Workspace, error) {
+	workspace, err := s.workspaceRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("workspace not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+	return workspace, nil
+}
+
+// ensureRedirectDomainAvailable checks that no workspace other than self uses domain
+func (s *WorkspaceService) ensureRedirectDomainAvailable(ctx context.Context, domain string, self uuid.UUID) error {
+	if domain == "" {
+		return nil
+	}
+	existing, err := s.workspaceRepo.FindByRedirectDomain(ctx, domain)
+	if err == nil {
+		if existing.ID != self {
+			return fmt.Errorf("redirect domain already in use: %s", domain)
+		}
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to check redirect domain: %w", err)
+	}
+	return nil
+}
+
+// validateWorkspaceName checks a trimmed workspace name
+func validateWorkspaceName(name string) error {
+	if name == "" {
+		return fmt.Errorf("invalid workspace: name is required")
+	}
+	if len(name) > 100 {
+		return fmt.Errorf("invalid workspace: name must be at most 100 characters")
+	}
+	return nil
+}
+
+// normalizeRedirectDomain lower-cases and validates a redirect domain; empty means none
+func normalizeRedirectDomain(domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if domain == "" {
+		return "", nil
+	}
+	if len(domain) > 255 || !redirectDomainPattern.MatchString(domain) {
+		return "", fmt.Errorf("invalid workspace: redirect_domain must be a host name like go.example.com, without scheme, port or path")
+	}
+	return domain, nil
+}
+
+// shortLinkBaseURL returns where a workspace's short links live: its redirect
+// domain over HTTPS, or api.base_url
+func shortLinkBaseURL(workspace *model.Workspace, cfg config.Config) string {
+	if workspace != nil && workspace.RedirectDomain != "" {
+		return "https://" + workspace.RedirectDomain
+	}
+	apiBaseURL := cfg.GetAPIBaseURL()
+	if apiBaseURL == "" {
+		apiBaseURL = "http://localhost:8080"
+	}
+	return apiBaseURL
+}
+
+// loadWorkspace returns the workspace with the given ID
+// A failed lookup is logged and returns nil, so short links fall back to api.base_url
+func loadWorkspace(ctx context.Context, workspaceRepo WorkspaceRepositoryInterface, id uuid.UUID, log logger.Logger) *model.Workspace {
+	workspace, err := workspaceRepo.FindByID(ctx, id)
+	if err != nil {
+		log.Warn("Failed to get workspace for short links", logger.Error(err), logger.String("workspace_id", id.String()))
+		return nil
+	}
+	return workspace
+}
+
+// servedOnHost reports whether a link of the workspace may redirect on the requested host
+// Links of a workspace with a redirect domain resolve on that domain and on the
+// api.base_url host (for links shared before the domain was set); other links,
+// and requests without a host, resolve anywhere
+func servedOnHost(workspace *model.Workspace, host string, cfg config.Config) bool {
+	if workspace == nil || workspace.RedirectDomain == "" || host == "" {
+		return true
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if strings.EqualFold(host, workspace.RedirectDomain) {
+		return true
+	}
+	if cfg != nil {
+		if u, err := url.Parse(cfg.GetAPIBaseURL()); err == nil && strings.EqualFold(host, u.Hostname()) {
+			return true
+		}
+	}
+	return false
+}
+
+// toWorkspaceResponse converts a workspace to a response
+func toWorkspaceResponse(workspace *model.Workspace) *dto.WorkspaceResponse {
+	return &dto.WorkspaceResponse{
+		ID:             workspace.ID,
+		Name:           workspace.Name,
+		Slug:           workspace.Slug,
+		RedirectDomain: workspace.RedirectDomain,
+		CreatedAt:      workspace.CreatedAt,
+		UpdatedAt:      workspace.UpdatedAt,
+	}
+}
@@ -0,0 +1,160 @@
+//go:build integration
+// +build integration
+
+package service
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/config"
+	"github.com/jonosize/affiliate-platform/internal/database"
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	"github.com/jonosize/affiliate-platform/internal/repository"
+	"github.com/jonosize/affiliate-platform/pkg/adapters/mock"
+)
+
+// WorkspaceIsolationTestSuite checks against a real database that two
+// workspaces never see each other's products, campaigns, links or clicks
+type WorkspaceIsolationTestSuite struct {
+	suite.Suite
+	db           *database.DB
+	workspaceIDs []uuid.UUID
+	productIDs   map[uuid.UUID]uuid.UUID // Workspace ID -> product ID
+	linkRepo     *repository.LinkRepository
+
+	productSvc   *ProductService
+	campaignSvc  *CampaignService
+	linkSvc      *LinkService
+	redirectSvc  *RedirectService
+	dashboardSvc *DashboardService
+}
+
+func (suite *WorkspaceIsolationTestSuite) SetupSuite() {
+	if testing.Short() {
+		suite.T().Skip("Skipping integration test in short mode")
+	}
+
+	configPath := os.Getenv("CONFIG_PATH")
+	if configPath == "" {
+		configPath = "./configs"
+	}
+	cfg := config.LoadOrPanic(configPath)
+	log, err := logger.NewZapLogger("info")
+	require.NoError(suite.T(), err)
+	db, err := database.InitGORM(cfg)
+	require.NoError(suite.T(), err)
+	suite.db = db
+
+	registry, err := mock.NewRegistry()
+	require.NoError(suite.T(), err)
+
+	productRepo := repository.NewProductRepository(db)
+	offerRepo := repository.NewOfferRepository(db)
+	campaignRepo := repository.NewCampaignRepository(db)
+	linkRepo := repository.NewLinkRepository(db)
+	suite.linkRepo = linkRepo
+	clickRepo := repository.NewClickRepository(db)
+	workspaceRepo := repository.NewWorkspaceRepository(db)
+
+	suite.productSvc = NewProductService(productRepo, offerRepo, repository.NewPriceHistoryRepository(db), registry, nil, nil, log)
+	suite.campaignSvc = NewCampaignService(campaignRepo, linkRepo, offerRepo, productRepo, nil, nil, cfg, log)
+	suite.linkSvc = NewLinkService(linkRepo, campaignRepo, productRepo, offerRepo, workspaceRepo, registry, nil, nil, cfg, log)
+	suite.redirectSvc = NewRedirectService(linkRepo, offerRepo, NewClickService(clickRepo, linkRepo, nil, nil, nil, log), registry, nil, cfg, log)
+	suite.dashboardSvc = NewDashboardService(clickRepo, repository.NewImpressionRepository(db), repository.NewConversionRepository(db), linkRepo, campaignRepo, productRepo, registry, log)
+
+	suite.productIDs = make(map[uuid.UUID]uuid.UUID)
+	for _, slug := range []string{"isolation-a", "isolation-b"} {
+		workspace := &model.Workspace{Name: slug, Slug: slug + "-" + uuid.NewString()[:8]}
+		require.NoError(suite.T(), workspaceRepo.Create(context.Background(), workspace))
+		suite.workspaceIDs = append(suite.workspaceIDs, workspace.ID)
+	}
+}
+
+func (suite *WorkspaceIsolationTestSuite) TearDownSuite() {
+	if suite.db == nil {
+		return
+	}
+	// Purging products cascades to their campaigns' links and clicks
+	for _, id := range suite.workspaceIDs {
+		ctx := auth.WithWorkspace(context.Background(), id)
+		if productID, ok := suite.productIDs[id]; ok {
+			_ = suite.productSvc.DeleteProduct(ctx, productID)
+			_ = suite.productSvc.PurgeProduct(ctx, productID, productID.String())
+		}
+		suite.db.Write.Unscoped().Where("workspace_id = ?", id).Delete(&model.Campaign{})
+		suite.db.Write.Delete(&model.Workspace{}, "id = ?", id)
+	}
+	_ = suite.db.Close()
+}
+
+// seed creates a product, a campaign and a link in a workspace and clicks the link
+func (suite *WorkspaceIsolationTestSuite) seed(workspaceID uuid.UUID, clicks int) *dto.LinkResponse {
+	ctx := auth.WithWorkspace(context.Background(), workspaceID)
+
+	product, err := suite.productSvc.CreateProduct(ctx, dto.CreateProductRequest{
+		LazadaURL: "https://www.lazada.co.th/products/isolation-" + workspaceID.String() + ".html",
+	})
+	require.NoError(suite.T(), err)
+	suite.productIDs[workspaceID] = product.ID
+
+	campaign, err := suite.campaignSvc.CreateCampaign(ctx, dto.CreateCampaignRequest{
+		Name:        "Isolation " + workspaceID.String(),
+		UTMCampaign: "isolation",
+		StartAt:     time.Now().Add(-time.Hour),
+		EndAt:       time.Now().Add(time.Hour),
+	})
+	require.NoError(suite.T(), err)
+
+	link, err := suite.linkSvc.CreateLink(ctx, dto.CreateLinkRequest{ProductID: product.ID, CampaignID: campaign.ID, Marketplace: "lazada"})
+	require.NoError(suite.T(), err)
+
+	// Redirects are public: no workspace in the context
+	for i := 0; i < clicks; i++ {
+		_, err := suite.redirectSvc.Redirect(context.Background(), link.ShortCode, dto.ClickMetadata{Method: "GET", UserAgent: chromeAndroidUA})
+		require.NoError(suite.T(), err)
+	}
+	return link
+}
+
+func (suite *WorkspaceIsolationTestSuite) TestDashboardIsIsolated() {
+	a, b := suite.workspaceIDs[0], suite.workspaceIDs[1]
+	linkA := suite.seed(a, 2)
+	linkB := suite.seed(b, 3)
+
+	for workspaceID, want := range map[uuid.UUID]int64{a: 2, b: 3} {
+		ctx := auth.WithWorkspace(context.Background(), workspaceID)
+		stats, err := suite.dashboardSvc.GetDashboardStats(ctx, dto.DashboardQueryParams{})
+		require.NoError(suite.T(), err)
+
+		assert.Equal(suite.T(), want, stats.TotalClicks)
+		assert.Equal(suite.T(), int64(1), stats.TotalLinks)
+		require.Len(suite.T(), stats.TopProducts, 1)
+		assert.Equal(suite.T(), suite.productIDs[workspaceID], stats.TopProducts[0].ProductID)
+		assert.Len(suite.T(), stats.RecentClicks, int(want))
+		for _, click := range stats.RecentClicks {
+			assert.Equal(suite.T(), suite.productIDs[workspaceID], click.ProductID)
+		}
+	}
+
+	// Neither workspace can read the other's link
+	_, err := suite.linkRepo.FindByID(auth.WithWorkspace(context.Background(), a), linkB.ID)
+	assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+	_, err = suite.linkRepo.FindByID(auth.WithWorkspace(context.Background(), b), linkA.ID)
+	assert.ErrorIs(suite.T(), err, gorm.ErrRecordNotFound)
+}
+
+func TestWorkspaceIsolationTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkspaceIsolationTestSuite))
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/jonosize/affiliate-platform/internal/auth"
+	"github.com/jonosize/affiliate-platform/internal/dto"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+	mockadapter "github.com/jonosize/affiliate-platform/pkg/adapters/mock"
+)
+
+// MockWorkspaceRepository is a mock implementation of WorkspaceRepositoryInterface
+type MockWorkspaceRepository struct {
+	mock.Mock
+}
+
+func (m *MockWorkspaceRepository) Create(ctx context.Context, workspace *model.Workspace) error {
+	args := m.Called(ctx, workspace)
+	return args.Error(0)
+}
+
+func (m *MockWorkspaceRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Workspace, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Workspace), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) FindBySlug(ctx context.Context, slug string) (*model.Workspace, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Workspace), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) FindByRedirectDomain(ctx context.Context, domain string) (*model.Workspace, error) {
+	args := m.Called(ctx, domain)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Workspace), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) FindAll(ctx context.Context, limit, offset int) ([]*model.Workspace, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.Workspace), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockWorkspaceRepository) Update(ctx context.Context, workspace *model.Workspace) error {
+	args := m.Called(ctx, workspace)
+	return args.Error(0)
+}
+
+func newTestWorkspaceService(t *testing.T) (*WorkspaceService, *MockWorkspaceRepository) {
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	repo := new(MockWorkspaceRepository)
+	return NewWorkspaceService(repo, log), repo
+}
+
+func TestWorkspaceService_CreateWorkspace(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("normalizes the redirect domain", func(t *testing.T) {
+		svc, repo := newTestWorkspaceService(t)
+		repo.On("FindBySlug", ctx, "gadget-deals").Return(nil, gorm.ErrRecordNotFound)
+		repo.On("FindByRedirectDomain", ctx, "go.gadgetdeals.example").Return(nil, gorm.ErrRecordNotFound)
+		repo.On("Create", ctx, mock.Anything).Return(nil)
+
+		resp, err := svc.CreateWorkspace(ctx, dto.CreateWorkspaceRequest{Name: " Gadget Deals ", Slug: "gadget-deals", RedirectDomain: "Go.GadgetDeals.example."})
+		require.NoError(t, err)
+		assert.Equal(t, "Gadget Deals", resp.Name)
+		assert.Equal(t, "go.gadgetdeals.example", resp.RedirectDomain)
+	})
+
+	t.Run("slug in use", func(t *testing.T) {
+		svc, repo := newTestWorkspaceService(t)
+		repo.On("FindBySlug", ctx, "default").Return(&model.Workspace{ID: model.DefaultWorkspaceID}, nil)
+
+		_, err := svc.CreateWorkspace(ctx, dto.CreateWorkspaceRequest{Name: "Again", Slug: "default"})
+		assert.EqualError(t, err, "slug already in use: default")
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("redirect domain in use", func(t *testing.T) {
+		svc, repo := newTestWorkspaceService(t)
+		repo.On("FindBySlug", ctx, "beauty").Return(nil, gorm.ErrRecordNotFound)
+		repo.On("FindByRedirectDomain", ctx, "go.example.com").Return(&model.Workspace{ID: uuid.New()}, nil)
+
+		_, err := svc.CreateWorkspace(ctx, dto.CreateWorkspaceRequest{Name: "Beauty", Slug: "beauty", RedirectDomain: "go.example.com"})
+		assert.EqualError(t, err, "redirect domain already in use: go.example.com")
+	})
+
+	invalid := []struct {
+		name string
+		req  dto.CreateWorkspaceRequest
+	}{
+		{name: "name", req: dto.CreateWorkspaceRequest{Slug: "x"}},
+		{name: "uppercase slug", req: dto.CreateWorkspaceRequest{Name: "x", Slug: "Gadgets"}},
+		{name: "slug with trailing hyphen", req: dto.CreateWorkspaceRequest{Name: "x", Slug: "gadgets-"}},
+		{name: "domain with scheme", req: dto.CreateWorkspaceRequest{Name: "x", Slug: "x", RedirectDomain: "https://go.example.com"}},
+		{name: "domain with port", req: dto.CreateWorkspaceRequest{Name: "x", Slug: "x", RedirectDomain: "go.example.com:8080"}},
+		{name: "domain with path", req: dto.CreateWorkspaceRequest{Name: "x", Slug: "x", RedirectDomain: "go.example.com/go"}},
+	}
+	for _, tt := range invalid {
+		t.Run("invalid "+tt.name, func(t *testing.T) {
+			svc, _ := newTestWorkspaceService(t)
+			_, err := svc.CreateWorkspace(ctx, tt.req)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid workspace: ")
+		})
+	}
+}
+
+func TestWorkspaceService_UpdateWorkspace(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	t.Run("keeping its own domain", func(t *testing.T) {
+		svc, repo := newTestWorkspaceService(t)
+		repo.On("FindByID", ctx, id).Return(&model.Workspace{ID: id, Name: "Beauty", RedirectDomain: "go.example.com"}, nil)
+		repo.On("FindByRedirectDomain", ctx, "go.example.com").Return(&model.Workspace{ID: id}, nil)
+		repo.On("Update", ctx, mock.Anything).Return(nil)
+
+		domain := "go.example.com"
+		resp, err := svc.UpdateWorkspace(ctx, id, dto.UpdateWorkspaceRequest{RedirectDomain: &domain})
+		require.NoError(t, err)
+		assert.Equal(t, "go.example.com", resp.RedirectDomain)
+	})
+
+	t.Run("removing the domain", func(t *testing.T) {
+		svc, repo := newTestWorkspaceService(t)
+		repo.On("FindByID", ctx, id).Return(&model.Workspace{ID: id, RedirectDomain: "go.example.com"}, nil)
+		repo.On("Update", ctx, mock.Anything).Return(nil)
+
+		empty := ""
+		resp, err := svc.UpdateWorkspace(ctx, id, dto.UpdateWorkspaceRequest{RedirectDomain: &empty})
+		require.NoError(t, err)
+		assert.Empty(t, resp.RedirectDomain)
+		repo.AssertNotCalled(t, "FindByRedirectDomain", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown workspace", func(t *testing.T) {
+		svc, repo := newTestWorkspaceService(t)
+		repo.On("FindByID", ctx, id).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.UpdateWorkspace(ctx, id, dto.UpdateWorkspaceRequest{})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Contains(t, err.Error(), "workspace not found")
+	})
+}
+
+func TestWorkspaceService_GetCurrentWorkspace(t *testing.T) {
+	svc, repo := newTestWorkspaceService(t)
+	id := uuid.New()
+	ctx := auth.WithWorkspace(context.Background(), id)
+	repo.On("FindByID", ctx, id).Return(&model.Workspace{ID: id, Slug: "beauty"}, nil)
+
+	resp, err := svc.GetCurrentWorkspace(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "beauty", resp.Slug)
+
+	_, err = svc.GetCurrentWorkspace(context.Background())
+	assert.Error(t, err)
+}
+
+func TestLinkService_FullURL_RedirectDomain(t *testing.T) {
+	ctx := context.Background()
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	cfg := &MockConfig{apiBaseURL: "https://api.example.com"}
+
+	branded := &model.Workspace{ID: uuid.New(), RedirectDomain: "go.gadgetdeals.example"}
+	workspaceRepo := new(MockWorkspaceRepository)
+	workspaceRepo.On("FindByID", ctx, branded.ID).Return(branded, nil)
+	workspaceRepo.On("FindByID", ctx, model.DefaultWorkspaceID).Return(&model.Workspace{ID: model.DefaultWorkspaceID}, nil)
+	broken := uuid.New()
+	workspaceRepo.On("FindByID", ctx, broken).Return(nil, gorm.ErrInvalidDB)
+
+	svc := NewLinkService(nil, nil, nil, nil, workspaceRepo, nil, nil, nil, cfg, log)
+
+	assert.Equal(t, "https://go.gadgetdeals.example/go/abc123", svc.fullURL(ctx, &model.Link{WorkspaceID: branded.ID, ShortCode: "abc123"}))
+	assert.Equal(t, "https://api.example.com/go/abc123", svc.fullURL(ctx, &model.Link{WorkspaceID: model.DefaultWorkspaceID, ShortCode: "abc123"}))
+	assert.Equal(t, "https://api.example.com/go/abc123", svc.fullURL(ctx, &model.Link{WorkspaceID: broken, ShortCode: "abc123"}), "lookup failures fall back to api.base_url")
+
+	// Preloaded workspaces are used as is
+	assert.Equal(t, "https://go.gadgetdeals.example/go/xyz", svc.fullURL(ctx, &model.Link{Workspace: branded, ShortCode: "xyz"}))
+}
+
+func TestRedirectService_Redirect_WorkspaceDomain(t *testing.T) {
+	workspace := &model.Workspace{ID: uuid.New(), RedirectDomain: "go.gadgetdeals.example"}
+
+	tests := []struct {
+		name      string
+		workspace *model.Workspace
+		host      string
+		wantErr   bool
+	}{
+		{name: "on the workspace domain", workspace: workspace, host: "go.gadgetdeals.example"},
+		{name: "host is case-insensitive and may carry a port", workspace: workspace, host: "GO.GadgetDeals.example:443"},
+		{name: "on the platform host", workspace: workspace, host: "api.example.com"},
+		{name: "on another brand's domain", workspace: workspace, host: "go.beautydeals.example", wantErr: true},
+		{name: "workspace without a domain", workspace: &model.Workspace{ID: workspace.ID}, host: "go.beautydeals.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			log, err := logger.NewZapLogger("info")
+			require.NoError(t, err)
+			registry, err := mockadapter.NewRegistry()
+			require.NoError(t, err)
+
+			link := &model.Link{
+				ID:          uuid.New(),
+				WorkspaceID: tt.workspace.ID,
+				Workspace:   tt.workspace,
+				Marketplace: model.MarketplaceLazada,
+				ShortCode:   "abc123",
+				TargetURL:   "https://www.lazada.co.th/products/matcha-i123456.html",
+			}
+			linkRepo := new(MockLinkRepository)
+			linkRepo.On("FindByShortCode", ctx, "abc123").Return(link, nil)
+
+			var tracked *model.Click
+			clickRepo := new(MockClickRepository)
+			clickRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				tracked = args.Get(1).(*model.Click)
+			}).Return(nil)
+
+			cfg := &MockConfig{apiBaseURL: "https://api.example.com"}
+			svc := NewRedirectService(linkRepo, nil, NewClickService(clickRepo, linkRepo, nil, nil, nil, log), registry, nil, cfg, log)
+			_, err = svc.Redirect(ctx, "abc123", dto.ClickMetadata{Method: "GET", UserAgent: chromeAndroidUA, Host: tt.host})
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrLinkNotFound)
+				assert.NotContains(t, err.Error(), "abc123", "the short code is not echoed")
+				assert.Nil(t, tracked)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, tracked)
+			assert.Equal(t, workspace.ID, tracked.WorkspaceID, "clicks are recorded in the link's workspace")
+		})
+	}
+}
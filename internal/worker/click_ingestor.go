@@ -0,0 +1,247 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jonosize/affiliate-platform/internal/config"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+var (
+	// ErrClickQueueFull is returned by Enqueue when the queue stayed full for the enqueue timeout
+	ErrClickQueueFull = errors.New("click queue full")
+	// ErrClickIngestorStopped is returned by Enqueue after Stop has been called
+	ErrClickIngestorStopped = errors.New("click ingestor stopped")
+)
+
+// Click ingestor defaults, used when config values are not positive
+const (
+	defaultClickQueueSize      = 10000
+	defaultClickIngestWorkers  = 2
+	defaultClickBatchSize      = 500
+	defaultClickFlushInterval  = time.Second
+	defaultClickEnqueueTimeout = 10 * time.Millisecond
+
+	// clickFlushTimeout bounds a single batch insert
+	clickFlushTimeout = 10 * time.Second
+)
+
+// ClickBatchWriter persists click events in batches
+type ClickBatchWriter interface {
+	CreateBatch(ctx context.Context, clicks []*model.Click) error
+}
+
+// ClickIngestorStats is a snapshot of the click ingestor counters
+type ClickIngestorStats struct {
+	Enqueued   int64 `json:"enqueued" example:"125000"`  // Clicks accepted into the queue
+	Dropped    int64 `json:"dropped" example:"0"`        // Clicks rejected because the queue was full or stopped
+	Persisted  int64 `json:"persisted" example:"124980"` // Clicks written to the database
+	Failed     int64 `json:"failed" example:"0"`         // Clicks lost to failed batch inserts
+	Batches    int64 `json:"batches" example:"310"`      // Batch inserts attempted
+	QueueDepth int   `json:"queue_depth" example:"20"`   // Clicks currently waiting in the queue
+	QueueSize  int   `json:"queue_size" example:"10000"` // Queue capacity
+}
+
+// ClickIngestor buffers click events in a bounded queue and persists them
+// with multi-row inserts from a pool of workers. Each worker flushes its batch
+// when it reaches the batch size or when the flush interval elapses.
+type ClickIngestor struct {
+	writer         ClickBatchWriter
+	logger         logger.Logger
+	queue          chan *model.Click
+	workers        int
+	batchSize      int
+	flushInterval  time.Duration
+	enqueueTimeout time.Duration
+
+	mu      sync.RWMutex // guards stopped and closing queue against concurrent Enqueue
+	stopped bool
+	wg      sync.WaitGroup
+
+	enqueued  atomic.Int64
+	dropped   atomic.Int64
+	persisted atomic.Int64
+	failed    atomic.Int64
+	batches   atomic.Int64
+}
+
+// NewClickIngestor creates a new click ingestor configured from cfg
+func NewClickIngestor(writer ClickBatchWriter, cfg config.Config, log logger.Logger) *ClickIngestor {
+	queueSize := positiveOr(cfg.GetClickQueueSize(), defaultClickQueueSize)
+	flushInterval := time.Duration(cfg.GetClickFlushInterval()) * time.Millisecond
+	if flushInterval <= 0 {
+		flushInterval = defaultClickFlushInterval
+	}
+	enqueueTimeout := time.Duration(cfg.GetClickEnqueueTimeout()) * time.Millisecond
+	if enqueueTimeout < 0 {
+		enqueueTimeout = defaultClickEnqueueTimeout
+	}
+
+	return &ClickIngestor{
+		writer:         writer,
+		logger:         log,
+		queue:          make(chan *model.Click, queueSize),
+		workers:        positiveOr(cfg.GetClickIngestWorkers(), defaultClickIngestWorkers),
+		batchSize:      positiveOr(cfg.GetClickBatchSize(), defaultClickBatchSize),
+		flushInterval:  flushInterval,
+		enqueueTimeout: enqueueTimeout,
+	}
+}
+
+// Start starts the worker pool
+func (i *ClickIngestor) Start() {
+	for n := 0; n < i.workers; n++ {
+		i.wg.Add(1)
+		go i.run()
+	}
+	i.logger.Info("Click ingestor started",
+		logger.Int("workers", i.workers),
+		logger.Int("queue_size", cap(i.queue)),
+		logger.Int("batch_size", i.batchSize),
+		logger.Duration("flush_interval", i.flushInterval))
+}
+
+// Enqueue adds a click to the queue without blocking the caller for longer
+// than the enqueue timeout; the click is dropped if the queue stays full
+func (i *ClickIngestor) Enqueue(click *model.Click) error {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if i.stopped {
+		i.dropped.Add(1)
+		return ErrClickIngestorStopped
+	}
+
+	// Fast path: queue has room
+	select {
+	case i.queue <- click:
+		i.enqueued.Add(1)
+		return nil
+	default:
+	}
+
+	// Backpressure: wait briefly for workers to free space
+	if i.enqueueTimeout > 0 {
+		timer := time.NewTimer(i.enqueueTimeout)
+		defer timer.Stop()
+		select {
+		case i.queue <- click:
+			i.enqueued.Add(1)
+			return nil
+		case <-timer.C:
+		}
+	}
+
+	if dropped := i.dropped.Add(1); dropped == 1 || dropped%1000 == 0 {
+		i.logger.Warn("Click queue full, dropping clicks",
+			logger.Int("dropped_total", int(dropped)),
+			logger.Int("queue_size", cap(i.queue)))
+	}
+	return ErrClickQueueFull
+}
+
+// Stop stops accepting clicks and drains the queue, waiting for workers to
+// flush until ctx is done
+func (i *ClickIngestor) Stop(ctx context.Context) error {
+	i.mu.Lock()
+	if i.stopped {
+		i.mu.Unlock()
+		return nil
+	}
+	i.stopped = true
+	close(i.queue)
+	i.mu.Unlock()
+
+	i.logger.Info("Draining click ingestor...", logger.Int("queued", len(i.queue)))
+
+	done := make(chan struct{})
+	go func() {
+		i.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		stats := i.Stats()
+		i.logger.Info("Click ingestor stopped",
+			logger.Int("persisted", int(stats.Persisted)),
+			logger.Int("dropped", int(stats.Dropped)),
+			logger.Int("failed", int(stats.Failed)))
+		return nil
+	case <-ctx.Done():
+		i.logger.Error("Click ingestor drain timed out", logger.Int("remaining", len(i.queue)))
+		return ctx.Err()
+	}
+}
+
+// Stats returns a snapshot of the ingestor counters
+func (i *ClickIngestor) Stats() ClickIngestorStats {
+	return ClickIngestorStats{
+		Enqueued:   i.enqueued.Load(),
+		Dropped:    i.dropped.Load(),
+		Persisted:  i.persisted.Load(),
+		Failed:     i.failed.Load(),
+		Batches:    i.batches.Load(),
+		QueueDepth: len(i.queue),
+		QueueSize:  cap(i.queue),
+	}
+}
+
+// run collects clicks into a batch and flushes on size, interval, or queue close
+func (i *ClickIngestor) run() {
+	defer i.wg.Done()
+
+	batch := make([]*model.Click, 0, i.batchSize)
+	ticker := time.NewTicker(i.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case click, ok := <-i.queue:
+			if !ok {
+				i.flush(batch)
+				return
+			}
+			batch = append(batch, click)
+			if len(batch) >= i.batchSize {
+				i.flush(batch)
+				batch = make([]*model.Click, 0, i.batchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				i.flush(batch)
+				batch = make([]*model.Click, 0, i.batchSize)
+			}
+		}
+	}
+}
+
+// flush writes a batch with a single multi-row insert
+func (i *ClickIngestor) flush(batch []*model.Click) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clickFlushTimeout)
+	defer cancel()
+
+	i.batches.Add(1)
+	if err := i.writer.CreateBatch(ctx, batch); err != nil {
+		i.failed.Add(int64(len(batch)))
+		i.logger.Error("Failed to persist click batch", logger.Error(err), logger.Int("batch_size", len(batch)))
+		return
+	}
+	i.persisted.Add(int64(len(batch)))
+}
+
+func positiveOr(value, fallback int) int {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/config"
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// fakeClickWriter records batches and can block until released
+type fakeClickWriter struct {
+	mu      sync.Mutex
+	batches [][]*model.Click
+	release chan struct{} // when non-nil, CreateBatch waits on it
+}
+
+func (f *fakeClickWriter) CreateBatch(ctx context.Context, clicks []*model.Click) error {
+	if f.release != nil {
+		<-f.release
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, clicks)
+	return nil
+}
+
+func (f *fakeClickWriter) batchSizes() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sizes := make([]int, len(f.batches))
+	for i, b := range f.batches {
+		sizes[i] = len(b)
+	}
+	return sizes
+}
+
+func newTestIngestor(t *testing.T, writer ClickBatchWriter, env map[string]string) *ClickIngestor {
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	cfg, err := config.NewViperConfig(t.TempDir())
+	require.NoError(t, err)
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	return NewClickIngestor(writer, cfg, log)
+}
+
+func TestClickIngestor_FlushesOnBatchSize(t *testing.T) {
+	writer := &fakeClickWriter{}
+	ingestor := newTestIngestor(t, writer, map[string]string{
+		"WORKER_CLICK_INGESTOR_WORKERS":           "1",
+		"WORKER_CLICK_INGESTOR_BATCH_SIZE":        "3",
+		"WORKER_CLICK_INGESTOR_FLUSH_INTERVAL_MS": "60000",
+	})
+	ingestor.Start()
+
+	for n := 0; n < 7; n++ {
+		require.NoError(t, ingestor.Enqueue(&model.Click{}))
+	}
+
+	assert.Eventually(t, func() bool {
+		return len(writer.batchSizes()) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	// The remaining click is flushed by the drain
+	require.NoError(t, ingestor.Stop(context.Background()))
+	assert.Equal(t, []int{3, 3, 1}, writer.batchSizes())
+
+	stats := ingestor.Stats()
+	assert.Equal(t, int64(7), stats.Enqueued)
+	assert.Equal(t, int64(7), stats.Persisted)
+	assert.Equal(t, int64(3), stats.Batches)
+}
+
+func TestClickIngestor_FlushesOnInterval(t *testing.T) {
+	writer := &fakeClickWriter{}
+	ingestor := newTestIngestor(t, writer, map[string]string{
+		"WORKER_CLICK_INGESTOR_WORKERS":           "1",
+		"WORKER_CLICK_INGESTOR_BATCH_SIZE":        "100",
+		"WORKER_CLICK_INGESTOR_FLUSH_INTERVAL_MS": "20",
+	})
+	ingestor.Start()
+	defer func() { _ = ingestor.Stop(context.Background()) }()
+
+	require.NoError(t, ingestor.Enqueue(&model.Click{}))
+	require.NoError(t, ingestor.Enqueue(&model.Click{}))
+
+	assert.Eventually(t, func() bool {
+		sizes := writer.batchSizes()
+		return len(sizes) == 1 && sizes[0] == 2
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestClickIngestor_DropsWhenFull(t *testing.T) {
+	writer := &fakeClickWriter{release: make(chan struct{})}
+	ingestor := newTestIngestor(t, writer, map[string]string{
+		"WORKER_CLICK_INGESTOR_WORKERS":            "1",
+		"WORKER_CLICK_INGESTOR_QUEUE_SIZE":         "2",
+		"WORKER_CLICK_INGESTOR_BATCH_SIZE":         "1",
+		"WORKER_CLICK_INGESTOR_ENQUEUE_TIMEOUT_MS": "1",
+	})
+	ingestor.Start()
+
+	// The worker takes one click and blocks in CreateBatch; two more fill the queue
+	require.NoError(t, ingestor.Enqueue(&model.Click{}))
+	assert.Eventually(t, func() bool { return ingestor.Stats().QueueDepth == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, ingestor.Enqueue(&model.Click{}))
+	require.NoError(t, ingestor.Enqueue(&model.Click{}))
+
+	assert.ErrorIs(t, ingestor.Enqueue(&model.Click{}), ErrClickQueueFull)
+	assert.Equal(t, int64(1), ingestor.Stats().Dropped)
+
+	close(writer.release)
+	require.NoError(t, ingestor.Stop(context.Background()))
+	assert.Equal(t, int64(3), ingestor.Stats().Persisted)
+
+	assert.ErrorIs(t, ingestor.Enqueue(&model.Click{}), ErrClickIngestorStopped)
+	assert.Equal(t, int64(2), ingestor.Stats().Dropped)
+}
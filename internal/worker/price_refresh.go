@@ -3,6 +3,7 @@ package worker
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
@@ -12,33 +13,77 @@ import (
 	"github.com/jonosize/affiliate-platform/internal/logger"
 	"github.com/jonosize/affiliate-platform/internal/model"
 	"github.com/jonosize/affiliate-platform/internal/repository"
+	"github.com/jonosize/affiliate-platform/internal/service"
 	"github.com/jonosize/affiliate-platform/pkg/adapters"
-	"github.com/jonosize/affiliate-platform/pkg/adapters/mock"
 )
 
+// Price alert evaluation settings
+// Webhook delivery runs off the refresh loop, so slow or unreachable endpoints
+// don't hold up the cron run.
+const (
+	alertQueueSize         = 1000
+	alertWorkers           = 4
+	alertEvaluationTimeout = time.Minute // Bounds the webhooks (with retries) for one price change
+)
+
+// PriceAlertEvaluator evaluates price alert rules for a changed offer (implemented by service.AlertService)
+type PriceAlertEvaluator interface {
+	EvaluatePriceChange(ctx context.Context, offer *model.Offer, oldPrice float64) error
+}
+
+// priceChange is a refreshed offer waiting for alert evaluation
+type priceChange struct {
+	offer    model.Offer
+	oldPrice float64
+}
+
 // PriceRefreshWorker handles periodic price refresh
 type PriceRefreshWorker struct {
-	cron        *cron.Cron
-	db          *database.DB
-	cfg         config.Config
-	logger      logger.Logger
-	offerRepo   *repository.OfferRepository
-	productRepo *repository.ProductRepository
+	cron             *cron.Cron
+	db               *database.DB
+	cfg              config.Config
+	registry         *adapters.Registry
+	logger           logger.Logger
+	offerRepo        *repository.OfferRepository
+	productRepo      *repository.ProductRepository
+	priceHistoryRepo *repository.PriceHistoryRepository
+	alertEvaluator   PriceAlertEvaluator
+
+	alerts        chan priceChange
+	alertsMu      sync.RWMutex // guards alertsStopped and closing alerts against concurrent queueAlert
+	alertsStopped bool
+	alertsCtx     context.Context
+	cancelAlerts  context.CancelFunc
+	alertsWG      sync.WaitGroup
 }
 
 // NewPriceRefreshWorker creates a new price refresh worker
-func NewPriceRefreshWorker(db *database.DB, cfg config.Config, log logger.Logger) *PriceRefreshWorker {
+func NewPriceRefreshWorker(db *database.DB, cfg config.Config, registry *adapters.Registry, log logger.Logger) *PriceRefreshWorker {
 	// Create cron with seconds precision for local timezone
 	// Using WithSeconds() means cron expression needs 6 fields: second minute hour day month weekday
 	c := cron.New(cron.WithSeconds(), cron.WithLocation(time.Local))
+	alertsCtx, cancelAlerts := context.WithCancel(context.Background())
 
 	return &PriceRefreshWorker{
-		cron:        c,
-		db:          db,
-		cfg:         cfg,
-		logger:      log,
-		offerRepo:   repository.NewOfferRepository(db),
-		productRepo: repository.NewProductRepository(db),
+		cron:             c,
+		db:               db,
+		cfg:              cfg,
+		registry:         registry,
+		logger:           log,
+		offerRepo:        repository.NewOfferRepository(db),
+		productRepo:      repository.NewProductRepository(db),
+		priceHistoryRepo: repository.NewPriceHistoryRepository(db),
+		alertEvaluator: service.NewAlertService(
+			repository.NewAlertRuleRepository(db),
+			repository.NewAlertDeliveryRepository(db),
+			repository.NewProductRepository(db),
+			repository.NewCampaignRepository(db),
+			registry,
+			log,
+		),
+		alerts:       make(chan priceChange, alertQueueSize),
+		alertsCtx:    alertsCtx,
+		cancelAlerts: cancelAlerts,
 	}
 }
 
@@ -54,19 +99,76 @@ func (w *PriceRefreshWorker) Start() error {
 		return fmt.Errorf("failed to schedule price refresh job: %w", err)
 	}
 
+	w.startAlertWorkers()
 	w.cron.Start()
 	w.logger.Info("Price refresh worker started", logger.String("cron", cronExpr))
 	return nil
 }
 
 // Stop stops the cron scheduler gracefully
+// Price changes still waiting for alert evaluation are dropped and in-flight
+// webhook deliveries are cancelled.
 func (w *PriceRefreshWorker) Stop() {
 	ctx := w.cron.Stop()
 	w.logger.Info("Stopping price refresh worker...")
 	<-ctx.Done()
+
+	w.alertsMu.Lock()
+	w.alertsStopped = true
+	close(w.alerts)
+	w.alertsMu.Unlock()
+	w.cancelAlerts()
+	w.alertsWG.Wait()
+
 	w.logger.Info("Price refresh worker stopped")
 }
 
+// startAlertWorkers starts the goroutines that evaluate queued price changes
+func (w *PriceRefreshWorker) startAlertWorkers() {
+	for n := 0; n < alertWorkers; n++ {
+		w.alertsWG.Add(1)
+		go w.evaluateAlerts()
+	}
+}
+
+// evaluateAlerts evaluates alert rules for queued price changes until the queue is closed
+func (w *PriceRefreshWorker) evaluateAlerts() {
+	defer w.alertsWG.Done()
+	for change := range w.alerts {
+		if w.alertsCtx.Err() != nil {
+			continue // Stopping: drain without delivering
+		}
+		ctx, cancel := context.WithTimeout(w.alertsCtx, alertEvaluationTimeout)
+		if err := w.alertEvaluator.EvaluatePriceChange(ctx, &change.offer, change.oldPrice); err != nil {
+			w.logger.Error("Failed to evaluate price alerts", logger.Error(err),
+				logger.String("product_id", change.offer.ProductID.String()),
+				logger.String("marketplace", string(change.offer.Marketplace)))
+		}
+		cancel()
+	}
+}
+
+// queueAlert hands a price change to the alert workers without blocking the refresh
+// The change is dropped, with a warning, when the queue is full or the worker is stopped.
+func (w *PriceRefreshWorker) queueAlert(offer *model.Offer, oldPrice float64) {
+	if offer.Price == oldPrice {
+		return
+	}
+
+	w.alertsMu.RLock()
+	defer w.alertsMu.RUnlock()
+	if !w.alertsStopped {
+		select {
+		case w.alerts <- priceChange{offer: *offer, oldPrice: oldPrice}:
+			return
+		default:
+		}
+	}
+	w.logger.Warn("Dropping price alert evaluation: alert queue full or stopped",
+		logger.String("product_id", offer.ProductID.String()),
+		logger.String("marketplace", string(offer.Marketplace)))
+}
+
 // refreshPrices refreshes prices for all offers
 func (w *PriceRefreshWorker) refreshPrices() {
 	ctx := context.Background()
@@ -81,13 +183,6 @@ func (w *PriceRefreshWorker) refreshPrices() {
 
 	w.logger.Info("Refreshing prices", logger.Int("product_count", len(products)))
 
-	// Get adapters
-	lazadaAdapter, shopeeAdapter, err := mock.GetMockAdapters()
-	if err != nil {
-		w.logger.Error("Failed to get adapters", logger.Error(err))
-		return
-	}
-
 	refreshedCount := 0
 	errorCount := 0
 
@@ -102,12 +197,11 @@ func (w *PriceRefreshWorker) refreshPrices() {
 
 		for _, offer := range offers {
 			// Select adapter based on marketplace
-			var adapter adapters.MarketplaceAdapter
-			if offer.Marketplace == model.MarketplaceLazada {
-				adapter = lazadaAdapter
-			} else if offer.Marketplace == model.MarketplaceShopee {
-				adapter = shopeeAdapter
-			} else {
+			adapter, err := w.registry.Get(adapters.Marketplace(offer.Marketplace))
+			if err != nil {
+				w.logger.Warn("Skipping offer for unregistered marketplace",
+					logger.String("product_id", product.ID.String()),
+					logger.String("marketplace", string(offer.Marketplace)))
 				continue
 			}
 
@@ -122,6 +216,7 @@ func (w *PriceRefreshWorker) refreshPrices() {
 			}
 
 			// Update offer with new price
+			oldPrice := offer.Price
 			offer.Price = offerData.Price
 			offer.StoreName = offerData.StoreName
 			offer.LastCheckedAt = time.Now()
@@ -136,6 +231,16 @@ func (w *PriceRefreshWorker) refreshPrices() {
 				continue
 			}
 
+			// Append to price history (offers only keep the latest price)
+			if err := w.priceHistoryRepo.Create(ctx, model.NewOfferPriceHistory(offer)); err != nil {
+				w.logger.Error("Failed to record price history", logger.Error(err),
+					logger.String("product_id", product.ID.String()),
+					logger.String("marketplace", string(offer.Marketplace)))
+			}
+
+			// Evaluate price alert rules and deliver webhooks in the background
+			w.queueAlert(offer, oldPrice)
+
 			refreshedCount++
 		}
 	}
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/internal/logger"
+	"github.com/jonosize/affiliate-platform/internal/model"
+)
+
+// fakeAlertEvaluator records price changes and blocks until its context ends
+// or it is released
+type fakeAlertEvaluator struct {
+	mu        sync.Mutex
+	evaluated []float64
+	cancelled int
+	release   chan struct{}
+}
+
+func (f *fakeAlertEvaluator) EvaluatePriceChange(ctx context.Context, offer *model.Offer, oldPrice float64) error {
+	select {
+	case <-f.release:
+	case <-ctx.Done():
+		f.mu.Lock()
+		f.cancelled++
+		f.mu.Unlock()
+		return ctx.Err()
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.evaluated = append(f.evaluated, offer.Price)
+	return nil
+}
+
+func (f *fakeAlertEvaluator) counts() (evaluated, cancelled int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.evaluated), f.cancelled
+}
+
+func newTestPriceRefreshWorker(t *testing.T, evaluator PriceAlertEvaluator) *PriceRefreshWorker {
+	log, err := logger.NewZapLogger("info")
+	require.NoError(t, err)
+	w := NewPriceRefreshWorker(nil, nil, nil, log)
+	w.alertEvaluator = evaluator
+	return w
+}
+
+func TestPriceRefreshWorker_Alerts(t *testing.T) {
+	offer := func(price float64) *model.Offer {
+		return &model.Offer{ProductID: uuid.New(), Marketplace: model.MarketplaceLazada, Price: price}
+	}
+
+	t.Run("evaluated in the background", func(t *testing.T) {
+		evaluator := &fakeAlertEvaluator{release: make(chan struct{})}
+		close(evaluator.release)
+		w := newTestPriceRefreshWorker(t, evaluator)
+		w.startAlertWorkers()
+
+		w.queueAlert(offer(100), 100) // Unchanged prices are skipped
+		w.queueAlert(offer(90), 100)
+		require.Eventually(t, func() bool {
+			evaluated, _ := evaluator.counts()
+			return evaluated == 1
+		}, time.Second, time.Millisecond)
+		w.Stop()
+
+		evaluated, _ := evaluator.counts()
+		assert.Equal(t, 1, evaluated)
+	})
+
+	t.Run("slow webhooks don't block the refresh", func(t *testing.T) {
+		evaluator := &fakeAlertEvaluator{release: make(chan struct{})}
+		w := newTestPriceRefreshWorker(t, evaluator)
+		w.startAlertWorkers()
+
+		// Keep every worker busy, then fill the queue and overflow it
+		for i := 0; i < alertWorkers; i++ {
+			w.queueAlert(offer(90), 100)
+		}
+		require.Eventually(t, func() bool { return len(w.alerts) == 0 }, time.Second, time.Millisecond)
+		start := time.Now()
+		for i := 0; i < alertQueueSize+10; i++ {
+			w.queueAlert(offer(90), 100)
+		}
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Len(t, w.alerts, alertQueueSize)
+
+		// Stopping cancels in-flight evaluations and drops queued ones
+		w.Stop()
+		evaluated, cancelled := evaluator.counts()
+		assert.Equal(t, 0, evaluated)
+		assert.Equal(t, alertWorkers, cancelled)
+
+		w.queueAlert(offer(80), 100) // Dropped after stop, without panicking
+	})
+}
@@ -0,0 +1,2 @@
+ALTER TABLE links ADD CONSTRAINT links_marketplace_check CHECK (marketplace IN ('lazada', 'shopee'));
+ALTER TABLE offers ADD CONSTRAINT offers_marketplace_check CHECK (marketplace IN ('lazada', 'shopee'));
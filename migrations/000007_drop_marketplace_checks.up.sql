@@ -0,0 +1,4 @@
+-- Drop hard-coded marketplace CHECK constraints
+-- Supported marketplaces are defined by the adapter registry
+ALTER TABLE offers DROP CONSTRAINT IF EXISTS offers_marketplace_check;
+ALTER TABLE links DROP CONSTRAINT IF EXISTS links_marketplace_check;
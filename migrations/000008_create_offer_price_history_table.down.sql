@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_offer_price_history_product_recorded;
+DROP INDEX IF EXISTS idx_offer_price_history_offer_id;
+DROP TABLE IF EXISTS offer_price_history;
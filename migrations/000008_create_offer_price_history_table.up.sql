@@ -0,0 +1,14 @@
+-- Offer Price History (append-only, one row per price observation)
+CREATE TABLE offer_price_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    offer_id UUID NOT NULL REFERENCES offers(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    marketplace VARCHAR(20) NOT NULL,
+    store_name VARCHAR(200),
+    price DECIMAL(10, 2) NOT NULL CHECK (price >= 0),
+    recorded_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_offer_price_history_offer_id ON offer_price_history(offer_id);
+CREATE INDEX idx_offer_price_history_product_recorded ON offer_price_history(product_id, recorded_at);
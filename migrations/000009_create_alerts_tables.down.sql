@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_alert_deliveries_rule_created;
+DROP TABLE IF EXISTS alert_deliveries;
+DROP INDEX IF EXISTS idx_alert_rules_campaign_id;
+DROP INDEX IF EXISTS idx_alert_rules_product_id;
+DROP TABLE IF EXISTS alert_rules;
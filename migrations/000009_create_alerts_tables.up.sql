@@ -0,0 +1,41 @@
+-- Price Alert Rules (scoped to exactly one product or one campaign)
+CREATE TABLE alert_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(200) NOT NULL,
+    product_id UUID REFERENCES products(id) ON DELETE CASCADE,
+    campaign_id UUID REFERENCES campaigns(id) ON DELETE CASCADE,
+    marketplace VARCHAR(20),
+    direction VARCHAR(10) NOT NULL DEFAULT 'drop' CHECK (direction IN ('drop', 'rise', 'any')),
+    threshold_type VARCHAR(10) NOT NULL CHECK (threshold_type IN ('percent', 'absolute')),
+    threshold DECIMAL(10, 2) NOT NULL CHECK (threshold > 0),
+    webhook_url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CHECK ((product_id IS NULL) <> (campaign_id IS NULL))
+);
+
+CREATE INDEX idx_alert_rules_product_id ON alert_rules(product_id);
+CREATE INDEX idx_alert_rules_campaign_id ON alert_rules(campaign_id);
+
+-- Alert Webhook Deliveries (delivery log)
+CREATE TABLE alert_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    alert_rule_id UUID NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+    offer_id UUID NOT NULL REFERENCES offers(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    marketplace VARCHAR(20) NOT NULL,
+    old_price DECIMAL(10, 2) NOT NULL,
+    new_price DECIMAL(10, 2) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER,
+    last_error TEXT,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_alert_deliveries_rule_created ON alert_deliveries(alert_rule_id, created_at);
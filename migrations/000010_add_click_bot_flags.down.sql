@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_clicks_human_timestamp;
+ALTER TABLE clicks
+    DROP COLUMN IF EXISTS bot_reason,
+    DROP COLUMN IF EXISTS is_bot;
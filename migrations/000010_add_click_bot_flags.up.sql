@@ -0,0 +1,7 @@
+-- Bot classification of clicks (link previews, crawlers, prefetch, excluded IP ranges)
+ALTER TABLE clicks
+    ADD COLUMN is_bot BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN bot_reason VARCHAR(50);
+
+-- Analytics exclude bots by default
+CREATE INDEX idx_clicks_human_timestamp ON clicks(timestamp DESC) WHERE is_bot = FALSE;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_clicks_link_visitor;
+
+ALTER TABLE clicks
+    DROP COLUMN IF EXISTS is_unique,
+    DROP COLUMN IF EXISTS visitor_id;
@@ -0,0 +1,15 @@
+-- Visitor fingerprint (first-party cookie or salted IP + user agent hash) and
+-- whether the click is the visitor's first on the link within the dedupe window
+ALTER TABLE clicks
+    ADD COLUMN visitor_id VARCHAR(64),
+    ADD COLUMN is_unique BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Backfill existing clicks: the first click per (link, IP, user agent) counts as unique
+UPDATE clicks SET is_unique = TRUE
+WHERE id IN (
+    SELECT DISTINCT ON (link_id, ip_address, user_agent) id
+    FROM clicks
+    ORDER BY link_id, ip_address, user_agent, timestamp
+);
+
+CREATE INDEX idx_clicks_link_visitor ON clicks(link_id, visitor_id);
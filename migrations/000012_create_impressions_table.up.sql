@@ -0,0 +1,18 @@
+-- Impressions: one 'campaign' row per public campaign view and one 'link' row
+-- per product link rendered in it
+CREATE TABLE impressions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('campaign', 'link')),
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    product_id UUID REFERENCES products(id) ON DELETE CASCADE,
+    link_id UUID REFERENCES links(id) ON DELETE CASCADE,
+    marketplace VARCHAR(20),
+    timestamp TIMESTAMP NOT NULL DEFAULT NOW(),
+    is_bot BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CHECK ((kind = 'campaign') = (link_id IS NULL))
+);
+
+CREATE INDEX idx_impressions_campaign_timestamp ON impressions(campaign_id, timestamp);
+CREATE INDEX idx_impressions_product_timestamp ON impressions(product_id, timestamp);
+CREATE INDEX idx_impressions_timestamp ON impressions(timestamp);
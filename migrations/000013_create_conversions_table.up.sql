@@ -0,0 +1,24 @@
+-- Conversions reported by affiliate network postbacks
+CREATE TABLE conversions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    network VARCHAR(50) NOT NULL,
+    order_id VARCHAR(100) NOT NULL,
+    click_ref VARCHAR(100),
+    click_id UUID REFERENCES clicks(id) ON DELETE SET NULL,
+    link_id UUID REFERENCES links(id) ON DELETE SET NULL,
+    campaign_id UUID REFERENCES campaigns(id) ON DELETE SET NULL,
+    product_id UUID REFERENCES products(id) ON DELETE SET NULL,
+    marketplace VARCHAR(20),
+    amount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    commission DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    currency VARCHAR(3),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    status_updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (network, order_id)
+);
+
+CREATE INDEX idx_conversions_campaign_created ON conversions(campaign_id, created_at);
+CREATE INDEX idx_conversions_created_at ON conversions(created_at);
+CREATE INDEX idx_conversions_click_id ON conversions(click_id);
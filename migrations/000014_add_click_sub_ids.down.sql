@@ -0,0 +1,6 @@
+ALTER TABLE clicks
+    DROP COLUMN IF EXISTS sub5,
+    DROP COLUMN IF EXISTS sub4,
+    DROP COLUMN IF EXISTS sub3,
+    DROP COLUMN IF EXISTS sub2,
+    DROP COLUMN IF EXISTS sub1;
@@ -0,0 +1,8 @@
+-- Publisher sub IDs (?sub1=..&sub5=.. on /go/:short_code), forwarded to the marketplace
+-- with the click ID (clicks.id) as affiliate sub-ID parameters
+ALTER TABLE clicks
+    ADD COLUMN sub1 VARCHAR(50),
+    ADD COLUMN sub2 VARCHAR(50),
+    ADD COLUMN sub3 VARCHAR(50),
+    ADD COLUMN sub4 VARCHAR(50),
+    ADD COLUMN sub5 VARCHAR(50);
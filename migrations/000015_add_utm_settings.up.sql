@@ -0,0 +1,14 @@
+-- Campaign-level UTM settings (values may use {{product_id}}, {{campaign_id}},
+-- {{marketplace}} and {{short_code}} placeholders; empty values are omitted)
+ALTER TABLE campaigns
+    ADD COLUMN utm_source VARCHAR(100) NOT NULL DEFAULT 'affiliate',
+    ADD COLUMN utm_medium VARCHAR(100) NOT NULL DEFAULT 'affiliate',
+    ADD COLUMN utm_content VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN utm_term VARCHAR(100) NOT NULL DEFAULT '';
+
+-- Per-link overrides (NULL inherits the campaign value)
+ALTER TABLE links
+    ADD COLUMN utm_source VARCHAR(100),
+    ADD COLUMN utm_medium VARCHAR(100),
+    ADD COLUMN utm_content VARCHAR(100),
+    ADD COLUMN utm_term VARCHAR(100);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS link_aliases;
+
+-- Fails if a vanity short code longer than 20 characters exists
+ALTER TABLE links ALTER COLUMN short_code TYPE VARCHAR(20);
@@ -0,0 +1,14 @@
+-- Vanity short codes are longer than generated ones (8-12 chars)
+ALTER TABLE links ALTER COLUMN short_code TYPE VARCHAR(32);
+
+-- Additional short codes that resolve to a link (including a link's previous codes)
+-- Short codes are unique across links.short_code and link_aliases.short_code,
+-- which the application enforces before writing
+CREATE TABLE link_aliases (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    link_id UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    short_code VARCHAR(32) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_link_aliases_link_id ON link_aliases(link_id);
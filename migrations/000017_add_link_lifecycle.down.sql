@@ -0,0 +1,12 @@
+ALTER TABLE clicks
+    DROP COLUMN IF EXISTS outcome_reason,
+    DROP COLUMN IF EXISTS outcome;
+
+ALTER TABLE campaigns
+    DROP CONSTRAINT IF EXISTS chk_campaigns_fallback_url,
+    DROP COLUMN IF EXISTS fallback_url,
+    DROP COLUMN IF EXISTS out_of_window_policy;
+
+ALTER TABLE links
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS status;
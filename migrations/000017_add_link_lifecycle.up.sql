@@ -0,0 +1,15 @@
+-- Link lifecycle: paused and archived links (and links past expires_at) stop redirecting
+ALTER TABLE links
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'archived')),
+    ADD COLUMN expires_at TIMESTAMP;
+
+-- What redirects do outside the campaign's start_at..end_at window
+ALTER TABLE campaigns
+    ADD COLUMN out_of_window_policy VARCHAR(20) NOT NULL DEFAULT 'redirect' CHECK (out_of_window_policy IN ('redirect', 'fallback', 'gone')),
+    ADD COLUMN fallback_url TEXT NOT NULL DEFAULT '',
+    ADD CONSTRAINT chk_campaigns_fallback_url CHECK (out_of_window_policy <> 'fallback' OR fallback_url <> '');
+
+-- What each redirect did (redirected, fallback or gone) and why, when not a normal redirect
+ALTER TABLE clicks
+    ADD COLUMN outcome VARCHAR(20) NOT NULL DEFAULT 'redirected',
+    ADD COLUMN outcome_reason VARCHAR(30);
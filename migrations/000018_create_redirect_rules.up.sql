@@ -0,0 +1,21 @@
+-- Per-link redirect rules, evaluated by priority (lowest first) on /go/:short_code
+-- Empty match lists match any visitor; the first matching active rule wins and
+-- links without a matching rule redirect to their own target URL
+CREATE TABLE redirect_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    link_id UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL DEFAULT '',
+    priority INTEGER NOT NULL DEFAULT 0,
+    os JSONB NOT NULL DEFAULT '[]',        -- e.g. ["ios", "android"]
+    countries JSONB NOT NULL DEFAULT '[]', -- ISO 3166-1 alpha-2, e.g. ["TH"]
+    languages JSONB NOT NULL DEFAULT '[]', -- primary language subtags, e.g. ["th"]
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('web', 'deep_link', 'marketplace')),
+    target_url TEXT NOT NULL,
+    fallback_url TEXT NOT NULL DEFAULT '', -- web fallback for deep links
+    marketplace VARCHAR(20),               -- alternate marketplace for marketplace targets
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_redirect_rules_link_id ON redirect_rules(link_id, priority);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_clicks_link_variant;
+
+ALTER TABLE clicks
+    DROP COLUMN IF EXISTS variant_id;
+
+DROP TABLE IF EXISTS link_variants;
@@ -0,0 +1,21 @@
+-- A/B split variants: a link with variants sends each visitor to one of them,
+-- picked by weight and kept stable per visitor
+CREATE TABLE link_variants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    link_id UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    weight INTEGER NOT NULL CHECK (weight >= 0 AND weight <= 10000), -- 0 stops sending traffic
+    marketplace VARCHAR(20), -- marketplace offer variants; NULL for landing URLs
+    target_url TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (link_id, name)
+);
+
+CREATE INDEX idx_link_variants_link_id ON link_variants(link_id);
+
+-- The variant each click was sent to (NULL when the link was not split)
+ALTER TABLE clicks
+    ADD COLUMN variant_id UUID REFERENCES link_variants(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_clicks_link_variant ON clicks(link_id, variant_id) WHERE variant_id IS NOT NULL;
@@ -0,0 +1,7 @@
+DELETE FROM links WHERE marketplace = 'best_price';
+
+ALTER TABLE clicks
+    DROP COLUMN IF EXISTS marketplace;
+
+ALTER TABLE campaigns
+    DROP COLUMN IF EXISTS best_price_link;
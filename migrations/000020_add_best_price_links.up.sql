@@ -0,0 +1,8 @@
+-- Campaigns may sync a best-price link per product (links.marketplace = 'best_price'),
+-- which redirects to the product's cheapest offer at click time
+ALTER TABLE campaigns
+    ADD COLUMN best_price_link BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- The marketplace a best-price link sent the click to (NULL: the link's own marketplace)
+ALTER TABLE clicks
+    ADD COLUMN marketplace VARCHAR(20);
@@ -0,0 +1,2 @@
+ALTER TABLE clicks
+    DROP COLUMN IF EXISTS source;
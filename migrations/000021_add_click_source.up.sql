@@ -0,0 +1,4 @@
+-- Where the visitor came from, from the ?src= marker on /go/:short_code
+-- (e.g. qr for scans of the link's QR code; NULL or empty: not marked)
+ALTER TABLE clicks
+    ADD COLUMN source VARCHAR(20);
@@ -0,0 +1,13 @@
+-- Admin API keys, sent as "Authorization: Bearer <key>"
+-- Only the SHA-256 hash of a key is stored; the key itself is shown once, on create
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    key_prefix VARCHAR(16) NOT NULL, -- first characters of the key, to tell keys apart
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes JSONB NOT NULL, -- read, write and/or worker
+    expires_at TIMESTAMP, -- NULL: never expires
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,26 @@
+-- Admin UI roles; each grants a set of admin API scopes (read, write, worker)
+CREATE TABLE roles (
+    name VARCHAR(30) PRIMARY KEY,
+    description TEXT NOT NULL,
+    scopes JSONB NOT NULL
+);
+
+INSERT INTO roles (name, description, scopes) VALUES
+    ('admin', 'Full access, including users, API keys and worker triggers', '["read", "write", "worker"]'),
+    ('campaign_manager', 'Manages products, campaigns, links and price alerts', '["read", "write"]'),
+    ('analyst', 'Read-only access to the catalog, stats and dashboard', '["read"]');
+
+-- Admin UI users, who log in with a password for a signed session token
+CREATE TABLE users (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL UNIQUE, -- stored lower-cased
+    name VARCHAR(100) NOT NULL,
+    password_hash VARCHAR(100) NOT NULL, -- bcrypt
+    role_name VARCHAR(30) NOT NULL REFERENCES roles(name),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_login_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_users_role_name ON users(role_name);
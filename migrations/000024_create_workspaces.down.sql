@@ -0,0 +1,8 @@
+ALTER TABLE api_keys DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE users DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE clicks DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE links DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE products DROP COLUMN IF EXISTS workspace_id;
+
+DROP TABLE IF EXISTS workspaces;
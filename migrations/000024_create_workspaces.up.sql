@@ -0,0 +1,37 @@
+-- Publisher workspaces: each affiliate brand on the deployment gets its own
+-- products, campaigns, links and clicks, users and API keys
+CREATE TABLE workspaces (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(50) NOT NULL UNIQUE,
+    redirect_domain VARCHAR(255) NOT NULL DEFAULT '', -- e.g. go.brand.com; empty: api.base_url
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_workspaces_redirect_domain ON workspaces(redirect_domain) WHERE redirect_domain <> '';
+
+-- Existing data moves to the default workspace
+INSERT INTO workspaces (id, name, slug) VALUES ('00000000-0000-0000-0000-000000000001', 'Default', 'default');
+
+ALTER TABLE products ADD COLUMN workspace_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES workspaces(id);
+ALTER TABLE campaigns ADD COLUMN workspace_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES workspaces(id);
+ALTER TABLE links ADD COLUMN workspace_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES workspaces(id);
+ALTER TABLE clicks ADD COLUMN workspace_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES workspaces(id);
+ALTER TABLE users ADD COLUMN workspace_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES workspaces(id);
+ALTER TABLE api_keys ADD COLUMN workspace_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES workspaces(id);
+
+-- New rows must name their workspace
+ALTER TABLE products ALTER COLUMN workspace_id DROP DEFAULT;
+ALTER TABLE campaigns ALTER COLUMN workspace_id DROP DEFAULT;
+ALTER TABLE links ALTER COLUMN workspace_id DROP DEFAULT;
+ALTER TABLE clicks ALTER COLUMN workspace_id DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN workspace_id DROP DEFAULT;
+ALTER TABLE api_keys ALTER COLUMN workspace_id DROP DEFAULT;
+
+CREATE INDEX idx_products_workspace_created ON products(workspace_id, created_at DESC);
+CREATE INDEX idx_campaigns_workspace_created ON campaigns(workspace_id, created_at DESC);
+CREATE INDEX idx_links_workspace_id ON links(workspace_id);
+CREATE INDEX idx_clicks_workspace_timestamp ON clicks(workspace_id, timestamp DESC);
+CREATE INDEX idx_users_workspace_id ON users(workspace_id);
+CREATE INDEX idx_api_keys_workspace_id ON api_keys(workspace_id);
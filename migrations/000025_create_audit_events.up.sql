@@ -0,0 +1,19 @@
+-- Audit log of admin mutations: who created, updated or deleted which entity,
+-- with a before/after diff. Events outlive their entity, so entity_id has no
+-- foreign key.
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID REFERENCES workspaces(id), -- NULL for changes made outside a workspace
+    actor VARCHAR(100) NOT NULL, -- principal subject, e.g. user:<id>, api_key:<id>, basic:<username>, or system
+    actor_name VARCHAR(255) NOT NULL DEFAULT '',
+    action VARCHAR(20) NOT NULL, -- create, update, delete or trigger
+    entity_type VARCHAR(30) NOT NULL, -- product, campaign, link or price_refresh
+    entity_id UUID,
+    before JSONB, -- changed fields before an update; the entity before a delete
+    after JSONB, -- changed fields after an update; the entity after a create
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_workspace_created ON audit_events(workspace_id, created_at DESC);
+CREATE INDEX idx_audit_events_entity ON audit_events(entity_type, entity_id);
+CREATE INDEX idx_audit_events_actor ON audit_events(actor);
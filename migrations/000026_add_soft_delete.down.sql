@@ -0,0 +1,17 @@
+-- Without deleted_at, soft-deleted rows would reappear; remove them as deletes used to
+DELETE FROM links WHERE deleted_at IS NOT NULL;
+DELETE FROM campaigns WHERE deleted_at IS NOT NULL;
+DELETE FROM products WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_links_deleted_at;
+DROP INDEX IF EXISTS idx_campaigns_deleted_at;
+DROP INDEX IF EXISTS idx_products_deleted_at;
+
+ALTER TABLE links
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE campaigns
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,15 @@
+-- Deleting a product, campaign or link sets deleted_at instead of removing the row,
+-- so their clicks and conversions keep counting in the dashboard. Links of a deleted
+-- product or campaign are hidden with it and come back when it is restored.
+ALTER TABLE products
+    ADD COLUMN deleted_at TIMESTAMP;
+
+ALTER TABLE campaigns
+    ADD COLUMN deleted_at TIMESTAMP;
+
+ALTER TABLE links
+    ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_products_deleted_at ON products(deleted_at);
+CREATE INDEX idx_campaigns_deleted_at ON campaigns(deleted_at);
+CREATE INDEX idx_links_deleted_at ON links(deleted_at);
@@ -16,6 +16,12 @@ type MarketplaceAdapter interface {
 	Marketplace() Marketplace
 }
 
+// SourceIDOfferFetcher is implemented by adapters that can look up an offer by the
+// source_id returned in ProductData (e.g. the mock adapter's fixture catalog)
+type SourceIDOfferFetcher interface {
+	FetchOfferBySourceID(ctx context.Context, sourceID int, marketplace Marketplace) (*OfferData, error)
+}
+
 type SourceType string
 
 const (
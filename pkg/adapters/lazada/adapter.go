@@ -17,6 +17,31 @@ import (
 	"github.com/jonosize/affiliate-platform/pkg/adapters"
 )
 
+// Domains are the Lazada domains product URLs and redirects are allowed on
+var Domains = []string{
+	"lazada.co.th",
+	"www.lazada.co.th",
+}
+
+// maxSubIDs is the number of publisher sub-ID slots Lazada tracking links accept (sub_id1..sub_id5)
+const maxSubIDs = 5
+
+// EncodeSubIDs sets Lazada affiliate sub-ID parameters: the click ID as sub_aff_id
+// and publisher sub IDs as sub_id1..sub_id5 (extra values are dropped)
+func EncodeSubIDs(q url.Values, clickID string, subIDs []string) {
+	if clickID != "" {
+		q.Set("sub_aff_id", clickID)
+	}
+	for i, subID := range subIDs {
+		if i >= maxSubIDs {
+			break
+		}
+		if subID != "" {
+			q.Set("sub_id"+strconv.Itoa(i+1), subID)
+		}
+	}
+}
+
 // LazadaAdapter implements MarketplaceAdapter using Lazada Open Platform API
 type LazadaAdapter struct {
 	appKey      string
@@ -1,20 +1,33 @@
 package mock
 
 import (
+	"fmt"
+
 	"github.com/jonosize/affiliate-platform/pkg/adapters"
+	"github.com/jonosize/affiliate-platform/pkg/adapters/lazada"
+	"github.com/jonosize/affiliate-platform/pkg/adapters/shopee"
 )
 
-// GetMockAdapters returns both Lazada and Shopee mock adapters
-func GetMockAdapters() (adapters.MarketplaceAdapter, adapters.MarketplaceAdapter, error) {
+// NewRegistry returns a registry with Lazada and Shopee mock adapters
+// Mock adapters share the real adapters' domains, URL matchers and sub-ID encoders
+func NewRegistry() (*adapters.Registry, error) {
 	lazadaAdapter, err := NewAdapterForMarketplace(adapters.MarketplaceLazada)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	shopeeAdapter, err := NewAdapterForMarketplace(adapters.MarketplaceShopee)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	registry := adapters.NewRegistry()
+	if err := registry.Register(lazadaAdapter, lazada.Domains, nil, lazada.EncodeSubIDs); err != nil {
+		return nil, fmt.Errorf("failed to register lazada mock adapter: %w", err)
+	}
+	if err := registry.Register(shopeeAdapter, shopee.Domains, shopee.MatchURL, shopee.EncodeSubIDs); err != nil {
+		return nil, fmt.Errorf("failed to register shopee mock adapter: %w", err)
 	}
 
-	return lazadaAdapter, shopeeAdapter, nil
+	return registry, nil
 }
@@ -0,0 +1,203 @@
+package adapters
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// URLMatcher reports whether a product URL belongs to a marketplace
+type URLMatcher func(u *url.URL) bool
+
+// SubIDEncoder sets a marketplace's affiliate sub-ID parameters on a target URL query
+// clickID identifies the click; subIDs are positional publisher values (sub1, sub2, ...)
+type SubIDEncoder func(q url.Values, clickID string, subIDs []string)
+
+// Registration binds a marketplace adapter to the domains it is allowed to redirect to,
+// the matcher used to recognize its product URLs and the encoder for its sub-ID parameters
+type Registration struct {
+	Adapter MarketplaceAdapter
+	Domains []string
+	Matcher URLMatcher
+	SubIDs  SubIDEncoder
+}
+
+// Registry maps marketplaces to their adapters, allowed domains and URL matchers
+// Adding a marketplace only requires registering its adapter here
+type Registry struct {
+	mu            sync.RWMutex
+	registrations map[Marketplace]*Registration
+	order         []Marketplace // Registration order, used for deterministic iteration
+}
+
+// NewRegistry creates an empty adapter registry
+func NewRegistry() *Registry {
+	return &Registry{
+		registrations: make(map[Marketplace]*Registration),
+	}
+}
+
+// Register registers an adapter for its marketplace
+// If matcher is nil, product URLs are matched against the given domains;
+// if subIDs is nil, redirects to the marketplace carry no sub-ID parameters
+func (r *Registry) Register(adapter MarketplaceAdapter, domains []string, matcher URLMatcher, subIDs SubIDEncoder) error {
+	if adapter == nil {
+		return fmt.Errorf("adapter is required")
+	}
+
+	marketplace := adapter.Marketplace()
+	if marketplace == "" {
+		return fmt.Errorf("adapter has no marketplace identifier")
+	}
+
+	normalized := make([]string, 0, len(domains))
+	for _, d := range domains {
+		if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+			normalized = append(normalized, d)
+		}
+	}
+
+	if matcher == nil {
+		matcher = DomainMatcher(normalized...)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.registrations[marketplace]; exists {
+		return fmt.Errorf("marketplace already registered: %s", marketplace)
+	}
+
+	r.registrations[marketplace] = &Registration{
+		Adapter: adapter,
+		Domains: normalized,
+		Matcher: matcher,
+		SubIDs:  subIDs,
+	}
+	r.order = append(r.order, marketplace)
+
+	return nil
+}
+
+// MustRegister registers an adapter and panics on error (for static wiring)
+func (r *Registry) MustRegister(adapter MarketplaceAdapter, domains []string, matcher URLMatcher, subIDs SubIDEncoder) {
+	if err := r.Register(adapter, domains, matcher, subIDs); err != nil {
+		panic(err)
+	}
+}
+
+// Get returns the adapter registered for a marketplace
+func (r *Registry) Get(marketplace Marketplace) (MarketplaceAdapter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reg, ok := r.registrations[marketplace]
+	if !ok {
+		return nil, fmt.Errorf("unsupported marketplace: %s", marketplace)
+	}
+	return reg.Adapter, nil
+}
+
+// Has reports whether a marketplace is registered
+func (r *Registry) Has(marketplace Marketplace) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.registrations[marketplace]
+	return ok
+}
+
+// Marketplaces returns all registered marketplaces in registration order
+func (r *Registry) Marketplaces() []Marketplace {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	marketplaces := make([]Marketplace, len(r.order))
+	copy(marketplaces, r.order)
+	return marketplaces
+}
+
+// AllowedDomains returns the allowed redirect domains across all marketplaces
+func (r *Registry) AllowedDomains() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	domains := make([]string, 0)
+	for _, m := range r.order {
+		domains = append(domains, r.registrations[m].Domains...)
+	}
+	return domains
+}
+
+// IsAllowedHost reports whether a hostname is an allowed redirect domain of any marketplace
+func (r *Registry) IsAllowedHost(hostname string) bool {
+	hostname = strings.ToLower(hostname)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, m := range r.order {
+		for _, domain := range r.registrations[m].Domains {
+			if hostname == domain {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MatchURL returns the marketplace whose matcher recognizes the URL
+func (r *Registry) MatchURL(rawURL string) (Marketplace, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL format: %w", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, m := range r.order {
+		if r.registrations[m].Matcher(u) {
+			return m, nil
+		}
+	}
+
+	return "", fmt.Errorf("URL does not belong to a supported marketplace: %s", rawURL)
+}
+
+// AppendSubIDs returns targetURL with the marketplace's sub-ID parameters set
+// The URL is returned unchanged if the marketplace has no sub-ID encoder
+func (r *Registry) AppendSubIDs(marketplace Marketplace, targetURL, clickID string, subIDs []string) (string, error) {
+	r.mu.RLock()
+	reg, ok := r.registrations[marketplace]
+	r.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("unsupported marketplace: %s", marketplace)
+	}
+	if reg.SubIDs == nil {
+		return targetURL, nil
+	}
+
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL format: %w", err)
+	}
+	q := u.Query()
+	reg.SubIDs(q, clickID, subIDs)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// DomainMatcher returns a URLMatcher that matches URLs whose hostname is one of the domains
+func DomainMatcher(domains ...string) URLMatcher {
+	allowed := make(map[string]bool, len(domains))
+	for _, d := range domains {
+		allowed[strings.ToLower(d)] = true
+	}
+	return func(u *url.URL) bool {
+		return allowed[strings.ToLower(u.Hostname())]
+	}
+}
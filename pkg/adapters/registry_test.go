@@ -0,0 +1,87 @@
+package adapters
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAdapter struct {
+	marketplace Marketplace
+}
+
+func (a *stubAdapter) FetchProduct(ctx context.Context, source string, sourceType SourceType) (*ProductData, error) {
+	return &ProductData{}, nil
+}
+
+func (a *stubAdapter) FetchOffer(ctx context.Context, productURL string) (*OfferData, error) {
+	return &OfferData{}, nil
+}
+
+func (a *stubAdapter) Marketplace() Marketplace {
+	return a.marketplace
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(&stubAdapter{marketplace: MarketplaceLazada}, []string{"lazada.co.th", "WWW.Lazada.co.th"}, nil, nil))
+	require.NoError(t, registry.Register(&stubAdapter{marketplace: "tiktok"}, []string{"shop.tiktok.com"}, nil, nil))
+
+	t.Run("duplicate registration", func(t *testing.T) {
+		err := registry.Register(&stubAdapter{marketplace: MarketplaceLazada}, nil, nil, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("lookup", func(t *testing.T) {
+		adapter, err := registry.Get("tiktok")
+		require.NoError(t, err)
+		assert.Equal(t, Marketplace("tiktok"), adapter.Marketplace())
+
+		_, err = registry.Get(MarketplaceShopee)
+		assert.Error(t, err)
+		assert.False(t, registry.Has(MarketplaceShopee))
+		assert.Equal(t, []Marketplace{MarketplaceLazada, "tiktok"}, registry.Marketplaces())
+	})
+
+	t.Run("allowed hosts", func(t *testing.T) {
+		assert.True(t, registry.IsAllowedHost("www.lazada.co.th"))
+		assert.True(t, registry.IsAllowedHost("shop.tiktok.com"))
+		assert.False(t, registry.IsAllowedHost("evil.com"))
+		assert.False(t, registry.IsAllowedHost("lazada.co.th.evil.com"))
+	})
+
+	t.Run("match URL", func(t *testing.T) {
+		marketplace, err := registry.MatchURL("https://www.lazada.co.th/products/example-i123456.html")
+		require.NoError(t, err)
+		assert.Equal(t, MarketplaceLazada, marketplace)
+
+		_, err = registry.MatchURL("https://example.com/products/1")
+		assert.Error(t, err)
+	})
+}
+
+func TestRegistry_AppendSubIDs(t *testing.T) {
+	registry := NewRegistry()
+	encoder := func(q url.Values, clickID string, subIDs []string) {
+		q.Set("click", clickID)
+		if len(subIDs) > 0 {
+			q.Set("s1", subIDs[0])
+		}
+	}
+	require.NoError(t, registry.Register(&stubAdapter{marketplace: MarketplaceLazada}, []string{"lazada.co.th"}, nil, encoder))
+	require.NoError(t, registry.Register(&stubAdapter{marketplace: "tiktok"}, []string{"shop.tiktok.com"}, nil, nil))
+
+	target, err := registry.AppendSubIDs(MarketplaceLazada, "https://lazada.co.th/p?utm_source=x", "c1", []string{"fb"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://lazada.co.th/p?click=c1&s1=fb&utm_source=x", target)
+
+	target, err = registry.AppendSubIDs("tiktok", "https://shop.tiktok.com/p?utm_source=x", "c1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://shop.tiktok.com/p?utm_source=x", target, "no encoder leaves the URL unchanged")
+
+	_, err = registry.AppendSubIDs(MarketplaceShopee, "https://shopee.co.th/p", "c1", nil)
+	assert.Error(t, err)
+}
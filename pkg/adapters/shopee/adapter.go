@@ -2,13 +2,49 @@ package shopee
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jonosize/affiliate-platform/pkg/adapters"
 )
 
+const (
+	pathGetItemBaseInfo = "/product/get_item_base_info"
+	pathGetModelList    = "/product/get_model_list"
+)
+
+var (
+	// Domains are the Shopee domains product URLs and redirects are allowed on
+	Domains = []string{
+		"shopee.co.th",
+		"www.shopee.co.th",
+	}
+
+	// ShortLinkHosts are Shopee's short link domains, resolved by following the redirect
+	ShortLinkHosts = []string{
+		"shp.ee",
+		"shope.ee",
+		"s.shopee.co.th",
+	}
+
+	// Matches "-i.<shop_id>.<item_id>" in SEO-friendly product URLs
+	// Example: /Matcha-Green-Tea-i.123456.789012
+	seoItemPattern = regexp.MustCompile(`-i\.(\d+)\.(\d+)`)
+
+	// Matches "/product/<shop_id>/<item_id>"
+	// Example: /product/123456/789012
+	productPathPattern = regexp.MustCompile(`^/product/(\d+)/(\d+)`)
+)
+
 // ShopeeAdapter implements MarketplaceAdapter using Shopee Open Platform API
 type ShopeeAdapter struct {
 	partnerID   string
@@ -33,7 +69,7 @@ func NewAdapter(partnerID, partnerKey, shopID, accessToken string) *ShopeeAdapte
 
 // SetAPIURL allows setting custom API URL (for different regions)
 func (a *ShopeeAdapter) SetAPIURL(apiURL string) {
-	a.apiURL = apiURL
+	a.apiURL = strings.TrimSuffix(apiURL, "/")
 }
 
 // Marketplace returns the marketplace identifier
@@ -43,20 +79,410 @@ func (a *ShopeeAdapter) Marketplace() adapters.Marketplace {
 
 // FetchProduct fetches product details from URL or SKU
 func (a *ShopeeAdapter) FetchProduct(ctx context.Context, source string, sourceType adapters.SourceType) (*adapters.ProductData, error) {
-	// TODO: Implement Shopee product fetching
-	// 1. Extract item ID from URL or use SKU directly
-	// 2. Call Shopee Open Platform API: /product/get_item_base_info
-	// 3. Parse response and return ProductData
-	// Reference: https://open.shopee.com/documents?module=2&type=1&id=365
-	return nil, fmt.Errorf("not implemented: Shopee FetchProduct")
+	var ref *itemRef
+	var err error
+
+	// Extract shop/item ID from URL or use SKU directly
+	if sourceType == adapters.SourceTypeURL {
+		ref, err = a.resolveItemRef(ctx, source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract item ID from URL: %w", err)
+		}
+	} else {
+		ref, err = parseSKU(source)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	item, err := a.getItemBaseInfo(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product from Shopee API: %w", err)
+	}
+
+	// Extract first image if available
+	imageURL := ""
+	if len(item.Image.ImageURLList) > 0 {
+		imageURL = item.Image.ImageURLList[0]
+	}
+
+	return &adapters.ProductData{
+		Title:                 item.ItemName,
+		ImageURL:              imageURL,
+		MarketplaceProductURL: ref.canonicalURL(),
+	}, nil
 }
 
 // FetchOffer fetches current offer/price
 func (a *ShopeeAdapter) FetchOffer(ctx context.Context, productURL string) (*adapters.OfferData, error) {
-	// TODO: Implement Shopee offer fetching
-	// 1. Extract item ID from URL
-	// 2. Call Shopee Open Platform API: /product/get_item_base_info (includes price)
-	// 3. Parse response and return OfferData
-	// Reference: https://open.shopee.com/documents?module=2&type=1&id=365
-	return nil, fmt.Errorf("not implemented: Shopee FetchOffer")
+	ref, err := a.resolveItemRef(ctx, productURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract item ID from URL: %w", err)
+	}
+
+	item, err := a.getItemBaseInfo(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch offer from Shopee API: %w", err)
+	}
+
+	price, ok := item.currentPrice()
+
+	// Items with variations carry their prices on models, use the cheapest one
+	if item.HasModel {
+		models, err := a.getModelList(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch models from Shopee API: %w", err)
+		}
+		if modelPrice, found := lowestModelPrice(models); found {
+			price, ok = modelPrice, true
+		}
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("no price available for item %d", ref.itemID)
+	}
+
+	return &adapters.OfferData{
+		StoreName:             fmt.Sprintf("Shopee Shop %d", ref.shopID),
+		Price:                 price,
+		MarketplaceProductURL: ref.canonicalURL(),
+	}, nil
+}
+
+// itemRef identifies a Shopee item by shop and item ID
+type itemRef struct {
+	shopID int64
+	itemID int64
+	host   string
+}
+
+// canonicalURL builds the product/<shop>/<item> form of the item URL
+func (r *itemRef) canonicalURL() string {
+	host := r.host
+	if host == "" {
+		host = "shopee.co.th"
+	}
+	return fmt.Sprintf("https://%s/product/%d/%d", host, r.shopID, r.itemID)
+}
+
+// ShopeeResponse is the envelope shared by all Shopee Open Platform v2 responses
+type ShopeeResponse struct {
+	Error     string          `json:"error"`
+	Message   string          `json:"message"`
+	RequestID string          `json:"request_id"`
+	Response  json.RawMessage `json:"response"`
+}
+
+// ShopeePriceInfo represents a price entry on an item or model
+type ShopeePriceInfo struct {
+	Currency      string  `json:"currency"`
+	OriginalPrice float64 `json:"original_price"`
+	CurrentPrice  float64 `json:"current_price"`
+}
+
+// ShopeeItem represents an item returned by get_item_base_info
+type ShopeeItem struct {
+	ItemID    int64             `json:"item_id"`
+	ItemName  string            `json:"item_name"`
+	ItemSKU   string            `json:"item_sku"`
+	HasModel  bool              `json:"has_model"`
+	PriceInfo []ShopeePriceInfo `json:"price_info"`
+	Image     struct {
+		ImageURLList []string `json:"image_url_list"`
+	} `json:"image"`
+}
+
+// currentPrice returns the item-level current price, if any
+func (i *ShopeeItem) currentPrice() (float64, bool) {
+	if len(i.PriceInfo) == 0 {
+		return 0, false
+	}
+	return i.PriceInfo[0].CurrentPrice, true
+}
+
+// ShopeeModel represents a model (variation) returned by get_model_list
+type ShopeeModel struct {
+	ModelID   int64             `json:"model_id"`
+	ModelSKU  string            `json:"model_sku"`
+	PriceInfo []ShopeePriceInfo `json:"price_info"`
+}
+
+// lowestModelPrice returns the lowest current price across all models
+func lowestModelPrice(models []ShopeeModel) (float64, bool) {
+	found := false
+	lowest := 0.0
+	for _, m := range models {
+		for _, p := range m.PriceInfo {
+			if !found || p.CurrentPrice < lowest {
+				lowest = p.CurrentPrice
+				found = true
+			}
+		}
+	}
+	return lowest, found
+}
+
+// getItemBaseInfo calls /product/get_item_base_info for a single item
+func (a *ShopeeAdapter) getItemBaseInfo(ctx context.Context, ref *itemRef) (*ShopeeItem, error) {
+	params := url.Values{}
+	params.Set("item_id_list", strconv.FormatInt(ref.itemID, 10))
+
+	var body struct {
+		ItemList []ShopeeItem `json:"item_list"`
+	}
+	if err := a.call(ctx, pathGetItemBaseInfo, ref, params, &body); err != nil {
+		return nil, err
+	}
+
+	if len(body.ItemList) == 0 {
+		return nil, fmt.Errorf("item %d not found", ref.itemID)
+	}
+
+	return &body.ItemList[0], nil
+}
+
+// getModelList calls /product/get_model_list for a single item
+func (a *ShopeeAdapter) getModelList(ctx context.Context, ref *itemRef) ([]ShopeeModel, error) {
+	params := url.Values{}
+	params.Set("item_id", strconv.FormatInt(ref.itemID, 10))
+
+	var body struct {
+		Model []ShopeeModel `json:"model"`
+	}
+	if err := a.call(ctx, pathGetModelList, ref, params, &body); err != nil {
+		return nil, err
+	}
+
+	return body.Model, nil
+}
+
+// call executes a signed GET request against a shop-level API endpoint and decodes the response body
+func (a *ShopeeAdapter) call(ctx context.Context, path string, ref *itemRef, params url.Values, out interface{}) error {
+	base, err := url.Parse(a.apiURL)
+	if err != nil {
+		return fmt.Errorf("invalid API URL: %w", err)
+	}
+
+	// Shop-level calls are authorized for the configured shop; fall back to the item's shop
+	shopID := a.shopID
+	if shopID == "" {
+		shopID = strconv.FormatInt(ref.shopID, 10)
+	}
+
+	apiPath := base.Path + path
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	params.Set("partner_id", a.partnerID)
+	params.Set("timestamp", timestamp)
+	params.Set("access_token", a.accessToken)
+	params.Set("shop_id", shopID)
+	params.Set("sign", a.generateSignature(apiPath, timestamp, shopID))
+
+	// Build request URL
+	base.Path = apiPath
+	base.RawQuery = params.Encode()
+
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(ctx, "GET", base.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	// Execute request
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close() // Ignore error on close
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	// Parse response
+	var apiResp ShopeeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if apiResp.Error != "" {
+		return fmt.Errorf("API error: %s - %s", apiResp.Error, apiResp.Message)
+	}
+
+	if err := json.Unmarshal(apiResp.Response, out); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	return nil
+}
+
+// generateSignature generates HMAC-SHA256 signature for Shopee shop-level API calls
+// Base string: partner_id + api_path + timestamp + access_token + shop_id
+func (a *ShopeeAdapter) generateSignature(apiPath, timestamp, shopID string) string {
+	baseString := a.partnerID + apiPath + timestamp + a.accessToken + shopID
+
+	mac := hmac.New(sha256.New, []byte(a.partnerKey))
+	mac.Write([]byte(baseString))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// resolveItemRef extracts shop/item IDs from a product URL, following short links if needed
+func (a *ShopeeAdapter) resolveItemRef(ctx context.Context, productURL string) (*itemRef, error) {
+	parsedURL, err := url.Parse(productURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if isShortLinkHost(parsedURL.Hostname()) {
+		resolvedURL, err := a.resolveShortLink(ctx, productURL)
+		if err != nil {
+			return nil, err
+		}
+		return extractItemRefFromURL(resolvedURL)
+	}
+
+	return extractItemRefFromURL(productURL)
+}
+
+// resolveShortLink follows redirects from a short link until it reaches a product URL
+func (a *ShopeeAdapter) resolveShortLink(ctx context.Context, shortURL string) (string, error) {
+	const maxHops = 5
+
+	// Don't follow redirects automatically - inspect each Location instead
+	client := *a.httpClient
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	current := shortURL
+	for i := 0; i < maxHops; i++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", current, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to create short link request: %w", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve short link: %w", err)
+		}
+		_ = resp.Body.Close() // Body is not needed
+
+		location := resp.Header.Get("Location")
+		if location == "" {
+			return "", fmt.Errorf("short link did not redirect: %s", shortURL)
+		}
+
+		next, err := req.URL.Parse(location)
+		if err != nil {
+			return "", fmt.Errorf("invalid short link redirect: %w", err)
+		}
+		current = next.String()
+
+		if !isShortLinkHost(next.Hostname()) {
+			return current, nil
+		}
+	}
+
+	return "", fmt.Errorf("too many redirects resolving short link: %s", shortURL)
+}
+
+// MatchURL reports whether u is a Shopee product URL, including short links
+func MatchURL(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	for _, d := range Domains {
+		if host == d {
+			return true
+		}
+	}
+	return isShortLinkHost(host)
+}
+
+// maxSubIDs is the number of sub-ID slots in Shopee's sub_id parameter
+const maxSubIDs = 5
+
+// EncodeSubIDs sets Shopee's sub_id parameter: up to five hyphen-separated values,
+// the click ID first followed by publisher sub IDs (extra values are dropped)
+// Values must not contain hyphens; empty slots are kept so positions stay stable
+func EncodeSubIDs(q url.Values, clickID string, subIDs []string) {
+	values := append([]string{clickID}, subIDs...)
+	if len(values) > maxSubIDs {
+		values = values[:maxSubIDs]
+	}
+	for len(values) > 0 && values[len(values)-1] == "" {
+		values = values[:len(values)-1]
+	}
+	if len(values) > 0 {
+		q.Set("sub_id", strings.Join(values, "-"))
+	}
+}
+
+// isShortLinkHost reports whether host is one of Shopee's short link domains
+func isShortLinkHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, h := range ShortLinkHosts {
+		if host == h {
+			return true
+		}
+	}
+	return false
+}
+
+// extractItemRefFromURL extracts shop and item IDs from a Shopee product URL
+// Examples:
+//
+//	https://shopee.co.th/product/123456/789012 -> shop 123456, item 789012
+//	https://shopee.co.th/Matcha-Green-Tea-i.123456.789012 -> shop 123456, item 789012
+func extractItemRefFromURL(productURL string) (*itemRef, error) {
+	parsedURL, err := url.Parse(productURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	path := parsedURL.Path
+	host := strings.ToLower(parsedURL.Hostname())
+
+	if m := productPathPattern.FindStringSubmatch(path); m != nil {
+		return newItemRef(m[1], m[2], host)
+	}
+
+	if m := seoItemPattern.FindStringSubmatch(path); m != nil {
+		return newItemRef(m[1], m[2], host)
+	}
+
+	// Try to extract from query parameters
+	q := parsedURL.Query()
+	if shopID, itemID := q.Get("shop_id"), q.Get("item_id"); shopID != "" && itemID != "" {
+		return newItemRef(shopID, itemID, host)
+	}
+
+	return nil, fmt.Errorf("could not extract item ID from URL: %s", productURL)
+}
+
+// parseSKU parses a "<shop_id>.<item_id>" or "<shop_id>/<item_id>" SKU
+func parseSKU(sku string) (*itemRef, error) {
+	parts := strings.FieldsFunc(sku, func(r rune) bool { return r == '.' || r == '/' || r == ':' })
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid Shopee SKU %q: expected <shop_id>.<item_id>", sku)
+	}
+	return newItemRef(parts[0], parts[1], "")
+}
+
+func newItemRef(shopIDStr, itemIDStr, host string) (*itemRef, error) {
+	shopID, err := strconv.ParseInt(shopIDStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shop ID %q: %w", shopIDStr, err)
+	}
+	itemID, err := strconv.ParseInt(itemIDStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid item ID %q: %w", itemIDStr, err)
+	}
+	if isShortLinkHost(host) {
+		host = ""
+	}
+	return &itemRef{shopID: shopID, itemID: itemID, host: host}, nil
 }
@@ -0,0 +1,255 @@
+package shopee
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonosize/affiliate-platform/pkg/adapters"
+)
+
+const (
+	testPartnerID   = "2001234"
+	testPartnerKey  = "test-partner-key"
+	testShopID      = "123456"
+	testAccessToken = "test-access-token"
+)
+
+// fakeShopeeAPI is a local stand-in for the Shopee Open Platform API
+type fakeShopeeAPI struct {
+	items    map[string]map[string]interface{}
+	models   map[string][]map[string]interface{}
+	requests []string
+}
+
+func (f *fakeShopeeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests = append(f.requests, r.URL.Path)
+	q := r.URL.Query()
+
+	// Verify signature exactly as Shopee does
+	base := q.Get("partner_id") + r.URL.Path + q.Get("timestamp") + q.Get("access_token") + q.Get("shop_id")
+	mac := hmac.New(sha256.New, []byte(testPartnerKey))
+	mac.Write([]byte(base))
+	if q.Get("sign") != hex.EncodeToString(mac.Sum(nil)) {
+		writeJSON(w, map[string]interface{}{"error": "error_sign", "message": "Wrong sign."})
+		return
+	}
+
+	switch r.URL.Path {
+	case "/api/v2/product/get_item_base_info":
+		item, ok := f.items[q.Get("item_id_list")]
+		itemList := []interface{}{}
+		if ok {
+			itemList = append(itemList, item)
+		}
+		writeJSON(w, map[string]interface{}{
+			"error":    "",
+			"message":  "",
+			"response": map[string]interface{}{"item_list": itemList},
+		})
+	case "/api/v2/product/get_model_list":
+		writeJSON(w, map[string]interface{}{
+			"error":    "",
+			"message":  "",
+			"response": map[string]interface{}{"model": f.models[q.Get("item_id")]},
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestAdapter(t *testing.T) (*ShopeeAdapter, *fakeShopeeAPI) {
+	api := &fakeShopeeAPI{
+		items: map[string]map[string]interface{}{
+			"789012": {
+				"item_id":    789012,
+				"item_name":  "Matcha Green Tea Powder 100g",
+				"has_model":  false,
+				"price_info": []map[string]interface{}{{"currency": "THB", "current_price": 299.0, "original_price": 350.0}},
+				"image":      map[string]interface{}{"image_url_list": []string{"https://cf.shopee.co.th/file/matcha.jpg"}},
+			},
+			"555555": {
+				"item_id":   555555,
+				"item_name": "Matcha Whisk Set",
+				"has_model": true,
+				"image":     map[string]interface{}{"image_url_list": []string{}},
+			},
+		},
+		models: map[string][]map[string]interface{}{
+			"555555": {
+				{"model_id": 1, "price_info": []map[string]interface{}{{"current_price": 459.0}}},
+				{"model_id": 2, "price_info": []map[string]interface{}{{"current_price": 389.0}}},
+			},
+		},
+	}
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	adapter := NewAdapter(testPartnerID, testPartnerKey, testShopID, testAccessToken)
+	adapter.SetAPIURL(server.URL + "/api/v2")
+
+	return adapter, api
+}
+
+func TestExtractItemRefFromURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		wantShopID int64
+		wantItemID int64
+		wantErr    bool
+	}{
+		{
+			name:       "product path",
+			url:        "https://shopee.co.th/product/123456/789012",
+			wantShopID: 123456,
+			wantItemID: 789012,
+		},
+		{
+			name:       "product path with query",
+			url:        "https://shopee.co.th/product/123456/789012?smtt=0.0.9",
+			wantShopID: 123456,
+			wantItemID: 789012,
+		},
+		{
+			name:       "seo slug",
+			url:        "https://shopee.co.th/Matcha-Green-Tea-Powder-i.123456.789012",
+			wantShopID: 123456,
+			wantItemID: 789012,
+		},
+		{
+			name:       "seo slug with thai characters",
+			url:        "https://shopee.co.th/%E0%B8%A1%E0%B8%B1%E0%B8%97%E0%B8%89%E0%B8%B0-i.123456.789012?sp_atk=abc",
+			wantShopID: 123456,
+			wantItemID: 789012,
+		},
+		{
+			name:       "query parameters",
+			url:        "https://shopee.co.th/universal-link?shop_id=123456&item_id=789012",
+			wantShopID: 123456,
+			wantItemID: 789012,
+		},
+		{
+			name:    "shop page",
+			url:     "https://shopee.co.th/matcha_official",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := extractItemRefFromURL(tt.url)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantShopID, ref.shopID)
+			assert.Equal(t, tt.wantItemID, ref.itemID)
+		})
+	}
+}
+
+func TestShopeeAdapter_FetchProduct(t *testing.T) {
+	adapter, _ := newTestAdapter(t)
+
+	product, err := adapter.FetchProduct(context.Background(), "https://shopee.co.th/Matcha-i.123456.789012", adapters.SourceTypeURL)
+	require.NoError(t, err)
+	assert.Equal(t, "Matcha Green Tea Powder 100g", product.Title)
+	assert.Equal(t, "https://cf.shopee.co.th/file/matcha.jpg", product.ImageURL)
+	assert.Equal(t, "https://shopee.co.th/product/123456/789012", product.MarketplaceProductURL)
+
+	product, err = adapter.FetchProduct(context.Background(), "123456.789012", adapters.SourceTypeSKU)
+	require.NoError(t, err)
+	assert.Equal(t, "Matcha Green Tea Powder 100g", product.Title)
+
+	_, err = adapter.FetchProduct(context.Background(), "https://shopee.co.th/product/123456/1", adapters.SourceTypeURL)
+	assert.Error(t, err)
+}
+
+func TestShopeeAdapter_FetchOffer(t *testing.T) {
+	adapter, api := newTestAdapter(t)
+
+	t.Run("item without models uses item price", func(t *testing.T) {
+		offer, err := adapter.FetchOffer(context.Background(), "https://shopee.co.th/product/123456/789012")
+		require.NoError(t, err)
+		assert.Equal(t, 299.0, offer.Price)
+		assert.Equal(t, "https://shopee.co.th/product/123456/789012", offer.MarketplaceProductURL)
+	})
+
+	t.Run("item with models uses lowest model price", func(t *testing.T) {
+		api.requests = nil
+		offer, err := adapter.FetchOffer(context.Background(), "https://shopee.co.th/Whisk-i.123456.555555")
+		require.NoError(t, err)
+		assert.Equal(t, 389.0, offer.Price)
+		assert.Equal(t, []string{"/api/v2/product/get_item_base_info", "/api/v2/product/get_model_list"}, api.requests)
+	})
+}
+
+func TestShopeeAdapter_InvalidSignature(t *testing.T) {
+	adapter, _ := newTestAdapter(t)
+	adapter.partnerKey = "wrong-key"
+
+	_, err := adapter.FetchOffer(context.Background(), "https://shopee.co.th/product/123456/789012")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error_sign")
+}
+
+func TestShopeeAdapter_ShortLink(t *testing.T) {
+	adapter, _ := newTestAdapter(t)
+
+	// Stand-in for shp.ee that redirects to the full product URL
+	shortLinks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://shopee.co.th/Matcha-i.123456.789012", http.StatusFound)
+	}))
+	t.Cleanup(shortLinks.Close)
+
+	// Route short link hosts to the stand-in server, everything else goes to the fake API
+	shortLinksURL, err := url.Parse(shortLinks.URL)
+	require.NoError(t, err)
+	transport := http.DefaultTransport
+	adapter.httpClient.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if isShortLinkHost(req.URL.Hostname()) {
+			req = req.Clone(req.Context())
+			req.URL.Scheme = shortLinksURL.Scheme
+			req.URL.Host = shortLinksURL.Host
+		}
+		return transport.RoundTrip(req)
+	})
+
+	offer, err := adapter.FetchOffer(context.Background(), "https://shp.ee/abc123")
+	require.NoError(t, err)
+	assert.Equal(t, 299.0, offer.Price)
+	assert.Equal(t, "https://shopee.co.th/product/123456/789012", offer.MarketplaceProductURL, "Short links resolve to the canonical item URL")
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestEncodeSubIDs(t *testing.T) {
+	q := url.Values{}
+	EncodeSubIDs(q, "click1", []string{"a", "", "c", "d", "e"})
+	assert.Equal(t, "click1-a--c-d", q.Get("sub_id"))
+
+	q = url.Values{}
+	EncodeSubIDs(q, "click1", nil)
+	assert.Equal(t, "click1", q.Get("sub_id"))
+}